@@ -0,0 +1,65 @@
+package bufpool
+
+import (
+	"bytes"
+	"sync"
+	"sync/atomic"
+)
+
+// ! Buffers más grandes que esto no vuelven al pool para no retener memoria de picos
+const maxPooledSize = 4 << 20
+
+type Pool struct {
+	pool      sync.Pool
+	gets      atomic.Int64
+	allocs    atomic.Int64
+	puts      atomic.Int64
+	discarded atomic.Int64
+}
+
+type Stats struct {
+	Gets      int64 `json:"gets"`
+	Allocs    int64 `json:"allocs"`
+	Puts      int64 `json:"puts"`
+	Discarded int64 `json:"discarded"`
+}
+
+// * Pool compartido por el proxy de imágenes y los writers de respuesta
+var Default = New()
+
+func New() *Pool {
+	p := &Pool{}
+	p.pool.New = func() any {
+		p.allocs.Add(1)
+		return new(bytes.Buffer)
+	}
+	return p
+}
+
+func (p *Pool) Get() *bytes.Buffer {
+	p.gets.Add(1)
+	buf := p.pool.Get().(*bytes.Buffer)
+	buf.Reset()
+	return buf
+}
+
+func (p *Pool) Put(buf *bytes.Buffer) {
+	if buf == nil {
+		return
+	}
+	if buf.Cap() > maxPooledSize {
+		p.discarded.Add(1)
+		return
+	}
+	p.puts.Add(1)
+	p.pool.Put(buf)
+}
+
+func (p *Pool) Stats() Stats {
+	return Stats{
+		Gets:      p.gets.Load(),
+		Allocs:    p.allocs.Load(),
+		Puts:      p.puts.Load(),
+		Discarded: p.discarded.Load(),
+	}
+}
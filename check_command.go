@@ -0,0 +1,401 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ChrisTheAbysswalker/meownder-backend/config"
+	mw "github.com/ChrisTheAbysswalker/meownder-backend/middleware"
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+	"github.com/ChrisTheAbysswalker/meownder-backend/redis"
+	"github.com/ChrisTheAbysswalker/meownder-backend/schemas"
+	"github.com/ChrisTheAbysswalker/meownder-backend/sentry"
+	s "github.com/ChrisTheAbysswalker/meownder-backend/services"
+)
+
+// * meownder check [-json] [-offline] [-timeout 5s]: valida configuración, perfiles,
+// * proveedores, base de datos y Redis. Sale con error si algo falla (gate de deploy
+// * o init container); los avisos no cortan salvo con -strict
+func runCheckCommand(cfg *config.Config, args []string) error {
+	flags := flag.NewFlagSet("check", flag.ContinueOnError)
+	asJSON := flags.Bool("json", false, "salida en JSON")
+	offline := flags.Bool("offline", false, "no contactar proveedores de imágenes")
+	strict := flags.Bool("strict", false, "tratar los avisos como fallos")
+	timeout := flags.Duration("timeout", 5*time.Second, "tiempo máximo de cada comprobación de red")
+	verbose := flags.Bool("v", false, "mostrar los logs de los servicios")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if !*verbose {
+		log.SetOutput(io.Discard)
+		defer log.SetOutput(os.Stderr)
+	}
+
+	checker := &checker{timeout: *timeout}
+	mix := checker.config(cfg)
+	checker.profiles(cfg)
+	checker.eventSchemas()
+	if *offline || mix == nil {
+		checker.skip("providers", "sin probar (-offline o configuración inválida)")
+	} else {
+		checker.providers(mix)
+	}
+	checker.database(cfg.Database)
+	checker.redis(cfg.Redis)
+
+	report := checker.report(*strict)
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			return err
+		}
+	} else {
+		printCheckReport(report)
+	}
+
+	if report.Status == m.CheckFail {
+		return fmt.Errorf("%d comprobaciones fallaron", countChecks(report, m.CheckFail))
+	}
+	return nil
+}
+
+type checker struct {
+	timeout time.Duration
+	results []m.CheckResult
+}
+
+// * Un check que devuelve checkWarning queda como aviso en vez de fallo
+type checkWarning string
+
+func (w checkWarning) Error() string {
+	return string(w)
+}
+
+func (c *checker) run(name string, fn func() (string, error)) {
+	start := time.Now()
+	detail, err := fn()
+	result := m.CheckResult{Name: name, Status: m.CheckOK, Detail: detail, DurationMs: time.Since(start).Milliseconds()}
+	var warning checkWarning
+	switch {
+	case errors.As(err, &warning):
+		result.Status = m.CheckWarn
+		result.Detail = warning.Error()
+	case err != nil:
+		result.Status = m.CheckFail
+		result.Detail = err.Error()
+	}
+	c.results = append(c.results, result)
+}
+
+func (c *checker) skip(name, detail string) {
+	c.results = append(c.results, m.CheckResult{Name: name, Status: m.CheckSkip, Detail: detail})
+}
+
+func (c *checker) config(cfg *config.Config) *s.ProviderMix {
+	var mix *s.ProviderMix
+	c.run("config.env", func() (string, error) {
+		if err := cfg.Validate(); err != nil {
+			detail := strings.ReplaceAll(err.Error(), "\n", "; ")
+			if cfg.Env == config.EnvStaging {
+				return "", checkWarning(detail)
+			}
+			return "", errors.New(detail)
+		}
+		return cfg.Env, nil
+	})
+
+	c.run("config.providers", func() (string, error) {
+		weights, err := s.ParseProviderWeights(cfg.Providers.Weights)
+		if err != nil {
+			return "", fmt.Errorf("IMAGE_PROVIDERS: %w", err)
+		}
+		headers, err := s.ParseProviderHeaders(cfg.Providers.Headers)
+		if err != nil {
+			return "", fmt.Errorf("PROVIDER_HEADERS: %w", err)
+		}
+		transport, err := s.NewProviderTransport(cfg.Providers.ProxyURL, cfg.Providers.UserAgent, headers)
+		if err != nil {
+			return "", fmt.Errorf("PROVIDER_PROXY_URL: %w", err)
+		}
+		if cfg.Providers.DNSCache {
+			dnsCache := s.NewDNSCache(cfg.Providers.DNSMinTTL, cfg.Providers.DNSMaxTTL)
+			if err := transport.UseDNSCache(dnsCache, cfg.Providers.IPFamily, cfg.Providers.FallbackDelay); err != nil {
+				return "", fmt.Errorf("PROVIDER_IP_FAMILY: %w", err)
+			}
+		}
+		if cfg.Providers.RateLimits != "" {
+			limits, err := s.ParseProviderWeights(cfg.Providers.RateLimits)
+			if err == nil {
+				err = transport.SetBudgets(limits, cfg.Providers.MaxQueue)
+			}
+			if err != nil {
+				return "", fmt.Errorf("PROVIDER_RATE_LIMITS: %w", err)
+			}
+		}
+		if mix, err = s.NewProviderMix(weights, cfg.Providers.TheCatAPIKey, cfg.BaseURL, transport); err != nil {
+			return "", err
+		}
+		return cfg.Providers.Weights, nil
+	})
+
+	c.run("config.ranking", func() (string, error) {
+		for _, name := range []string{cfg.Ranking.Live, cfg.Ranking.Shadow} {
+			if name == "" {
+				continue
+			}
+			if _, err := s.ParseDeckRanker(name); err != nil {
+				return "", err
+			}
+		}
+		return cfg.Ranking.Live, nil
+	})
+
+	c.run("config.runtime", func() (string, error) {
+		if _, err := mw.ParseLogLevel(cfg.Logging.Level); err != nil {
+			return "", fmt.Errorf("LOG_LEVEL: %w", err)
+		}
+		if _, err := s.ParseFeatureFlags(cfg.Features.Flags); err != nil {
+			return "", fmt.Errorf("FEATURE_FLAGS: %w", err)
+		}
+		return "log " + cfg.Logging.Level, nil
+	})
+
+	c.run("config.backends", func() (string, error) {
+		if cfg.RateLimit.Backend == "redis" && cfg.Redis.URL == "" {
+			return "", fmt.Errorf("RATE_LIMIT_BACKEND=redis requiere REDIS_URL")
+		}
+		switch cfg.Scheduler.Lock {
+		case "":
+		case "redis":
+			if cfg.Redis.URL == "" {
+				return "", fmt.Errorf("SCHEDULER_LOCK=redis requiere REDIS_URL")
+			}
+		case "database":
+			if cfg.Database.URL == "" {
+				return "", fmt.Errorf("SCHEDULER_LOCK=database requiere DATABASE_URL")
+			}
+		default:
+			return "", fmt.Errorf("SCHEDULER_LOCK desconocido: %s", cfg.Scheduler.Lock)
+		}
+		return fmt.Sprintf("rate limit %s", cfg.RateLimit.Backend), nil
+	})
+
+	c.run("config.integrations", func() (string, error) {
+		var enabled []string
+		if cfg.Sentry.DSN != "" {
+			if _, err := sentry.New(cfg.Sentry.DSN, cfg.Sentry.Environment, cfg.Sentry.Release); err != nil {
+				return "", fmt.Errorf("SENTRY_DSN: %w", err)
+			}
+			enabled = append(enabled, "sentry")
+		}
+		if cfg.Alerts.WebhookURL != "" {
+			notifier, err := s.NewAlertNotifier(cfg.Alerts.WebhookURL, cfg.Alerts.WebhookKind)
+			if err != nil {
+				return "", fmt.Errorf("ALERT_WEBHOOK_URL: %w", err)
+			}
+			enabled = append(enabled, "alertas "+notifier.Kind())
+		}
+		if cfg.Chaos.Enabled {
+			return "", checkWarning("CHAOS_ENABLED activo: se inyectan fallos")
+		}
+		return strings.Join(enabled, ", "), nil
+	})
+
+	c.run("config.abuse", func() (string, error) {
+		detail := "puntaje apagado"
+		if cfg.Abuse.Scoring {
+			detail = fmt.Sprintf("puntaje sobre %d/min", cfg.Abuse.MaxPerMinute)
+		}
+		if cfg.Abuse.ASNFile != "" {
+			index, err := s.LoadASNIndex(cfg.Abuse.ASNFile)
+			if err != nil {
+				return "", fmt.Errorf("ABUSE_ASN_FILE: %w", err)
+			}
+			detail += fmt.Sprintf(", %d rangos ASN", index.Len())
+		}
+		if cfg.Abuse.PowDifficulty > 0 {
+			if cfg.Abuse.PowDifficulty > 32 {
+				return "", fmt.Errorf("ABUSE_POW_DIFFICULTY=%d: más de 32 bits es impracticable para un navegador", cfg.Abuse.PowDifficulty)
+			}
+			if cfg.Abuse.PowSecret == "" {
+				return "", checkWarning("ABUSE_POW_SECRET vacío: con varias réplicas se rechazan los desafíos ajenos")
+			}
+			detail += fmt.Sprintf(", prueba de trabajo %d bits", cfg.Abuse.PowDifficulty)
+		}
+		return detail, nil
+	})
+
+	if cfg.TLS.Enabled() && !cfg.TLS.AutocertEnabled() {
+		c.run("config.tls", func() (string, error) {
+			cert, err := tls.LoadX509KeyPair(cfg.TLS.CertFile, cfg.TLS.KeyFile)
+			if err != nil {
+				return "", err
+			}
+			if len(cert.Certificate) == 0 || cert.Leaf == nil {
+				return cfg.TLS.CertFile, nil
+			}
+			if until := time.Until(cert.Leaf.NotAfter); until < 14*24*time.Hour {
+				return "", checkWarning(fmt.Sprintf("el certificado vence en %s", until.Round(time.Hour)))
+			}
+			return cfg.TLS.CertFile, nil
+		})
+	}
+	return mix
+}
+
+func (c *checker) profiles(cfg *config.Config) {
+	tenants := []m.TenantSpec{{ID: s.DefaultTenantID, ProfilesFile: "cats.json"}}
+	c.run("config.tenants", func() (string, error) {
+		specs, err := s.LoadTenantSpecs(cfg.Tenants.File)
+		if err != nil {
+			return "", err
+		}
+		tenants = append(tenants, specs...)
+		return fmt.Sprintf("%d tenants", len(tenants)), nil
+	})
+
+	for _, tenant := range tenants {
+		path := tenant.ProfilesFile
+		c.run("profiles."+tenant.ID, func() (string, error) {
+			count, problems, err := s.ValidateProfilesFile(path)
+			if err != nil {
+				return "", err
+			}
+			if len(problems) > 0 {
+				return "", fmt.Errorf("%s: %s", path, strings.Join(problems, "; "))
+			}
+			return fmt.Sprintf("%s: %d perfiles", path, count), nil
+		})
+	}
+}
+
+func (c *checker) eventSchemas() {
+	c.run("events.schemas", func() (string, error) {
+		if err := schemas.Check(); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%d temas", len(schemas.Topics())), nil
+	})
+}
+
+// * Un proveedor caído es aviso (el mix reparte entre los demás); todos caídos es fallo
+func (c *checker) providers(mix *s.ProviderMix) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	probes := mix.Probe(ctx, c.timeout)
+	failed := 0
+	for _, probe := range probes {
+		if probe.Err != nil {
+			failed++
+		}
+	}
+	for _, probe := range probes {
+		result := m.CheckResult{Name: "provider." + probe.Name, Status: m.CheckOK, DurationMs: probe.Latency.Milliseconds()}
+		if probe.Err != nil {
+			result.Status = m.CheckWarn
+			if failed == len(probes) {
+				result.Status = m.CheckFail
+			}
+			result.Detail = probe.Err.Error()
+		}
+		c.results = append(c.results, result)
+	}
+}
+
+func (c *checker) database(cfg config.DatabaseConfig) {
+	if cfg.URL == "" {
+		c.skip("database", "sin DATABASE_URL")
+		return
+	}
+	c.run("database", func() (string, error) {
+		migrator, db, err := openMigrator(cfg)
+		if err != nil {
+			return "", err
+		}
+		defer db.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+		defer cancel()
+		if err := db.PingContext(ctx); err != nil {
+			return "", err
+		}
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return "", err
+		}
+		pending := 0
+		for _, status := range statuses {
+			if status.AppliedAt == nil {
+				pending++
+			}
+		}
+		if pending > 0 && !cfg.MigrateOnStart {
+			return "", checkWarning(fmt.Sprintf("%d migraciones pendientes (DATABASE_MIGRATE_ON_START apagado)", pending))
+		}
+		return fmt.Sprintf("%s, %d migraciones pendientes", cfg.Driver, pending), nil
+	})
+}
+
+func (c *checker) redis(cfg config.RedisConfig) {
+	if cfg.URL == "" {
+		c.skip("redis", "sin REDIS_URL")
+		return
+	}
+	c.run("redis", func() (string, error) {
+		client, err := redis.New(cfg.URL, 1, c.timeout)
+		if err != nil {
+			return "", err
+		}
+		defer client.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+		defer cancel()
+		return "", client.Ping(ctx)
+	})
+}
+
+func (c *checker) report(strict bool) m.CheckReport {
+	report := m.CheckReport{Status: m.CheckOK, Checks: c.results}
+	for _, result := range c.results {
+		if result.Status == m.CheckFail || (strict && result.Status == m.CheckWarn) {
+			report.Status = m.CheckFail
+			break
+		}
+		if result.Status == m.CheckWarn {
+			report.Status = m.CheckWarn
+		}
+	}
+	return report
+}
+
+func countChecks(report m.CheckReport, status string) int {
+	count := 0
+	for _, result := range report.Checks {
+		if result.Status == status {
+			count++
+		}
+	}
+	return count
+}
+
+func printCheckReport(report m.CheckReport) {
+	icons := map[string]string{m.CheckOK: "✅", m.CheckWarn: "⚠️ ", m.CheckFail: "❌", m.CheckSkip: "⏭️ "}
+	for _, result := range report.Checks {
+		fmt.Printf("%s %-22s %6dms  %s\n", icons[result.Status], result.Name, result.DurationMs, result.Detail)
+	}
+	fmt.Printf("\n%s %d ok, %d avisos, %d fallos, %d omitidas\n", icons[report.Status],
+		countChecks(report, m.CheckOK), countChecks(report, m.CheckWarn),
+		countChecks(report, m.CheckFail), countChecks(report, m.CheckSkip))
+}
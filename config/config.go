@@ -0,0 +1,903 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Config struct {
+	// * dev, staging o prod: cambia los valores por defecto (ver envProfiles)
+	Env     string
+	Port    string
+	BaseURL string
+	// * KEY=VALUE que pisa al entorno; se relee con SIGHUP o POST /api/admin/config/reload
+	File           string
+	Server         ServerConfig
+	Security       SecurityConfig
+	Limits         LimitsConfig
+	Abuse          AbuseConfig
+	SwipeFraud     SwipeFraudConfig
+	Logging        LoggingConfig
+	Sentry         SentryConfig
+	Alerts         AlertsConfig
+	Health         HealthConfig
+	Chaos          ChaosConfig
+	Features       FeaturesConfig
+	Proxy          ProxyConfig
+	TLS            TLSConfig
+	Share          ShareConfig
+	Admin          AdminConfig
+	CORS           CORSConfig
+	JSON           JSONConfig
+	Webhooks       WebhooksConfig
+	Matching       MatchingConfig
+	Telegram       TelegramConfig
+	WarmUp         WarmUpConfig
+	Reservoir      ReservoirConfig
+	Retry          RetryConfig
+	LoadShed       LoadShedConfig
+	Cache          CacheConfig
+	Providers      ProvidersConfig
+	ImageQuality   ImageQualityConfig
+	StableImages   StableImagesConfig
+	Ranking        RankingConfig
+	Sponsored      SponsoredConfig
+	DeckPrefetch   DeckPrefetchConfig
+	DeckChanges    DeckChangesConfig
+	Search         SearchConfig
+	RateLimit      RateLimitConfig
+	Redis          RedisConfig
+	Scheduler      SchedulerConfig
+	Database       DatabaseConfig
+	Backup         BackupConfig
+	Jobs           JobsConfig
+	Tenants        TenantsConfig
+	Icebreakers    IcebreakersConfig
+	TextGen        TextGenConfig
+	Compatibility  CompatibilityConfig
+	Themes         ThemesConfig
+	Collections    CollectionsConfig
+	Digest         DigestConfig
+	OwnedCats      OwnedCatsConfig
+	Widget         WidgetConfig
+	APITokens      APITokensConfig
+	SignedRequests SignedRequestsConfig
+	Links          LinksConfig
+}
+
+// * Log de peticiones: los cuerpos solo se guardan en errores 4xx/5xx o en la fracción
+// * muestreada, siempre recortados y con los campos sensibles redactados
+type LoggingConfig struct {
+	// * debug, info, warn o error (ver middleware.LogLevel); recargable en caliente
+	Level          string
+	BodySampleRate float64
+	CaptureErrors  bool
+	MaxBodyBytes   int
+	RedactFields   []string
+	SkipPaths      []string
+
+	// * Archivo además de (o en lugar de) la salida estándar; vacío = solo consola
+	File       string
+	Console    bool
+	MaxSizeMB  int
+	MaxAge     time.Duration
+	MaxBackups int
+	Compress   bool
+}
+
+// * Reporte de errores opcional; vale cualquier servidor compatible con sentry
+type SentryConfig struct {
+	DSN         string
+	Environment string
+	// * Vacío = revisión de git embebida en el binario
+	Release string
+}
+
+// * Alertas de SLO por webhook; sin AlertWebhookURL el monitor no arranca
+type AlertsConfig struct {
+	WebhookURL  string
+	WebhookKind string
+	Interval    time.Duration
+	Cooldown    time.Duration
+	Window      time.Duration
+	ErrorRate   float64
+	MinRequests int
+	P99         time.Duration
+}
+
+// * Umbrales de frescura para marcar /health como "degraded"; 0 desactiva cada uno
+type HealthConfig struct {
+	DatasetMaxAge time.Duration
+	ProbeMaxAge   time.Duration
+}
+
+// ! Solo para entornos de prueba: habilita /admin/chaos y la inyección de fallas
+type ChaosConfig struct {
+	Enabled bool
+}
+
+// * Tope de cuerpo según el tipo y de cabeceras por petición; 0 desactiva cada uno. Las
+// * rutas con cuerpos grandes (import, restore) tienen su propio tope
+type LimitsConfig struct {
+	MaxJSONBytes      int64
+	MaxMultipartBytes int64
+	// * Cualquier otro tipo de cuerpo
+	MaxBodyBytes int64
+	MaxHeaders   int
+	// * Por valor; el total lo acota SERVER_MAX_HEADER_BYTES
+	MaxHeaderValueBytes int
+}
+
+// * Puntaje de abuso por IP, lista de bloqueo y prueba de trabajo en rutas de escritura
+type AbuseConfig struct {
+	Scoring bool
+	// * Peticiones ponderadas por minuto que suman 100 puntos de velocidad
+	MaxPerMinute int
+	BlockScore   float64
+	// * 429 seguidos antes de bloquear la IP durante AutoBlockTTL; 0 = nunca
+	AutoBlockAfter int
+	AutoBlockTTL   time.Duration
+	BlocklistFile  string
+	// * TSV de iptoasn.com para bloquear por ASN
+	ASNFile string
+	// * Bits en cero exigidos; 0 apaga la prueba de trabajo
+	PowDifficulty int
+	PowSecret     string
+	PowTTL        time.Duration
+	// * Clave compartida con la app para firmar peticiones en lugar de resolver desafíos
+	ClientSecret string
+}
+
+// * Swipes no humanos por usuario (ver services.SwipeFraudDetector)
+type SwipeFraudConfig struct {
+	Enabled           bool
+	MaxLikesPerMinute int
+	UniformWindow     int
+	MaxUniformity     float64
+	Cooldown          time.Duration
+}
+
+// * Funciones opcionales apagadas sin desplegar, ej. "chat=off,widget=off"; recargable
+type FeaturesConfig struct {
+	Flags string
+}
+
+type SecurityConfig struct {
+	Enabled               bool
+	ContentTypeOptions    string
+	FrameOptions          string
+	ReferrerPolicy        string
+	ContentSecurityPolicy string
+	HTMLSecurityPolicy    string
+}
+
+type ServerConfig struct {
+	SocketPath        string
+	SocketMode        os.FileMode
+	ReadTimeout       time.Duration
+	ReadHeaderTimeout time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	MaxHeaderBytes    int
+	H2C               bool
+}
+
+type ProxyConfig struct {
+	TrustedProxies  []string
+	RemoteIPHeaders []string
+}
+
+type TLSConfig struct {
+	CertFile        string
+	KeyFile         string
+	AutocertDomains []string
+	AutocertEmail   string
+	AutocertCache   string
+	RedirectHTTP    bool
+	HTTPPort        string
+}
+
+func (t TLSConfig) Enabled() bool {
+	return t.AutocertEnabled() || (t.CertFile != "" && t.KeyFile != "")
+}
+
+func (t TLSConfig) AutocertEnabled() bool {
+	return len(t.AutocertDomains) > 0
+}
+
+type ShareConfig struct {
+	DeepLinkBase string
+}
+
+type AdminConfig struct {
+	APIKey string
+	// * Sin clave y con esto en false (por defecto en dev) las rutas de admin no piden auth
+	RequireKey bool
+}
+
+// * "*" permite cualquier origen; si no, solo se devuelve el Origin que esté en la lista
+type CORSConfig struct {
+	AllowedOrigins []string
+}
+
+// * Convención de claves si el cliente no pide una con ?case= o el Accept: snake o camel
+type JSONConfig struct {
+	Case string
+}
+
+type WebhooksConfig struct {
+	DiscordURL   string
+	SlackURL     string
+	CatOfDayHour int
+	CatOfDayMin  int
+	// * Secreto de firma de los canales de DISCORD/SLACK_WEBHOOK_URL; vacío = uno al azar
+	SigningSecret string
+	// * Cuánto sigue firmando el secreto anterior tras rotarlo
+	SecretGrace time.Duration
+	// * Canales (con sus secretos) creados o editados por el admin; vacío = solo en memoria
+	File string
+}
+
+type MatchingConfig struct {
+	MatchProbability float64
+	// * Matches mutuos sin mensajes: aviso y luego archivo; 0 en ExpiryInterval apaga el job
+	NudgeAfter     time.Duration
+	ArchiveAfter   time.Duration
+	ExpiryInterval time.Duration
+}
+
+// * Shadow vacío apaga la comparación; el candidato nunca decide lo que se sirve
+type RankingConfig struct {
+	Live             string
+	Shadow           string
+	ShadowSampleRate float64
+	ShadowTopK       int
+}
+
+// * Perfiles patrocinados: un lugar cada Every tarjetas del mazo, como mucho MaxPerDeck
+type SponsoredConfig struct {
+	Every      int
+	MaxPerDeck int
+}
+
+// * POST /deck/prefetch: cuánto se guarda el mazo preparado y cuánto puede tardar el HEAD
+// * de sus imágenes
+type DeckPrefetchConfig struct {
+	TTL        time.Duration
+	Validation time.Duration
+}
+
+// * GET /deck/changes: cuánto se sostiene una petición sin cambios (nunca más que
+// * SERVER_WRITE_TIMEOUT menos un margen) y cuántos cambios se guardan por tenant
+type DeckChangesConfig struct {
+	Hold   time.Duration
+	Retain int
+}
+
+// * Margen que se deja entre el fin de la espera y el corte de SERVER_WRITE_TIMEOUT
+const deckChangesMargin = 5 * time.Second
+
+// * Hold que realmente se usa: sin límite de escritura (0) no hay nada que recortar. Con
+// * un límite tan corto que el margen se lo come entero se espera la mitad del límite,
+// * así la petición sigue sostenida y termina antes del corte
+func (d DeckChangesConfig) EffectiveHold(writeTimeout time.Duration) time.Duration {
+	if writeTimeout <= 0 {
+		return d.Hold
+	}
+	limit := writeTimeout - deckChangesMargin
+	if limit <= 0 {
+		limit = writeTimeout / 2
+	}
+	return min(d.Hold, limit)
+}
+
+// * ?q= en /profiles: "memory" indexa en el proceso; "elasticsearch" usa SEARCH_ELASTIC_URL
+// * con un índice <SEARCH_ELASTIC_INDEX>-<tenant>. Vacío desactiva la búsqueda de texto.
+// * Boosts es campo:peso (name, breed, personality, hobbies, tags, bio) y Fuzziness
+// * "auto" o cuántas letras de diferencia se toleran (0-2)
+type SearchConfig struct {
+	Backend      string
+	ElasticURL   string
+	ElasticIndex string
+	Timeout      time.Duration
+	SyncInterval time.Duration
+	Boosts       []string
+	Fuzziness    string
+	MaxHits      int
+}
+
+// * "memory" limita por réplica; "redis" comparte el límite entre todas (REDIS_URL)
+type RateLimitConfig struct {
+	Backend string
+}
+
+// * Compartido por los límites de peticiones y los locks del scheduler
+type RedisConfig struct {
+	URL string
+}
+
+// * Lock vacío: cada réplica corre todos los jobs. "redis" o "database" para varias
+type SchedulerConfig struct {
+	Lock    string
+	LockTTL time.Duration
+}
+
+type TenantsConfig struct {
+	// * JSON con los refugios adicionales; el tenant "default" siempre existe
+	File string
+	// * Dominio base para resolver el tenant por subdominio; vacío = solo X-Tenant
+	Domain string
+}
+
+// * Sin ICEBREAKER_LLM_URL las frases salen solo de plantillas
+type IcebreakersConfig struct {
+	LLMURL    string
+	LLMAPIKey string
+	LLMModel  string
+	Timeout   time.Duration
+}
+
+// * Bios y personalidades para el admin; sin TEXTGEN_LLM_URL solo plantillas
+type TextGenConfig struct {
+	LLMURL        string
+	LLMAPIKey     string
+	LLMModel      string
+	Timeout       time.Duration
+	RatePerMinute int
+}
+
+// * Mini-juego público de compatibilidad de nombres; el límite es por IP
+type CompatibilityConfig struct {
+	RatePerMinute int
+	CacheTTL      time.Duration
+}
+
+// * Temas de temporada para las imágenes de cataas; sin archivo no hay temas
+type ThemesConfig struct {
+	File string
+}
+
+// * Colecciones curadas; vacío = solo en memoria
+type CollectionsConfig struct {
+	File string
+}
+
+// * Doble opt-in de gatos de usuarios; el correo sale por el SMTP del resumen semanal
+type OwnedCatsConfig struct {
+	Verify    bool
+	VerifyTTL time.Duration
+	// * Los confirmados quedan en draft hasta que un admin los publique
+	Review         bool
+	ExpiryInterval time.Duration
+}
+
+// * Resumen semanal por correo; sin SMTPHost los correos solo van al log
+type DigestConfig struct {
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+	From         string
+	Weekday      string
+	Hour         int
+	Minute       int
+}
+
+// * Tokens de solo lectura para widget e integradores; vacío = solo en memoria
+type APITokensConfig struct {
+	File string
+}
+
+// * Secret es el secreto HMAC del tenant por defecto; los demás lo traen en TENANTS_FILE
+type SignedRequestsConfig struct {
+	Secret string
+	// * Diferencia máxima aceptada entre X-Signature-Timestamp y el reloj del servidor
+	Skew time.Duration
+}
+
+// * Claves del widget embebible: "clave=https://blog.com https://otro.com", separadas por comas
+type WidgetConfig struct {
+	Keys []string
+}
+
+type LinksConfig struct {
+	// * Creación de enlaces cortos por IP; las redirecciones no se limitan
+	RatePerMinute int
+}
+
+type BackupConfig struct {
+	Dir string
+	// * 0 desactiva el respaldo programado
+	Interval time.Duration
+	Keep     int
+}
+
+// * Trabajos de admin en segundo plano (import, refresco de imágenes, respaldos, exports)
+type JobsConfig struct {
+	Workers int
+	// * Archivos generados por los trabajos (respaldos y exports para bajar)
+	Dir       string
+	Retention time.Duration
+	// * Aviso al terminar cada trabajo; vacío = solo el websocket de eventos
+	WebhookURL  string
+	WebhookKind string
+}
+
+// * Sin DATABASE_URL el servicio sigue funcionando solo en memoria
+type DatabaseConfig struct {
+	Driver         string
+	URL            string
+	MigrateOnStart bool
+	// * Cada cuánto el relay publica los eventos pendientes del outbox
+	OutboxInterval time.Duration
+}
+
+type ProvidersConfig struct {
+	// * Pesos por proveedor, ej. "cataas=70,thecatapi=30"
+	Weights      string
+	TheCatAPIKey string
+	// * Vacío: HTTPS_PROXY/HTTP_PROXY del entorno
+	ProxyURL  string
+	UserAgent string
+	// * Headers extra por proveedor, ej. "cataas:X-Contact=ops@ejemplo.com"
+	Headers string
+	// * Peticiones por segundo por proveedor, ej. "cataas=10"; vacío no limita
+	RateLimits string
+	// * Peticiones que pueden esperar turno antes de rechazar las nuevas
+	MaxQueue int
+	// * Cache DNS propia: el TTL del registro acotado a [DNSMinTTL, DNSMaxTTL]
+	DNSCache  bool
+	DNSMinTTL time.Duration
+	DNSMaxTTL time.Duration
+	// * any, prefer-ipv4, ipv4 o ipv6; FallbackDelay es la espera antes de probar la otra familia
+	IPFamily      string
+	FallbackDelay time.Duration
+}
+
+// * Variante para clientes con Save-Data o ?quality=low
+type ImageQualityConfig struct {
+	LowSize        string
+	LowMaxWidth    int
+	LowJPEGQuality int
+	// * Cuánto dura lo declarado en POST /me/capabilities sin volver a declararlo
+	CapabilitiesTTL time.Duration
+}
+
+// * Cada perfil conserva su imagen entre reinicios; solo cambia con un refresco explícito
+type StableImagesConfig struct {
+	Enabled bool
+	Dir     string
+}
+
+type TelegramConfig struct {
+	BotToken string
+}
+
+type WarmUpConfig struct {
+	URLs               int
+	Prefetch           bool
+	Timeout            time.Duration
+	ValidationDeadline time.Duration
+}
+
+type ReservoirConfig struct {
+	Path            string
+	MaxURLs         int
+	RefreshInterval time.Duration
+	// * Cada cuánto se revisan las imágenes ya asignadas a perfiles
+	PruneInterval time.Duration
+}
+
+type RetryConfig struct {
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+	Jitter      float64
+	MaxAttempts int
+}
+
+type LoadShedConfig struct {
+	MaxCatsInFlight int
+	MaxDeckInFlight int
+	RetryAfter      time.Duration
+}
+
+type CacheConfig struct {
+	MaxEntries  int
+	ProfilesTTL time.Duration
+	RootTTL     time.Duration
+}
+
+const defaultAPIPolicy = "default-src 'none'; frame-ancestors 'none'"
+
+// ! Alpine necesita 'unsafe-eval' y la página usa scripts inline
+const defaultHTMLPolicy = "default-src 'self'; " +
+	"script-src 'self' 'unsafe-inline' 'unsafe-eval' https://cdn.tailwindcss.com https://cdn.jsdelivr.net; " +
+	"style-src 'self' 'unsafe-inline'; " +
+	"img-src 'self' data: https://cataas.com https://cdn2.thecatapi.com; " +
+	"connect-src 'self'; " +
+	"frame-ancestors 'none'"
+
+func Load() *Config {
+	env := normalizeEnv(getEnv("APP_ENV", EnvProd))
+	profile := profileFor(env)
+	port := getEnv("PORT", "8080")
+	baseURL := getEnv("RENDER_EXTERNAL_URL", fmt.Sprintf("http://localhost:%s", port))
+
+	corsOrigins := profile.corsOrigins
+	if corsOrigins == nil {
+		corsOrigins = []string{baseURL}
+	}
+
+	return &Config{
+		Env:     env,
+		Port:    port,
+		BaseURL: baseURL,
+		File:    os.Getenv("CONFIG_FILE"),
+		Server: ServerConfig{
+			SocketPath:        getEnv("SERVER_SOCKET", ""),
+			SocketMode:        os.FileMode(getEnvInt("SERVER_SOCKET_MODE", 0o660)),
+			ReadTimeout:       getEnvDuration("SERVER_READ_TIMEOUT", 15*time.Second),
+			ReadHeaderTimeout: getEnvDuration("SERVER_READ_HEADER_TIMEOUT", 5*time.Second),
+			WriteTimeout:      getEnvDuration("SERVER_WRITE_TIMEOUT", 30*time.Second),
+			IdleTimeout:       getEnvDuration("SERVER_IDLE_TIMEOUT", 120*time.Second),
+			MaxHeaderBytes:    getEnvInt("SERVER_MAX_HEADER_BYTES", 1<<20),
+			H2C:               getEnvBool("SERVER_H2C", false),
+		},
+		Security: SecurityConfig{
+			Enabled:               getEnvBool("SECURITY_HEADERS_ENABLED", true),
+			ContentTypeOptions:    getEnv("SECURITY_CONTENT_TYPE_OPTIONS", "nosniff"),
+			FrameOptions:          getEnv("SECURITY_FRAME_OPTIONS", "DENY"),
+			ReferrerPolicy:        getEnv("SECURITY_REFERRER_POLICY", "strict-origin-when-cross-origin"),
+			ContentSecurityPolicy: getEnv("SECURITY_CSP", defaultAPIPolicy),
+			HTMLSecurityPolicy:    getEnv("SECURITY_HTML_CSP", defaultHTMLPolicy),
+		},
+		Logging: LoggingConfig{
+			Level:          getEnv("LOG_LEVEL", profile.logLevel),
+			BodySampleRate: getEnvFloat("LOG_BODY_SAMPLE_RATE", 0),
+			CaptureErrors:  getEnvBool("LOG_ERROR_BODIES", true),
+			MaxBodyBytes:   getEnvInt("LOG_MAX_BODY_BYTES", 2048),
+			RedactFields: getEnvList("LOG_REDACT_FIELDS", []string{
+				"password", "token", "secret", "key", "authorization", "email", "phone",
+			}),
+			SkipPaths:  getEnvList("LOG_SKIP_PATHS", nil),
+			File:       getEnv("LOG_FILE", ""),
+			Console:    getEnvBool("LOG_CONSOLE", true),
+			MaxSizeMB:  getEnvInt("LOG_MAX_SIZE_MB", 100),
+			MaxAge:     getEnvDuration("LOG_MAX_AGE", 7*24*time.Hour),
+			MaxBackups: getEnvInt("LOG_MAX_BACKUPS", 5),
+			Compress:   getEnvBool("LOG_COMPRESS", true),
+		},
+		Sentry: SentryConfig{
+			DSN:         getEnv("SENTRY_DSN", ""),
+			Environment: getEnv("SENTRY_ENVIRONMENT", profile.sentryEnvironment),
+			Release:     getEnv("SENTRY_RELEASE", ""),
+		},
+		Alerts: AlertsConfig{
+			WebhookURL:  getEnv("ALERT_WEBHOOK_URL", ""),
+			WebhookKind: getEnv("ALERT_WEBHOOK_KIND", ""),
+			Interval:    getEnvDuration("ALERT_INTERVAL", 30*time.Second),
+			Cooldown:    getEnvDuration("ALERT_COOLDOWN", 15*time.Minute),
+			Window:      getEnvDuration("ALERT_WINDOW", 5*time.Minute),
+			ErrorRate:   getEnvFloat("ALERT_ERROR_RATE", 0.05),
+			MinRequests: getEnvInt("ALERT_MIN_REQUESTS", 50),
+			P99:         getEnvDuration("ALERT_P99_LATENCY", 2*time.Second),
+		},
+		Health: HealthConfig{
+			DatasetMaxAge: getEnvDuration("HEALTH_DATASET_MAX_AGE", 24*time.Hour),
+			ProbeMaxAge:   getEnvDuration("HEALTH_PROBE_MAX_AGE", 30*time.Minute),
+		},
+		Chaos: ChaosConfig{
+			Enabled: getEnvBool("CHAOS_ENABLED", false),
+		},
+		Limits: LimitsConfig{
+			MaxJSONBytes:        int64(getEnvInt("REQUEST_MAX_JSON_BYTES", 1<<20)),
+			MaxMultipartBytes:   int64(getEnvInt("REQUEST_MAX_MULTIPART_BYTES", 10<<20)),
+			MaxBodyBytes:        int64(getEnvInt("REQUEST_MAX_BODY_BYTES", 1<<20)),
+			MaxHeaders:          getEnvInt("REQUEST_MAX_HEADERS", 100),
+			MaxHeaderValueBytes: getEnvInt("REQUEST_MAX_HEADER_VALUE_BYTES", 8<<10),
+		},
+		Abuse: AbuseConfig{
+			Scoring:        getEnvBool("ABUSE_SCORING", true),
+			MaxPerMinute:   getEnvInt("ABUSE_MAX_PER_MINUTE", 240),
+			BlockScore:     getEnvFloat("ABUSE_BLOCK_SCORE", 100),
+			AutoBlockAfter: getEnvInt("ABUSE_AUTO_BLOCK_AFTER", 50),
+			AutoBlockTTL:   getEnvDuration("ABUSE_AUTO_BLOCK_TTL", 15*time.Minute),
+			BlocklistFile:  getEnv("ABUSE_BLOCKLIST_FILE", ""),
+			ASNFile:        getEnv("ABUSE_ASN_FILE", ""),
+			PowDifficulty:  getEnvInt("ABUSE_POW_DIFFICULTY", 0),
+			PowSecret:      getEnv("ABUSE_POW_SECRET", ""),
+			PowTTL:         getEnvDuration("ABUSE_POW_TTL", 5*time.Minute),
+			ClientSecret:   getEnv("ABUSE_CLIENT_SECRET", ""),
+		},
+		SwipeFraud: SwipeFraudConfig{
+			Enabled:           getEnvBool("SWIPE_FRAUD", true),
+			MaxLikesPerMinute: getEnvInt("SWIPE_FRAUD_MAX_LIKES_PER_MINUTE", 60),
+			UniformWindow:     getEnvInt("SWIPE_FRAUD_UNIFORM_WINDOW", 15),
+			MaxUniformity:     getEnvFloat("SWIPE_FRAUD_MAX_UNIFORMITY", 0.05),
+			Cooldown:          getEnvDuration("SWIPE_FRAUD_COOLDOWN", 10*time.Minute),
+		},
+		Features: FeaturesConfig{
+			Flags: getEnv("FEATURE_FLAGS", ""),
+		},
+		Proxy: ProxyConfig{
+			// * Por defecto solo loopback y redes privadas (el balanceador de Render)
+			TrustedProxies: getEnvList("TRUSTED_PROXIES", []string{
+				"127.0.0.1", "::1", "10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16",
+			}),
+			RemoteIPHeaders: getEnvList("REMOTE_IP_HEADERS", []string{"X-Forwarded-For", "X-Real-IP"}),
+		},
+		TLS: TLSConfig{
+			CertFile:        getEnv("TLS_CERT_FILE", ""),
+			KeyFile:         getEnv("TLS_KEY_FILE", ""),
+			AutocertDomains: getEnvList("TLS_AUTOCERT_DOMAINS", nil),
+			AutocertEmail:   getEnv("TLS_AUTOCERT_EMAIL", ""),
+			AutocertCache:   getEnv("TLS_AUTOCERT_CACHE", "certs"),
+			RedirectHTTP:    getEnvBool("TLS_REDIRECT_HTTP", false),
+			HTTPPort:        getEnv("TLS_HTTP_PORT", "80"),
+		},
+		Share: ShareConfig{
+			DeepLinkBase: getEnv("SHARE_DEEP_LINK_BASE", "meownder://profiles/"),
+		},
+		Admin: AdminConfig{
+			APIKey:     getEnv("ADMIN_API_KEY", ""),
+			RequireKey: getEnvBool("ADMIN_REQUIRE_KEY", profile.adminRequireKey),
+		},
+		CORS: CORSConfig{
+			AllowedOrigins: getEnvList("CORS_ALLOWED_ORIGINS", corsOrigins),
+		},
+		JSON: JSONConfig{
+			Case: getEnv("JSON_CASE", "snake"),
+		},
+		Webhooks: WebhooksConfig{
+			DiscordURL:    getEnv("DISCORD_WEBHOOK_URL", ""),
+			SlackURL:      getEnv("SLACK_WEBHOOK_URL", ""),
+			CatOfDayHour:  getEnvInt("CAT_OF_DAY_HOUR", 9),
+			CatOfDayMin:   getEnvInt("CAT_OF_DAY_MINUTE", 0),
+			SigningSecret: getEnv("WEBHOOK_SIGNING_SECRET", ""),
+			SecretGrace:   getEnvDuration("WEBHOOK_SECRET_GRACE", 24*time.Hour),
+			File:          getEnv("WEBHOOKS_FILE", "data/webhooks.json"),
+		},
+		Matching: MatchingConfig{
+			MatchProbability: getEnvFloat("MATCH_PROBABILITY", 0.5),
+			NudgeAfter:       getEnvDuration("MATCH_NUDGE_AFTER", 3*24*time.Hour),
+			ArchiveAfter:     getEnvDuration("MATCH_ARCHIVE_AFTER", 7*24*time.Hour),
+			ExpiryInterval:   getEnvDuration("MATCH_EXPIRY_INTERVAL", time.Hour),
+		},
+		Tenants: TenantsConfig{
+			File:   getEnv("TENANTS_FILE", "data/tenants.json"),
+			Domain: getEnv("TENANT_DOMAIN", ""),
+		},
+		Icebreakers: IcebreakersConfig{
+			LLMURL:    getEnv("ICEBREAKER_LLM_URL", ""),
+			LLMAPIKey: getEnv("ICEBREAKER_LLM_API_KEY", ""),
+			LLMModel:  getEnv("ICEBREAKER_LLM_MODEL", "gpt-4o-mini"),
+			Timeout:   getEnvDuration("ICEBREAKER_LLM_TIMEOUT", 3*time.Second),
+		},
+		TextGen: TextGenConfig{
+			LLMURL:        getEnv("TEXTGEN_LLM_URL", ""),
+			LLMAPIKey:     getEnv("TEXTGEN_LLM_API_KEY", ""),
+			LLMModel:      getEnv("TEXTGEN_LLM_MODEL", "gpt-4o-mini"),
+			Timeout:       getEnvDuration("TEXTGEN_TIMEOUT", 10*time.Second),
+			RatePerMinute: getEnvInt("TEXTGEN_RATE_PER_MINUTE", 20),
+		},
+		Compatibility: CompatibilityConfig{
+			RatePerMinute: getEnvInt("COMPATIBILITY_RATE_PER_MINUTE", 30),
+			CacheTTL:      getEnvDuration("COMPATIBILITY_CACHE_TTL", 24*time.Hour),
+		},
+		Themes: ThemesConfig{
+			File: getEnv("THEMES_FILE", "themes.json"),
+		},
+		Collections: CollectionsConfig{
+			File: getEnv("COLLECTIONS_FILE", "data/collections.json"),
+		},
+		Links: LinksConfig{
+			RatePerMinute: getEnvInt("LINKS_RATE_PER_MINUTE", 20),
+		},
+		APITokens: APITokensConfig{
+			File: getEnv("API_TOKENS_FILE", "data/api-tokens.json"),
+		},
+		SignedRequests: SignedRequestsConfig{
+			Secret: getEnv("SIGNED_REQUESTS_SECRET", ""),
+			Skew:   getEnvDuration("SIGNED_REQUESTS_SKEW", 5*time.Minute),
+		},
+		Widget: WidgetConfig{
+			Keys: getEnvList("WIDGET_KEYS", nil),
+		},
+		OwnedCats: OwnedCatsConfig{
+			Verify:         getEnvBool("OWNED_CATS_VERIFY", true),
+			VerifyTTL:      getEnvDuration("OWNED_CATS_VERIFY_TTL", 48*time.Hour),
+			Review:         getEnvBool("OWNED_CATS_REVIEW", false),
+			ExpiryInterval: getEnvDuration("OWNED_CATS_EXPIRY_INTERVAL", 15*time.Minute),
+		},
+		Digest: DigestConfig{
+			SMTPHost:     getEnv("SMTP_HOST", ""),
+			SMTPPort:     getEnvInt("SMTP_PORT", 587),
+			SMTPUsername: getEnv("SMTP_USERNAME", ""),
+			SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+			From:         getEnv("DIGEST_FROM", "Meownder <resumen@meownder.app>"),
+			Weekday:      getEnv("DIGEST_WEEKDAY", "monday"),
+			Hour:         getEnvInt("DIGEST_HOUR", 9),
+			Minute:       getEnvInt("DIGEST_MINUTE", 0),
+		},
+		Backup: BackupConfig{
+			Dir:      getEnv("BACKUP_DIR", "data/backups"),
+			Interval: getEnvDuration("BACKUP_INTERVAL", 24*time.Hour),
+			Keep:     getEnvInt("BACKUP_KEEP", 7),
+		},
+		Jobs: JobsConfig{
+			Workers:     getEnvInt("JOBS_WORKERS", 2),
+			Dir:         getEnv("JOBS_DIR", filepath.Join(os.TempDir(), "meownder-jobs")),
+			Retention:   getEnvDuration("JOBS_RETENTION", 24*time.Hour),
+			WebhookURL:  getEnv("JOBS_WEBHOOK_URL", ""),
+			WebhookKind: getEnv("JOBS_WEBHOOK_KIND", ""),
+		},
+		Database: DatabaseConfig{
+			Driver:         getEnv("DATABASE_DRIVER", "postgres"),
+			URL:            getEnv("DATABASE_URL", ""),
+			MigrateOnStart: getEnvBool("DATABASE_MIGRATE_ON_START", false),
+			OutboxInterval: getEnvDuration("OUTBOX_RELAY_INTERVAL", 5*time.Second),
+		},
+		Providers: ProvidersConfig{
+			Weights:       getEnv("IMAGE_PROVIDERS", profile.providers),
+			TheCatAPIKey:  getEnv("THECATAPI_KEY", ""),
+			ProxyURL:      getEnv("PROVIDER_PROXY_URL", ""),
+			UserAgent:     getEnv("PROVIDER_USER_AGENT", "meownder-backend/1.0 (+https://meownder-backend.onrender.com)"),
+			Headers:       getEnv("PROVIDER_HEADERS", ""),
+			RateLimits:    getEnv("PROVIDER_RATE_LIMITS", "cataas=10"),
+			MaxQueue:      getEnvInt("PROVIDER_MAX_QUEUE", 100),
+			DNSCache:      getEnvBool("PROVIDER_DNS_CACHE", true),
+			DNSMinTTL:     getEnvDuration("PROVIDER_DNS_MIN_TTL", 5*time.Second),
+			DNSMaxTTL:     getEnvDuration("PROVIDER_DNS_MAX_TTL", 5*time.Minute),
+			IPFamily:      getEnv("PROVIDER_IP_FAMILY", "any"),
+			FallbackDelay: getEnvDuration("PROVIDER_FALLBACK_DELAY", 300*time.Millisecond),
+		},
+		RateLimit: RateLimitConfig{
+			Backend: getEnv("RATE_LIMIT_BACKEND", "memory"),
+		},
+		Redis: RedisConfig{
+			URL: getEnv("REDIS_URL", ""),
+		},
+		Scheduler: SchedulerConfig{
+			Lock: getEnv("SCHEDULER_LOCK", ""),
+			// * Cuánto dura un reclamo; basta con que supere el desfase entre relojes
+			LockTTL: getEnvDuration("SCHEDULER_LOCK_TTL", 24*time.Hour),
+		},
+		Ranking: RankingConfig{
+			Live:             getEnv("DECK_RANKING", "archetype"),
+			Shadow:           getEnv("DECK_RANKING_SHADOW", ""),
+			ShadowSampleRate: getEnvFloat("DECK_RANKING_SHADOW_SAMPLE", 0.1),
+			ShadowTopK:       getEnvInt("DECK_RANKING_SHADOW_TOP_K", 10),
+		},
+		Sponsored: SponsoredConfig{
+			Every:      getEnvInt("SPONSORED_EVERY", 5),
+			MaxPerDeck: getEnvInt("SPONSORED_MAX_PER_DECK", 2),
+		},
+		DeckPrefetch: DeckPrefetchConfig{
+			TTL:        getEnvDuration("DECK_PREFETCH_TTL", 2*time.Minute),
+			Validation: getEnvDuration("DECK_PREFETCH_VALIDATION", 3*time.Second),
+		},
+		DeckChanges: DeckChangesConfig{
+			Hold:   getEnvDuration("DECK_CHANGES_HOLD", 25*time.Second),
+			Retain: getEnvInt("DECK_CHANGES_RETAIN", 1000),
+		},
+		Search: SearchConfig{
+			Backend:      getEnv("SEARCH_BACKEND", "memory"),
+			ElasticURL:   getEnv("SEARCH_ELASTIC_URL", ""),
+			ElasticIndex: getEnv("SEARCH_ELASTIC_INDEX", "meownder-profiles"),
+			Timeout:      getEnvDuration("SEARCH_TIMEOUT", 2*time.Second),
+			SyncInterval: getEnvDuration("SEARCH_SYNC_INTERVAL", 5*time.Second),
+			Boosts:       getEnvList("SEARCH_BOOSTS", nil),
+			Fuzziness:    getEnv("SEARCH_FUZZINESS", "auto"),
+			MaxHits:      getEnvInt("SEARCH_MAX_HITS", 1000),
+		},
+		ImageQuality: ImageQualityConfig{
+			LowSize:         getEnv("IMAGE_LOW_SIZE", "small"),
+			LowMaxWidth:     getEnvInt("IMAGE_LOW_MAX_WIDTH", 480),
+			LowJPEGQuality:  getEnvInt("IMAGE_LOW_JPEG_QUALITY", 60),
+			CapabilitiesTTL: getEnvDuration("CLIENT_CAPABILITIES_TTL", 12*time.Hour),
+		},
+		StableImages: StableImagesConfig{
+			Enabled: getEnvBool("STABLE_IMAGES", false),
+			Dir:     getEnv("STABLE_IMAGES_DIR", "data/profile-images"),
+		},
+		Telegram: TelegramConfig{
+			BotToken: getEnv("TELEGRAM_BOT_TOKEN", ""),
+		},
+		WarmUp: WarmUpConfig{
+			URLs:     getEnvInt("WARMUP_URLS", 10),
+			Prefetch: getEnvBool("WARMUP_PREFETCH", true),
+			Timeout:  getEnvDuration("WARMUP_TIMEOUT", 20*time.Second),
+			// * Presupuesto por petición de /api/cats?validated=true
+			ValidationDeadline: getEnvDuration("VALIDATION_DEADLINE", 3*time.Second),
+		},
+		Reservoir: ReservoirConfig{
+			Path:            getEnv("RESERVOIR_PATH", "data/reservoir.json"),
+			MaxURLs:         getEnvInt("RESERVOIR_MAX_URLS", 200),
+			RefreshInterval: getEnvDuration("RESERVOIR_REFRESH_INTERVAL", 10*time.Minute),
+			PruneInterval:   getEnvDuration("PRUNE_IMAGES_INTERVAL", 30*time.Minute),
+		},
+		Retry: RetryConfig{
+			BaseDelay:   getEnvDuration("RETRY_BASE_DELAY", 100*time.Millisecond),
+			MaxDelay:    getEnvDuration("RETRY_MAX_DELAY", 2*time.Second),
+			Jitter:      getEnvFloat("RETRY_JITTER", 0.5),
+			MaxAttempts: getEnvInt("RETRY_MAX_ATTEMPTS", 3),
+		},
+		LoadShed: LoadShedConfig{
+			MaxCatsInFlight: getEnvInt("LOAD_SHED_MAX_CATS", 50),
+			MaxDeckInFlight: getEnvInt("LOAD_SHED_MAX_DECK", 100),
+			RetryAfter:      getEnvDuration("LOAD_SHED_RETRY_AFTER", 2*time.Second),
+		},
+		Cache: CacheConfig{
+			MaxEntries:  getEnvInt("CACHE_MAX_ENTRIES", 500),
+			ProfilesTTL: getEnvDuration("CACHE_PROFILES_TTL", 30*time.Second),
+			RootTTL:     getEnvDuration("CACHE_ROOT_TTL", 5*time.Minute),
+		},
+	}
+}
+
+func getEnv(key, fallback string) string {
+	if value := strings.TrimSpace(lookupEnv(key)); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// * Acepta decimal, octal (0660) o hexadecimal
+func getEnvInt(key string, fallback int) int {
+	if value := lookupEnv(key); value != "" {
+		if parsed, err := strconv.ParseInt(value, 0, 64); err == nil {
+			return int(parsed)
+		}
+	}
+	return fallback
+}
+
+func getEnvFloat(key string, fallback float64) float64 {
+	if value := lookupEnv(key); value != "" {
+		if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+			return parsed
+		}
+	}
+	return fallback
+}
+
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	if value := lookupEnv(key); value != "" {
+		if parsed, err := time.ParseDuration(value); err == nil {
+			return parsed
+		}
+	}
+	return fallback
+}
+
+func getEnvBool(key string, fallback bool) bool {
+	if value := lookupEnv(key); value != "" {
+		if parsed, err := strconv.ParseBool(value); err == nil {
+			return parsed
+		}
+	}
+	return fallback
+}
+
+// * Lista separada por comas; "none" desactiva la lista por completo
+func getEnvList(key string, fallback []string) []string {
+	value := strings.TrimSpace(lookupEnv(key))
+	if value == "" {
+		return fallback
+	}
+	if strings.EqualFold(value, "none") {
+		return nil
+	}
+
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
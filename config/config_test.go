@@ -0,0 +1,26 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDeckChangesEffectiveHold(t *testing.T) {
+	changes := DeckChangesConfig{Hold: 25 * time.Second}
+	cases := []struct {
+		name         string
+		writeTimeout time.Duration
+		want         time.Duration
+	}{
+		{"sin límite de escritura", 0, 25 * time.Second},
+		{"límite holgado", time.Minute, 25 * time.Second},
+		{"recorte por el margen", 20 * time.Second, 15 * time.Second},
+		{"margen igual al límite", 5 * time.Second, 2500 * time.Millisecond},
+		{"límite más corto que el margen", 2 * time.Second, time.Second},
+	}
+	for _, tc := range cases {
+		if got := changes.EffectiveHold(tc.writeTimeout); got != tc.want {
+			t.Errorf("%s: %s, se esperaba %s", tc.name, got, tc.want)
+		}
+	}
+}
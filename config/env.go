@@ -0,0 +1,97 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+const (
+	EnvDev     = "dev"
+	EnvStaging = "staging"
+	EnvProd    = "prod"
+)
+
+// * Valores por defecto de cada APP_ENV; una variable explícita siempre gana
+type envProfile struct {
+	logLevel  string
+	providers string
+	// * nil = solo el propio BaseURL
+	corsOrigins []string
+	// * false = sin ADMIN_API_KEY las rutas de admin quedan abiertas
+	adminRequireKey   bool
+	sentryEnvironment string
+}
+
+var envProfiles = map[string]envProfile{
+	EnvDev: {
+		logLevel:          "debug",
+		providers:         "offline=100",
+		corsOrigins:       []string{"*"},
+		adminRequireKey:   false,
+		sentryEnvironment: "development",
+	},
+	EnvStaging: {
+		logLevel:          "info",
+		providers:         "cataas=100",
+		adminRequireKey:   true,
+		sentryEnvironment: "staging",
+	},
+	EnvProd: {
+		logLevel:          "info",
+		providers:         "cataas=100",
+		adminRequireKey:   true,
+		sentryEnvironment: "production",
+	},
+}
+
+// * "development" y "production" también valen; un valor desconocido lo rechaza Validate
+func normalizeEnv(name string) string {
+	switch name = strings.ToLower(strings.TrimSpace(name)); name {
+	case "development", "local":
+		return EnvDev
+	case "production":
+		return EnvProd
+	}
+	return name
+}
+
+func profileFor(env string) envProfile {
+	if profile, ok := envProfiles[env]; ok {
+		return profile
+	}
+	return envProfiles[EnvProd]
+}
+
+// * Combinaciones peligrosas según el entorno. En prod son fatales al arrancar; en
+// * staging solo se avisan; en dev no se revisan
+func (c *Config) Validate() error {
+	if _, ok := envProfiles[c.Env]; !ok {
+		return fmt.Errorf("APP_ENV desconocido: %q (dev, staging o prod)", c.Env)
+	}
+	if c.Env == EnvDev {
+		return nil
+	}
+
+	var problems []error
+	if !c.Admin.RequireKey {
+		problems = append(problems, errors.New("ADMIN_REQUIRE_KEY=false deja las rutas de admin sin autenticación"))
+	}
+	if c.Admin.APIKey != "" && len(c.Admin.APIKey) < 16 {
+		problems = append(problems, errors.New("ADMIN_API_KEY debe tener al menos 16 caracteres"))
+	}
+	if slices.Contains(c.CORS.AllowedOrigins, "*") {
+		problems = append(problems, errors.New("CORS_ALLOWED_ORIGINS=* permite cualquier origen"))
+	}
+	if c.Chaos.Enabled {
+		problems = append(problems, errors.New("CHAOS_ENABLED inyecta fallas a propósito"))
+	}
+	if strings.Contains(c.Providers.Weights, "offline") {
+		problems = append(problems, errors.New("el proveedor offline es solo para desarrollo"))
+	}
+	if strings.EqualFold(c.Logging.Level, "debug") {
+		problems = append(problems, errors.New("LOG_LEVEL=debug guarda el cuerpo de todas las peticiones"))
+	}
+	return errors.Join(problems...)
+}
@@ -0,0 +1,64 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"sync/atomic"
+)
+
+// * Valores de CONFIG_FILE; pisan a las variables de entorno del mismo nombre
+var fileValues atomic.Pointer[map[string]string]
+
+func lookupEnv(key string) string {
+	if values := fileValues.Load(); values != nil {
+		if value, ok := (*values)[key]; ok {
+			return value
+		}
+	}
+	return os.Getenv(key)
+}
+
+// * Lee el archivo y arma la configuración con sus valores encima del entorno. Devuelve
+// * también los valores leídos, para saber qué claves cambiaron entre una lectura y otra
+func LoadFile(path string) (*Config, map[string]string, error) {
+	values, err := readFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	fileValues.Store(&values)
+	return Load(), values, nil
+}
+
+// * Formato .env: KEY=VALUE por línea, # para comentarios, "export " y comillas opcionales
+func readFile(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+	for number := 1; scanner.Scan(); number++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, found := strings.Cut(strings.TrimPrefix(line, "export "), "=")
+		key = strings.TrimSpace(key)
+		if !found || key == "" || strings.ContainsAny(key, " \t") {
+			return nil, fmt.Errorf("%s:%d: se esperaba KEY=VALUE", path, number)
+		}
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		values[key] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
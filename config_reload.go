@@ -0,0 +1,191 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"maps"
+	"os"
+	"os/signal"
+	"slices"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/ChrisTheAbysswalker/meownder-backend/config"
+	mw "github.com/ChrisTheAbysswalker/meownder-backend/middleware"
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+	"github.com/ChrisTheAbysswalker/meownder-backend/ratelimit"
+	s "github.com/ChrisTheAbysswalker/meownder-backend/services"
+)
+
+// * Un valor que se aplica sin reiniciar
+type reloadable struct {
+	key   string
+	value func(cfg *config.Config) string
+	apply func(cfg *config.Config) error
+}
+
+// * Relee la configuración (CONFIG_FILE encima del entorno) con SIGHUP o desde
+// * /api/admin/config/reload. Solo lo recargable se aplica; cada cambio queda en el log
+// * de auditoría y las demás claves del archivo que cambien avisan que piden reinicio
+type configReloader struct {
+	path  string
+	items []reloadable
+
+	mutex  sync.Mutex
+	values map[string]string
+	file   map[string]string
+	last   *m.ConfigReload
+}
+
+func newConfigReloader(cfg *config.Config, file map[string]string, logLevel *mw.LogLevel, limiters *ratelimit.Factory, features *s.FeatureFlags, providers *s.ProviderMix) *configReloader {
+	r := &configReloader{
+		path: cfg.File,
+		file: file,
+		// ! Los pesos van primero: son lo único que puede fallar al aplicar y SetWeights
+		// ! valida antes de tocar nada, así un error no deja la recarga a medias
+		items: []reloadable{
+			{
+				key:   "IMAGE_PROVIDERS",
+				value: func(cfg *config.Config) string { return cfg.Providers.Weights },
+				apply: func(cfg *config.Config) error {
+					weights, err := s.ParseProviderWeights(cfg.Providers.Weights)
+					if err != nil {
+						return err
+					}
+					return providers.SetWeights(weights)
+				},
+			},
+			{
+				key:   "LOG_LEVEL",
+				value: func(cfg *config.Config) string { return cfg.Logging.Level },
+				apply: func(cfg *config.Config) error { return logLevel.Set(cfg.Logging.Level) },
+			},
+			{
+				key:   "FEATURE_FLAGS",
+				value: func(cfg *config.Config) string { return cfg.Features.Flags },
+				apply: func(cfg *config.Config) error { return features.Set(cfg.Features.Flags) },
+			},
+			{
+				key:   "LINKS_RATE_PER_MINUTE",
+				value: func(cfg *config.Config) string { return strconv.Itoa(cfg.Links.RatePerMinute) },
+				apply: func(cfg *config.Config) error {
+					limiters.SetLimit("links", cfg.Links.RatePerMinute)
+					return nil
+				},
+			},
+			{
+				key:   "COMPATIBILITY_RATE_PER_MINUTE",
+				value: func(cfg *config.Config) string { return strconv.Itoa(cfg.Compatibility.RatePerMinute) },
+				apply: func(cfg *config.Config) error {
+					limiters.SetLimit("compatibility", cfg.Compatibility.RatePerMinute)
+					return nil
+				},
+			},
+		},
+	}
+	r.values = r.snapshot(cfg)
+	return r
+}
+
+func (r *configReloader) snapshot(cfg *config.Config) map[string]string {
+	values := make(map[string]string, len(r.items))
+	for _, item := range r.items {
+		values[item.key] = item.value(cfg)
+	}
+	return values
+}
+
+// * Se valida todo antes de aplicar: con un valor inválido la recarga se rechaza entera
+func (r *configReloader) validate(cfg *config.Config) error {
+	if _, err := mw.ParseLogLevel(cfg.Logging.Level); err != nil {
+		return fmt.Errorf("LOG_LEVEL: %w", err)
+	}
+	if _, err := s.ParseFeatureFlags(cfg.Features.Flags); err != nil {
+		return fmt.Errorf("FEATURE_FLAGS: %w", err)
+	}
+	if _, err := s.ParseProviderWeights(cfg.Providers.Weights); err != nil {
+		return fmt.Errorf("IMAGE_PROVIDERS: %w", err)
+	}
+	return nil
+}
+
+func (r *configReloader) Reload(source string) (m.ConfigReload, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	cfg, file := config.Load(), r.file
+	if r.path != "" {
+		var err error
+		if cfg, file, err = config.LoadFile(r.path); err != nil {
+			log.Printf("⚠️ Recarga de configuración (%s) rechazada: %v", source, err)
+			return m.ConfigReload{}, err
+		}
+	}
+	if err := r.validate(cfg); err != nil {
+		log.Printf("⚠️ Recarga de configuración (%s) rechazada: %v", source, err)
+		return m.ConfigReload{}, err
+	}
+
+	reload := m.ConfigReload{Source: source, ReloadedAt: time.Now(), Changes: []m.ConfigChange{}}
+	next := r.snapshot(cfg)
+	for _, item := range r.items {
+		from, to := r.values[item.key], next[item.key]
+		if from == to {
+			continue
+		}
+		if err := item.apply(cfg); err != nil {
+			err = fmt.Errorf("%s: %w", item.key, err)
+			log.Printf("⚠️ Recarga de configuración (%s) rechazada: %v", source, err)
+			return m.ConfigReload{}, err
+		}
+		reload.Changes = append(reload.Changes, m.ConfigChange{Key: item.key, From: from, To: to})
+		log.Printf("📝 [auditoría] config %s: %q -> %q (%s)", item.key, from, to, source)
+	}
+
+	// * Solo los nombres: el resto del archivo puede tener secretos
+	for _, key := range slices.Sorted(maps.Keys(union(r.file, file))) {
+		if _, ok := next[key]; !ok && r.file[key] != file[key] {
+			reload.RestartRequired = append(reload.RestartRequired, key)
+		}
+	}
+	if len(reload.RestartRequired) > 0 {
+		log.Printf("⚠️ Cambios en %s que requieren reiniciar: %v", r.path, reload.RestartRequired)
+	}
+
+	r.values, r.file, r.last = next, file, &reload
+	log.Printf("🔄 Configuración recargada (%s): %d cambios", source, len(reload.Changes))
+	return reload, nil
+}
+
+func (r *configReloader) Current() m.RuntimeConfig {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return m.RuntimeConfig{
+		File:       r.path,
+		Values:     maps.Clone(r.values),
+		LastReload: r.last,
+	}
+}
+
+// * kill -HUP <pid> recarga la configuración
+func (r *configReloader) watchSignals() {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+	go func() {
+		for range signals {
+			r.Reload("SIGHUP")
+		}
+	}()
+}
+
+func union(a, b map[string]string) map[string]string {
+	merged := maps.Clone(a)
+	if merged == nil {
+		merged = make(map[string]string, len(b))
+	}
+	maps.Copy(merged, b)
+	return merged
+}
@@ -0,0 +1,99 @@
+package content
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+	"text/template"
+)
+
+// * Textos embebidos por locale: <sección>/<locale>.json. El primer locale es el de respaldo
+//
+//go:embed horoscope/*.json quiz/*.json compatibility/*.json
+var files embed.FS
+
+const DefaultLocale = "es"
+
+var (
+	cache      = make(map[string]any)
+	templates  = make(map[string]*template.Template)
+	cacheMutex sync.Mutex
+)
+
+// * Carga y decodifica una sección en dest; se cachea por sección y locale
+func Load[T any](section, locale string) (*T, error) {
+	key := section + "/" + locale
+	cacheMutex.Lock()
+	defer cacheMutex.Unlock()
+
+	if cached, ok := cache[key]; ok {
+		return cached.(*T), nil
+	}
+
+	data, err := files.ReadFile(path.Join(section, locale+".json"))
+	if err != nil {
+		return nil, fmt.Errorf("sin contenido %s para el locale %s", section, locale)
+	}
+	var value T
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, fmt.Errorf("contenido %s inválido: %w", key, err)
+	}
+	cache[key] = &value
+	return &value, nil
+}
+
+// * Locales disponibles para una sección, según los archivos embebidos
+func Locales(section string) []string {
+	entries, err := files.ReadDir(section)
+	if err != nil {
+		return nil
+	}
+	locales := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		locales = append(locales, strings.TrimSuffix(entry.Name(), ".json"))
+	}
+	return locales
+}
+
+// * Primer locale soportado de un Accept-Language ("en-US,en;q=0.9,es;q=0.8"); sin
+// * coincidencias devuelve DefaultLocale
+func Negotiate(section, acceptLanguage string) string {
+	supported := make(map[string]bool)
+	for _, locale := range Locales(section) {
+		supported[locale] = true
+	}
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, _, _ := strings.Cut(strings.TrimSpace(part), ";")
+		base, _, _ := strings.Cut(strings.ToLower(tag), "-")
+		if supported[base] {
+			return base
+		}
+	}
+	return DefaultLocale
+}
+
+// * text/template con cache por texto fuente
+func Render(source string, data any) (string, error) {
+	cacheMutex.Lock()
+	tmpl, ok := templates[source]
+	if !ok {
+		var err error
+		tmpl, err = template.New("").Option("missingkey=error").Parse(source)
+		if err != nil {
+			cacheMutex.Unlock()
+			return "", err
+		}
+		templates[source] = tmpl
+	}
+	cacheMutex.Unlock()
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	mw "github.com/ChrisTheAbysswalker/meownder-backend/middleware"
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+	s "github.com/ChrisTheAbysswalker/meownder-backend/services"
+)
+
+type AbuseHandler struct {
+	guard     *s.AbuseGuard
+	blocklist *s.Blocklist
+	pow       *s.ProofOfWork
+}
+
+func NewAbuseHandler(guard *s.AbuseGuard, blocklist *s.Blocklist, pow *s.ProofOfWork) *AbuseHandler {
+	return &AbuseHandler{
+		guard:     guard,
+		blocklist: blocklist,
+		pow:       pow,
+	}
+}
+
+// * Desafío para las rutas de escritura; con la prueba de trabajo apagada responde 404
+func (h *AbuseHandler) GetChallenge(c *gin.Context) {
+	if !h.pow.Enabled() {
+		c.JSON(http.StatusNotFound, m.ErrorResponse{
+			Error:   "proof_of_work_disabled",
+			Message: "La prueba de trabajo no está activada",
+		})
+		return
+	}
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, h.pow.Challenge())
+}
+
+func (h *AbuseHandler) ListBlocks(c *gin.Context) {
+	if !h.requireDefaultTenant(c) {
+		return
+	}
+
+	entries := h.blocklist.List()
+	c.JSON(http.StatusOK, gin.H{
+		"entries": entries,
+		"count":   len(entries),
+	})
+}
+
+func (h *AbuseHandler) AddBlock(c *gin.Context) {
+	if !h.requireDefaultTenant(c) {
+		return
+	}
+
+	var req m.BlockRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	entry, err := h.blocklist.Add(req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, m.ErrorResponse{
+			Error:   "invalid_block",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	log.Printf("📝 [auditoría] bloqueo %s agregado: %s %s (%s)", entry.ID, entry.Kind, entry.Value, mw.ClientIP(c))
+	c.JSON(http.StatusCreated, entry)
+}
+
+func (h *AbuseHandler) RemoveBlock(c *gin.Context) {
+	if !h.requireDefaultTenant(c) {
+		return
+	}
+
+	if err := h.blocklist.Remove(c.Param("id")); err != nil {
+		if errors.Is(err, s.ErrBlockNotFound) {
+			c.JSON(http.StatusNotFound, m.ErrorResponse{
+				Error:   "block_not_found",
+				Message: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, m.ErrorResponse{
+			Error:   "internal_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	log.Printf("📝 [auditoría] bloqueo %s quitado (%s)", c.Param("id"), mw.ClientIP(c))
+	c.Status(http.StatusNoContent)
+}
+
+// * ?limit= IPs con mayor puntaje (20 por defecto, máximo 200)
+func (h *AbuseHandler) GetStats(c *gin.Context) {
+	if !h.requireDefaultTenant(c) {
+		return
+	}
+
+	limit := 20
+	if raw := c.Query("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 || parsed > 200 {
+			c.JSON(http.StatusBadRequest, m.ErrorResponse{
+				Error:   "invalid_limit",
+				Message: "limit debe estar entre 1 y 200",
+			})
+			return
+		}
+		limit = parsed
+	}
+	c.JSON(http.StatusOK, h.guard.Stats(limit))
+}
+
+// ! Los bloqueos son de todo el servicio: el admin de otro refugio no los toca
+func (h *AbuseHandler) requireDefaultTenant(c *gin.Context) bool {
+	if tenant := TenantFrom(c); tenant != nil && tenant.ID != s.DefaultTenantID {
+		c.JSON(http.StatusForbidden, m.ErrorResponse{
+			Error:   "forbidden",
+			Message: "Solo el admin del refugio principal puede gestionar bloqueos",
+		})
+		return false
+	}
+	return true
+}
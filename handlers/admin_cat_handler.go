@@ -0,0 +1,361 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	mw "github.com/ChrisTheAbysswalker/meownder-backend/middleware"
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+	s "github.com/ChrisTheAbysswalker/meownder-backend/services"
+)
+
+type AdminCatHandler struct {
+	service *s.CatService
+	writer  *s.TextWriter
+	jobs    *s.JobManager
+}
+
+func NewAdminCatHandler(service *s.CatService, writer *s.TextWriter, jobs *s.JobManager) *AdminCatHandler {
+	return &AdminCatHandler{
+		service: service,
+		writer:  writer,
+		jobs:    jobs,
+	}
+}
+
+func (h *AdminCatHandler) ListCats(c *gin.Context) {
+	var query m.AdminProfilesQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	page := query.Page
+	if page == 0 {
+		page = 1
+	}
+
+	// * ?q= busca solo entre los perfiles públicos: borradores y pendientes no se indexan
+	profiles, total, ok := filterProfiles(c, tenantCats(c, h.service), query.Q, m.ProfileFilter{
+		Breed:          query.Breed,
+		Hobby:          query.Hobby,
+		Tags:           s.NormalizeTags(query.Tags),
+		MinAge:         query.MinAge,
+		MaxAge:         query.MaxAge,
+		Offset:         (page - 1) * query.Limit,
+		Limit:          query.Limit,
+		IncludeDeleted: query.IncludeDeleted,
+		Status:         query.Status,
+	})
+	if !ok {
+		return
+	}
+	for i := range profiles {
+		quality := s.ProfileQualityOf(profiles[i])
+		profiles[i].Quality = &quality
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"cats":  profiles,
+		"count": len(profiles),
+		"total": total,
+		"page":  page,
+		"limit": query.Limit,
+	})
+}
+
+// * Reemplaza las etiquetas del perfil; una lista vacía las quita todas
+func (h *AdminCatHandler) SetTags(c *gin.Context) {
+	var param m.ProfileIDParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	var req m.TagsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	profile, err := tenantCats(c, h.service).SetTags(param.ID, req.Tags)
+	if err != nil {
+		respondProfileError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
+
+// * {"until": "..."} opcional; sin until el patrocinio dura hasta quitarlo
+func (h *AdminCatHandler) Sponsor(c *gin.Context) {
+	var param m.ProfileIDParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	var req m.SponsorRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondValidationError(c, err)
+			return
+		}
+	}
+
+	profile, err := tenantCats(c, h.service).Sponsor(param.ID, req.Until)
+	if err != nil {
+		respondProfileError(c, err)
+		return
+	}
+
+	log.Printf("📝 [auditoría] perfil %d patrocinado (%s)", param.ID, mw.ClientIP(c))
+	c.JSON(http.StatusOK, profile)
+}
+
+func (h *AdminCatHandler) Unsponsor(c *gin.Context) {
+	var param m.ProfileIDParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	profile, err := tenantCats(c, h.service).Unsponsor(param.ID)
+	if err != nil {
+		respondProfileError(c, err)
+		return
+	}
+
+	log.Printf("📝 [auditoría] patrocinio del perfil %d quitado (%s)", param.ID, mw.ClientIP(c))
+	c.JSON(http.StatusOK, profile)
+}
+
+func (h *AdminCatHandler) RemoveTag(c *gin.Context) {
+	var param m.TagParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	removed, err := tenantCats(c, h.service).RemoveTag(param.Tag)
+	if err != nil {
+		if errors.Is(err, s.ErrTagNotFound) {
+			c.JSON(http.StatusNotFound, m.ErrorResponse{
+				Error:   "tag_not_found",
+				Message: err.Error(),
+			})
+			return
+		}
+		respondProfileError(c, err)
+		return
+	}
+
+	log.Printf("📝 [auditoría] etiqueta %q quitada de %d perfiles (%s)", param.Tag, removed, mw.ClientIP(c))
+	c.JSON(http.StatusOK, gin.H{
+		"tag":     param.Tag,
+		"removed": removed,
+	})
+}
+
+// * Perfiles del menos al más completo, con lo que le falta a cada uno
+func (h *AdminCatHandler) GetQualityReport(c *gin.Context) {
+	var query m.ProfileQualityQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, tenantCats(c, h.service).QualityReport(query))
+}
+
+func (h *AdminCatHandler) DeleteCat(c *gin.Context) {
+	var param m.ProfileIDParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	profile, err := tenantCats(c, h.service).SoftDeleteCatProfile(param.ID)
+	if err != nil {
+		respondProfileError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
+
+func (h *AdminCatHandler) RestoreCat(c *gin.Context) {
+	var param m.ProfileIDParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	profile, err := tenantCats(c, h.service).RestoreCatProfile(param.ID)
+	if err != nil {
+		respondProfileError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
+
+// * Borrado definitivo; queda registrado en el log de auditoría
+func (h *AdminCatHandler) PurgeCat(c *gin.Context) {
+	var param m.ProfileIDParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	profile, err := tenantCats(c, h.service).AdminGetCatProfile(param.ID)
+	if err != nil {
+		respondProfileError(c, err)
+		return
+	}
+
+	if err := tenantCats(c, h.service).PurgeCatProfile(param.ID); err != nil {
+		respondProfileError(c, err)
+		return
+	}
+
+	log.Printf("📝 [auditoría] perfil %d (%s) purgado desde %s", profile.ID, profile.Name, mw.ClientIP(c))
+	c.Status(http.StatusNoContent)
+}
+
+func (h *AdminCatHandler) SetStatus(c *gin.Context) {
+	var param m.ProfileIDParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	var req m.StatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	profile, err := tenantCats(c, h.service).TransitionCatProfile(param.ID, req.Status)
+	if err != nil {
+		respondProfileError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
+
+func (h *AdminCatHandler) GenerateText(c *gin.Context) {
+	var param m.ProfileIDParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	var req m.GenerateTextRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	cats := tenantCats(c, h.service)
+	cat, err := cats.AdminGetCatProfile(param.ID)
+	if err != nil {
+		respondProfileError(c, err)
+		return
+	}
+
+	generated, err := h.writer.Fill(c.Request.Context(), cat, req.Fields, req.Overwrite)
+	if err != nil {
+		respondProfileError(c, err)
+		return
+	}
+	if len(generated) > 0 {
+		if cat, err = cats.SetProfileText(cat.ID, cat.Bio, cat.Personality); err != nil {
+			respondProfileError(c, err)
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, m.GenerateTextResponse{
+		Cat:       *cat,
+		Generated: generated,
+	})
+}
+
+// * Cada imagen nueva se verifica con un HEAD antes de asignarse
+const refreshValidationTimeout = 5 * time.Second
+
+// * Siempre en segundo plano: responde 202 con el trabajo (ver JobHandler)
+func (h *AdminCatHandler) RefreshImages(c *gin.Context) {
+	var req m.RefreshImagesRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondValidationError(c, err)
+			return
+		}
+	}
+
+	cats := tenantCats(c, h.service)
+	targets, err := cats.RefreshTargets(req)
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, m.ErrorResponse{
+			Error:   "no_profiles_matched",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	startJob(c, h.jobs, m.JobRefreshImages, func(ctx context.Context, job *s.JobHandle) (any, error) {
+		return cats.RefreshImagesSelective(ctx, targets, req, refreshValidationTimeout, job)
+	})
+}
+
+func respondProfileError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, s.ErrInvalidTransition):
+		c.JSON(http.StatusConflict, m.ErrorResponse{
+			Error:   "invalid_transition",
+			Message: err.Error(),
+		})
+	case errors.Is(err, s.ErrProfileNotFound):
+		c.JSON(http.StatusNotFound, m.ErrorResponse{
+			Error:   "profile_not_found",
+			Message: err.Error(),
+		})
+	case errors.Is(err, s.ErrProfileAlreadyDeleted):
+		c.JSON(http.StatusConflict, m.ErrorResponse{
+			Error:   "already_deleted",
+			Message: err.Error(),
+		})
+	case errors.Is(err, s.ErrProfileNotDeleted):
+		c.JSON(http.StatusConflict, m.ErrorResponse{
+			Error:   "not_deleted",
+			Message: err.Error(),
+		})
+	case errors.Is(err, s.ErrNotSponsored):
+		c.JSON(http.StatusConflict, m.ErrorResponse{
+			Error:   "not_sponsored",
+			Message: err.Error(),
+		})
+	case errors.Is(err, s.ErrSponsorOwnedCat):
+		c.JSON(http.StatusConflict, m.ErrorResponse{
+			Error:   "owned_cat",
+			Message: err.Error(),
+		})
+	case errors.Is(err, s.ErrSponsorUntilPast):
+		c.JSON(http.StatusBadRequest, m.ErrorResponse{
+			Error:   "invalid_sponsorship",
+			Message: err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, m.ErrorResponse{
+			Error:   "internal_error",
+			Message: err.Error(),
+		})
+	}
+}
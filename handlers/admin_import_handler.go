@@ -0,0 +1,239 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+
+	mw "github.com/ChrisTheAbysswalker/meownder-backend/middleware"
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+	s "github.com/ChrisTheAbysswalker/meownder-backend/services"
+)
+
+// * Tope propio de la ruta (mw.RequestLimits no aplica el general)
+const MaxImportBytes = 5 << 20
+
+var importContentTypes = map[string]string{
+	"text/csv":                s.FormatCSV,
+	"application/csv":         s.FormatCSV,
+	"application/x-ndjson":    s.FormatJSONL,
+	"application/jsonl":       s.FormatJSONL,
+	"application/x-jsonlines": s.FormatJSONL,
+}
+
+func (h *AdminCatHandler) ImportCats(c *gin.Context) {
+	var query m.ImportQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	format := query.Format
+	if format == "" {
+		mediaType, _, _ := mime.ParseMediaType(c.ContentType())
+		format = importContentTypes[mediaType]
+	}
+	if format == "" {
+		c.JSON(http.StatusUnsupportedMediaType, m.ErrorResponse{
+			Error:   "unsupported_format",
+			Message: "Enviar text/csv o application/x-ndjson, o indicar ?format=csv|jsonl",
+		})
+		return
+	}
+
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, MaxImportBytes)
+	rows, rowErrors, err := s.ParseProfileImport(format, body)
+	if respondBodyTooLarge(c, err) {
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, m.ErrorResponse{
+			Error:   "invalid_import",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	valid := make([]m.ProfileImportRow, 0, len(rows))
+	for _, row := range rows {
+		if errs := validateImportRow(row); len(errs) > 0 {
+			rowErrors = append(rowErrors, errs...)
+			continue
+		}
+		valid = append(valid, row)
+	}
+
+	cats := tenantCats(c, h.service)
+	if wantsAsync(c) {
+		startJob(c, h.jobs, m.JobImport, func(ctx context.Context, job *s.JobHandle) (any, error) {
+			report, err := h.applyImport(ctx, cats, query, rows, valid, rowErrors, job)
+			if err != nil {
+				return nil, err
+			}
+			if len(report.Errors) > 0 || (len(report.Duplicates) > 0 && !query.Force) {
+				return report, fmt.Errorf("el import tiene %d errores y %d posibles repetidos (ver result)", len(report.Errors), len(report.Duplicates))
+			}
+			return report, nil
+		})
+		return
+	}
+
+	report, err := h.applyImport(c.Request.Context(), cats, query, rows, valid, rowErrors, nil)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, m.ErrorResponse{
+			Error:   "text_generation_failed",
+			Message: err.Error(),
+		})
+		return
+	}
+	if len(report.Errors) > 0 || (len(report.Duplicates) > 0 && !query.Force) {
+		c.JSON(http.StatusUnprocessableEntity, report)
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// * El error es solo de generación de texto; los problemas de filas van en el reporte
+func (h *AdminCatHandler) applyImport(ctx context.Context, cats *s.CatService, query m.ImportQuery, rows, valid []m.ProfileImportRow, rowErrors []m.ImportRowError, job *s.JobHandle) (m.ImportReport, error) {
+	job.SetTotal(len(valid))
+
+	// * Solo si el import se va a aplicar: no tiene sentido gastar llamadas al LLM en un dry-run.
+	// * Es lo lento del import, así que el progreso avanza por fila generada
+	generate := query.GenerateText && !query.DryRun && len(rowErrors) == 0
+	if generate {
+		if err := h.fillImportText(ctx, valid, job); err != nil {
+			return m.ImportReport{}, err
+		}
+	}
+
+	// * Con errores de formato igual se corre la validación del servicio en seco, así el
+	// * reporte trae todos los problemas de una vez
+	report := cats.ImportCatProfiles(valid, query.DryRun || len(rowErrors) > 0, query.Force)
+	report.DryRun = query.DryRun
+	report.Rows = len(rows) + countUnparsed(rowErrors, rows)
+	report.Errors = append(report.Errors, rowErrors...)
+	sort.SliceStable(report.Errors, func(i, j int) bool { return report.Errors[i].Line < report.Errors[j].Line })
+	if !generate {
+		job.Advance(len(valid))
+	}
+	return report, nil
+}
+
+func (h *AdminCatHandler) ExportCats(c *gin.Context) {
+	var query m.ExportQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+	if query.Format == "" {
+		query.Format = s.FormatCSV
+		if mw.WantsNDJSON(c.Request) {
+			query.Format = s.FormatJSONL
+		}
+	}
+
+	profiles, _ := tenantCats(c, h.service).FilterCatProfiles(m.ProfileFilter{IncludeDeleted: query.IncludeDeleted})
+
+	contentType := "text/csv; charset=utf-8"
+	if query.Format == s.FormatJSONL {
+		contentType = mw.NDJSONType
+	}
+	filename := fmt.Sprintf("meownder-cats-%s.%s", time.Now().Format("20060102"), query.Format)
+
+	if wantsAsync(c) {
+		startJob(c, h.jobs, m.JobExport, func(_ context.Context, job *s.JobHandle) (any, error) {
+			job.SetTotal(len(profiles))
+			if err := writeJobArtifact(job, filename, contentType, func(w io.Writer) error {
+				return s.ExportCatProfiles(w, query.Format, profiles)
+			}); err != nil {
+				return nil, err
+			}
+			job.Advance(len(profiles))
+			return gin.H{"profiles": len(profiles), "format": query.Format}, nil
+		})
+		return
+	}
+
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Header("X-Total-Count", strconv.Itoa(len(profiles)))
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	if err := s.ExportCatProfiles(c.Writer, query.Format, profiles); err != nil {
+		_ = c.Error(err)
+	}
+}
+
+func (h *AdminCatHandler) fillImportText(ctx context.Context, rows []m.ProfileImportRow, job *s.JobHandle) error {
+	for i := range rows {
+		row := &rows[i]
+		if row.Bio != "" && row.Personality != "" {
+			job.Advance(1)
+			continue
+		}
+		cat := m.CatProfile{
+			Name:        row.Name,
+			Age:         row.Age,
+			Breed:       row.Breed,
+			Hobbies:     row.Hobbies,
+			Personality: row.Personality,
+			Bio:         row.Bio,
+		}
+		fields := []string{m.TextFieldBio, m.TextFieldPersonality}
+		if _, err := h.writer.Fill(ctx, &cat, fields, false); err != nil {
+			return fmt.Errorf("línea %d: %w", row.Line, err)
+		}
+		row.Bio = cat.Bio
+		row.Personality = cat.Personality
+		job.Advance(1)
+	}
+	return nil
+}
+
+func validateImportRow(row m.ProfileImportRow) []m.ImportRowError {
+	err := binding.Validator.ValidateStruct(row)
+	if err == nil {
+		return nil
+	}
+
+	var verrs validator.ValidationErrors
+	if !errors.As(err, &verrs) {
+		return []m.ImportRowError{{Line: row.Line, Message: err.Error()}}
+	}
+
+	rowErrors := make([]m.ImportRowError, 0, len(verrs))
+	for _, fe := range verrs {
+		rowErrors = append(rowErrors, m.ImportRowError{
+			Line:    row.Line,
+			Field:   fe.Field(),
+			Message: validationMessage(fe),
+		})
+	}
+	return rowErrors
+}
+
+// * Filas que ni siquiera se pudieron leer (no llegaron a rows)
+func countUnparsed(rowErrors []m.ImportRowError, rows []m.ProfileImportRow) int {
+	parsed := make(map[int]bool, len(rows))
+	for _, row := range rows {
+		parsed[row.Line] = true
+	}
+	unparsed := make(map[int]bool)
+	for _, e := range rowErrors {
+		if !parsed[e.Line] {
+			unparsed[e.Line] = true
+		}
+	}
+	return len(unparsed)
+}
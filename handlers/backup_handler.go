@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+	s "github.com/ChrisTheAbysswalker/meownder-backend/services"
+)
+
+// * Tope propio de la ruta (mw.RequestLimits no aplica el general)
+const MaxRestoreBytes = 64 << 20
+
+type BackupHandler struct {
+	service *s.BackupService
+	jobs    *s.JobManager
+}
+
+func NewBackupHandler(service *s.BackupService, jobs *s.JobManager) *BackupHandler {
+	return &BackupHandler{
+		service: service,
+		jobs:    jobs,
+	}
+}
+
+// * Con ?async=true el respaldo se arma en un trabajo y se baja de /api/admin/jobs/:id/download
+func (h *BackupHandler) Download(c *gin.Context) {
+	filename := s.BackupFileName(time.Now())
+	if wantsAsync(c) {
+		startJob(c, h.jobs, m.JobBackup, func(_ context.Context, job *s.JobHandle) (any, error) {
+			job.SetTotal(1)
+			var manifest m.BackupManifest
+			err := writeJobArtifact(job, filename, "application/gzip", func(w io.Writer) error {
+				var err error
+				manifest, err = h.service.Write(w)
+				return err
+			})
+			if err != nil {
+				return nil, err
+			}
+			job.Advance(1)
+			return manifest, nil
+		})
+		return
+	}
+
+	c.Header("Content-Type", "application/gzip")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Status(http.StatusOK)
+
+	if _, err := h.service.Write(c.Writer); err != nil {
+		_ = c.Error(err)
+	}
+}
+
+func (h *BackupHandler) Restore(c *gin.Context) {
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, MaxRestoreBytes)
+	manifest, err := h.service.Restore(body)
+	if respondBodyTooLarge(c, err) {
+		return
+	}
+	if err != nil {
+		if errors.Is(err, s.ErrRestoreUnsupported) {
+			c.JSON(http.StatusConflict, m.ErrorResponse{
+				Error:   "restore_unsupported",
+				Message: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusBadRequest, m.ErrorResponse{
+			Error:   "invalid_backup",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, manifest)
+}
@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	s "github.com/ChrisTheAbysswalker/meownder-backend/services"
+)
+
+// * Gamificación: insignias, rachas y misiones diarias
+type BadgeHandler struct {
+	service *s.BadgeService
+	quests  *s.QuestService
+}
+
+func NewBadgeHandler(service *s.BadgeService, quests *s.QuestService) *BadgeHandler {
+	return &BadgeHandler{
+		service: service,
+		quests:  quests,
+	}
+}
+
+func (h *BadgeHandler) GetBadges(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, tenantBadges(c, h.service).Badges(userID))
+}
+
+func (h *BadgeHandler) GetQuests(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, tenantQuests(c, h.quests).Quests(userID))
+}
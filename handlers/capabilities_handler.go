@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	mw "github.com/ChrisTheAbysswalker/meownder-backend/middleware"
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+	s "github.com/ChrisTheAbysswalker/meownder-backend/services"
+)
+
+const mediaCapabilitiesKey = "media_capabilities"
+
+type CapabilitiesHandler struct {
+	service *s.ClientCapabilities
+}
+
+func NewCapabilitiesHandler(service *s.ClientCapabilities) *CapabilitiesHandler {
+	return &CapabilitiesHandler{
+		service: service,
+	}
+}
+
+// * Deja en el contexto las capacidades que declaró el usuario de X-User-ID (si las hay)
+// * para imageQuality y mediaOptions, y las suma a la clave del cache de respuestas.
+// * Va después de TenantScope
+func ClientMedia(service *s.ClientCapabilities) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		caps, ok := service.Get(tenantID(c), c.GetHeader(userIDHeader))
+		if ok {
+			c.Set(mediaCapabilitiesKey, caps)
+			c.Set(mw.MediaVariantKey, caps.Quality+"/static="+strconv.FormatBool(caps.StaticOnly))
+			c.Writer.Header().Add("Vary", userIDHeader)
+		}
+		c.Next()
+	}
+}
+
+func clientCapabilities(c *gin.Context) (m.MediaCapabilities, bool) {
+	caps, ok := c.Get(mediaCapabilitiesKey)
+	if !ok {
+		return m.MediaCapabilities{}, false
+	}
+	return caps.(m.MediaCapabilities), true
+}
+
+func (h *CapabilitiesHandler) GetCapabilities(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	caps, ok := h.service.Get(tenantID(c), userID)
+	if !ok {
+		respondCapabilitiesError(c, s.ErrNoCapabilities)
+		return
+	}
+	c.JSON(http.StatusOK, caps)
+}
+
+func (h *CapabilitiesHandler) DeclareCapabilities(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	var req m.CapabilitiesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, h.service.Declare(tenantID(c), userID, req))
+}
+
+func (h *CapabilitiesHandler) ForgetCapabilities(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Forget(tenantID(c), userID); err != nil {
+		respondCapabilitiesError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func respondCapabilitiesError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, s.ErrNoCapabilities):
+		c.JSON(http.StatusNotFound, m.ErrorResponse{
+			Error:   "no_capabilities",
+			Message: err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, m.ErrorResponse{
+			Error:   "internal_error",
+			Message: err.Error(),
+		})
+	}
+}
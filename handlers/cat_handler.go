@@ -1,57 +1,189 @@
 package handlers
 
 import (
+	"context"
 	"net/http"
 	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/ChrisTheAbysswalker/meownder-backend/bufpool"
+	mw "github.com/ChrisTheAbysswalker/meownder-backend/middleware"
 	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
 	s "github.com/ChrisTheAbysswalker/meownder-backend/services"
 )
 
 type CatHandler struct {
-	service *s.CatService
+	service            *s.CatService
+	themes             *s.ThemeService
+	quality            *s.ImageQualityPolicy
+	validationDeadline time.Duration
+	freshness          s.FreshnessPolicy
 }
 
-func NewCatHandler(service *s.CatService) *CatHandler {
+func NewCatHandler(service *s.CatService, themes *s.ThemeService, quality *s.ImageQualityPolicy, validationDeadline time.Duration, freshness s.FreshnessPolicy) *CatHandler {
 	return &CatHandler{
-		service: service,
+		service:            service,
+		themes:             themes,
+		quality:            quality,
+		validationDeadline: validationDeadline,
+		freshness:          freshness,
 	}
 }
 
 func (h *CatHandler) GetCatProfiles(c *gin.Context) {
-	profiles := h.service.GetCatProfiles()
+	var query m.ProfilesQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondValidationError(c, err)
+		return
+	}
 
-	if len(profiles) == 0 {
-		c.JSON(http.StatusNotFound, m.ErrorResponse{
-			Error:   "no_profiles_found",
-			Message: "No se encontraron perfiles de gatos",
-		})
+	// * Sin page ni limit van todos; con page hace falta un tamaño de página, si no
+	// * ?page=3 devolvería siempre la primera
+	page := query.Page
+	if page == 0 {
+		page = 1
+	} else if query.Limit == 0 {
+		query.Limit = defaultPageLimit
+	}
+
+	profiles, total, ok := filterProfiles(c, tenantCats(c, h.service), query.Q, m.ProfileFilter{
+		Breed:  query.Breed,
+		Hobby:  query.Hobby,
+		Tags:   s.NormalizeTags(query.Tags),
+		MinAge: query.MinAge,
+		MaxAge: query.MaxAge,
+		Offset: (page - 1) * query.Limit,
+		Limit:  query.Limit,
+		Status: m.StatusActive,
+	})
+	if !ok {
+		return
+	}
+
+	// * Stream NDJSON: un perfil por línea, el total en X-Total-Count
+	if mw.WantsNDJSON(c.Request) {
+		c.Header("X-Total-Count", strconv.Itoa(total))
+		stream := startNDJSON(c)
+		for _, cat := range profiles {
+			if !stream.Send(cat) {
+				return
+			}
+		}
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"cats":  profiles,
 		"count": len(profiles),
+		"total": total,
+		"page":  page,
+		"limit": query.Limit,
+	})
+}
+
+// * Mismos filtros que GetCatProfiles; 0 es una respuesta válida, no un 404
+func (h *CatHandler) CountCatProfiles(c *gin.Context) {
+	var query m.ProfilesQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	filter := m.ProfileFilter{
+		Breed:  query.Breed,
+		Hobby:  query.Hobby,
+		Tags:   s.NormalizeTags(query.Tags),
+		MinAge: query.MinAge,
+		MaxAge: query.MaxAge,
+		Status: m.StatusActive,
+	}
+	total := 0
+	if query.Q == "" {
+		total = tenantCats(c, h.service).CountCatProfiles(filter)
+	} else {
+		// * Con búsqueda de texto el conteo sale del mismo índice
+		_, matched, ok := filterProfiles(c, tenantCats(c, h.service), query.Q, filter)
+		if !ok {
+			return
+		}
+		total = matched
+	}
+
+	c.Header("X-Total-Count", strconv.Itoa(total))
+	c.JSON(http.StatusOK, gin.H{
+		"total": total,
+	})
+}
+
+// * Razas, edades, hobbies y personalidades del catálogo publicado, más conteos de datos faltantes
+func (h *CatHandler) GetSummary(c *gin.Context) {
+	c.JSON(http.StatusOK, tenantCats(c, h.service).Summary())
+}
+
+// * Etiquetas de los perfiles publicados, de la más usada a la menos (?limit=, 50 por defecto)
+func (h *CatHandler) GetTags(c *gin.Context) {
+	var query m.TagsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+	if query.Limit == 0 {
+		query.Limit = 50
+	}
+
+	tags := tenantCats(c, h.service).Tags(query.Limit)
+	c.JSON(http.StatusOK, gin.H{
+		"tags":  tags,
+		"count": len(tags),
 	})
 }
 
 func (h *CatHandler) GetCatProfileByID(c *gin.Context) {
-	idStr := c.Param("id")
-	id, err := strconv.Atoi(idStr)
+	var param m.ProfileIDParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	profile, err := tenantCats(c, h.service).GetCatProfileByID(param.ID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, m.ErrorResponse{
-			Error:   "invalid_id",
-			Message: "El ID debe ser un número válido",
+		c.JSON(http.StatusNotFound, m.ErrorResponse{
+			Error:   "profile_not_found",
+			Message: err.Error(),
 		})
 		return
 	}
 
-	profile, err := h.service.GetCatProfileByID(id)
+	c.JSON(http.StatusOK, profile)
+}
+
+// * HEAD /profiles/:id: solo el status (200, 404 o 400), sin serializar el perfil
+func (h *CatHandler) HeadCatProfile(c *gin.Context) {
+	var param m.ProfileIDParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	profile, err := tenantCats(c, h.service).GetCatProfileByID(param.ID)
+	if err != nil {
+		c.Status(http.StatusNotFound)
+		return
+	}
+
+	if !profile.UpdatedAt.IsZero() {
+		c.Header("Last-Modified", profile.UpdatedAt.UTC().Format(http.TimeFormat))
+	}
+	c.Header("Content-Type", "application/json; charset=utf-8")
+	c.Status(http.StatusOK)
+}
+
+func (h *CatHandler) GetCatOfTheDay(c *gin.Context) {
+	profile, err := tenantCats(c, h.service).CatOfTheDay(time.Now())
 	if err != nil {
 		c.JSON(http.StatusNotFound, m.ErrorResponse{
-			Error:   "profile_not_found",
+			Error:   "no_profiles_found",
 			Message: err.Error(),
 		})
 		return
@@ -61,7 +193,7 @@ func (h *CatHandler) GetCatProfileByID(c *gin.Context) {
 }
 
 func (h *CatHandler) RefreshImages(c *gin.Context) {
-	if err := h.service.RefreshCatImages(); err != nil {
+	if err := tenantCats(c, h.service).RefreshCatImages(); err != nil {
 		c.JSON(http.StatusInternalServerError, m.ErrorResponse{
 			Error:   "refresh_failed",
 			Message: err.Error(),
@@ -75,15 +207,45 @@ func (h *CatHandler) RefreshImages(c *gin.Context) {
 }
 
 func (h *CatHandler) GetCats(c *gin.Context) {
-	// * Obtener parámetro count (default: 5, max: 10)
-	count := 5
-	if countStr := c.Query("count"); countStr != "" {
-		if parsed, err := strconv.Atoi(countStr); err == nil && parsed > 0 && parsed <= 10 {
-			count = parsed
-		}
+	// * Parámetro count (default: 5, max: 10); valores fuera de rango devuelven 400
+	var query m.CatsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondValidationError(c, err)
+		return
 	}
 
-	urls, batch, err := h.service.GenerateCatURLs(count)
+	count := query.Count
+	if count == 0 {
+		count = 5
+	}
+
+	quality, ok := imageQuality(c)
+	if !ok {
+		return
+	}
+
+	opts, theme := h.themes.Decorate(mediaOptions(c, h.quality.Options(m.ImageOptions{
+		Tag:  query.Tag,
+		Size: query.Size,
+	}, quality)), time.Now())
+
+	// * max_wait_ms acota la espera; con validated gana el plazo más corto
+	ctx := c.Request.Context()
+	if query.MaxWaitMs > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(query.MaxWaitMs)*time.Millisecond)
+		defer cancel()
+	}
+
+	var batch *m.CatBatch
+	var err error
+	if query.Validated {
+		ctx, cancel := context.WithTimeout(ctx, h.validationDeadline)
+		defer cancel()
+		batch, err = tenantCats(c, h.service).GenerateValidatedCatURLs(ctx, count, opts)
+	} else {
+		batch, err = tenantCats(c, h.service).GenerateCatURLs(ctx, count, opts)
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, m.ErrorResponse{
 			Error:   "no_images_available",
@@ -92,22 +254,50 @@ func (h *CatHandler) GetCats(c *gin.Context) {
 		return
 	}
 
+	// * Lote incompleto: con ?partial=fail no se entrega nada; si no, va con lo que faltó
+	partial := len(batch.URLs) < batch.Requested
+	if partial && query.Partial == m.PartialFail {
+		c.JSON(http.StatusBadGateway, m.PartialBatchError{
+			ErrorResponse: m.ErrorResponse{
+				Error:   "partial_batch",
+				Message: "Solo se consiguieron " + strconv.Itoa(len(batch.URLs)) + " de " + strconv.Itoa(batch.Requested) + " imágenes",
+			},
+			Requested: batch.Requested,
+			Delivered: len(batch.URLs),
+			Errors:    batch.Errors,
+		})
+		return
+	}
+
 	response := m.CatResponse{
-		URLs:  urls,
-		Count: len(urls),
-		Batch: batch,
+		URLs:      batch.URLs,
+		Count:     len(batch.URLs),
+		Batch:     batch.Batch,
+		Stale:     batch.Stale,
+		Meta:      batch.Meta,
+		Theme:     theme,
+		ImageMeta: h.imageMeta(c, batch.URLs),
+		Requested: batch.Requested,
+		Delivered: len(batch.URLs),
+		Partial:   partial,
+		Errors:    batch.Errors,
+		Truncated: batch.Truncated,
 	}
 
 	c.JSON(http.StatusOK, response)
 }
 
+// * Siempre 200 mientras el proceso responda: "degraded" es para dashboards, no para
+// * que el balanceador saque la instancia
 func (h *CatHandler) Health(c *gin.Context) {
-	profiles := h.service.GetCatProfiles()
+	profiles := tenantCats(c, h.service).GetCatProfiles()
+	freshness := tenantCats(c, h.service).Freshness(h.freshness)
 	
 	response := m.HealthResponse{
-		Status:    "healthy",
+		Status:    freshness.Status(),
 		Timestamp: time.Now().Unix(),
-		Batches:   h.service.GetBatchCount(),
+		Batches:   tenantCats(c, h.service).GetBatchCount(),
+		Freshness: freshness,
 	}
 
 	if len(profiles) > 0 {
@@ -115,10 +305,33 @@ func (h *CatHandler) Health(c *gin.Context) {
 			"status":    response.Status,
 			"timestamp": response.Timestamp,
 			"batches":   response.Batches,
+			"freshness": response.Freshness,
 			"profiles_loaded": len(profiles),
+			"retries":         tenantCats(c, h.service).RetryStats(),
+			"buffer_pool":     bufpool.Default.Stats(),
+			"image_pruning":   tenantCats(c, h.service).PruneStats(),
+			"providers":       tenantCats(c, h.service).ProviderStats(),
+			"outbound":        tenantCats(c, h.service).OutboundStats(),
+			"dns":             tenantCats(c, h.service).DNSStats(),
 		})
 		return
 	}
 
 	c.JSON(http.StatusOK, response)
-}
\ No newline at end of file
+}
+
+// * nil cuando no se sabe nada de ninguna: así el campo se omite
+func (h *CatHandler) imageMeta(c *gin.Context, urls []string) map[string]m.ImageMeta {
+	var known map[string]m.ImageMeta
+	for _, url := range urls {
+		meta, ok := tenantCats(c, h.service).ImageMeta(url)
+		if !ok {
+			continue
+		}
+		if known == nil {
+			known = make(map[string]m.ImageMeta)
+		}
+		known[url] = meta
+	}
+	return known
+}
@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+	s "github.com/ChrisTheAbysswalker/meownder-backend/services"
+)
+
+type ChaosHandler struct {
+	chaos *s.Chaos
+}
+
+func NewChaosHandler(chaos *s.Chaos) *ChaosHandler {
+	return &ChaosHandler{chaos: chaos}
+}
+
+func (h *ChaosHandler) GetChaos(c *gin.Context) {
+	c.JSON(http.StatusOK, h.chaos.Stats())
+}
+
+func (h *ChaosHandler) UpdateChaos(c *gin.Context) {
+	var settings m.ChaosSettings
+	if err := c.ShouldBindJSON(&settings); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	h.chaos.Update(settings)
+	c.JSON(http.StatusOK, h.chaos.Stats())
+}
+
+func (h *ChaosHandler) ResetChaos(c *gin.Context) {
+	h.chaos.Reset()
+	c.Status(http.StatusNoContent)
+}
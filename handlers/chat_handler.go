@@ -0,0 +1,279 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/websocket"
+
+	mw "github.com/ChrisTheAbysswalker/meownder-backend/middleware"
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+	s "github.com/ChrisTheAbysswalker/meownder-backend/services"
+)
+
+type ChatHandler struct {
+	service *s.ChatService
+}
+
+func NewChatHandler(service *s.ChatService) *ChatHandler {
+	return &ChatHandler{
+		service: service,
+	}
+}
+
+func (h *ChatHandler) GetMessages(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	var params m.MatchIDParam
+	if err := c.ShouldBindUri(&params); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	response, err := tenantChat(c, h.service).Messages(userID, params.ID)
+	if err != nil {
+		respondChatError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *ChatHandler) SendMessage(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	var params m.MatchIDParam
+	if err := c.ShouldBindUri(&params); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	var req m.SendMessageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	message, err := tenantChat(c, h.service).Send(userID, params.ID, req.Text)
+	if err != nil {
+		respondChatError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, message)
+}
+
+func (h *ChatHandler) MarkRead(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	var params m.MatchIDParam
+	if err := c.ShouldBindUri(&params); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	var req m.ReadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	response, err := tenantChat(c, h.service).MarkRead(userID, params.ID, req.LastReadMessageID)
+	if err != nil {
+		respondChatError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *ChatHandler) AddReaction(c *gin.Context) {
+	var req m.ReactionRequest
+	h.react(c, &req, func() string { return req.Emoji }, false)
+}
+
+func (h *ChatHandler) RemoveReaction(c *gin.Context) {
+	var query m.ReactionQuery
+	h.react(c, &query, func() string { return query.Emoji }, true)
+}
+
+// * POST trae el emoji en el cuerpo y DELETE en la query; el resto es igual
+func (h *ChatHandler) react(c *gin.Context, input any, emoji func() string, remove bool) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	var params m.MessageIDParam
+	if err := c.ShouldBindUri(&params); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	bind := c.ShouldBindJSON
+	if remove {
+		bind = c.ShouldBindQuery
+	}
+	if err := bind(input); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	message, err := tenantChat(c, h.service).React(userID, params.ID, emoji(), remove)
+	if err != nil {
+		respondChatError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, message)
+}
+
+func (h *ChatHandler) GetPresence(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	var params m.MatchIDParam
+	if err := c.ShouldBindUri(&params); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	presence, err := tenantChat(c, h.service).Presence(userID, params.ID)
+	if err != nil {
+		respondChatError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, presence)
+}
+
+// * Los navegadores no pueden mandar headers en un WebSocket: se acepta ?user_id= también
+func (h *ChatHandler) Events(c *gin.Context) {
+	userID := c.GetHeader(userIDHeader)
+	if userID == "" {
+		userID = c.Query("user_id")
+	}
+	if userID == "" {
+		requireUserID(c)
+		return
+	}
+
+	chat := tenantChat(c, h.service)
+	codec := caseCodec(mw.JSONCaseFrom(c))
+	websocket.Handler(func(conn *websocket.Conn) {
+		defer conn.Close()
+		// * La conexión secuestrada hereda el WriteTimeout del servidor: hay que quitarlo
+		conn.SetDeadline(time.Time{})
+
+		events, cancel := chat.Subscribe(userID)
+		defer cancel()
+
+		// * El cliente solo manda eventos de "escribiendo…"; cualquier error de lectura es desconexión
+		closed := make(chan struct{})
+		go func() {
+			defer close(closed)
+			for {
+				var event m.ChatClientEvent
+				if err := codec.Receive(conn, &event); err != nil {
+					var syntaxErr *json.SyntaxError
+					var typeErr *json.UnmarshalTypeError
+					if errors.As(err, &syntaxErr) || errors.As(err, &typeErr) {
+						continue
+					}
+					return
+				}
+				if event.Type != m.ChatEventTyping {
+					continue
+				}
+				if err := chat.Typing(userID, event.MatchID, event.Typing); err != nil {
+					log.Printf("⚠️ Evento typing ignorado de %s: %v", userID, err)
+				}
+			}
+		}()
+
+		for {
+			select {
+			case event := <-events:
+				if err := codec.Send(conn, event); err != nil {
+					log.Printf("⚠️ Error enviando evento de chat a %s: %v", userID, err)
+					return
+				}
+			case <-closed:
+				return
+			}
+		}
+	}).ServeHTTP(c.Writer, c.Request)
+}
+
+// * Los eventos del websocket siguen la misma convención de claves que las respuestas
+func caseCodec(mode string) websocket.Codec {
+	return websocket.Codec{
+		Marshal: func(v any) ([]byte, byte, error) {
+			data, err := mw.MarshalCase(mode, v)
+			return data, websocket.TextFrame, err
+		},
+		Unmarshal: func(data []byte, _ byte, v any) error {
+			return mw.UnmarshalCase(mode, data, v)
+		},
+	}
+}
+
+func respondChatError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, s.ErrMatchNotFound):
+		c.JSON(http.StatusNotFound, m.ErrorResponse{
+			Error:   "match_not_found",
+			Message: err.Error(),
+		})
+	case errors.Is(err, s.ErrMessageNotFound):
+		c.JSON(http.StatusNotFound, m.ErrorResponse{
+			Error:   "message_not_found",
+			Message: err.Error(),
+		})
+	case errors.Is(err, s.ErrChatUnavailable):
+		c.JSON(http.StatusConflict, m.ErrorResponse{
+			Error:   "chat_unavailable",
+			Message: err.Error(),
+		})
+	case errors.Is(err, s.ErrInvalidEmoji):
+		c.JSON(http.StatusBadRequest, m.ErrorResponse{
+			Error:   "invalid_emoji",
+			Message: err.Error(),
+		})
+	case errors.Is(err, s.ErrMatchArchived):
+		c.JSON(http.StatusConflict, m.ErrorResponse{
+			Error:   "match_archived",
+			Message: err.Error(),
+		})
+	case errors.Is(err, s.ErrMatchNotArchived):
+		c.JSON(http.StatusConflict, m.ErrorResponse{
+			Error:   "match_not_archived",
+			Message: err.Error(),
+		})
+	case errors.Is(err, s.ErrReadBackwards):
+		c.JSON(http.StatusConflict, m.ErrorResponse{
+			Error:   "read_backwards",
+			Message: err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, m.ErrorResponse{
+			Error:   "internal_error",
+			Message: err.Error(),
+		})
+	}
+}
@@ -0,0 +1,200 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	mw "github.com/ChrisTheAbysswalker/meownder-backend/middleware"
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+	s "github.com/ChrisTheAbysswalker/meownder-backend/services"
+)
+
+type CollectionHandler struct {
+	collections *s.CollectionService
+	cats        *s.CatService
+	swipes      *s.SwipeService
+	quality     *s.ImageQualityPolicy
+}
+
+func NewCollectionHandler(collections *s.CollectionService, cats *s.CatService, swipes *s.SwipeService, quality *s.ImageQualityPolicy) *CollectionHandler {
+	return &CollectionHandler{
+		collections: collections,
+		cats:        cats,
+		swipes:      swipes,
+		quality:     quality,
+	}
+}
+
+// * Colecciones del refugio con portada y cantidad de perfiles; las vacías no se muestran
+func (h *CollectionHandler) ListCollections(c *gin.Context) {
+	quality, ok := imageQuality(c)
+	if !ok {
+		return
+	}
+
+	cats := tenantCats(c, h.cats)
+	collections := make([]m.CollectionSummary, 0)
+	for _, collection := range h.collections.List(tenantID(c)) {
+		profiles := h.quality.Profiles(cats.CollectionProfiles(collection), quality)
+		if len(profiles) == 0 {
+			continue
+		}
+		collections = append(collections, collectionSummary(collection, profiles))
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"collections": collections,
+		"count":       len(collections),
+	})
+}
+
+func (h *CollectionHandler) GetCollection(c *gin.Context) {
+	collection, ok := h.tenantCollection(c)
+	if !ok {
+		return
+	}
+	quality, ok := imageQuality(c)
+	if !ok {
+		return
+	}
+
+	profiles := h.quality.Profiles(tenantCats(c, h.cats).CollectionProfiles(collection), quality)
+	c.JSON(http.StatusOK, m.CollectionResponse{
+		CollectionSummary: collectionSummary(collection, profiles),
+		Cats:              profiles,
+	})
+}
+
+// * Mazo temático: el mismo de GET /deck pero solo con perfiles de la colección
+func (h *CollectionHandler) GetCollectionDeck(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+	collection, ok := h.tenantCollection(c)
+	if !ok {
+		return
+	}
+
+	var query m.DeckQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+	quality, ok := imageQuality(c)
+	if !ok {
+		return
+	}
+
+	matcher := s.CollectionMatcher(collection)
+	if tags := s.TagMatcher(s.NormalizeTags(query.Tags)); tags != nil {
+		inCollection := matcher
+		matcher = func(cat m.CatProfile) bool { return inCollection(cat) && tags(cat) }
+	}
+
+	cats, seed := tenantSwipes(c, h.swipes).Deck(userID, query.Seed, query.Limit, matcher)
+	cats, truncated := validateDeck(c, tenantSwipes(c, h.swipes), cats, query.MaxWaitMs)
+	respondDeck(c, h.quality, cats, seed, quality, truncated)
+}
+
+// * Admin: todas, incluso las que hoy no tienen perfiles
+func (h *CollectionHandler) AdminListCollections(c *gin.Context) {
+	collections := h.collections.List(tenantID(c))
+	c.JSON(http.StatusOK, gin.H{
+		"collections": collections,
+		"count":       len(collections),
+	})
+}
+
+func (h *CollectionHandler) PutCollection(c *gin.Context) {
+	var param m.CollectionParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+	var req m.CollectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	collection, created, err := h.collections.Put(tenantID(c), param.Slug, req)
+	if err != nil {
+		respondCollectionError(c, err)
+		return
+	}
+
+	log.Printf("📝 [auditoría] colección %s guardada: %d perfiles, etiquetas %v (%s)",
+		collection.Slug, len(collection.ProfileIDs), collection.Tags, mw.ClientIP(c))
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	c.JSON(status, collection)
+}
+
+func (h *CollectionHandler) DeleteCollection(c *gin.Context) {
+	var param m.CollectionParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	if err := h.collections.Delete(tenantID(c), param.Slug); err != nil {
+		respondCollectionError(c, err)
+		return
+	}
+
+	log.Printf("📝 [auditoría] colección %s borrada (%s)", param.Slug, mw.ClientIP(c))
+	c.Status(http.StatusNoContent)
+}
+
+func (h *CollectionHandler) tenantCollection(c *gin.Context) (m.Collection, bool) {
+	var param m.CollectionParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		respondValidationError(c, err)
+		return m.Collection{}, false
+	}
+
+	collection, err := h.collections.Get(tenantID(c), param.Slug)
+	if err != nil {
+		respondCollectionError(c, err)
+		return m.Collection{}, false
+	}
+	return collection, true
+}
+
+// * Sin imagen propia la portada es la del primer perfil
+func collectionSummary(collection m.Collection, profiles []m.CatProfile) m.CollectionSummary {
+	summary := m.CollectionSummary{
+		Collection: collection,
+		Cover:      collection.Image,
+		Count:      len(profiles),
+	}
+	if summary.Cover == "" && len(profiles) > 0 {
+		summary.Cover = profiles[0].Img
+	}
+	return summary
+}
+
+func respondCollectionError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, s.ErrCollectionNotFound):
+		c.JSON(http.StatusNotFound, m.ErrorResponse{
+			Error:   "collection_not_found",
+			Message: err.Error(),
+		})
+	case errors.Is(err, s.ErrInvalidSlug), errors.Is(err, s.ErrEmptyCollection):
+		c.JSON(http.StatusBadRequest, m.ErrorResponse{
+			Error:   "invalid_collection",
+			Message: err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, m.ErrorResponse{
+			Error:   "internal_error",
+			Message: err.Error(),
+		})
+	}
+}
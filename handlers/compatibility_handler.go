@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+	s "github.com/ChrisTheAbysswalker/meownder-backend/services"
+)
+
+// * Público y sin estado: pensado para el widget compartible de marketing
+type CompatibilityHandler struct {
+	cacheTTL time.Duration
+}
+
+func NewCompatibilityHandler(cacheTTL time.Duration) *CompatibilityHandler {
+	return &CompatibilityHandler{
+		cacheTTL: cacheTTL,
+	}
+}
+
+func (h *CompatibilityHandler) GetNameCompatibility(c *gin.Context) {
+	var query m.NameCompatibilityQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	locale := s.CompatibilityLocale(query.Locale, c.GetHeader("Accept-Language"))
+	result, err := s.NameCompatibility(query.A, query.B, locale)
+	if err != nil {
+		if errors.Is(err, s.ErrInvalidName) {
+			c.JSON(http.StatusBadRequest, m.ErrorResponse{
+				Error:   "invalid_name",
+				Message: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, m.ErrorResponse{
+			Error:   "compatibility_unavailable",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	// * El resultado es determinista: CDNs y navegadores pueden guardarlo tranquilos
+	c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", int(h.cacheTTL.Seconds())))
+	c.Header("Vary", "Accept-Language")
+	c.Header("Content-Language", locale)
+	c.JSON(http.StatusOK, result)
+}
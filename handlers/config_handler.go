@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	mw "github.com/ChrisTheAbysswalker/meownder-backend/middleware"
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+	s "github.com/ChrisTheAbysswalker/meownder-backend/services"
+)
+
+type ConfigReloader interface {
+	Reload(source string) (m.ConfigReload, error)
+	Current() m.RuntimeConfig
+}
+
+type ConfigHandler struct {
+	reloader ConfigReloader
+}
+
+func NewConfigHandler(reloader ConfigReloader) *ConfigHandler {
+	return &ConfigHandler{reloader: reloader}
+}
+
+func (h *ConfigHandler) GetConfig(c *gin.Context) {
+	if !h.requireDefaultTenant(c) {
+		return
+	}
+	c.JSON(http.StatusOK, h.reloader.Current())
+}
+
+// * Relee CONFIG_FILE y aplica lo recargable; si algo es inválido no se aplica nada
+func (h *ConfigHandler) Reload(c *gin.Context) {
+	if !h.requireDefaultTenant(c) {
+		return
+	}
+
+	reload, err := h.reloader.Reload("api " + mw.ClientIP(c))
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, m.ErrorResponse{
+			Error:   "invalid_config",
+			Message: err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, reload)
+}
+
+// ! La configuración es de todo el servicio: el admin de otro refugio no la toca
+func (h *ConfigHandler) requireDefaultTenant(c *gin.Context) bool {
+	if tenant := TenantFrom(c); tenant != nil && tenant.ID != s.DefaultTenantID {
+		c.JSON(http.StatusForbidden, m.ErrorResponse{
+			Error:   "forbidden",
+			Message: "Solo el admin del refugio principal puede ver o recargar la configuración",
+		})
+		return false
+	}
+	return true
+}
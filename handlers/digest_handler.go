@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+	s "github.com/ChrisTheAbysswalker/meownder-backend/services"
+)
+
+type DigestHandler struct {
+	service *s.DigestService
+}
+
+func NewDigestHandler(service *s.DigestService) *DigestHandler {
+	return &DigestHandler{
+		service: service,
+	}
+}
+
+func (h *DigestHandler) GetSubscription(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	sub, err := h.service.Subscription(tenantID(c), userID)
+	if err != nil {
+		respondDigestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, sub)
+}
+
+func (h *DigestHandler) Subscribe(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	var req m.DigestSubscribeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, h.service.Subscribe(tenantID(c), userID, req.Email))
+}
+
+func (h *DigestHandler) Unsubscribe(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Unsubscribe(tenantID(c), userID); err != nil {
+		respondDigestError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *DigestHandler) Preview(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	digest, err := h.service.Preview(tenantID(c), userID, time.Now())
+	if err != nil {
+		respondDigestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, digest)
+}
+
+// * GET desde el enlace del correo y POST para el one-click de List-Unsubscribe-Post
+func (h *DigestHandler) UnsubscribeByToken(c *gin.Context) {
+	var query m.DigestUnsubscribeQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	if err := h.service.UnsubscribeByToken(query.Token); err != nil {
+		respondDigestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Te diste de baja del resumen semanal",
+	})
+}
+
+func (h *DigestHandler) SendNow(c *gin.Context) {
+	c.JSON(http.StatusOK, h.service.SendTenant(c.Request.Context(), tenantID(c), time.Now()))
+}
+
+func respondDigestError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, s.ErrNotSubscribed):
+		c.JSON(http.StatusNotFound, m.ErrorResponse{
+			Error:   "not_subscribed",
+			Message: err.Error(),
+		})
+	case errors.Is(err, s.ErrInvalidUnsubscribeToken):
+		c.JSON(http.StatusNotFound, m.ErrorResponse{
+			Error:   "invalid_token",
+			Message: err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, m.ErrorResponse{
+			Error:   "digest_failed",
+			Message: err.Error(),
+		})
+	}
+}
+
+func tenantID(c *gin.Context) string {
+	if tenant := TenantFrom(c); tenant != nil {
+		return tenant.ID
+	}
+	return s.DefaultTenantID
+}
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+	"github.com/ChrisTheAbysswalker/meownder-backend/schemas"
+)
+
+// * Esquemas publicados de los eventos (outbox) para que los integradores validen lo que reciben
+type EventSchemaHandler struct{}
+
+func NewEventSchemaHandler() *EventSchemaHandler {
+	return &EventSchemaHandler{}
+}
+
+func (h *EventSchemaHandler) ListSchemas(c *gin.Context) {
+	topics := schemas.Topics()
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, gin.H{
+		"topics": topics,
+		"count":  len(topics),
+	})
+}
+
+// * Una versión publicada no cambia nunca: se cachea como inmutable; latest no
+func (h *EventSchemaHandler) GetSchema(c *gin.Context) {
+	var param m.EventSchemaParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	cacheControl := "public, max-age=86400, immutable"
+	version, err := strconv.Atoi(strings.TrimPrefix(param.Version, "v"))
+	if param.Version == "latest" {
+		version, err = 0, nil
+		if latest, ok := schemas.Latest(param.Topic); ok {
+			version = latest
+		}
+		cacheControl = "public, max-age=300"
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, m.ErrorResponse{
+			Error:   "invalid_version",
+			Message: "La versión debe ser un número o latest",
+		})
+		return
+	}
+
+	document, ok := schemas.Document(param.Topic, version)
+	if !ok {
+		c.JSON(http.StatusNotFound, m.ErrorResponse{
+			Error:   "schema_not_found",
+			Message: "No hay esquema para " + param.Topic + " (versión " + param.Version + ")",
+		})
+		return
+	}
+	c.Header("Cache-Control", cacheControl)
+	c.Data(http.StatusOK, "application/schema+json", document)
+}
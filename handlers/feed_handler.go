@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+	s "github.com/ChrisTheAbysswalker/meownder-backend/services"
+)
+
+type FeedHandler struct {
+	service *s.FeedService
+}
+
+func NewFeedHandler(service *s.FeedService) *FeedHandler {
+	return &FeedHandler{
+		service: service,
+	}
+}
+
+func (h *FeedHandler) GetFeed(c *gin.Context) {
+	feed := h.service.Feed()
+	if len(feed) == 0 {
+		c.JSON(http.StatusServiceUnavailable, m.ErrorResponse{
+			Error:   "feed_unavailable",
+			Message: "El feed aún no está disponible",
+		})
+		return
+	}
+
+	c.Data(http.StatusOK, "application/atom+xml; charset=utf-8", feed)
+}
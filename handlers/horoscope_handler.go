@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+	s "github.com/ChrisTheAbysswalker/meownder-backend/services"
+)
+
+type HoroscopeHandler struct {
+	service *s.CatService
+}
+
+func NewHoroscopeHandler(service *s.CatService) *HoroscopeHandler {
+	return &HoroscopeHandler{
+		service: service,
+	}
+}
+
+func (h *HoroscopeHandler) GetHoroscope(c *gin.Context) {
+	var param m.ProfileIDParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	var query m.HoroscopeQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	date := time.Now().UTC()
+	if query.Date != "" {
+		// * El formato ya lo validó el binding
+		date, _ = time.Parse("2006-01-02", query.Date)
+	}
+	locale := s.HoroscopeLocale(query.Locale, c.GetHeader("Accept-Language"))
+
+	horoscope, err := tenantCats(c, h.service).Horoscope(param.ID, date, locale)
+	if err != nil {
+		c.JSON(http.StatusNotFound, m.ErrorResponse{
+			Error:   "profile_not_found",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.Header("Content-Language", locale)
+	c.JSON(http.StatusOK, horoscope)
+}
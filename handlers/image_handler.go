@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+	s "github.com/ChrisTheAbysswalker/meownder-backend/services"
+)
+
+type ImageHandler struct {
+	service *s.CatService
+	proxy   *s.ImageProxy
+}
+
+func NewImageHandler(service *s.CatService, proxy *s.ImageProxy) *ImageHandler {
+	return &ImageHandler{
+		service: service,
+		proxy:   proxy,
+	}
+}
+
+func (h *ImageHandler) GetProfileImage(c *gin.Context) {
+	var param m.ProfileIDParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	quality, ok := imageQuality(c)
+	if !ok {
+		return
+	}
+
+	profile, err := tenantCats(c, h.service).GetCatProfileByID(param.ID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, m.ErrorResponse{
+			Error:   "profile_not_found",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	image, err := h.proxy.FetchQuality(profile.Img, quality)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, m.ErrorResponse{
+			Error:   "image_unavailable",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=300")
+	// * Para reservar espacio sin decodificar la imagen (HEAD o antes de terminar la descarga)
+	if image.Meta.Width > 0 && image.Meta.Height > 0 {
+		c.Header("X-Image-Width", strconv.Itoa(image.Meta.Width))
+		c.Header("X-Image-Height", strconv.Itoa(image.Meta.Height))
+	}
+	c.Data(http.StatusOK, image.ContentType, image.Data)
+}
+
+// * Imágenes del proveedor offline (APP_ENV=dev); la misma semilla da siempre el mismo gato
+func (h *ImageHandler) GetOfflineCat(c *gin.Context) {
+	seed := strings.TrimSuffix(c.Param("file"), ".png")
+	c.Header("Cache-Control", "public, max-age=86400, immutable")
+	c.Data(http.StatusOK, "image/png", s.OfflineCatPNG(seed))
+}
@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	mw "github.com/ChrisTheAbysswalker/meownder-backend/middleware"
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+)
+
+// * ?quality= explícito, el header Save-Data del navegador o lo que el cliente declaró en
+// * POST /me/capabilities, en ese orden
+func imageQuality(c *gin.Context) (string, bool) {
+	var query m.QualityQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondValidationError(c, err)
+		return "", false
+	}
+
+	c.Writer.Header().Add("Vary", "Save-Data")
+	if query.Quality != "" {
+		return query.Quality, true
+	}
+	if mw.SavesData(c.Request) {
+		return m.ImageQualityLow, true
+	}
+	if caps, ok := clientCapabilities(c); ok {
+		return caps.Quality, true
+	}
+	return m.ImageQualityHigh, true
+}
+
+// * Sin GIFs si el cliente declaró que no los muestra
+func mediaOptions(c *gin.Context, opts m.ImageOptions) m.ImageOptions {
+	if caps, ok := clientCapabilities(c); ok && caps.StaticOnly {
+		opts.StaticOnly = true
+		if opts.Tag == "gif" {
+			opts.Tag = ""
+		}
+	}
+	return opts
+}
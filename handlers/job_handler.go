@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/websocket"
+
+	mw "github.com/ChrisTheAbysswalker/meownder-backend/middleware"
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+	s "github.com/ChrisTheAbysswalker/meownder-backend/services"
+)
+
+type JobHandler struct {
+	jobs *s.JobManager
+}
+
+func NewJobHandler(jobs *s.JobManager) *JobHandler {
+	return &JobHandler{
+		jobs: jobs,
+	}
+}
+
+func (h *JobHandler) ListJobs(c *gin.Context) {
+	jobs := h.jobs.List(jobTenant(c))
+	c.JSON(http.StatusOK, gin.H{
+		"jobs":  jobs,
+		"count": len(jobs),
+	})
+}
+
+func (h *JobHandler) GetJob(c *gin.Context) {
+	job, ok := h.tenantJob(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, job)
+}
+
+func (h *JobHandler) DownloadJob(c *gin.Context) {
+	job, ok := h.tenantJob(c)
+	if !ok {
+		return
+	}
+
+	path, filename, contentType, err := h.jobs.Artifact(job.ID)
+	if err != nil {
+		respondJobError(c, err)
+		return
+	}
+	c.Header("Content-Type", contentType)
+	c.FileAttachment(path, filename)
+}
+
+// * Eventos job.updated / job.finished de los trabajos del tenant mientras la conexión siga abierta
+func (h *JobHandler) Events(c *gin.Context) {
+	tenant := jobTenant(c)
+	codec := caseCodec(mw.JSONCaseFrom(c))
+	websocket.Handler(func(conn *websocket.Conn) {
+		defer conn.Close()
+		// * La conexión secuestrada hereda el WriteTimeout del servidor: hay que quitarlo
+		conn.SetDeadline(time.Time{})
+
+		events, cancel := h.jobs.Subscribe()
+		defer cancel()
+
+		// * El cliente no manda nada: cualquier lectura que falle es desconexión
+		closed := make(chan struct{})
+		go func() {
+			defer close(closed)
+			var discard []byte
+			for websocket.Message.Receive(conn, &discard) == nil {
+			}
+		}()
+
+		for {
+			select {
+			case event := <-events:
+				if tenant != "" && event.Job.Tenant != tenant {
+					continue
+				}
+				if err := codec.Send(conn, event); err != nil {
+					log.Printf("⚠️ Error enviando evento de trabajo: %v", err)
+					return
+				}
+			case <-closed:
+				return
+			}
+		}
+	}).ServeHTTP(c.Writer, c.Request)
+}
+
+// ! Un trabajo de otro refugio responde 404, igual que uno inexistente
+func (h *JobHandler) tenantJob(c *gin.Context) (m.Job, bool) {
+	job, err := h.jobs.Get(c.Param("id"))
+	if err == nil {
+		if tenant := jobTenant(c); tenant != "" && job.Tenant != tenant {
+			err = s.ErrJobNotFound
+		}
+	}
+	if err != nil {
+		respondJobError(c, err)
+		return m.Job{}, false
+	}
+	return job, true
+}
+
+// * Tenant dueño de los trabajos que crea o ve el admin; el refugio principal ve todos
+func jobTenant(c *gin.Context) string {
+	if tenant := TenantFrom(c); tenant != nil && tenant.ID != s.DefaultTenantID {
+		return tenant.ID
+	}
+	return ""
+}
+
+// * ?async=true o "Prefer: respond-async" (RFC 7240) pasan la operación a un trabajo
+func wantsAsync(c *gin.Context) bool {
+	switch strings.ToLower(c.Query("async")) {
+	case "1", "true":
+		return true
+	}
+	for _, pref := range strings.Split(c.GetHeader("Prefer"), ",") {
+		if strings.EqualFold(strings.TrimSpace(pref), "respond-async") {
+			return true
+		}
+	}
+	return false
+}
+
+// * Encola el trabajo y responde 202 con el trabajo y Location para seguirlo
+func startJob(c *gin.Context, jobs *s.JobManager, kind string, run s.JobFunc) {
+	job, err := jobs.Start(kind, jobTenant(c), run)
+	if err != nil {
+		respondJobError(c, err)
+		return
+	}
+
+	log.Printf("📝 [auditoría] trabajo %s (%s) encolado (%s)", job.ID, job.Kind, mw.ClientIP(c))
+	c.Header("Location", fmt.Sprintf("/api/admin/jobs/%s", job.ID))
+	c.Header("Preference-Applied", "respond-async")
+	c.JSON(http.StatusAccepted, job)
+}
+
+// * Escribe el archivo descargable del trabajo; un error de escritura hace fallar el trabajo
+func writeJobArtifact(job *s.JobHandle, filename, contentType string, write func(w io.Writer) error) error {
+	file, err := job.Artifact(filename, contentType)
+	if err != nil {
+		return err
+	}
+	if err := write(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func respondJobError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, s.ErrJobRunning):
+		c.JSON(http.StatusConflict, m.ErrorResponse{
+			Error:   "job_running",
+			Message: err.Error(),
+		})
+	case errors.Is(err, s.ErrJobNotFound):
+		c.JSON(http.StatusNotFound, m.ErrorResponse{
+			Error:   "job_not_found",
+			Message: err.Error(),
+		})
+	case errors.Is(err, s.ErrNoArtifact):
+		c.JSON(http.StatusConflict, m.ErrorResponse{
+			Error:   "no_download",
+			Message: err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, m.ErrorResponse{
+			Error:   "internal_error",
+			Message: err.Error(),
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+	s "github.com/ChrisTheAbysswalker/meownder-backend/services"
+)
+
+type LinkHandler struct {
+	service *s.LinkService
+	cats    *s.CatService
+}
+
+func NewLinkHandler(service *s.LinkService, cats *s.CatService) *LinkHandler {
+	return &LinkHandler{
+		service: service,
+		cats:    cats,
+	}
+}
+
+func (h *LinkHandler) CreateLink(c *gin.Context) {
+	var req m.ShortLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	if req.CatID > 0 {
+		if _, err := tenantCats(c, h.cats).GetCatProfileByID(req.CatID); err != nil {
+			c.JSON(http.StatusNotFound, m.ErrorResponse{
+				Error:   "profile_not_found",
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	link, err := h.service.Create(c.Request.Context(), tenantID(c), req)
+	if err != nil {
+		respondLinkError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, link)
+}
+
+func (h *LinkHandler) GetLink(c *gin.Context) {
+	var param m.LinkCodeParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	link, err := h.service.Get(param.Code)
+	if err != nil {
+		respondLinkError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, link)
+}
+
+func (h *LinkHandler) Redirect(c *gin.Context) {
+	var param m.LinkCodeParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	target, err := h.service.Resolve(c.Request.Context(), param.Code)
+	if err != nil {
+		respondLinkError(c, err)
+		return
+	}
+
+	// * 302 y sin cache: si el navegador guarda la redirección, los clics dejan de contarse
+	c.Header("Cache-Control", "no-store")
+	c.Redirect(http.StatusFound, target)
+}
+
+func respondLinkError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, s.ErrLinkNotFound):
+		c.JSON(http.StatusNotFound, m.ErrorResponse{
+			Error:   "link_not_found",
+			Message: err.Error(),
+		})
+	case errors.Is(err, s.ErrLinkExpired):
+		c.JSON(http.StatusGone, m.ErrorResponse{
+			Error:   "link_expired",
+			Message: err.Error(),
+		})
+	case errors.Is(err, s.ErrForeignLinkURL), errors.Is(err, s.ErrLinkTarget):
+		c.JSON(http.StatusBadRequest, m.ErrorResponse{
+			Error:   "invalid_link_target",
+			Message: err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, m.ErrorResponse{
+			Error:   "link_failed",
+			Message: err.Error(),
+		})
+	}
+}
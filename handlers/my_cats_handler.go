@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+	s "github.com/ChrisTheAbysswalker/meownder-backend/services"
+)
+
+// * Gatos registrados por el propio usuario (X-User-ID); entran al mazo de los demás
+type MyCatsHandler struct {
+	service *s.CatService
+	// * nil = sin doble opt-in: el gato se publica al registrarlo
+	verifier *s.OwnedCatVerifier
+}
+
+func NewMyCatsHandler(service *s.CatService, verifier *s.OwnedCatVerifier) *MyCatsHandler {
+	return &MyCatsHandler{
+		service:  service,
+		verifier: verifier,
+	}
+}
+
+func (h *MyCatsHandler) ListCats(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	cats := tenantCats(c, h.service).OwnedCats(userID)
+	c.JSON(http.StatusOK, gin.H{
+		"cats":  cats,
+		"count": len(cats),
+	})
+}
+
+func (h *MyCatsHandler) AddCat(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	var req m.OwnedCatRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	// * ?force=true registra el gato aunque se parezca a uno ya cargado
+	force := c.Query("force") == "true"
+	var cat *m.CatProfile
+	var err error
+	if h.verifier != nil {
+		cat, err = h.verifier.Submit(c.Request.Context(), tenantID(c), userID, req, force)
+	} else {
+		cat, err = tenantCats(c, h.service).AddOwnedCat(userID, req, force, m.StatusActive)
+	}
+	if errors.Is(err, s.ErrEmailRequired) {
+		c.JSON(http.StatusBadRequest, m.ErrorResponse{
+			Error:   "email_required",
+			Message: err.Error(),
+		})
+		return
+	}
+	if errors.Is(err, s.ErrVerificationNotSent) {
+		c.JSON(http.StatusBadGateway, m.ErrorResponse{
+			Error:   "verification_not_sent",
+			Message: err.Error(),
+		})
+		return
+	}
+	var duplicate *s.DuplicateError
+	if errors.As(err, &duplicate) {
+		c.JSON(http.StatusConflict, m.DuplicateResponse{
+			ErrorResponse: m.ErrorResponse{
+				Error:   "likely_duplicate",
+				Message: err.Error(),
+			},
+			Duplicate: duplicate.Duplicate,
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, m.ErrorResponse{
+			Error:   "register_failed",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, cat)
+}
+
+// * Enlace del correo de confirmación: no requiere X-User-ID, el token alcanza
+func (h *MyCatsHandler) VerifyCat(c *gin.Context) {
+	var query m.VerifyCatQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+	if h.verifier == nil {
+		c.JSON(http.StatusNotFound, m.ErrorResponse{
+			Error:   "verification_disabled",
+			Message: "La confirmación de gatos propios no está activada",
+		})
+		return
+	}
+
+	cat, err := h.verifier.Confirm(query.Token)
+	if errors.Is(err, s.ErrInvalidVerifyToken) {
+		c.JSON(http.StatusNotFound, m.ErrorResponse{
+			Error:   "invalid_token",
+			Message: err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, m.ErrorResponse{
+			Error:   "verify_failed",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, cat)
+}
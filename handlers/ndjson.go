@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	mw "github.com/ChrisTheAbysswalker/meownder-backend/middleware"
+)
+
+// * Respuesta NDJSON: un objeto por línea y flush después de cada uno, así el cliente
+// * empieza a pintar antes de que el lote termine. Los metadatos (seed, total) van en
+// * cabeceras porque salen antes que el primer elemento. Respeta ?case=camel
+type ndjsonStream struct {
+	c    *gin.Context
+	mode string
+}
+
+func startNDJSON(c *gin.Context) *ndjsonStream {
+	c.Header("Content-Type", mw.NDJSONType)
+	c.Header("Cache-Control", "no-store")
+	// * Sin esto nginx junta la respuesta entera antes de mandarla
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	return &ndjsonStream{c: c, mode: mw.JSONCaseFrom(c)}
+}
+
+// * Devuelve false si el cliente se fue o la escritura falló: hay que dejar de generar
+func (s *ndjsonStream) Send(item any) bool {
+	if s.c.Request.Context().Err() != nil {
+		return false
+	}
+	data, err := mw.MarshalCase(s.mode, item)
+	if err != nil {
+		_ = s.c.Error(err)
+		return false
+	}
+	if _, err := s.c.Writer.Write(append(data, '\n')); err != nil {
+		return false
+	}
+	s.c.Writer.Flush()
+	return true
+}
@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+	s "github.com/ChrisTheAbysswalker/meownder-backend/services"
+)
+
+const defaultProfileChangesLimit = 100
+
+type ProfileChangesHandler struct {
+	service *s.CatService
+	quality *s.ImageQualityPolicy
+}
+
+func NewProfileChangesHandler(service *s.CatService, quality *s.ImageQualityPolicy) *ProfileChangesHandler {
+	return &ProfileChangesHandler{
+		service: service,
+		quality: quality,
+	}
+}
+
+// * Para refugios e índices de búsqueda: se guarda el cursor de la respuesta y se pide
+// * de nuevo con ?since= hasta que has_more sea false
+func (h *ProfileChangesHandler) GetChanges(c *gin.Context) {
+	var query m.ProfileChangesQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+	since, err := s.DecodeCursor(query.Since)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, m.ErrorResponse{
+			Error:   "invalid_cursor",
+			Message: err.Error(),
+		})
+		return
+	}
+	quality, ok := imageQuality(c)
+	if !ok {
+		return
+	}
+
+	limit := query.Limit
+	if limit == 0 {
+		limit = defaultProfileChangesLimit
+	}
+
+	changes := tenantCats(c, h.service).ProfileChanges(since, limit)
+	for i := range changes.Changes {
+		if changes.Changes[i].Profile != nil {
+			changes.Changes[i].Profile = &h.quality.Profiles([]m.CatProfile{*changes.Changes[i].Profile}, quality)[0]
+		}
+	}
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, changes)
+}
@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+	s "github.com/ChrisTheAbysswalker/meownder-backend/services"
+)
+
+// * Con ?q= los perfiles salen del índice de búsqueda (por relevancia); sin q, del filtro de
+// * siempre. Si la búsqueda falla ya respondió y devuelve ok=false
+func filterProfiles(c *gin.Context, cats *s.CatService, text string, filter m.ProfileFilter) ([]m.CatProfile, int, bool) {
+	if text == "" {
+		profiles, total := cats.FilterCatProfiles(filter)
+		return profiles, total, true
+	}
+	profiles, total, err := cats.SearchCatProfiles(c.Request.Context(), text, filter)
+	if err != nil {
+		respondSearchError(c, err)
+		return nil, 0, false
+	}
+	return profiles, total, true
+}
+
+func respondSearchError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, s.ErrSearchUnavailable):
+		c.JSON(http.StatusServiceUnavailable, m.ErrorResponse{
+			Error:   "search_unavailable",
+			Message: err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, m.ErrorResponse{
+			Error:   "internal_error",
+			Message: err.Error(),
+		})
+	}
+}
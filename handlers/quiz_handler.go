@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+	s "github.com/ChrisTheAbysswalker/meownder-backend/services"
+)
+
+type QuizHandler struct {
+	service *s.SwipeService
+}
+
+func NewQuizHandler(service *s.SwipeService) *QuizHandler {
+	return &QuizHandler{
+		service: service,
+	}
+}
+
+func (h *QuizHandler) GetQuiz(c *gin.Context) {
+	var query m.QuizQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	locale := s.QuizLocale(query.Locale, c.GetHeader("Accept-Language"))
+	quiz, err := s.Quiz(locale)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, m.ErrorResponse{
+			Error:   "quiz_unavailable",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.Header("Content-Language", locale)
+	c.JSON(http.StatusOK, quiz)
+}
+
+func (h *QuizHandler) SubmitAnswers(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	var req m.QuizAnswersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	locale := s.QuizLocale(req.Locale, c.GetHeader("Accept-Language"))
+	result, err := tenantSwipes(c, h.service).ScoreQuiz(userID, req.Answers, locale)
+	if err != nil {
+		if errors.Is(err, s.ErrInvalidQuizAnswers) {
+			c.JSON(http.StatusBadRequest, m.ErrorResponse{
+				Error:   "invalid_answers",
+				Message: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, m.ErrorResponse{
+			Error:   "quiz_unavailable",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+	s "github.com/ChrisTheAbysswalker/meownder-backend/services"
+)
+
+type ReadinessHandler struct {
+	readiness *s.Readiness
+	service   *s.CatService
+	freshness s.FreshnessPolicy
+}
+
+func NewReadinessHandler(readiness *s.Readiness, service *s.CatService, freshness s.FreshnessPolicy) *ReadinessHandler {
+	return &ReadinessHandler{
+		readiness: readiness,
+		service:   service,
+		freshness: freshness,
+	}
+}
+
+func (h *ReadinessHandler) Ready(c *gin.Context) {
+	if !h.readiness.IsReady() {
+		c.Header("Retry-After", "5")
+		c.JSON(http.StatusServiceUnavailable, m.ErrorResponse{
+			Error:   "warming_up",
+			Message: "El servicio está precalentando caches",
+		})
+		return
+	}
+
+	// * Degradado sigue siendo listo: solo se informa, el código no cambia
+	freshness := h.service.Freshness(h.freshness)
+	c.JSON(http.StatusOK, gin.H{
+		"status":          "ready",
+		"health":          freshness.Status(),
+		"timestamp":       time.Now().Unix(),
+		"validated_pool":  h.service.ValidatedPoolSize(),
+		"profiles_loaded": len(h.service.GetCatProfiles()),
+		"freshness":       freshness,
+	})
+}
@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+	"github.com/ChrisTheAbysswalker/meownder-backend/qrcode"
+	s "github.com/ChrisTheAbysswalker/meownder-backend/services"
+)
+
+type ShareHandler struct {
+	service      *s.CatService
+	links        *s.LinkService
+	baseURL      string
+	deepLinkBase string
+}
+
+func NewShareHandler(service *s.CatService, links *s.LinkService, baseURL, deepLinkBase string) *ShareHandler {
+	return &ShareHandler{
+		service:      service,
+		links:        links,
+		baseURL:      strings.TrimRight(baseURL, "/"),
+		deepLinkBase: deepLinkBase,
+	}
+}
+
+// * Enlace corto del perfil (cuenta clics); si no se puede crear, la URL larga de siempre
+func (h *ShareHandler) shortShareURL(c *gin.Context, catID int) string {
+	link, err := h.links.ProfileLink(c.Request.Context(), tenantID(c), catID)
+	if err != nil {
+		log.Printf("⚠️ Sin enlace corto para el perfil %d: %v", catID, err)
+		return h.links.ShareURL(tenantID(c), catID)
+	}
+	return link.ShortURL
+}
+
+func (h *ShareHandler) ShareProfile(c *gin.Context) {
+	var param m.ProfileIDParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	profile, err := tenantCats(c, h.service).GetCatProfileByID(param.ID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, m.ErrorResponse{
+			Error:   "profile_not_found",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.HTML(http.StatusOK, "share.html", m.SharePage{
+		Name:        profile.Name,
+		Title:       fmt.Sprintf("%s, %d años · %s", profile.Name, profile.Age, profile.Breed),
+		Description: profile.Bio,
+		ImageURL:    s.TenantURL(h.baseURL, fmt.Sprintf("/api/profiles/%d/image", profile.ID), tenantID(c)),
+		ShareURL:    h.links.ShareURL(tenantID(c), profile.ID),
+		ShortURL:    h.shortShareURL(c, profile.ID),
+		// * El esquema del deep link (meownder://) no es http, html/template lo bloquearía
+		DeepLink: template.URL(fmt.Sprintf("%s%d", h.deepLinkBase, profile.ID)),
+	})
+}
+
+func (h *ShareHandler) ProfileQR(c *gin.Context) {
+	var param m.ProfileIDParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	var query m.QRQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	if _, err := tenantCats(c, h.service).GetCatProfileByID(param.ID); err != nil {
+		c.JSON(http.StatusNotFound, m.ErrorResponse{
+			Error:   "profile_not_found",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	size := query.Size
+	if size == 0 {
+		size = 256
+	}
+
+	// * El binding ya validó el nivel, no puede fallar aquí
+	level, _ := qrcode.ParseLevel(query.Level)
+
+	// * El enlace corto da un QR más pequeño y los escaneos cuentan como clics
+	code, err := qrcode.Encode([]byte(h.shortShareURL(c, param.ID)), level)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, m.ErrorResponse{
+			Error:   "qr_generation_failed",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	img, err := code.PNG(size)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, m.ErrorResponse{
+			Error:   "qr_generation_failed",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=86400")
+	c.Data(http.StatusOK, "image/png", img)
+}
@@ -0,0 +1,24 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	s "github.com/ChrisTheAbysswalker/meownder-backend/services"
+)
+
+type StatsHandler struct {
+	stats *s.StatsCollector
+}
+
+func NewStatsHandler(stats *s.StatsCollector) *StatsHandler {
+	return &StatsHandler{
+		stats: stats,
+	}
+}
+
+func (h *StatsHandler) GetStats(c *gin.Context) {
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, h.stats.Snapshot())
+}
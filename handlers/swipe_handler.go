@@ -0,0 +1,492 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	mw "github.com/ChrisTheAbysswalker/meownder-backend/middleware"
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+	s "github.com/ChrisTheAbysswalker/meownder-backend/services"
+)
+
+const userIDHeader = "X-User-ID"
+
+type SwipeHandler struct {
+	service     *s.SwipeService
+	quality     *s.ImageQualityPolicy
+	changesHold time.Duration
+}
+
+func NewSwipeHandler(service *s.SwipeService, quality *s.ImageQualityPolicy, changesHold time.Duration) *SwipeHandler {
+	return &SwipeHandler{
+		service:     service,
+		quality:     quality,
+		changesHold: changesHold,
+	}
+}
+
+func (h *SwipeHandler) Swipe(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	var req m.SwipeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	result, err := tenantSwipes(c, h.service).Record(c.Request.Context(), userID, req.CatID, req.FromCatID, req.Direction)
+	if err != nil {
+		var throttled *s.ThrottledError
+		if errors.As(err, &throttled) {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(throttled.RetryAfter.Seconds()))))
+			c.JSON(http.StatusTooManyRequests, m.ErrorResponse{
+				Error:   "swipes_throttled",
+				Message: err.Error(),
+			})
+			return
+		}
+		if code, ok := fromCatErrorCode(err); ok {
+			c.JSON(http.StatusBadRequest, m.ErrorResponse{
+				Error:   code,
+				Message: err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, s.ErrAlreadySwiped) {
+			c.JSON(http.StatusConflict, m.ErrorResponse{
+				Error:   "already_swiped",
+				Message: err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, s.ErrNoSuperLikes) {
+			c.JSON(http.StatusConflict, m.ErrorResponse{
+				Error:   "no_super_likes",
+				Message: err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, s.ErrProfileUnavailable) {
+			c.JSON(http.StatusConflict, m.ErrorResponse{
+				Error:   "profile_unavailable",
+				Message: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusNotFound, m.ErrorResponse{
+			Error:   "profile_not_found",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, result)
+}
+
+func (h *SwipeHandler) GetMatches(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	cursor, limit, ok := bindPage(c)
+	if !ok {
+		return
+	}
+
+	matches, next := tenantSwipes(c, h.service).MatchesPage(userID, cursor, limit)
+	c.JSON(http.StatusOK, gin.H{
+		"matches":     matches,
+		"count":       len(matches),
+		"next_cursor": encodeCursor(next),
+	})
+}
+
+func (h *SwipeHandler) GetArchivedMatches(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	matches := tenantSwipes(c, h.service).ArchivedMatches(userID)
+	c.JSON(http.StatusOK, gin.H{
+		"matches": matches,
+		"count":   len(matches),
+	})
+}
+
+func (h *SwipeHandler) ReviveMatch(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	var params m.MatchIDParam
+	if err := c.ShouldBindUri(&params); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	match, err := tenantSwipes(c, h.service).ReviveMatch(c.Request.Context(), userID, params.ID)
+	if err != nil {
+		respondChatError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, match)
+}
+
+func (h *SwipeHandler) GetIcebreakers(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	var params m.MatchIDParam
+	if err := c.ShouldBindUri(&params); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	icebreakers, err := tenantSwipes(c, h.service).MatchIcebreakers(c.Request.Context(), userID, params.ID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, m.ErrorResponse{
+			Error:   "match_not_found",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, m.IcebreakersResponse{
+		MatchID:     params.ID,
+		Icebreakers: icebreakers,
+	})
+}
+
+func (h *SwipeHandler) GetHistory(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	cursor, limit, ok := bindPage(c)
+	if !ok {
+		return
+	}
+
+	swipes, next := tenantSwipes(c, h.service).HistoryPage(userID, cursor, limit)
+	c.JSON(http.StatusOK, gin.H{
+		"swipes":      swipes,
+		"count":       len(swipes),
+		"next_cursor": encodeCursor(next),
+	})
+}
+
+func (h *SwipeHandler) GetNextProfile(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	quality, ok := imageQuality(c)
+	if !ok {
+		return
+	}
+
+	profile := tenantSwipes(c, h.service).NextCandidate(userID)
+	if profile == nil {
+		c.JSON(http.StatusNotFound, m.ErrorResponse{
+			Error:   "no_more_profiles",
+			Message: "Ya viste todos los gatos disponibles",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, h.quality.Profiles([]m.CatProfile{*profile}, quality)[0])
+}
+
+// * Botón "sorpréndeme": un gato al azar entre los mejores del mazo del usuario
+func (h *SwipeHandler) GetRandomProfile(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	quality, ok := imageQuality(c)
+	if !ok {
+		return
+	}
+
+	profile := tenantSwipes(c, h.service).Surprise(userID)
+	if profile == nil {
+		c.JSON(http.StatusNotFound, m.ErrorResponse{
+			Error:   "no_more_profiles",
+			Message: "Ya viste todos los gatos disponibles",
+		})
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, h.quality.Profiles([]m.CatProfile{*profile}, quality)[0])
+}
+
+func (h *SwipeHandler) GetDeck(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	var query m.DeckQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	quality, ok := imageQuality(c)
+	if !ok {
+		return
+	}
+
+	tags := s.NormalizeTags(query.Tags)
+	// * Mazo reservado con POST /deck/prefetch: ya viene rankeado y con imágenes revisadas
+	if cats, seed, truncated, ok := tenantSwipes(c, h.service).PrefetchedDeck(c.Request.Context(), userID, query.Seed, query.Limit, tags); ok {
+		c.Header("X-Deck-Prefetched", "true")
+		respondDeck(c, h.quality, cats, seed, quality, truncated)
+		return
+	}
+
+	cats, seed := tenantSwipes(c, h.service).Deck(userID, query.Seed, query.Limit, s.TagMatcher(tags))
+	cats, truncated := validateDeck(c, tenantSwipes(c, h.service), cats, query.MaxWaitMs)
+	respondDeck(c, h.quality, cats, seed, quality, truncated)
+}
+
+// * Long-polling para clientes sin WebSocket: sostiene la petición hasta que hay cambios
+// * después de ?since= o hasta ?wait= segundos (tope changesHold). Sin cambios responde
+// * 304 con el cursor en X-Deck-Cursor; con reset: true hay que volver a pedir el mazo
+func (h *SwipeHandler) GetDeckChanges(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	var query m.DeckChangesQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+	quality, ok := imageQuality(c)
+	if !ok {
+		return
+	}
+
+	hold := h.changesHold
+	if query.Wait > 0 {
+		hold = min(hold, time.Duration(query.Wait)*time.Second)
+	}
+
+	changes := tenantSwipes(c, h.service).DeckChanges(c.Request.Context(), userID, query.Since, hold)
+	c.Header("Cache-Control", "no-store")
+	c.Header("X-Deck-Cursor", strconv.FormatInt(changes.Cursor, 10))
+	if query.Since != nil && len(changes.Changes) == 0 && !changes.Reset {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	for i := range changes.Changes {
+		if changes.Changes[i].Cat != nil {
+			changes.Changes[i].Cat = &h.quality.Profiles([]m.CatProfile{*changes.Changes[i].Cat}, quality)[0]
+		}
+	}
+	c.JSON(http.StatusOK, changes)
+}
+
+// * Arma en segundo plano el próximo mazo con los mismos parámetros de GET /deck y lo
+// * reserva hasta que vence; responde 202 sin esperar
+func (h *SwipeHandler) PrefetchDeck(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	var query m.DeckQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	reservation, err := tenantSwipes(c, h.service).PrefetchDeck(userID, query.Seed, query.Limit, s.NormalizeTags(query.Tags))
+	if err != nil {
+		c.JSON(http.StatusNotFound, m.ErrorResponse{
+			Error:   "prefetch_disabled",
+			Message: err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusAccepted, reservation)
+}
+
+// * Con ?max_wait_ms solo quedan los perfiles cuya imagen respondió dentro del plazo
+func validateDeck(c *gin.Context, swipes *s.SwipeService, cats []m.CatProfile, maxWaitMs int) ([]m.CatProfile, bool) {
+	if maxWaitMs == 0 {
+		return cats, false
+	}
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Duration(maxWaitMs)*time.Millisecond)
+	defer cancel()
+	return swipes.ValidateDeck(ctx, cats)
+}
+
+// * Con Accept: application/x-ndjson el mazo sale un perfil por línea, con la semilla y el
+// * tamaño en X-Deck-Seed y X-Total-Count (y X-Truncated si max_wait_ms cortó la revisión)
+func respondDeck(c *gin.Context, policy *s.ImageQualityPolicy, cats []m.CatProfile, seed int64, quality string, truncated bool) {
+	if !mw.WantsNDJSON(c.Request) {
+		c.JSON(http.StatusOK, m.DeckResponse{
+			Cats:      policy.Profiles(cats, quality),
+			Count:     len(cats),
+			Seed:      seed,
+			Truncated: truncated,
+		})
+		return
+	}
+
+	c.Header("X-Deck-Seed", strconv.FormatInt(seed, 10))
+	c.Header("X-Total-Count", strconv.Itoa(len(cats)))
+	if truncated {
+		c.Header("X-Truncated", "true")
+	}
+	stream := startNDJSON(c)
+	for i := range cats {
+		if !stream.Send(policy.Profiles(cats[i:i+1], quality)[0]) {
+			return
+		}
+	}
+}
+
+func (h *SwipeHandler) GetPreferences(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, tenantSwipes(c, h.service).Preferences(userID))
+}
+
+func (h *SwipeHandler) UpdatePreferences(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	var prefs m.Preferences
+	if err := c.ShouldBindJSON(&prefs); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tenantSwipes(c, h.service).SetPreferences(userID, prefs))
+}
+
+// * Errores de swipes entre gatos de usuarios: siempre culpa de la petición
+func fromCatErrorCode(err error) (string, bool) {
+	switch {
+	case errors.Is(err, s.ErrOwnCat):
+		return "own_cat", true
+	case errors.Is(err, s.ErrNoOwnCat):
+		return "no_own_cat", true
+	case errors.Is(err, s.ErrFromCatRequired):
+		return "from_cat_required", true
+	case errors.Is(err, s.ErrNotYourCat):
+		return "not_your_cat", true
+	}
+	return "", false
+}
+
+func requireUserID(c *gin.Context) (string, bool) {
+	userID := c.GetHeader(userIDHeader)
+	if userID == "" {
+		c.JSON(http.StatusBadRequest, m.ErrorResponse{
+			Error:   "missing_user",
+			Message: "Se requiere el header " + userIDHeader,
+		})
+		return "", false
+	}
+	return userID, true
+}
+
+const defaultPageLimit = 20
+
+func bindPage(c *gin.Context) (*s.Cursor, int, bool) {
+	var query m.PageQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondValidationError(c, err)
+		return nil, 0, false
+	}
+
+	cursor, err := s.DecodeCursor(query.Cursor)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, m.ErrorResponse{
+			Error:   "invalid_cursor",
+			Message: err.Error(),
+		})
+		return nil, 0, false
+	}
+
+	if query.Limit == 0 {
+		query.Limit = defaultPageLimit
+	}
+	return cursor, query.Limit, true
+}
+
+// * "" cuando ya no hay más páginas
+func encodeCursor(cursor *s.Cursor) string {
+	if cursor == nil {
+		return ""
+	}
+	return cursor.Encode()
+}
+
+// * Métricas del ranking en sombra; con el modo apagado solo informa el ranking en vivo
+// * Sesiones marcadas por swipes no humanos
+func (h *SwipeHandler) GetSwipeFlags(c *gin.Context) {
+	flags := tenantSwipes(c, h.service).SwipeFlags()
+	c.JSON(http.StatusOK, gin.H{
+		"flags": flags,
+		"count": len(flags),
+	})
+}
+
+func (h *SwipeHandler) ClearSwipeFlag(c *gin.Context) {
+	var param m.SwipeFlagParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	if err := tenantSwipes(c, h.service).ClearSwipeFlag(param.UserID); err != nil {
+		c.JSON(http.StatusNotFound, m.ErrorResponse{
+			Error:   "flag_not_found",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	log.Printf("📝 [auditoría] marca de swipes de %s quitada (%s)", param.UserID, mw.ClientIP(c))
+	c.Status(http.StatusNoContent)
+}
+
+// * Patrocinados del refugio con sus impresiones
+func (h *SwipeHandler) GetSponsoredReport(c *gin.Context) {
+	c.JSON(http.StatusOK, tenantSwipes(c, h.service).SponsoredReport())
+}
+
+func (h *SwipeHandler) GetShadowRanking(c *gin.Context) {
+	c.JSON(http.StatusOK, tenantSwipes(c, h.service).ShadowRankingStats())
+}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	mw "github.com/ChrisTheAbysswalker/meownder-backend/middleware"
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+	s "github.com/ChrisTheAbysswalker/meownder-backend/services"
+)
+
+const (
+	tenantHeader = "X-Tenant"
+	tenantKey    = "tenant"
+)
+
+// * Resuelve el tenant por X-Tenant, por subdominio de domain (ej. norte.meownder.app) o
+// * por ?tenant= (enlaces para compartir, que se abren sin cabeceras); sin ninguno se usa
+// * el tenant por defecto
+func TenantScope(registry *s.TenantRegistry, domain string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Writer.Header().Add("Vary", tenantHeader)
+
+		id := strings.ToLower(strings.TrimSpace(c.GetHeader(tenantHeader)))
+		if id == "" && domain != "" {
+			host := strings.ToLower(c.Request.Host)
+			if i := strings.LastIndexByte(host, ':'); i >= 0 {
+				host = host[:i]
+			}
+			if sub, ok := strings.CutSuffix(host, "."+domain); ok && !strings.Contains(sub, ".") {
+				id = sub
+			}
+		}
+		if id == "" {
+			id = strings.ToLower(strings.TrimSpace(c.Query(s.TenantQueryParam)))
+		}
+		if id == "" {
+			id = s.DefaultTenantID
+		}
+
+		tenant, ok := registry.Get(id)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusNotFound, m.ErrorResponse{
+				Error:   "tenant_not_found",
+				Message: "No existe el refugio " + id,
+			})
+			return
+		}
+
+		c.Set(tenantKey, tenant)
+		c.Set(mw.TenantIDKey, tenant.ID)
+		c.Next()
+	}
+}
+
+func TenantFrom(c *gin.Context) *s.Tenant {
+	if tenant, ok := c.Get(tenantKey); ok {
+		return tenant.(*s.Tenant)
+	}
+	return nil
+}
+
+// * El servicio del tenant de la petición; fallback cuando la ruta no pasó por TenantScope
+func tenantCats(c *gin.Context, fallback *s.CatService) *s.CatService {
+	if tenant := TenantFrom(c); tenant != nil {
+		return tenant.Cats
+	}
+	return fallback
+}
+
+func tenantChat(c *gin.Context, fallback *s.ChatService) *s.ChatService {
+	if tenant := TenantFrom(c); tenant != nil {
+		return tenant.Chat
+	}
+	return fallback
+}
+
+func tenantBadges(c *gin.Context, fallback *s.BadgeService) *s.BadgeService {
+	if tenant := TenantFrom(c); tenant != nil {
+		return tenant.Badges
+	}
+	return fallback
+}
+
+func tenantQuests(c *gin.Context, fallback *s.QuestService) *s.QuestService {
+	if tenant := TenantFrom(c); tenant != nil {
+		return tenant.Quests
+	}
+	return fallback
+}
+
+func tenantSwipes(c *gin.Context, fallback *s.SwipeService) *s.SwipeService {
+	if tenant := TenantFrom(c); tenant != nil {
+		return tenant.Swipes
+	}
+	return fallback
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+	s "github.com/ChrisTheAbysswalker/meownder-backend/services"
+)
+
+type ThemeHandler struct {
+	service *s.ThemeService
+}
+
+func NewThemeHandler(service *s.ThemeService) *ThemeHandler {
+	return &ThemeHandler{
+		service: service,
+	}
+}
+
+func (h *ThemeHandler) ListThemes(c *gin.Context) {
+	c.JSON(http.StatusOK, h.service.Themes())
+}
+
+func (h *ThemeHandler) SetOverride(c *gin.Context) {
+	var req m.ThemeOverrideRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	if err := h.service.SetOverride(req.ThemeID); err != nil {
+		if errors.Is(err, s.ErrThemeNotFound) {
+			c.JSON(http.StatusNotFound, m.ErrorResponse{
+				Error:   "theme_not_found",
+				Message: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, m.ErrorResponse{
+			Error:   "theme_override_failed",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, h.service.Themes())
+}
+
+func (h *ThemeHandler) ClearOverride(c *gin.Context) {
+	h.service.ClearOverride()
+	c.JSON(http.StatusOK, h.service.Themes())
+}
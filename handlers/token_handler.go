@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	mw "github.com/ChrisTheAbysswalker/meownder-backend/middleware"
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+	s "github.com/ChrisTheAbysswalker/meownder-backend/services"
+)
+
+// * Tokens de solo lectura para el widget e integradores (ver middleware.ScopedTokens)
+type TokenHandler struct {
+	tokens *s.APITokens
+}
+
+func NewTokenHandler(tokens *s.APITokens) *TokenHandler {
+	return &TokenHandler{
+		tokens: tokens,
+	}
+}
+
+func (h *TokenHandler) ListTokens(c *gin.Context) {
+	tokens := h.tokens.List(tenantID(c))
+	c.JSON(http.StatusOK, gin.H{
+		"tokens": tokens,
+		"count":  len(tokens),
+	})
+}
+
+// * La respuesta es la única vez que se ve el token en claro
+func (h *TokenHandler) CreateToken(c *gin.Context) {
+	var req m.APITokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	created, err := h.tokens.Create(tenantID(c), req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, m.ErrorResponse{
+			Error:   "invalid_token_request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	log.Printf("📝 [auditoría] token %s (%s) creado con alcances %v (%s)", created.ID, created.Name, created.Scopes, mw.ClientIP(c))
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusCreated, created)
+}
+
+func (h *TokenHandler) RevokeToken(c *gin.Context) {
+	if err := h.tokens.Revoke(tenantID(c), c.Param("id")); err != nil {
+		if errors.Is(err, s.ErrTokenNotFound) {
+			c.JSON(http.StatusNotFound, m.ErrorResponse{
+				Error:   "token_not_found",
+				Message: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, m.ErrorResponse{
+			Error:   "internal_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	log.Printf("📝 [auditoría] token %s revocado (%s)", c.Param("id"), mw.ClientIP(c))
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+)
+
+const problemContentType = "application/problem+json"
+
+func init() {
+	// * Reportar los errores con el nombre del parámetro (form/uri) y no el del struct
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(func(fld reflect.StructField) string {
+			for _, tag := range []string{"form", "uri", "json"} {
+				name := strings.SplitN(fld.Tag.Get(tag), ",", 2)[0]
+				if name != "" && name != "-" {
+					return name
+				}
+			}
+			return fld.Name
+		})
+	}
+}
+
+func respondValidationError(c *gin.Context, err error) {
+	if respondBodyTooLarge(c, err) {
+		return
+	}
+
+	problem := m.ProblemResponse{
+		Type:   "validation_error",
+		Title:  "Parámetros inválidos",
+		Status: http.StatusBadRequest,
+		Detail: "Uno o más parámetros de la solicitud no son válidos",
+	}
+
+	var verrs validator.ValidationErrors
+	if errors.As(err, &verrs) {
+		for _, fe := range verrs {
+			problem.Errors = append(problem.Errors, m.FieldError{
+				Field:   fe.Field(),
+				Rule:    fe.Tag(),
+				Message: validationMessage(fe),
+			})
+		}
+	} else {
+		// ! Errores de conversión (ej. count=abc) no pasan por el validador
+		problem.Detail = err.Error()
+	}
+
+	c.Header("Content-Type", problemContentType)
+	c.AbortWithStatusJSON(http.StatusBadRequest, problem)
+}
+
+// * El cuerpo pasó el tope de mw.RequestLimits (o el de la ruta) mientras se leía
+func respondBodyTooLarge(c *gin.Context, err error) bool {
+	var tooLarge *http.MaxBytesError
+	if !errors.As(err, &tooLarge) {
+		return false
+	}
+	c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, m.ErrorResponse{
+		Error:   "payload_too_large",
+		Message: fmt.Sprintf("El cuerpo supera el máximo de %d bytes", tooLarge.Limit),
+	})
+	return true
+}
+
+func validationMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return fmt.Sprintf("%s es obligatorio", fe.Field())
+	case "min":
+		return fmt.Sprintf("%s debe ser mayor o igual a %s", fe.Field(), fe.Param())
+	case "max":
+		return fmt.Sprintf("%s debe ser menor o igual a %s", fe.Field(), fe.Param())
+	case "oneof":
+		return fmt.Sprintf("%s debe ser uno de: %s", fe.Field(), fe.Param())
+	case "alphanum":
+		return fmt.Sprintf("%s solo puede contener letras y números", fe.Field())
+	case "gtefield":
+		return fmt.Sprintf("%s debe ser mayor o igual a %s", fe.Field(), toSnakeCase(fe.Param()))
+	default:
+		return fmt.Sprintf("%s no cumple la regla %s", fe.Field(), fe.Tag())
+	}
+}
+
+func toSnakeCase(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		if r >= 'A' && r <= 'Z' {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r += 'a' - 'A'
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
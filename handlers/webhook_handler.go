@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	mw "github.com/ChrisTheAbysswalker/meownder-backend/middleware"
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+	s "github.com/ChrisTheAbysswalker/meownder-backend/services"
+)
+
+type WebhookHandler struct {
+	service *s.WebhookService
+}
+
+func NewWebhookHandler(service *s.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		service: service,
+	}
+}
+
+func (h *WebhookHandler) ListChannels(c *gin.Context) {
+	channels := h.service.ListChannels()
+	c.JSON(http.StatusOK, gin.H{
+		"channels": channels,
+		"count":    len(channels),
+	})
+}
+
+func (h *WebhookHandler) CreateChannel(c *gin.Context) {
+	var req m.WebhookChannelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, h.service.CreateChannel(req))
+}
+
+func (h *WebhookHandler) UpdateChannel(c *gin.Context) {
+	var req m.WebhookChannelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	channel, err := h.service.UpdateChannel(c.Param("id"), req)
+	if err != nil {
+		respondChannelError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, channel)
+}
+
+func (h *WebhookHandler) DeleteChannel(c *gin.Context) {
+	if err := h.service.DeleteChannel(c.Param("id")); err != nil {
+		respondChannelError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// * El secreto nuevo sale solo en esta respuesta; el anterior sigue firmando durante la gracia
+func (h *WebhookHandler) RotateSecret(c *gin.Context) {
+	var req m.RotateSecretRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondValidationError(c, err)
+			return
+		}
+	}
+
+	channel, err := h.service.RotateSecret(c.Param("id"), req)
+	if err != nil {
+		respondChannelError(c, err)
+		return
+	}
+
+	log.Printf("📝 [auditoría] secreto del canal %s rotado a v%d (%s)", channel.ID, channel.SecretVersion, mw.ClientIP(c))
+	c.JSON(http.StatusOK, channel)
+}
+
+func (h *WebhookHandler) TestChannel(c *gin.Context) {
+	if err := h.service.SendTest(c.Request.Context(), c.Param("id")); err != nil {
+		respondChannelError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Mensaje de prueba enviado",
+	})
+}
+
+func respondChannelError(c *gin.Context, err error) {
+	if errors.Is(err, s.ErrChannelNotFound) {
+		c.JSON(http.StatusNotFound, m.ErrorResponse{
+			Error:   "channel_not_found",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusBadGateway, m.ErrorResponse{
+		Error:   "webhook_failed",
+		Message: err.Error(),
+	})
+}
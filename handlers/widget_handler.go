@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	mw "github.com/ChrisTheAbysswalker/meownder-backend/middleware"
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+	s "github.com/ChrisTheAbysswalker/meownder-backend/services"
+)
+
+const defaultWidgetInterval = 15
+
+// * Widget embebible para blogs: un iframe que rota gatos. La restricción por origen
+// * la aplica el navegador con frame-ancestors; la API además revisa el Origin
+type WidgetHandler struct {
+	service *s.CatService
+	keys    *s.WidgetKeys
+	baseURL string
+}
+
+func NewWidgetHandler(service *s.CatService, keys *s.WidgetKeys, baseURL string) *WidgetHandler {
+	return &WidgetHandler{
+		service: service,
+		keys:    keys,
+		baseURL: strings.TrimRight(baseURL, "/"),
+	}
+}
+
+func (h *WidgetHandler) GetWidget(c *gin.Context) {
+	var query m.WidgetQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	origins, err := h.origins(c, query.Key)
+	if err != nil {
+		respondWidgetError(c, err)
+		return
+	}
+
+	interval := query.Interval
+	if interval == 0 {
+		interval = defaultWidgetInterval
+	}
+
+	nonce := make([]byte, 16)
+	rand.Read(nonce)
+	page := m.WidgetPage{
+		Key:      query.Key,
+		Interval: interval,
+		Nonce:    base64.StdEncoding.EncodeToString(nonce),
+		BaseURL:  h.baseURL,
+	}
+
+	// * Esta ruta sí se puede incrustar: fuera X-Frame-Options, y la CSP solo permite
+	// * los orígenes de la clave como padres del iframe
+	header := c.Writer.Header()
+	header.Del("X-Frame-Options")
+	header.Set("Content-Security-Policy", "default-src 'none'; "+
+		"script-src 'nonce-"+page.Nonce+"'; "+
+		"style-src 'unsafe-inline'; "+
+		"img-src 'self' "+h.baseURL+"; "+
+		"connect-src 'self'; "+
+		"frame-ancestors "+strings.Join(origins, " "))
+	c.HTML(http.StatusOK, "widget.html", page)
+}
+
+func (h *WidgetHandler) GetWidgetCat(c *gin.Context) {
+	var query m.WidgetCatQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	// * Un token con alcance widget ya lo validó el middleware, origen incluido
+	if _, ok := mw.TokenFrom(c); !ok {
+		if err := h.keys.Check(query.Key, c.GetHeader("Origin")); err != nil {
+			respondWidgetError(c, err)
+			return
+		}
+	}
+
+	cat, err := tenantCats(c, h.service).WidgetCat(query.Except, h.baseURL, tenantID(c))
+	if err != nil {
+		c.JSON(http.StatusNotFound, m.ErrorResponse{
+			Error:   "no_profiles_found",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, cat)
+}
+
+// * Orígenes que pueden incrustar el widget: los del token o los de la clave fija
+func (h *WidgetHandler) origins(c *gin.Context, key string) ([]string, error) {
+	if grant, ok := mw.TokenFrom(c); ok {
+		return grant.Origins, nil
+	}
+	return h.keys.Origins(key)
+}
+
+func respondWidgetError(c *gin.Context, err error) {
+	code := "invalid_widget_key"
+	if errors.Is(err, s.ErrWidgetOrigin) {
+		code = "origin_not_allowed"
+	}
+	c.JSON(http.StatusForbidden, m.ErrorResponse{
+		Error:   code,
+		Message: err.Error(),
+	})
+}
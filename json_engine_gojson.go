@@ -0,0 +1,6 @@
+//go:build go_json
+
+package main
+
+// * Con -tags=go_json gin serializa con goccy/go-json (más rápido en listas de perfiles)
+const jsonEngine = "goccy/go-json"
@@ -0,0 +1,5 @@
+//go:build !go_json
+
+package main
+
+const jsonEngine = "encoding/json"
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/render"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+)
+
+// * Serialización de las respuestas más pesadas con el motor de gin elegido al compilar:
+// *   go test -run=^$ -bench=JSON -benchmem .               (encoding/json)
+// *   go test -run=^$ -bench=JSON -benchmem -tags=go_json . (goccy/go-json)
+
+// * Perfiles de cats.json repetidos hasta n, con los campos opcionales que suele traer
+// * un listado real (etiquetas, metadatos de imagen, paleta)
+func benchProfiles(b *testing.B, n int) []m.CatProfile {
+	b.Helper()
+	data, err := os.ReadFile("cats.json")
+	if err != nil {
+		b.Fatal(err)
+	}
+	var file struct {
+		Cats []m.CatProfile `json:"cats"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		b.Fatal(err)
+	}
+
+	created := time.Date(2026, 1, 15, 10, 0, 0, 0, time.UTC)
+	profiles := make([]m.CatProfile, n)
+	for i := range profiles {
+		cat := file.Cats[i%len(file.Cats)].Clone()
+		cat.ID = i + 1
+		cat.Img = fmt.Sprintf("https://cdn2.thecatapi.com/images/%07d.jpg", i)
+		cat.Status = m.StatusActive
+		cat.Tags = []string{"gato-de-regazo", "senior"}
+		cat.UpdatedAt = created.Add(time.Duration(i) * time.Minute)
+		cat.CreatedAt = &created
+		cat.ImageMeta = &m.ImageMeta{Width: 800, Height: 600, ContentType: "image/jpeg", Format: "jpeg", Bytes: 84213}
+		cat.Palette = []string{"#d9a066", "#4b3621", "#f5f0e6"}
+		profiles[i] = cat
+	}
+	return profiles
+}
+
+// * Descarta lo escrito; solo cuenta bytes
+type discardWriter struct {
+	header  http.Header
+	written int
+}
+
+func (w *discardWriter) Header() http.Header { return w.header }
+func (w *discardWriter) WriteHeader(int)     {}
+func (w *discardWriter) Write(p []byte) (int, error) {
+	w.written += len(p)
+	return len(p), nil
+}
+
+func benchmarkRender(b *testing.B, payload any) {
+	b.Run(jsonEngine, func(b *testing.B) {
+		w := &discardWriter{header: http.Header{}}
+		if err := (render.JSON{Data: payload}).Render(w); err != nil {
+			b.Fatal(err)
+		}
+		b.SetBytes(int64(w.written))
+		b.ReportAllocs()
+		b.ResetTimer()
+		for b.Loop() {
+			if err := (render.JSON{Data: payload}).Render(w); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+// * GET /api/deck con el límite máximo
+func BenchmarkDeckJSON(b *testing.B) {
+	benchmarkRender(b, m.DeckResponse{Cats: benchProfiles(b, 50), Count: 50, Seed: 424242})
+}
+
+// * GET /api/profiles con una página grande, igual que CatHandler.GetCatProfiles
+func BenchmarkProfileListJSON(b *testing.B) {
+	profiles := benchProfiles(b, 500)
+	benchmarkRender(b, gin.H{
+		"cats":  profiles,
+		"count": len(profiles),
+		"total": len(profiles),
+		"page":  1,
+		"limit": len(profiles),
+	})
+}
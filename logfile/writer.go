@@ -0,0 +1,234 @@
+// Package logfile escribe logs a disco con rotación por tamaño y limpieza de los
+// archivos rotados por antigüedad y cantidad, para despliegues sin recolector de logs.
+package logfile
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+type Options struct {
+	// * Tamaño a partir del cual se rota; 0 = nunca
+	MaxSize int64
+	// * Rotados más viejos que esto se borran; 0 = sin límite de edad
+	MaxAge time.Duration
+	// * Cuántos rotados se conservan; 0 = todos
+	MaxBackups int
+	Compress   bool
+}
+
+// * io.Writer seguro para usar desde varios goroutines (log.SetOutput)
+type Writer struct {
+	path string
+	opts Options
+
+	file  *os.File
+	size  int64
+	mutex sync.Mutex
+
+	// * Una sola limpieza a la vez; las que llegan mientras tanto se acumulan en una
+	cleanup chan struct{}
+}
+
+func Open(path string, opts Options) (*Writer, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("error creando directorio de logs: %w", err)
+	}
+
+	w := &Writer{
+		path:    path,
+		opts:    opts,
+		cleanup: make(chan struct{}, 1),
+	}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+
+	go w.cleanupLoop()
+	w.requestCleanup()
+	return w, nil
+}
+
+func (w *Writer) Write(p []byte) (int, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.file == nil {
+		return 0, os.ErrClosed
+	}
+	if w.opts.MaxSize > 0 && w.size > 0 && w.size+int64(len(p)) > w.opts.MaxSize {
+		if err := w.rotate(); err != nil {
+			// * Mejor seguir escribiendo en el archivo grande que perder líneas
+			fmt.Fprintf(os.Stderr, "⚠️ Error rotando %s: %v\n", w.path, err)
+		}
+	}
+
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+func (w *Writer) Close() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.file == nil {
+		return nil
+	}
+	err := w.file.Close()
+	w.file = nil
+	return err
+}
+
+func (w *Writer) open() error {
+	file, err := os.OpenFile(w.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("error abriendo %s: %w", w.path, err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	w.file = file
+	w.size = info.Size()
+	return nil
+}
+
+// ! Llamar con mutex tomado
+func (w *Writer) rotate() error {
+	if err := w.file.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(w.path, w.backupName(time.Now())); err != nil {
+		// * Sin rotar no queda otra que reabrir el mismo archivo
+		if openErr := w.open(); openErr != nil {
+			return openErr
+		}
+		return err
+	}
+	if err := w.open(); err != nil {
+		return err
+	}
+	w.requestCleanup()
+	return nil
+}
+
+// * app.log -> app-2026-10-16T15-04-05.000.log
+func (w *Writer) backupName(at time.Time) string {
+	ext := filepath.Ext(w.path)
+	base := strings.TrimSuffix(w.path, ext)
+	return base + "-" + at.Format(backupTimeFormat) + ext
+}
+
+func (w *Writer) requestCleanup() {
+	select {
+	case w.cleanup <- struct{}{}:
+	default:
+	}
+}
+
+func (w *Writer) cleanupLoop() {
+	for range w.cleanup {
+		if err := w.cleanBackups(); err != nil {
+			log.Printf("⚠️ Error limpiando logs rotados: %v", err)
+		}
+	}
+}
+
+type backup struct {
+	path string
+	at   time.Time
+}
+
+func (w *Writer) cleanBackups() error {
+	backups, err := w.backups()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for i, b := range backups {
+		expired := w.opts.MaxAge > 0 && now.Sub(b.at) > w.opts.MaxAge
+		excess := w.opts.MaxBackups > 0 && i >= w.opts.MaxBackups
+		if expired || excess {
+			if err := os.Remove(b.path); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			continue
+		}
+		if w.opts.Compress && !strings.HasSuffix(b.path, ".gz") {
+			if err := compress(b.path); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// * Rotados del más nuevo al más viejo, comprimidos o no
+func (w *Writer) backups() ([]backup, error) {
+	dir := filepath.Dir(w.path)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	ext := filepath.Ext(w.path)
+	prefix := strings.TrimSuffix(filepath.Base(w.path), ext) + "-"
+	var backups []backup
+	for _, entry := range entries {
+		name := entry.Name()
+		stamp, ok := strings.CutPrefix(name, prefix)
+		if !ok || entry.IsDir() {
+			continue
+		}
+		stamp = strings.TrimSuffix(strings.TrimSuffix(stamp, ".gz"), ext)
+		at, err := time.ParseInLocation(backupTimeFormat, stamp, time.Local)
+		if err != nil {
+			continue
+		}
+		backups = append(backups, backup{path: filepath.Join(dir, name), at: at})
+	}
+
+	sort.Slice(backups, func(i, j int) bool { return backups[i].at.After(backups[j].at) })
+	return backups, nil
+}
+
+func compress(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	gz := gzip.NewWriter(dst)
+	if _, err := io.Copy(gz, src); err != nil {
+		dst.Close()
+		os.Remove(path + ".gz")
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		dst.Close()
+		os.Remove(path + ".gz")
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
@@ -1,69 +1,853 @@
 package main
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
+	"html/template"
+	"io"
 	"log"
 	"os"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
+	"github.com/ChrisTheAbysswalker/meownder-backend/config"
 	h "github.com/ChrisTheAbysswalker/meownder-backend/handlers"
+	"github.com/ChrisTheAbysswalker/meownder-backend/logfile"
+	mw "github.com/ChrisTheAbysswalker/meownder-backend/middleware"
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+	"github.com/ChrisTheAbysswalker/meownder-backend/ratelimit"
+	"github.com/ChrisTheAbysswalker/meownder-backend/redis"
+	"github.com/ChrisTheAbysswalker/meownder-backend/retry"
+	"github.com/ChrisTheAbysswalker/meownder-backend/scheduler"
+	"github.com/ChrisTheAbysswalker/meownder-backend/schemas"
+	"github.com/ChrisTheAbysswalker/meownder-backend/search"
+	"github.com/ChrisTheAbysswalker/meownder-backend/sentry"
+	"github.com/ChrisTheAbysswalker/meownder-backend/server"
+	"github.com/ChrisTheAbysswalker/meownder-backend/storage"
 	s "github.com/ChrisTheAbysswalker/meownder-backend/services"
+	"github.com/ChrisTheAbysswalker/meownder-backend/telegram"
 )
 
 func main() {
 	gin.SetMode(gin.ReleaseMode)
 
-	router := gin.Default()
+	cfg := config.Load()
+	var configFile map[string]string
+	if cfg.File != "" {
+		var err error
+		if cfg, configFile, err = config.LoadFile(cfg.File); err != nil {
+			log.Fatal("Error en CONFIG_FILE: ", err)
+		}
+	}
+
+	if cfg.Logging.File != "" {
+		logFile, err := logfile.Open(cfg.Logging.File, logfile.Options{
+			MaxSize:    int64(cfg.Logging.MaxSizeMB) << 20,
+			MaxAge:     cfg.Logging.MaxAge,
+			MaxBackups: cfg.Logging.MaxBackups,
+			Compress:   cfg.Logging.Compress,
+		})
+		if err != nil {
+			log.Fatal("Error en LOG_FILE: ", err)
+		}
+		var output io.Writer = logFile
+		if cfg.Logging.Console {
+			output = io.MultiWriter(os.Stderr, logFile)
+		}
+		// * gin escribe por su cuenta las pilas de los panics recuperados
+		log.SetOutput(output)
+		gin.DefaultErrorWriter = output
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrateCommand(cfg.Database, os.Args[2:]); err != nil {
+			log.Fatal("Error en migrate: ", err)
+		}
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "simulate" {
+		if err := runSimulateCommand(cfg.Matching.MatchProbability, os.Args[2:]); err != nil {
+			log.Fatal("Error en simulate: ", err)
+		}
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "check" {
+		if err := runCheckCommand(cfg, os.Args[2:]); err != nil {
+			log.Fatal("Error en check: ", err)
+		}
+		return
+	}
+
+	// * En staging los problemas solo se avisan; en prod (o con APP_ENV inválido) no arranca
+	if err := cfg.Validate(); err != nil {
+		if cfg.Env != config.EnvStaging {
+			log.Fatalf("Configuración inválida para APP_ENV=%s:\n%v", cfg.Env, err)
+		}
+		log.Printf("⚠️ Configuración riesgosa para APP_ENV=%s: %v", cfg.Env, err)
+	}
+	log.Printf("🌱 Entorno %s", cfg.Env)
+
+	if cfg.Database.URL != "" && cfg.Database.MigrateOnStart {
+		if err := migrateOnStart(cfg.Database); err != nil {
+			log.Fatal("Error aplicando migraciones: ", err)
+		}
+	}
+
+	var reporter *sentry.Client
+	if cfg.Sentry.DSN != "" {
+		var err error
+		if reporter, err = sentry.New(cfg.Sentry.DSN, cfg.Sentry.Environment, cfg.Sentry.Release); err != nil {
+			log.Fatal("Error en SENTRY_DSN:", err)
+		}
+		log.Printf("🛰️ Reporte de errores activo (entorno %s, release %s)", cfg.Sentry.Environment, reporter.Release())
+	}
+
+	router := gin.New()
+	router.RemoteIPHeaders = cfg.Proxy.RemoteIPHeaders
+	if err := router.SetTrustedProxies(cfg.Proxy.TrustedProxies); err != nil {
+		log.Fatal("Error configurando proxies de confianza:", err)
+	}
+
+	logLevel, err := mw.NewLogLevel(cfg.Logging.Level)
+	if err != nil {
+		log.Fatal("Error en LOG_LEVEL:", err)
+	}
+	features, err := s.NewFeatureFlags(cfg.Features.Flags)
+	if err != nil {
+		log.Fatal("Error en FEATURE_FLAGS:", err)
+	}
+	jsonCase, err := mw.ParseJSONCase(cfg.JSON.Case)
+	if err != nil {
+		log.Fatal("Error en JSON_CASE:", err)
+	}
+
+	router.Use(mw.RealIP())
+	router.Use(mw.RequestLog(cfg.Logging, logLevel, s.APITokenPrefix), mw.Recovery(reporter))
+	router.Use(mw.RequestLimits(cfg.Limits, map[string]int64{
+		"/api/admin/cats/import": h.MaxImportBytes,
+		"/api/admin/restore":     h.MaxRestoreBytes,
+	}))
+	router.Use(corsMiddleware(cfg.CORS.AllowedOrigins))
+	router.Use(mw.SecurityHeaders(cfg.Security))
+	router.Use(mw.JSONCase(jsonCase))
+
+	var redisClient *redis.Client
+	if cfg.Redis.URL != "" {
+		var err error
+		redisClient, err = redis.New(cfg.Redis.URL, 16, 500*time.Millisecond)
+		if err != nil {
+			log.Fatal("Error en REDIS_URL:", err)
+		}
+		// * Que no responda al arrancar no es fatal: límites y locks reintentan en cada uso
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		if err := redisClient.Ping(ctx); err != nil {
+			log.Printf("⚠️ Redis no responde: %v", err)
+		}
+		cancel()
+	}
+
+	limiters, err := ratelimit.NewFactory(cfg.RateLimit.Backend, redisClient)
+	if err != nil {
+		log.Fatal("Error en RATE_LIMIT_BACKEND:", err)
+	}
+
+	retryPolicy := retry.NewPolicy(cfg.Retry.BaseDelay, cfg.Retry.MaxDelay, cfg.Retry.Jitter, cfg.Retry.MaxAttempts)
+	stats := s.NewStatsCollector()
+	router.Use(mw.Metrics(stats))
+
+	var chaos *s.Chaos
+	if cfg.Chaos.Enabled {
+		chaos = s.NewChaos()
+		router.Use(mw.FaultInjection(chaos))
+		log.Println("🐒 Modo caos disponible en /api/admin/chaos (no usar en producción)")
+	}
+
+	var asnIndex *s.ASNIndex
+	if cfg.Abuse.ASNFile != "" {
+		asnIndex, err = s.LoadASNIndex(cfg.Abuse.ASNFile)
+		if err != nil {
+			log.Fatal("Error en ABUSE_ASN_FILE:", err)
+		}
+		log.Printf("🌐 Índice de ASN cargado: %d rangos", asnIndex.Len())
+	}
+	blocklist := s.NewBlocklist(cfg.Abuse.BlocklistFile, asnIndex)
+	abusePolicy := s.AbusePolicy{
+		BlockScore:     cfg.Abuse.BlockScore,
+		AutoBlockAfter: cfg.Abuse.AutoBlockAfter,
+		AutoBlockTTL:   cfg.Abuse.AutoBlockTTL,
+	}
+	if cfg.Abuse.Scoring {
+		abusePolicy.MaxPerMinute = cfg.Abuse.MaxPerMinute
+	}
+	abuseGuard := s.NewAbuseGuard(abusePolicy, blocklist)
+	// * Las imágenes son lo que más se scrapea: pesan más en el puntaje
+	router.Use(mw.AbuseShield(abuseGuard, map[string]int{
+		"/api/cats":                 3,
+		"/api/profiles/:id/image":   5,
+		s.OfflineCatsPath + ":file": 5,
+	}))
+	pow := s.NewProofOfWork(cfg.Abuse.PowSecret, cfg.Abuse.ClientSecret, cfg.Abuse.PowDifficulty, cfg.Abuse.PowTTL)
+	if pow.Enabled() {
+		log.Printf("⛏️ Prueba de trabajo en rutas de escritura: %d bits", cfg.Abuse.PowDifficulty)
+	}
+	reservoir := s.NewURLReservoir(cfg.Reservoir.Path, cfg.Reservoir.MaxURLs)
+	imageMeta := s.NewImageMetaIndex(2000)
+	stats.Gauge("url_reservoir", reservoir.Size)
+	stats.Gauge("image_meta", imageMeta.Size)
+	liveRanker, err := s.ParseDeckRanker(cfg.Ranking.Live)
+	if err != nil {
+		log.Fatal("Error en DECK_RANKING:", err)
+	}
+	var shadowRanking *s.ShadowRanking
+	if cfg.Ranking.Shadow != "" {
+		candidateRanker, err := s.ParseDeckRanker(cfg.Ranking.Shadow)
+		if err != nil {
+			log.Fatal("Error en DECK_RANKING_SHADOW:", err)
+		}
+		shadowRanking = s.NewShadowRanking(liveRanker, candidateRanker, cfg.Ranking.ShadowSampleRate, cfg.Ranking.ShadowTopK)
+		log.Printf("🌓 Ranking en sombra: %s (en vivo: %s, muestra %.0f%%)", candidateRanker.Name(), liveRanker.Name(), cfg.Ranking.ShadowSampleRate*100)
+	}
+	providerWeights, err := s.ParseProviderWeights(cfg.Providers.Weights)
+	if err != nil {
+		log.Fatal("Error en IMAGE_PROVIDERS:", err)
+	}
+	providerHeaders, err := s.ParseProviderHeaders(cfg.Providers.Headers)
+	if err != nil {
+		log.Fatal("Error en PROVIDER_HEADERS:", err)
+	}
+	providerTransport, err := s.NewProviderTransport(cfg.Providers.ProxyURL, cfg.Providers.UserAgent, providerHeaders)
+	if err != nil {
+		log.Fatal("Error en PROVIDER_PROXY_URL:", err)
+	}
+	if cfg.Providers.DNSCache {
+		dnsCache := s.NewDNSCache(cfg.Providers.DNSMinTTL, cfg.Providers.DNSMaxTTL)
+		stats.Gauge("dns", dnsCache.Size)
+		if err := providerTransport.UseDNSCache(dnsCache, cfg.Providers.IPFamily, cfg.Providers.FallbackDelay); err != nil {
+			log.Fatal("Error en PROVIDER_IP_FAMILY:", err)
+		}
+	}
+	if cfg.Providers.RateLimits != "" {
+		providerLimits, err := s.ParseProviderWeights(cfg.Providers.RateLimits)
+		if err != nil {
+			log.Fatal("Error en PROVIDER_RATE_LIMITS:", err)
+		}
+		if err := providerTransport.SetBudgets(providerLimits, cfg.Providers.MaxQueue); err != nil {
+			log.Fatal("Error en PROVIDER_RATE_LIMITS:", err)
+		}
+	}
+	providers, err := s.NewProviderMix(providerWeights, cfg.Providers.TheCatAPIKey, cfg.BaseURL, providerTransport)
+	if err != nil {
+		log.Fatal("Error configurando proveedores de imágenes:", err)
+	}
+	providerTransport.SetChaos(chaos)
+	providers.SetChaos(chaos)
+	if err := schemas.Check(); err != nil {
+		log.Fatal("Error en los esquemas de eventos: ", err)
+	}
+	var db *sql.DB
+	if cfg.Database.URL != "" {
+		if db, err = storage.Open(cfg.Database.Driver, cfg.Database.URL); err != nil {
+			log.Fatal("Error conectando a la base de datos: ", err)
+		}
+	}
+
+	var icebreakerLLM s.IcebreakerGenerator
+	if cfg.Icebreakers.LLMURL != "" {
+		icebreakerLLM = s.NewLLMIcebreakers(s.NewOpenAIClient(cfg.Icebreakers.LLMURL, cfg.Icebreakers.LLMAPIKey, cfg.Icebreakers.LLMModel))
+	}
+	icebreakers := s.NewIcebreakerService(icebreakerLLM, cfg.Icebreakers.Timeout)
+
+	tenantSpecs, err := s.LoadTenantSpecs(cfg.Tenants.File)
+	if err != nil {
+		log.Fatal("Error cargando tenants: ", err)
+	}
+	var stableImages *s.StableImages
+	if cfg.StableImages.Enabled {
+		stableImages, err = s.NewStableImages(cfg.StableImages.Dir)
+		if err != nil {
+			log.Fatal("Error cargando imágenes fijas: ", err)
+		}
+	}
+	searchBoosts, err := search.ParseBoosts(cfg.Search.Boosts)
+	if err != nil {
+		log.Fatal("Error en SEARCH_BOOSTS:", err)
+	}
+	searchFuzziness, err := search.ParseFuzziness(cfg.Search.Fuzziness)
+	if err != nil {
+		log.Fatal("Error en SEARCH_FUZZINESS:", err)
+	}
+	newSearchIndex := func(tenantID string) (search.Index, error) {
+		switch cfg.Search.Backend {
+		case "memory":
+			return search.NewMemory(searchBoosts, searchFuzziness), nil
+		case "elasticsearch":
+			if cfg.Search.ElasticURL == "" {
+				return nil, fmt.Errorf("SEARCH_BACKEND=elasticsearch requiere SEARCH_ELASTIC_URL")
+			}
+			return search.NewElasticsearch(cfg.Search.ElasticURL, cfg.Search.ElasticIndex+"-"+tenantID, searchBoosts, searchFuzziness, cfg.Search.Timeout)
+		}
+		return nil, fmt.Errorf("SEARCH_BACKEND desconocido: %s", cfg.Search.Backend)
+	}
+	var profileSearches []*s.ProfileSearch
+	if cfg.Search.Backend != "" {
+		log.Printf("🔎 Búsqueda de texto con índice %s", cfg.Search.Backend)
+	}
+	tenants := s.NewTenantRegistry()
+	for _, spec := range append([]m.TenantSpec{{
+		ID:            s.DefaultTenantID,
+		Name:          "Meownder",
+		ProfilesFile:  "cats.json",
+		AdminKey:      cfg.Admin.APIKey,
+		SigningSecret: cfg.SignedRequests.Secret,
+	}}, tenantSpecs...) {
+		tenantCats := s.NewCatService(spec.ProfilesFile, reservoir, retryPolicy, providers, imageMeta, stats, reporter)
+		if stableImages != nil {
+			tenantCats.SetStableImages(stableImages, spec.ID)
+		}
+		var swipeStore s.SwipeStore
+		if db != nil {
+			swipeStore = storage.NewSQLSwipeStore(db, cfg.Database.Driver, spec.ID)
+		}
+		tenantSwipes := s.NewSwipeService(tenantCats, cfg.Matching.MatchProbability, s.NewRandSource(uint64(time.Now().UnixNano())), swipeStore, icebreakers)
+		tenantSwipes.SetRanking(liveRanker, shadowRanking)
+		tenantSwipes.SetSponsors(s.NewSponsorRotation(cfg.Sponsored.Every, cfg.Sponsored.MaxPerDeck))
+		tenantSwipes.SetDeckPrefetch(s.NewDeckPrefetch(cfg.DeckPrefetch.TTL, cfg.DeckPrefetch.Validation))
+		tenantSwipes.EnableDeckChanges(cfg.DeckChanges.Retain)
+		if cfg.Search.Backend != "" {
+			index, err := newSearchIndex(spec.ID)
+			if err != nil {
+				log.Fatal("Error en SEARCH_BACKEND: ", err)
+			}
+			// * No va al scheduler: con SCHEDULER_LOCK correría en una sola réplica y cada
+			// * índice en memoria necesita su propia sincronización
+			profileSearch := s.NewProfileSearch(tenantCats, index, cfg.Search.MaxHits, cfg.Search.Timeout)
+			profileSearch.Start(context.Background(), cfg.Search.SyncInterval)
+			tenantCats.SetSearch(profileSearch)
+			profileSearches = append(profileSearches, profileSearch)
+		}
+		if cfg.SwipeFraud.Enabled {
+			tenantSwipes.SetFraudDetector(s.NewSwipeFraudDetector(s.SwipeFraudPolicy{
+				MaxLikesPerMinute: cfg.SwipeFraud.MaxLikesPerMinute,
+				UniformWindow:     cfg.SwipeFraud.UniformWindow,
+				MaxUniformity:     cfg.SwipeFraud.MaxUniformity,
+				Cooldown:          cfg.SwipeFraud.Cooldown,
+			}))
+		}
+		if err := tenantSwipes.Restore(context.Background()); err != nil {
+			log.Fatal("Error restaurando swipes de ", spec.ID, ": ", err)
+		}
+		tenantChat := s.NewChatService(tenantSwipes)
+		tenants.Add(&s.Tenant{
+			ID:            spec.ID,
+			Name:          spec.Name,
+			AdminKey:      spec.AdminKey,
+			SigningSecret: spec.SigningSecret,
+			Cats:          tenantCats,
+			Swipes:        tenantSwipes,
+			Chat:          tenantChat,
+			Badges:        s.NewBadgeService(tenantSwipes, tenantCats, tenantChat),
+			Quests:        s.NewQuestService(tenantSwipes, tenantCats, tenantChat),
+		})
+	}
+	log.Printf("🏠 Tenants cargados: %d", len(tenants.All()))
+	router.Use(h.TenantScope(tenants, cfg.Tenants.Domain))
+	apiTokens := s.NewAPITokens(cfg.APITokens.File)
+	router.Use(mw.ScopedTokens(apiTokens, s.APITokenPrefix))
+
+	// * Feed, webhooks, respaldos y Telegram siguen atados al tenant por defecto
+	catService := tenants.Default().Cats
+	swipeService := tenants.Default().Swipes
+	imageQuality, err := s.NewImageQualityPolicy(cfg.ImageQuality.LowSize, cfg.ImageQuality.LowMaxWidth, cfg.ImageQuality.LowJPEGQuality)
+	if err != nil {
+		log.Fatal("Error en IMAGE_LOW_*:", err)
+	}
+	imageProxy := s.NewImageProxy(retryPolicy, imageMeta, imageQuality, providerTransport)
+	imageProxy.SetStableImages(stableImages)
+	stats.Gauge("image_proxy", imageProxy.CacheSize)
+	stats.Gauge("validated_pool", func() int {
+		total := 0
+		for _, tenant := range tenants.All() {
+			total += tenant.Cats.ValidatedPoolSize()
+		}
+		return total
+	})
+	if cfg.Alerts.WebhookURL != "" {
+		notifier, err := s.NewAlertNotifier(cfg.Alerts.WebhookURL, cfg.Alerts.WebhookKind)
+		if err != nil {
+			log.Fatal("Error en ALERT_WEBHOOK_URL:", err)
+		}
+		// * El mix de proveedores es compartido: con los circuitos del tenant por defecto alcanza
+		monitor := s.NewAlertMonitor(stats, catService.OpenCircuits, s.AlertThresholds{
+			ErrorRate:   cfg.Alerts.ErrorRate,
+			MinRequests: int64(cfg.Alerts.MinRequests),
+			P99:         cfg.Alerts.P99,
+			Window:      cfg.Alerts.Window,
+		}, notifier, cfg.Alerts.Cooldown)
+		go monitor.Run(context.Background(), cfg.Alerts.Interval)
+		log.Printf("🚨 Monitor de alertas activo (%s, cada %s)", notifier.Kind(), cfg.Alerts.Interval)
+	}
+	readiness := s.NewReadiness()
+	feedService := s.NewFeedService(catService, cfg.BaseURL)
+	var linkStore s.LinkStore
+	if db != nil {
+		linkStore = storage.NewSQLLinkStore(db, cfg.Database.Driver)
+	}
+	links := s.NewLinkService(cfg.BaseURL, linkStore)
+	if err := links.Restore(context.Background()); err != nil {
+		log.Fatal("Error restaurando enlaces cortos: ", err)
+	}
+	webhookService := s.NewWebhookService(catService, links, cfg.BaseURL, cfg.Webhooks.File, initialWebhookChannels(cfg.Webhooks), cfg.Webhooks.SecretGrace)
+
+	themes, err := s.LoadThemes(cfg.Themes.File)
+	if err != nil {
+		log.Fatal("Error cargando temas: ", err)
+	}
+
+	freshnessPolicy := s.FreshnessPolicy{
+		DatasetMaxAge: cfg.Health.DatasetMaxAge,
+		ProbeMaxAge:   cfg.Health.ProbeMaxAge,
+	}
+	catHandler := h.NewCatHandler(catService, themes, imageQuality, cfg.WarmUp.ValidationDeadline, freshnessPolicy)
+	themeHandler := h.NewThemeHandler(themes)
+	collections := s.NewCollectionService(cfg.Collections.File)
+	collectionHandler := h.NewCollectionHandler(collections, catService, swipeService, imageQuality)
+	widgetKeys, err := s.ParseWidgetKeys(cfg.Widget.Keys)
+	if err != nil {
+		log.Fatal("Error en WIDGET_KEYS: ", err)
+	}
+	widgetHandler := h.NewWidgetHandler(catService, widgetKeys, cfg.BaseURL)
+	tokenHandler := h.NewTokenHandler(apiTokens)
+	imageHandler := h.NewImageHandler(catService, imageProxy)
+	shareHandler := h.NewShareHandler(catService, links, cfg.BaseURL, cfg.Share.DeepLinkBase)
+	linkHandler := h.NewLinkHandler(links, catService)
+	horoscopeHandler := h.NewHoroscopeHandler(catService)
+	profileChangesHandler := h.NewProfileChangesHandler(catService, imageQuality)
+	quizHandler := h.NewQuizHandler(swipeService)
+	compatibilityHandler := h.NewCompatibilityHandler(cfg.Compatibility.CacheTTL)
+	feedHandler := h.NewFeedHandler(feedService)
+	webhookHandler := h.NewWebhookHandler(webhookService)
+	// * La petición sostenida tiene que terminar antes de que el servidor corte la escritura
+	changesHold := cfg.DeckChanges.EffectiveHold(cfg.Server.WriteTimeout)
+	if changesHold < cfg.DeckChanges.Hold {
+		log.Printf("⚠️ DECK_CHANGES_HOLD recortado a %s por SERVER_WRITE_TIMEOUT", changesHold)
+	}
+	swipeHandler := h.NewSwipeHandler(swipeService, imageQuality, changesHold)
+	capabilities := s.NewClientCapabilities(imageQuality, cfg.ImageQuality.CapabilitiesTTL)
+	capabilitiesHandler := h.NewCapabilitiesHandler(capabilities)
+	chatHandler := h.NewChatHandler(tenants.Default().Chat)
+	badgeHandler := h.NewBadgeHandler(tenants.Default().Badges, tenants.Default().Quests)
+	var textGen s.TextGenProvider
+	if cfg.TextGen.LLMURL != "" {
+		textGen = s.NewLLMTextGen(s.NewOpenAIClient(cfg.TextGen.LLMURL, cfg.TextGen.LLMAPIKey, cfg.TextGen.LLMModel))
+	}
+	textWriter := s.NewTextWriter(textGen, cfg.TextGen.RatePerMinute, cfg.TextGen.Timeout)
+	jobManager := s.NewJobManager(cfg.Jobs.Workers, cfg.Jobs.Dir, cfg.Jobs.Retention, "/api/admin/jobs/")
+	if cfg.Jobs.WebhookURL != "" {
+		notifier, err := s.NewAlertNotifier(cfg.Jobs.WebhookURL, cfg.Jobs.WebhookKind)
+		if err != nil {
+			log.Fatal("Error en JOBS_WEBHOOK_URL:", err)
+		}
+		jobManager.OnFinish(func(job m.Job) {
+			ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+			defer cancel()
+			if err := notifier.NotifyJob(ctx, job); err != nil {
+				log.Printf("⚠️ Error avisando el fin del trabajo %s: %v", job.ID, err)
+			}
+		})
+	}
+	jobHandler := h.NewJobHandler(jobManager)
+	adminCatHandler := h.NewAdminCatHandler(catService, textWriter, jobManager)
+	backupService := s.NewBackupService(catService, swipeService, cfg.Backup.Dir, cfg.Backup.Keep)
+	backupHandler := h.NewBackupHandler(backupService, jobManager)
+	catService.OnTransition(webhookService.AnnounceAdoption)
+	readinessHandler := h.NewReadinessHandler(readiness, catService, freshnessPolicy)
+	statsHandler := h.NewStatsHandler(stats)
 
-	router.Use(corsMiddleware())
+	var mailer s.Mailer = s.LogMailer{}
+	if cfg.Digest.SMTPHost != "" {
+		smtpMailer, err := s.NewSMTPMailer(cfg.Digest.SMTPHost, cfg.Digest.SMTPPort, cfg.Digest.SMTPUsername, cfg.Digest.SMTPPassword, cfg.Digest.From)
+		if err != nil {
+			log.Fatal("Error configurando SMTP: ", err)
+		}
+		mailer = smtpMailer
+	}
+	digestTemplate, err := template.ParseFiles("templates/digest.html")
+	if err != nil {
+		log.Fatal("Error cargando plantilla del resumen: ", err)
+	}
+	digestService := s.NewDigestService(tenants, mailer, digestTemplate, cfg.BaseURL)
+	digestHandler := h.NewDigestHandler(digestService)
+	var catVerifier *s.OwnedCatVerifier
+	if cfg.OwnedCats.Verify {
+		verifyTemplate, err := template.ParseFiles("templates/verify_cat.html")
+		if err != nil {
+			log.Fatal("Error cargando plantilla de confirmación: ", err)
+		}
+		catVerifier = s.NewOwnedCatVerifier(tenants, mailer, verifyTemplate, cfg.BaseURL, cfg.OwnedCats.VerifyTTL, cfg.OwnedCats.Review)
+	}
+	myCatsHandler := h.NewMyCatsHandler(catService, catVerifier)
 
-	catService := s.NewCatService()
+	go s.WarmUp(context.Background(), catService, imageProxy, s.WarmUpOptions{
+		URLs:     cfg.WarmUp.URLs,
+		Prefetch: cfg.WarmUp.Prefetch,
+		Timeout:  cfg.WarmUp.Timeout,
+	}, readiness)
 
-	catHandler := h.NewCatHandler(catService)
+	responseCache := mw.NewResponseCache(cfg.Cache.MaxEntries)
+	for _, tenant := range tenants.All() {
+		tenant.Cats.OnReload(responseCache.Purge)
+	}
+	collections.OnChange(responseCache.Purge)
+	for _, profileSearch := range profileSearches {
+		profileSearch.OnIndexed(responseCache.Purge)
+	}
+	profilesCache := responseCache.Cache(cfg.Cache.ProfilesTTL)
 
-	api := router.Group("/api")
+	jobs := scheduler.New()
+	jobs.SetReporter(reporter)
+	switch cfg.Scheduler.Lock {
+	case "":
+	case "redis":
+		if redisClient == nil {
+			log.Fatal("SCHEDULER_LOCK=redis requiere REDIS_URL")
+		}
+		jobs.SetLocker(scheduler.NewRedisLocker(redisClient, "meownder:jobs", cfg.Scheduler.LockTTL))
+	case "database":
+		if db == nil {
+			log.Fatal("SCHEDULER_LOCK=database requiere DATABASE_URL")
+		}
+		jobs.SetLocker(storage.NewSQLJobLocker(db, cfg.Database.Driver, scheduler.Owner(), cfg.Scheduler.LockTTL))
+	default:
+		log.Fatal("SCHEDULER_LOCK desconocido: ", cfg.Scheduler.Lock)
+	}
+	jobs.Daily("cat-of-the-day", cfg.Webhooks.CatOfDayHour, cfg.Webhooks.CatOfDayMin, webhookService.PostCatOfTheDay)
+	jobs.Every("revalidate-reservoir", cfg.Reservoir.RefreshInterval, func(ctx context.Context) error {
+		for _, tenant := range tenants.All() {
+			tenant.Cats.PrevalidateURLs(ctx, cfg.WarmUp.URLs, 5*time.Second)
+		}
+		return nil
+	})
+	jobs.Every("prune-profile-images", cfg.Reservoir.PruneInterval, func(ctx context.Context) error {
+		for _, tenant := range tenants.All() {
+			if err := tenant.Cats.PruneBrokenImages(ctx, 5*time.Second); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if cfg.Backup.Interval > 0 {
+		jobs.Every("backup", cfg.Backup.Interval, func(ctx context.Context) error {
+			_, err := backupService.SaveToDir()
+			return err
+		})
+	}
+	if cfg.Matching.ExpiryInterval > 0 {
+		jobs.Every("expire-stale-matches", cfg.Matching.ExpiryInterval, func(ctx context.Context) error {
+			for _, tenant := range tenants.All() {
+				if err := tenant.Chat.ExpireStaleMatches(ctx, cfg.Matching.NudgeAfter, cfg.Matching.ArchiveAfter); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+	if catVerifier != nil {
+		jobs.Every("expire-cat-verifications", cfg.OwnedCats.ExpiryInterval, catVerifier.ExpirePending)
+	}
+	digestWeekday, ok := s.ParseWeekday(cfg.Digest.Weekday)
+	if !ok {
+		log.Fatal("DIGEST_WEEKDAY inválido: ", cfg.Digest.Weekday)
+	}
+	jobs.Weekly("weekly-digest", digestWeekday, cfg.Digest.Hour, cfg.Digest.Minute, func(ctx context.Context) error {
+		digestService.SendAll(ctx, time.Now())
+		return nil
+	})
+	if db != nil {
+		jobs.Every("outbox-relay", cfg.Database.OutboxInterval, storage.NewRelay(db, cfg.Database.Driver, webhookService).RelayOnce)
+	}
+	jobs.Start(context.Background())
+
+	if cfg.Telegram.BotToken != "" {
+		go telegram.NewBot(cfg.Telegram.BotToken, swipeService).Run(context.Background())
+	}
+
+	reloader := newConfigReloader(cfg, configFile, logLevel, limiters, features, providers)
+	reloader.watchSignals()
+	configHandler := h.NewConfigHandler(reloader)
+	abuseHandler := h.NewAbuseHandler(abuseGuard, blocklist, pow)
+	writeProof := mw.WriteProof(pow, s.PowHeader, s.ClientSigHeader)
+
+	router.LoadHTMLGlob("templates/*.html")
+
+	api := router.Group("/api", h.ClientMedia(capabilities))
 	{
-		api.GET("/cats", catHandler.GetCats)
+		api.GET("/cats", mw.ConcurrencyLimit(cfg.LoadShed.MaxCatsInFlight, cfg.LoadShed.RetryAfter), catHandler.GetCats)
 		api.GET("/health", catHandler.Health)
-		api.GET("/profiles", catHandler.GetCatProfiles)
-		api.GET("/profiles/:id", catHandler.GetCatProfileByID)
+		api.GET("/stats", statsHandler.GetStats)
+		api.GET("/ready", readinessHandler.Ready)
+		api.GET("/challenge", abuseHandler.GetChallenge)
+		api.GET("/profiles", profilesCache, catHandler.GetCatProfiles)
+		api.GET("/profiles/count", profilesCache, catHandler.CountCatProfiles)
+		api.GET("/profiles/summary", profilesCache, catHandler.GetSummary)
+		api.GET("/tags", profilesCache, catHandler.GetTags)
+		api.GET("/collections", profilesCache, collectionHandler.ListCollections)
+		api.GET("/collections/:slug", profilesCache, collectionHandler.GetCollection)
+		api.GET("/profiles/:id", profilesCache, catHandler.GetCatProfileByID)
+		api.GET("/changes", profileChangesHandler.GetChanges)
+		api.HEAD("/profiles/:id", catHandler.HeadCatProfile)
+		api.GET("/profiles/:id/image", imageHandler.GetProfileImage)
+		api.GET("/profiles/:id/qr", shareHandler.ProfileQR)
+		api.GET("/profiles/:id/horoscope", mw.Feature(features, "horoscope"), horoscopeHandler.GetHoroscope)
 		api.POST("/profiles/refresh", catHandler.RefreshImages)
+		api.GET("/cat-of-the-day", profilesCache, catHandler.GetCatOfTheDay)
+		deckLimit := mw.ConcurrencyLimit(cfg.LoadShed.MaxDeckInFlight, cfg.LoadShed.RetryAfter)
+		api.GET("/next", deckLimit, swipeHandler.GetNextProfile)
+		api.GET("/deck", deckLimit, swipeHandler.GetDeck)
+		api.POST("/deck/prefetch", deckLimit, swipeHandler.PrefetchDeck)
+		// * Fuera de deckLimit: las peticiones sostenidas ocuparían los lugares del mazo
+		api.GET("/deck/changes", swipeHandler.GetDeckChanges)
+		api.GET("/collections/:slug/deck", deckLimit, collectionHandler.GetCollectionDeck)
+		api.GET("/profiles/random", deckLimit, swipeHandler.GetRandomProfile)
+		api.POST("/swipes", writeProof, swipeHandler.Swipe)
+		api.GET("/matches", swipeHandler.GetMatches)
+		api.GET("/matches/archived", swipeHandler.GetArchivedMatches)
+		api.POST("/matches/:id/revive", swipeHandler.ReviveMatch)
+		api.GET("/matches/:id/icebreakers", swipeHandler.GetIcebreakers)
+		chatFeature := mw.Feature(features, "chat")
+		api.GET("/matches/:id/messages", chatFeature, chatHandler.GetMessages)
+		api.POST("/matches/:id/messages", chatFeature, writeProof, chatHandler.SendMessage)
+		api.POST("/matches/:id/read", chatFeature, chatHandler.MarkRead)
+		api.GET("/matches/:id/presence", chatFeature, chatHandler.GetPresence)
+		api.POST("/messages/:id/reactions", chatFeature, writeProof, chatHandler.AddReaction)
+		api.DELETE("/messages/:id/reactions", chatFeature, chatHandler.RemoveReaction)
+		api.GET("/chat/events", chatFeature, chatHandler.Events)
+		api.GET("/me/history", swipeHandler.GetHistory)
+		api.GET("/me/preferences", swipeHandler.GetPreferences)
+		api.PUT("/me/preferences", swipeHandler.UpdatePreferences)
+		api.GET("/me/capabilities", capabilitiesHandler.GetCapabilities)
+		api.POST("/me/capabilities", capabilitiesHandler.DeclareCapabilities)
+		api.DELETE("/me/capabilities", capabilitiesHandler.ForgetCapabilities)
+		api.GET("/me/digest", digestHandler.GetSubscription)
+		api.PUT("/me/digest", digestHandler.Subscribe)
+		api.DELETE("/me/digest", digestHandler.Unsubscribe)
+		api.GET("/me/digest/preview", digestHandler.Preview)
+		api.GET("/digest/unsubscribe", digestHandler.UnsubscribeByToken)
+		api.POST("/digest/unsubscribe", digestHandler.UnsubscribeByToken)
+		api.POST("/links", mw.Feature(features, "links"), writeProof, mw.RateLimit(limiters.New("links", cfg.Links.RatePerMinute)), linkHandler.CreateLink)
+		api.GET("/links/:code", mw.Feature(features, "links"), linkHandler.GetLink)
+		api.GET("/widget/cat", mw.Feature(features, "widget"), widgetHandler.GetWidgetCat)
+		api.GET("/quiz", mw.Feature(features, "quiz"), quizHandler.GetQuiz)
+		api.POST("/quiz/answers", mw.Feature(features, "quiz"), quizHandler.SubmitAnswers)
+		// * Sin cache del servidor: la respuesta varía con Accept-Language; la cachean CDN y navegador
+		api.GET("/compatibility/names", mw.Feature(features, "compatibility"), mw.RateLimit(limiters.New("compatibility", cfg.Compatibility.RatePerMinute)), compatibilityHandler.GetNameCompatibility)
+		api.GET("/me/badges", badgeHandler.GetBadges)
+		api.GET("/me/quests", badgeHandler.GetQuests)
+		api.GET("/me/cats", myCatsHandler.ListCats)
+		api.POST("/me/cats", writeProof, myCatsHandler.AddCat)
+		api.GET("/me/cats/verify", myCatsHandler.VerifyCat)
 	}
 
-	router.GET("/", func(c *gin.Context) {
-		c.File("./public/index.html")
+	adminAuth := mw.AdminAuthFunc(func(c *gin.Context) string {
+		return h.TenantFrom(c).AdminKey
 	})
+	if cfg.Admin.APIKey == "" && !cfg.Admin.RequireKey {
+		// * Solo en dev por defecto; Validate lo rechaza en prod
+		log.Println("⚠️ Rutas de admin sin autenticación (ADMIN_API_KEY vacío, ADMIN_REQUIRE_KEY=false)")
+		adminAuth = func(c *gin.Context) { c.Next() }
+	}
+	signedRequests := mw.SignedRequests(s.NewRequestSignatures(cfg.SignedRequests.Skew), func(c *gin.Context) string {
+		return h.TenantFrom(c).SigningSecret
+	})
+	// * Rutas del catálogo y los swipes de un refugio: abren con la clave del tenant de la petición
+	admin := api.Group("/admin", signedRequests, adminAuth)
+	{
+		admin.GET("/cats", adminCatHandler.ListCats)
+		admin.GET("/cats/export", adminCatHandler.ExportCats)
+		admin.GET("/quality", adminCatHandler.GetQualityReport)
+		admin.POST("/cats/import", adminCatHandler.ImportCats)
+		admin.DELETE("/cats/:id", adminCatHandler.DeleteCat)
+		admin.POST("/cats/:id/restore", adminCatHandler.RestoreCat)
+		admin.POST("/cats/:id/purge", adminCatHandler.PurgeCat)
+		admin.POST("/cats/:id/status", adminCatHandler.SetStatus)
+		admin.POST("/cats/:id/text", adminCatHandler.GenerateText)
+		admin.PUT("/cats/:id/tags", adminCatHandler.SetTags)
+		admin.DELETE("/tags/:tag", adminCatHandler.RemoveTag)
+		admin.PUT("/cats/:id/sponsor", adminCatHandler.Sponsor)
+		admin.DELETE("/cats/:id/sponsor", adminCatHandler.Unsponsor)
+		admin.GET("/sponsored", swipeHandler.GetSponsoredReport)
+		admin.GET("/swipe-flags", swipeHandler.GetSwipeFlags)
+		admin.DELETE("/swipe-flags/:user_id", swipeHandler.ClearSwipeFlag)
+		admin.GET("/collections", collectionHandler.AdminListCollections)
+		admin.PUT("/collections/:slug", collectionHandler.PutCollection)
+		admin.DELETE("/collections/:slug", collectionHandler.DeleteCollection)
+		admin.POST("/refresh-images", adminCatHandler.RefreshImages)
+		admin.GET("/jobs", jobHandler.ListJobs)
+		admin.GET("/jobs/events", jobHandler.Events)
+		admin.GET("/jobs/:id", jobHandler.GetJob)
+		admin.GET("/jobs/:id/download", jobHandler.DownloadJob)
+		admin.POST("/digest/send", digestHandler.SendNow)
+		admin.GET("/ranking/shadow", swipeHandler.GetShadowRanking)
+	}
 
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080" 
+	// ! Estado de todo el servicio o atado al refugio principal (respaldos, webhooks, tokens,
+	// ! configuración, bloqueos, temas, caos): solo con la clave del refugio principal, aunque
+	// ! X-Tenant nombre a otro
+	operatorAuth := mw.AdminAuthFunc(func(*gin.Context) string {
+		return tenants.Default().AdminKey
+	})
+	if cfg.Admin.APIKey == "" && !cfg.Admin.RequireKey {
+		operatorAuth = func(c *gin.Context) { c.Next() }
+	}
+	operator := api.Group("/admin", signedRequests, operatorAuth)
+	{
+		operator.GET("/webhooks", webhookHandler.ListChannels)
+		operator.POST("/webhooks", webhookHandler.CreateChannel)
+		operator.PUT("/webhooks/:id", webhookHandler.UpdateChannel)
+		operator.DELETE("/webhooks/:id", webhookHandler.DeleteChannel)
+		operator.POST("/webhooks/:id/test", webhookHandler.TestChannel)
+		operator.POST("/webhooks/:id/rotate-secret", webhookHandler.RotateSecret)
+		operator.GET("/tokens", tokenHandler.ListTokens)
+		operator.POST("/tokens", tokenHandler.CreateToken)
+		operator.DELETE("/tokens/:id", tokenHandler.RevokeToken)
+		operator.GET("/backup", backupHandler.Download)
+		operator.POST("/restore", backupHandler.Restore)
+		operator.GET("/themes", themeHandler.ListThemes)
+		operator.PUT("/themes/override", themeHandler.SetOverride)
+		operator.DELETE("/themes/override", themeHandler.ClearOverride)
+		operator.GET("/config", configHandler.GetConfig)
+		operator.POST("/config/reload", configHandler.Reload)
+		operator.GET("/blocklist", abuseHandler.ListBlocks)
+		operator.POST("/blocklist", abuseHandler.AddBlock)
+		operator.DELETE("/blocklist/:id", abuseHandler.RemoveBlock)
+		operator.GET("/abuse", abuseHandler.GetStats)
+		if chaos != nil {
+			chaosHandler := h.NewChaosHandler(chaos)
+			operator.GET("/chaos", chaosHandler.GetChaos)
+			operator.PUT("/chaos", chaosHandler.UpdateChaos)
+			operator.DELETE("/chaos", chaosHandler.ResetChaos)
+		}
 	}
 
-	baseURL := os.Getenv("RENDER_EXTERNAL_URL")
-	if baseURL == "" {
-		baseURL = fmt.Sprintf("http://localhost:%s", port)
+	router.GET("/feed.xml", mw.Feature(features, "feed"), feedHandler.GetFeed)
+	eventSchemaHandler := h.NewEventSchemaHandler()
+	router.GET("/events/schemas", eventSchemaHandler.ListSchemas)
+	router.GET("/events/schemas/:topic/:version", eventSchemaHandler.GetSchema)
+	router.GET("/share/:id", mw.HTMLSecurityPolicy(cfg.Security), shareHandler.ShareProfile)
+	router.GET("/widget", mw.Feature(features, "widget"), widgetHandler.GetWidget)
+	router.GET("/l/:code", mw.Feature(features, "links"), linkHandler.Redirect)
+	if providers.Has("offline") {
+		router.GET(s.OfflineCatsPath+":file", imageHandler.GetOfflineCat)
 	}
 
+	router.GET("/", mw.HTMLSecurityPolicy(cfg.Security), responseCache.Cache(cfg.Cache.RootTTL), func(c *gin.Context) {
+		c.File("./public/index.html")
+	})
+
+	baseURL := cfg.BaseURL
+
 	fmt.Printf("🚀 Meownder API corriendo en %s\n", baseURL)
+	fmt.Printf("🧩 Serializador JSON: %s\n", jsonEngine)
 	fmt.Printf("📡 Endpoints disponibles:\n")
 	fmt.Printf("   • GET  %s/api/profiles         - Obtener todos los perfiles de gatos\n", baseURL)
 	fmt.Printf("   • GET  %s/api/profiles/:id     - Obtener perfil por ID\n", baseURL)
 	fmt.Printf("   • POST %s/api/profiles/refresh - Refrescar imágenes\n", baseURL)
+	fmt.Printf("   • GET  %s/api/profiles/:id/image - Imagen actual del perfil (proxy)\n", baseURL)
+	fmt.Printf("   • GET  %s/api/profiles/:id/qr  - Código QR del enlace para compartir\n", baseURL)
+	fmt.Printf("   • GET  %s/api/profiles/:id/horoscope - Horóscopo gatuno del día (?date=, ?locale=es|en|pt)\n", baseURL)
+	fmt.Printf("   • GET  %s/share/:id            - Página para compartir un perfil\n", baseURL)
+	fmt.Printf("   • GET  %s/widget?key=          - Widget embebible (iframe) con gatos rotando\n", baseURL)
+	fmt.Printf("   • POST %s/api/links            - Crear enlace corto (GET /l/:code redirige y cuenta clics)\n", baseURL)
+	fmt.Printf("   • GET  %s/api/next             - Siguiente gato sin ver (X-User-ID)\n", baseURL)
+	fmt.Printf("   • GET  %s/api/deck?seed=42     - Mazo barajado reproducible (X-User-ID)\n", baseURL)
+	fmt.Printf("   • POST %s/api/deck/prefetch    - Prepara y reserva el próximo mazo (X-User-ID)\n", baseURL)
+	fmt.Printf("   • GET  %s/api/deck/changes?since= - Long-polling de cambios del mazo (X-User-ID)\n", baseURL)
+	fmt.Printf("   • POST %s/api/swipes           - Registrar like/pass (X-User-ID)\n", baseURL)
+	fmt.Printf("   • GET  %s/api/me/history       - Historial de swipes paginado por cursor (X-User-ID)\n", baseURL)
+	fmt.Printf("   • PUT  %s/api/me/preferences   - Preferencias que aplica el mazo (X-User-ID)\n", baseURL)
+	fmt.Printf("   • POST %s/api/me/capabilities  - Formatos y ancho máximo de imagen del cliente (X-User-ID)\n", baseURL)
+	fmt.Printf("   • *    %s/api/me/digest        - Suscripción al resumen semanal por correo (X-User-ID)\n", baseURL)
+	fmt.Printf("   • GET  %s/api/me/digest/preview - Vista previa del resumen semanal\n", baseURL)
+	fmt.Printf("   • GET  %s/api/quiz             - Preguntas del quiz de personalidad\n", baseURL)
+	fmt.Printf("   • POST %s/api/quiz/answers     - Calcula tu arquetipo y ajusta el mazo (X-User-ID)\n", baseURL)
+	fmt.Printf("   • GET  %s/api/compatibility/names?a=&b= - Compatibilidad de nombres (mini-juego)\n", baseURL)
+	fmt.Printf("   • GET  %s/api/me/badges        - Insignias y racha diaria (X-User-ID)\n", baseURL)
+	fmt.Printf("   • GET  %s/api/me/quests        - Misiones del día; dan super-likes (X-User-ID)\n", baseURL)
+	fmt.Printf("   • POST %s/api/me/cats          - Registrar un gato propio (GET para listarlos)\n", baseURL)
+	fmt.Printf("   • GET  %s/api/matches          - Matches del usuario (X-User-ID)\n", baseURL)
+	fmt.Printf("   • GET  %s/api/matches/archived - Matches archivados por falta de mensajes (POST /:id/revive)\n", baseURL)
+	fmt.Printf("   • GET  %s/api/matches/:id/icebreakers - Frases para romper el hielo\n", baseURL)
+	fmt.Printf("   • *    %s/api/matches/:id/messages - Chat de un match mutuo (POST /read para recibos)\n", baseURL)
+	fmt.Printf("   • POST %s/api/messages/:id/reactions - Reaccionar con un emoji (DELETE ?emoji= para quitarla)\n", baseURL)
+	fmt.Printf("   • GET  %s/api/matches/:id/presence - En línea / última vez del otro dueño\n", baseURL)
+	fmt.Printf("   • WS   %s/api/chat/events      - Eventos de chat en tiempo real (?user_id=)\n", baseURL)
+	fmt.Printf("   • GET  %s/api/cat-of-the-day   - Gato del día\n", baseURL)
+	fmt.Printf("   • *    %s/api/admin/webhooks   - Canales Discord/Slack (requiere ADMIN_API_KEY)\n", baseURL)
+	fmt.Printf("   • *    %s/api/admin/cats       - Borrado lógico, restauración y purga de perfiles\n", baseURL)
+	fmt.Printf("   • POST %s/api/admin/cats/:id/text - Generar bio y personalidad\n", baseURL)
+	fmt.Printf("   • GET  %s/api/admin/backup     - Respaldo .tar.gz (POST /api/admin/restore para cargarlo)\n", baseURL)
+	fmt.Printf("   • POST %s/api/admin/digest/send - Enviar ya el resumen semanal del refugio\n", baseURL)
+	fmt.Printf("   • GET  %s/api/admin/ranking/shadow - Divergencia del ranking candidato en sombra\n", baseURL)
+	fmt.Printf("   • POST %s/api/admin/config/reload - Recargar configuración en caliente (también con SIGHUP)\n", baseURL)
+	if chaos != nil {
+		fmt.Printf("   • *    %s/api/admin/chaos      - Inyección de fallas (modo caos)\n", baseURL)
+	}
+	fmt.Printf("   • *    %s/api/admin/themes     - Temas de temporada y override manual\n", baseURL)
+	fmt.Printf("   • GET  %s/feed.xml             - Feed Atom de perfiles nuevos\n", baseURL)
+	fmt.Printf("   • GET  %s/events/schemas       - JSON Schema versionado de los eventos (/:topic/:version)\n", baseURL)
 	fmt.Printf("   • GET  %s/api/cats?count=5     - Obtener imágenes de gatos (legacy)\n", baseURL)
+	fmt.Printf("   • GET  %s/api/cats?validated=true - Imágenes verificadas con HEAD (más lento)\n", baseURL)
 	fmt.Printf("   • GET  %s/api/health           - Health check\n", baseURL)
+	fmt.Printf("   • GET  %s/api/stats            - Estadísticas del servicio\n", baseURL)
+	fmt.Printf("   • GET  %s/api/ready            - Readiness (tras el warm-up)\n", baseURL)
 	fmt.Printf("   • GET  %s/                 - Información de la API\n", baseURL)
 
-	if err := router.Run(":" + port); err != nil {
+	if err := server.Run(router, cfg); err != nil {
+		reporter.CaptureError(err, nil)
+		reporter.Flush(2 * time.Second)
 		log.Fatal("Error al iniciar el servidor:", err)
 	}
 }
 
-func corsMiddleware() gin.HandlerFunc {
+func initialWebhookChannels(cfg config.WebhooksConfig) []m.WebhookChannel {
+	var secrets []m.WebhookSecret
+	if cfg.SigningSecret != "" {
+		secrets = []m.WebhookSecret{{Version: 1, Secret: cfg.SigningSecret}}
+	}
+	var channels []m.WebhookChannel
+	if cfg.DiscordURL != "" {
+		channels = append(channels, m.WebhookChannel{ID: "discord-default", Name: "Discord", Kind: "discord", URL: cfg.DiscordURL, Enabled: true, Secrets: secrets})
+	}
+	if cfg.SlackURL != "" {
+		channels = append(channels, m.WebhookChannel{ID: "slack-default", Name: "Slack", Kind: "slack", URL: cfg.SlackURL, Enabled: true, Secrets: secrets})
+	}
+	return channels
+}
+
+// * Con "*" cualquier origen; si no, se devuelve el Origin solo si está en la lista
+func corsMiddleware(allowedOrigins []string) gin.HandlerFunc {
+	allowAll := slices.Contains(allowedOrigins, "*")
+	allowed := make(map[string]bool, len(allowedOrigins))
+	for _, origin := range allowedOrigins {
+		allowed[strings.TrimSuffix(origin, "/")] = true
+	}
+
 	return func(c *gin.Context) {
-		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		if allowAll {
+			c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
+		} else {
+			c.Writer.Header().Add("Vary", "Origin")
+			if origin := c.GetHeader("Origin"); allowed[origin] {
+				c.Writer.Header().Set("Access-Control-Allow-Origin", origin)
+			}
+		}
+		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, HEAD, POST, PUT, DELETE, OPTIONS")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-User-ID, X-Admin-Key, X-Tenant, X-Proof-Of-Work, X-Client-Signature, X-Api-Token, Prefer")
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+)
+
+type AbuseInspector interface {
+	Inspect(ip string, header http.Header, cost int) (status int, retryAfter time.Duration)
+}
+
+type WriteVerifier interface {
+	VerifyRequest(method, uri, proof, signature string, body []byte) error
+}
+
+// * Lista de bloqueo y puntaje de abuso por IP. costs pondera rutas (por FullPath): las
+// * imágenes son lo que más scrapean y cuentan más. Admin y sondas quedan fuera
+func AbuseShield(inspector AbuseInspector, costs map[string]int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		path := c.Request.URL.Path
+		if strings.HasPrefix(path, "/api/admin") || path == "/api/health" || path == "/api/ready" {
+			c.Next()
+			return
+		}
+
+		cost := 1
+		if weight, ok := costs[c.FullPath()]; ok {
+			cost = weight
+		}
+
+		status, retryAfter := inspector.Inspect(ClientIP(c), c.Request.Header, cost)
+		switch status {
+		case 0:
+			c.Next()
+		case http.StatusForbidden:
+			c.AbortWithStatusJSON(status, m.ErrorResponse{
+				Error:   "blocked",
+				Message: "Acceso bloqueado",
+			})
+		default:
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			c.AbortWithStatusJSON(status, m.ErrorResponse{
+				Error:   "suspected_bot",
+				Message: "Demasiada actividad automatizada desde tu conexión, intenta de nuevo en un momento",
+			})
+		}
+	}
+}
+
+// * Para rutas de escritura: prueba de trabajo de /api/challenge o firma de cliente propio.
+// * El cuerpo solo se lee si viene firma, que lo cubre
+func WriteProof(verifier WriteVerifier, proofHeader, signatureHeader string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		signature := c.GetHeader(signatureHeader)
+		var body []byte
+		if signature != "" {
+			var err error
+			if body, err = signedBody(c); err != nil {
+				c.AbortWithStatusJSON(http.StatusBadRequest, m.ErrorResponse{
+					Error:   "invalid_body",
+					Message: err.Error(),
+				})
+				return
+			}
+		}
+
+		err := verifier.VerifyRequest(c.Request.Method, c.Request.URL.RequestURI(), c.GetHeader(proofHeader), signature, body)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusPreconditionRequired, m.ErrorResponse{
+				Error:   "proof_of_work_required",
+				Message: err.Error(),
+			})
+			return
+		}
+		c.Next()
+	}
+}
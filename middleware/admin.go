@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+)
+
+// * Acepta la clave en X-Admin-Key o como Authorization: Bearer <clave>
+func AdminAuth(apiKey string) gin.HandlerFunc {
+	return AdminAuthFunc(func(*gin.Context) string { return apiKey })
+}
+
+// * Igual que AdminAuth, pero la clave se decide por petición (ej. una por tenant).
+// * Una petición ya firmada (ver SignedRequests) no necesita clave
+func AdminAuthFunc(keyFor func(c *gin.Context) string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if SignedFrom(c) {
+			c.Next()
+			return
+		}
+
+		apiKey := keyFor(c)
+		if apiKey == "" {
+			c.AbortWithStatusJSON(http.StatusForbidden, m.ErrorResponse{
+				Error:   "admin_disabled",
+				Message: "Las rutas de administración no están habilitadas (ADMIN_API_KEY vacío)",
+			})
+			return
+		}
+
+		provided := c.GetHeader("X-Admin-Key")
+		if provided == "" {
+			provided = strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+		}
+
+		if subtle.ConstantTimeCompare([]byte(provided), []byte(apiKey)) != 1 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, m.ErrorResponse{
+				Error:   "unauthorized",
+				Message: "Clave de administración inválida",
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,130 @@
+package middleware
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/ChrisTheAbysswalker/meownder-backend/bufpool"
+)
+
+type cachedResponse struct {
+	status      int
+	contentType string
+	body        []byte
+	expiresAt   time.Time
+}
+
+// * Lo fija el middleware de tenants; vacío si la ruta no es multi-tenant
+const TenantIDKey = "tenant_id"
+
+// * Lo fija el middleware de capacidades del cliente: la misma URL puede salir con otra
+// * calidad de imagen según lo que declaró el usuario
+const MediaVariantKey = "media_variant"
+
+// * Cache en memoria para respuestas GET idempotentes; se invalida completo con Purge
+type ResponseCache struct {
+	entries    map[string]cachedResponse
+	mutex      sync.RWMutex
+	maxEntries int
+}
+
+func NewResponseCache(maxEntries int) *ResponseCache {
+	return &ResponseCache{
+		entries:    make(map[string]cachedResponse),
+		maxEntries: maxEntries,
+	}
+}
+
+func (rc *ResponseCache) Purge() {
+	rc.mutex.Lock()
+	rc.entries = make(map[string]cachedResponse)
+	rc.mutex.Unlock()
+}
+
+func (rc *ResponseCache) Cache(ttl time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// * Los streams NDJSON no se cachean: la clave no distingue el Accept
+		if ttl <= 0 || c.Request.Method != http.MethodGet || WantsNDJSON(c.Request) {
+			c.Next()
+			return
+		}
+
+		// * Cada tenant tiene su propio catálogo: la misma URL no es la misma respuesta. Save-Data
+		// * cambia la calidad de las imágenes igual que ?quality=low
+		variant := c.GetString(MediaVariantKey)
+		if SavesData(c.Request) {
+			variant += "+save-data"
+		}
+		key := c.GetString(TenantIDKey) + "|" + variant + "|" + c.Request.URL.RequestURI()
+
+		rc.mutex.RLock()
+		entry, ok := rc.entries[key]
+		rc.mutex.RUnlock()
+
+		if ok && time.Now().Before(entry.expiresAt) {
+			c.Header("X-Cache", "HIT")
+			c.Data(entry.status, entry.contentType, entry.body)
+			c.Abort()
+			return
+		}
+
+		recorder := &bodyRecorder{ResponseWriter: c.Writer, body: bufpool.Default.Get()}
+		defer bufpool.Default.Put(recorder.body)
+		c.Writer = recorder
+		c.Header("X-Cache", "MISS")
+		c.Next()
+
+		if c.Writer.Status() != http.StatusOK {
+			return
+		}
+
+		rc.mutex.Lock()
+		if len(rc.entries) >= rc.maxEntries {
+			rc.evictExpired()
+		}
+		if len(rc.entries) < rc.maxEntries {
+			rc.entries[key] = cachedResponse{
+				status:      http.StatusOK,
+				contentType: c.Writer.Header().Get("Content-Type"),
+				body:        bytes.Clone(recorder.body.Bytes()),
+				expiresAt:   time.Now().Add(ttl),
+			}
+		}
+		rc.mutex.Unlock()
+	}
+}
+
+// * Save-Data: on (Client Hints): el cliente pide ahorrar datos
+func SavesData(req *http.Request) bool {
+	return strings.EqualFold(strings.TrimSpace(req.Header.Get("Save-Data")), "on")
+}
+
+// ! Llamar con el mutex de escritura tomado
+func (rc *ResponseCache) evictExpired() {
+	now := time.Now()
+	for key, entry := range rc.entries {
+		if now.After(entry.expiresAt) {
+			delete(rc.entries, key)
+		}
+	}
+}
+
+type bodyRecorder struct {
+	gin.ResponseWriter
+	body *bytes.Buffer
+}
+
+func (w *bodyRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *bodyRecorder) WriteString(data string) (int, error) {
+	w.body.WriteString(data)
+	return w.ResponseWriter.WriteString(data)
+}
@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+)
+
+type FaultInjector interface {
+	HTTPFault() (delay time.Duration, status int)
+}
+
+// * Solo para pruebas de resiliencia; las rutas de admin quedan fuera para poder
+// * apagar el caos aunque esté inyectando errores al 100%
+func FaultInjection(injector FaultInjector) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if strings.HasPrefix(c.Request.URL.Path, "/api/admin") {
+			c.Next()
+			return
+		}
+
+		delay, status := injector.HTTPFault()
+		if delay > 0 {
+			timer := time.NewTimer(delay)
+			select {
+			case <-c.Request.Context().Done():
+				timer.Stop()
+			case <-timer.C:
+			}
+		}
+		if status != 0 {
+			c.AbortWithStatusJSON(status, m.ErrorResponse{
+				Error:   "chaos_injected",
+				Message: http.StatusText(status) + " (inyectado por el modo caos)",
+			})
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+const clientIPKey = "client_ip"
+
+// * Resuelve la IP real una sola vez (respetando los proxies de confianza) para que
+// * el rate limiter, los logs y las métricas usen siempre el mismo valor
+func RealIP() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(clientIPKey, c.ClientIP())
+		c.Next()
+	}
+}
+
+func ClientIP(c *gin.Context) string {
+	if ip := c.GetString(clientIPKey); ip != "" {
+		return ip
+	}
+	return c.ClientIP()
+}
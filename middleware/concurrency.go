@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+)
+
+// * Limita las peticiones simultáneas de una ruta; el exceso se rechaza al instante
+// * con 503 en vez de encolarse, para que la latencia se mantenga predecible
+func ConcurrencyLimit(max int, retryAfter time.Duration) gin.HandlerFunc {
+	if max <= 0 {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	slots := make(chan struct{}, max)
+	retrySeconds := strconv.Itoa(int(retryAfter.Round(time.Second).Seconds()))
+
+	return func(c *gin.Context) {
+		select {
+		case slots <- struct{}{}:
+			defer func() { <-slots }()
+			c.Next()
+		default:
+			c.Header("Retry-After", retrySeconds)
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, m.ErrorResponse{
+				Error:   "overloaded",
+				Message: "Demasiadas peticiones en curso, intenta de nuevo en unos segundos",
+			})
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+)
+
+type FeatureGate interface {
+	Enabled(name string) bool
+}
+
+// * Con la feature apagada la ruta responde 404 como si no existiera
+func Feature(gate FeatureGate, name string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !gate.Enabled(name) {
+			c.AbortWithStatusJSON(http.StatusNotFound, m.ErrorResponse{
+				Error:   "feature_disabled",
+				Message: "Esta función está desactivada por el momento",
+			})
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,354 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+)
+
+const (
+	CaseSnake = "snake"
+	CaseCamel = "camel"
+
+	jsonCaseKey = "json_case"
+	rawBodyKey  = "raw_body"
+)
+
+var dataMapFields = func() map[string]bool {
+	fields := make(map[string]bool, len(m.DataMapFields))
+	for _, field := range m.DataMapFields {
+		fields[field] = true
+	}
+	return fields
+}()
+
+func ParseJSONCase(value string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", CaseSnake:
+		return CaseSnake, nil
+	case CaseCamel:
+		return CaseCamel, nil
+	}
+	return "", fmt.Errorf("convención desconocida %q (usa snake o camel)", value)
+}
+
+// * Los modelos se serializan en snake_case; con ?case=camel o "Accept: application/json;
+// * case=camel" las claves de las respuestas JSON pasan a camelCase y las de los cuerpos
+// * JSON que llegan vuelven a snake_case antes del binding. defaultCase aplica si el
+// * cliente no pide nada. En snake no se toca nada: no cuesta nada a los clientes de siempre
+func JSONCase(defaultCase string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Writer.Header().Add("Vary", "Accept")
+
+		mode := requestedCase(c.Request)
+		if mode == "" {
+			mode = defaultCase
+		}
+		c.Set(jsonCaseKey, mode)
+		if mode != CaseCamel {
+			c.Next()
+			return
+		}
+
+		if c.Request.Body != nil && c.Request.Body != http.NoBody && isJSON(c.GetHeader("Content-Type")) {
+			c.Request.Body = renamedBody(c, c.Request.Body)
+		}
+
+		writer := &caseWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+		writer.finish()
+	}
+}
+
+// * snake o camel según la petición; los handlers que escriben JSON fuera de c.JSON
+// * (websocket) lo usan con MarshalCase
+func JSONCaseFrom(c *gin.Context) string {
+	if mode := c.GetString(jsonCaseKey); mode != "" {
+		return mode
+	}
+	return CaseSnake
+}
+
+func MarshalCase(mode string, v any) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil || mode != CaseCamel {
+		return data, err
+	}
+	return renameKeys(data, snakeToCamel)
+}
+
+func UnmarshalCase(mode string, data []byte, v any) error {
+	if mode == CaseCamel {
+		if renamed, err := renameKeys(data, camelToSnake); err == nil {
+			data = renamed
+		}
+	}
+	return json.Unmarshal(data, v)
+}
+
+func requestedCase(req *http.Request) string {
+	if value := req.URL.Query().Get("case"); value != "" {
+		if mode, err := ParseJSONCase(value); err == nil {
+			return mode
+		}
+	}
+	for _, accepted := range strings.Split(req.Header.Get("Accept"), ",") {
+		_, params, err := mime.ParseMediaType(strings.TrimSpace(accepted))
+		if err != nil || params["case"] == "" {
+			continue
+		}
+		if mode, err := ParseJSONCase(params["case"]); err == nil {
+			return mode
+		}
+	}
+	return ""
+}
+
+func isJSON(contentType string) bool {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// * Si el cuerpo no se puede leer (ej. pasa el tope de RequestLimits) el handler recibe
+// * el mismo error al leerlo, así responde igual que sin conversión. El original queda en
+// * el contexto para verificar firmas (ver SignedRequests)
+func renamedBody(c *gin.Context, body io.ReadCloser) io.ReadCloser {
+	data, err := io.ReadAll(body)
+	body.Close()
+	if err != nil {
+		return io.NopCloser(io.MultiReader(bytes.NewReader(data), errReader{err}))
+	}
+	c.Set(rawBodyKey, data)
+	if renamed, err := renameKeys(data, camelToSnake); err == nil {
+		data = renamed
+	}
+	return io.NopCloser(bytes.NewReader(data))
+}
+
+type errReader struct{ err error }
+
+func (r errReader) Read([]byte) (int, error) { return 0, r.err }
+
+// * Junta las respuestas JSON para renombrar claves al final; cualquier otro tipo de
+// * contenido (imágenes, HTML, streams) se escribe directo
+type caseWriter struct {
+	gin.ResponseWriter
+	buffer    bytes.Buffer
+	buffering bool
+	decided   bool
+}
+
+func (w *caseWriter) decide() {
+	if !w.decided {
+		w.decided = true
+		w.buffering = isJSON(w.Header().Get("Content-Type"))
+	}
+}
+
+func (w *caseWriter) Write(data []byte) (int, error) {
+	w.decide()
+	if w.buffering {
+		return w.buffer.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *caseWriter) WriteString(data string) (int, error) {
+	w.decide()
+	if w.buffering {
+		return w.buffer.WriteString(data)
+	}
+	return w.ResponseWriter.WriteString(data)
+}
+
+func (w *caseWriter) Written() bool {
+	return w.ResponseWriter.Written() || w.buffer.Len() > 0
+}
+
+func (w *caseWriter) Size() int {
+	if w.buffering && !w.ResponseWriter.Written() {
+		return w.buffer.Len()
+	}
+	return w.ResponseWriter.Size()
+}
+
+func (w *caseWriter) finish() {
+	if !w.buffering || w.buffer.Len() == 0 {
+		return
+	}
+	data := w.buffer.Bytes()
+	if renamed, err := renameKeys(data, snakeToCamel); err == nil {
+		data = renamed
+	}
+	if w.Header().Get("Content-Length") != "" {
+		w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	}
+	w.ResponseWriter.Write(data)
+}
+
+type caseFrame struct {
+	object bool
+	// * Objeto con claves de datos (ver m.DataMapFields)
+	data      bool
+	expectKey bool
+	count     int
+}
+
+// * Reescribe las claves de objeto token a token: conserva el orden de los campos y los
+// * números tal cual llegaron
+func renameKeys(data []byte, rename func(string) string) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var out bytes.Buffer
+	out.Grow(len(data))
+	var stack []*caseFrame
+	nextIsData := false
+
+	beforeValue := func() {
+		if len(stack) == 0 {
+			return
+		}
+		if top := stack[len(stack)-1]; !top.object {
+			if top.count > 0 {
+				out.WriteByte(',')
+			}
+			top.count++
+		}
+	}
+	afterValue := func() {
+		if len(stack) > 0 && stack[len(stack)-1].object {
+			stack[len(stack)-1].expectKey = true
+		}
+	}
+
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch value := token.(type) {
+		case json.Delim:
+			switch value {
+			case '{', '[':
+				beforeValue()
+				stack = append(stack, &caseFrame{object: value == '{', data: value == '{' && nextIsData, expectKey: true})
+				nextIsData = false
+				out.WriteByte(byte(value))
+			default:
+				stack = stack[:len(stack)-1]
+				out.WriteByte(byte(value))
+				afterValue()
+			}
+			continue
+		case string:
+			if len(stack) > 0 {
+				if top := stack[len(stack)-1]; top.object && top.expectKey {
+					if top.count > 0 {
+						out.WriteByte(',')
+					}
+					top.count++
+					top.expectKey = false
+
+					key := value
+					if !top.data {
+						key = rename(value)
+						nextIsData = dataMapFields[value] || dataMapFields[key]
+					}
+					encoded, _ := json.Marshal(key)
+					out.Write(encoded)
+					out.WriteByte(':')
+					continue
+				}
+			}
+		}
+
+		beforeValue()
+		nextIsData = false
+		encoded, err := json.Marshal(token)
+		if err != nil {
+			return nil, err
+		}
+		out.Write(encoded)
+		afterValue()
+	}
+	return out.Bytes(), nil
+}
+
+// * Solo claves en snake_case en minúsculas ("expires_at"); "LOG_LEVEL" o "/api/cats" quedan igual
+func snakeToCamel(key string) string {
+	if !strings.Contains(key, "_") || strings.HasPrefix(key, "_") || strings.HasSuffix(key, "_") || strings.Contains(key, "__") {
+		return key
+	}
+	for _, r := range key {
+		if r != '_' && (r < 'a' || r > 'z') && (r < '0' || r > '9') {
+			return key
+		}
+	}
+
+	var out strings.Builder
+	upper := false
+	for _, r := range key {
+		switch {
+		case r == '_':
+			upper = true
+		case upper && r >= 'a' && r <= 'z':
+			out.WriteRune(r - 'a' + 'A')
+			upper = false
+		default:
+			out.WriteRune(r)
+			upper = false
+		}
+	}
+	return out.String()
+}
+
+// * "expiresAt" -> "expires_at"; las siglas van juntas ("catID" -> "cat_id")
+func camelToSnake(key string) string {
+	if key == "" || key[0] < 'a' || key[0] > 'z' {
+		return key
+	}
+	hasUpper := false
+	for _, r := range key {
+		switch {
+		case r >= 'A' && r <= 'Z':
+			hasUpper = true
+		case (r < 'a' || r > 'z') && (r < '0' || r > '9'):
+			return key
+		}
+	}
+	if !hasUpper {
+		return key
+	}
+
+	isUpper := func(b byte) bool { return b >= 'A' && b <= 'Z' }
+	var out strings.Builder
+	for i := 0; i < len(key); i++ {
+		b := key[i]
+		if !isUpper(b) {
+			out.WriteByte(b)
+			continue
+		}
+		nextLower := i+1 < len(key) && !isUpper(key[i+1])
+		if !isUpper(key[i-1]) || nextLower {
+			out.WriteByte('_')
+		}
+		out.WriteByte(b - 'A' + 'a')
+	}
+	return out.String()
+}
@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+const (
+	LevelDebug = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+var logLevelNames = []string{"debug", "info", "warn", "error"}
+
+// * Nivel del log de peticiones: debug guarda los cuerpos de todas, info registra
+// * todas, warn solo 4xx/5xx y error solo 5xx. Se cambia en caliente con Set
+type LogLevel struct {
+	level atomic.Int32
+}
+
+func NewLogLevel(name string) (*LogLevel, error) {
+	level := &LogLevel{}
+	if err := level.Set(name); err != nil {
+		return nil, err
+	}
+	return level, nil
+}
+
+func ParseLogLevel(name string) (int, error) {
+	for level, known := range logLevelNames {
+		if strings.EqualFold(strings.TrimSpace(name), known) {
+			return level, nil
+		}
+	}
+	return 0, fmt.Errorf("nivel de log desconocido: %q (debug, info, warn o error)", name)
+}
+
+func (l *LogLevel) Set(name string) error {
+	level, err := ParseLogLevel(name)
+	if err != nil {
+		return err
+	}
+	l.level.Store(int32(level))
+	return nil
+}
+
+func (l *LogLevel) Level() int {
+	return int(l.level.Load())
+}
+
+func (l *LogLevel) String() string {
+	return logLevelNames[l.Level()]
+}
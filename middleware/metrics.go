@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RequestRecorder interface {
+	RequestServed(status int, latency time.Duration)
+}
+
+// * Alimenta la ventana de peticiones que usan GET /stats y el monitor de alertas
+func Metrics(recorder RequestRecorder) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+		recorder.RequestServed(c.Writer.Status(), time.Since(start))
+	}
+}
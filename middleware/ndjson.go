@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"mime"
+	"net/http"
+	"strings"
+)
+
+const NDJSONType = "application/x-ndjson"
+
+// * El cliente pidió la respuesta como stream de un JSON por línea (Accept: application/x-ndjson)
+func WantsNDJSON(req *http.Request) bool {
+	for _, accepted := range strings.Split(req.Header.Get("Accept"), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(accepted))
+		if err == nil && mediaType == NDJSONType {
+			return true
+		}
+	}
+	return false
+}
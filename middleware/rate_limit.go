@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+	"github.com/ChrisTheAbysswalker/meownder-backend/ratelimit"
+)
+
+// * Límite por IP real (ver RealIP); limiter nil o con límite 0 no limita. Si el backend falla
+// * (Redis caído) se deja pasar: es preferible perder el límite a tumbar la ruta
+func RateLimit(limiter ratelimit.Limiter) gin.HandlerFunc {
+	if limiter == nil {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	return func(c *gin.Context) {
+		// * El límite se puede apagar en caliente (recarga de configuración)
+		if limiter.Limit() <= 0 {
+			c.Next()
+			return
+		}
+
+		decision, err := limiter.Allow(c.Request.Context(), ClientIP(c))
+		if err != nil {
+			log.Printf("⚠️ Límite de peticiones no disponible, se deja pasar: %v", err)
+			c.Next()
+			return
+		}
+
+		c.Header("X-RateLimit-Limit", strconv.Itoa(limiter.Limit()))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(decision.Remaining))
+		if !decision.Allowed {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(decision.RetryAfter.Seconds()))))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, m.ErrorResponse{
+				Error:   "rate_limited",
+				Message: "Demasiadas peticiones, intenta de nuevo en un momento",
+			})
+			return
+		}
+		c.Next()
+	}
+}
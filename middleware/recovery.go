@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+	"github.com/ChrisTheAbysswalker/meownder-backend/sentry"
+)
+
+// * Como gin.Recovery (que sigue logueando la pila) pero además reporta el panic y
+// * responde con el mismo formato de error que el resto de la API
+func Recovery(reporter *sentry.Client) gin.HandlerFunc {
+	return gin.CustomRecovery(func(c *gin.Context, recovered any) {
+		reporter.CapturePanic(recovered, map[string]string{
+			"method": c.Request.Method,
+			"route":  c.FullPath(),
+		})
+		c.AbortWithStatusJSON(http.StatusInternalServerError, m.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Error interno del servidor",
+		})
+	})
+}
@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/ChrisTheAbysswalker/meownder-backend/config"
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+)
+
+// * Corta cabeceras excesivas con 431 y cuerpos que pasan el tope de su tipo con 413:
+// * con Content-Length se responde antes de leer nada; sin él (chunked) el cuerpo queda
+// * envuelto en MaxBytesReader y el handler recibe *http.MaxBytesError al pasarse.
+// * routes da topes propios por ruta (FullPath) a los endpoints de cuerpos grandes
+func RequestLimits(cfg config.LimitsConfig, routes map[string]int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if message := headerProblem(c.Request.Header, cfg); message != "" {
+			c.AbortWithStatusJSON(http.StatusRequestHeaderFieldsTooLarge, m.ErrorResponse{
+				Error:   "headers_too_large",
+				Message: message,
+			})
+			return
+		}
+
+		if c.Request.Body == nil || c.Request.Body == http.NoBody {
+			c.Next()
+			return
+		}
+
+		limit, ok := routes[c.FullPath()]
+		if !ok {
+			limit = bodyLimit(c.ContentType(), cfg)
+		}
+		if limit <= 0 {
+			c.Next()
+			return
+		}
+
+		if c.Request.ContentLength > limit {
+			c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, m.ErrorResponse{
+				Error:   "payload_too_large",
+				Message: fmt.Sprintf("El cuerpo supera el máximo de %d bytes", limit),
+			})
+			return
+		}
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit)
+		c.Next()
+	}
+}
+
+func bodyLimit(contentType string, cfg config.LimitsConfig) int64 {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch {
+	case jsonMediaType(mediaType):
+		return cfg.MaxJSONBytes
+	case strings.HasPrefix(mediaType, "multipart/"):
+		return cfg.MaxMultipartBytes
+	}
+	return cfg.MaxBodyBytes
+}
+
+// * El total ya lo limita http.Server (MaxHeaderBytes); acá la cantidad y cada valor
+func headerProblem(header http.Header, cfg config.LimitsConfig) string {
+	count := 0
+	for name, values := range header {
+		count += len(values)
+		if cfg.MaxHeaderValueBytes <= 0 {
+			continue
+		}
+		for _, value := range values {
+			if len(value) > cfg.MaxHeaderValueBytes {
+				return fmt.Sprintf("La cabecera %s supera el máximo de %d bytes", name, cfg.MaxHeaderValueBytes)
+			}
+		}
+	}
+	if cfg.MaxHeaders > 0 && count > cfg.MaxHeaders {
+		return fmt.Sprintf("La petición trae %d cabeceras (máximo %d)", count, cfg.MaxHeaders)
+	}
+	return ""
+}
@@ -0,0 +1,283 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math/rand/v2"
+	"mime"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/ChrisTheAbysswalker/meownder-backend/config"
+)
+
+const redactedValue = "[REDACTED]"
+
+// * Reemplaza a gin.Logger: una línea por petición con status, tamaño y latencia, y
+// * además el cuerpo de la petición y de la respuesta cuando acaba en 4xx/5xx o cae en
+// * la muestra, para poder investigar errores de producción sin reproducirlos. level
+// * decide qué peticiones se registran (ver LogLevel). Los tokens con tokenPrefix se
+// * tapan vengan en el campo que vengan (?key= del widget, cuerpos, etc.)
+func RequestLog(cfg config.LoggingConfig, level *LogLevel, tokenPrefix string) gin.HandlerFunc {
+	redactor := newRedactor(cfg.RedactFields, tokenPrefix)
+	skip := make(map[string]bool, len(cfg.SkipPaths))
+	for _, path := range cfg.SkipPaths {
+		skip[path] = true
+	}
+
+	return func(c *gin.Context) {
+		if skip[c.Request.URL.Path] {
+			c.Next()
+			return
+		}
+
+		start := time.Now()
+		current := level.Level()
+		sampled := current == LevelDebug || (cfg.BodySampleRate > 0 && rand.Float64() < cfg.BodySampleRate)
+		capture := (sampled || cfg.CaptureErrors) && cfg.MaxBodyBytes > 0
+
+		var requestBody []byte
+		var truncated bool
+		var recorder *cappedRecorder
+		if capture {
+			if textual(c.GetHeader("Content-Type")) {
+				requestBody, truncated = peekBody(c.Request, cfg.MaxBodyBytes)
+			}
+			recorder = &cappedRecorder{ResponseWriter: c.Writer, max: cfg.MaxBodyBytes}
+			c.Writer = recorder
+		}
+
+		c.Next()
+
+		status := c.Writer.Status()
+		if (current == LevelWarn && status < http.StatusBadRequest) ||
+			(current == LevelError && status < http.StatusInternalServerError) {
+			return
+		}
+		line := fmt.Sprintf("%s %s %d %dB %s ip=%s",
+			c.Request.Method, redactor.url(c.Request.URL), status, max(c.Writer.Size(), 0),
+			time.Since(start).Round(time.Microsecond), ClientIP(c))
+		if len(c.Errors) > 0 {
+			line += " errors=" + oneLine(c.Errors.String())
+		}
+
+		if capture && (sampled || status >= http.StatusBadRequest) {
+			if len(requestBody) > 0 {
+				line += " req=" + redactor.body(c.GetHeader("Content-Type"), requestBody, truncated)
+			}
+			if recorder.body.Len() > 0 && textual(c.Writer.Header().Get("Content-Type")) {
+				line += " resp=" + redactor.body(c.Writer.Header().Get("Content-Type"), recorder.body.Bytes(), recorder.truncated)
+			}
+		}
+
+		log.Printf("%s %s", statusEmoji(status), line)
+	}
+}
+
+func statusEmoji(status int) string {
+	switch {
+	case status >= http.StatusInternalServerError:
+		return "❌"
+	case status >= http.StatusBadRequest:
+		return "⚠️"
+	}
+	return "📝"
+}
+
+// * Solo tiene sentido guardar cuerpos legibles; imágenes y multipart se omiten
+func textual(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return jsonMediaType(mediaType) ||
+		mediaType == "application/x-www-form-urlencoded" ||
+		strings.HasPrefix(mediaType, "text/")
+}
+
+// * Incluye variantes como application/problem+json
+func jsonMediaType(mediaType string) bool {
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// * Lee hasta limit bytes y deja el cuerpo intacto para el handler
+func peekBody(req *http.Request, limit int) ([]byte, bool) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, false
+	}
+
+	prefix := make([]byte, limit+1)
+	n, _ := io.ReadFull(req.Body, prefix)
+	prefix = prefix[:n]
+	req.Body = readCloser{
+		Reader: io.MultiReader(bytes.NewReader(prefix), req.Body),
+		Closer: req.Body,
+	}
+
+	if n > limit {
+		return prefix[:limit], true
+	}
+	return prefix, false
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// * Como bodyRecorder pero con tope: las respuestas grandes no se copian enteras
+type cappedRecorder struct {
+	gin.ResponseWriter
+	body      bytes.Buffer
+	max       int
+	truncated bool
+}
+
+func (w *cappedRecorder) Write(data []byte) (int, error) {
+	w.record(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *cappedRecorder) WriteString(data string) (int, error) {
+	w.record([]byte(data))
+	return w.ResponseWriter.WriteString(data)
+}
+
+func (w *cappedRecorder) record(data []byte) {
+	room := w.max - w.body.Len()
+	if len(data) > room {
+		data = data[:max(room, 0)]
+		w.truncated = true
+	}
+	w.body.Write(data)
+}
+
+// * Un campo es sensible si su nombre contiene alguna de las palabras configuradas
+// * (así "access_token" o "user_email" también quedan cubiertos)
+type redactor struct {
+	fields []string
+	// ! Para JSON recortado que ya no se puede parsear
+	pattern *regexp.Regexp
+	// * Tokens reconocibles por su prefijo, aunque el campo no parezca sensible
+	tokens *regexp.Regexp
+}
+
+func newRedactor(fields []string, tokenPrefix string) *redactor {
+	r := &redactor{}
+	if tokenPrefix != "" {
+		r.tokens = regexp.MustCompile(regexp.QuoteMeta(tokenPrefix) + `[A-Za-z0-9_-]+`)
+	}
+	var quoted []string
+	for _, field := range fields {
+		if field = strings.ToLower(strings.TrimSpace(field)); field != "" {
+			r.fields = append(r.fields, field)
+			quoted = append(quoted, regexp.QuoteMeta(field))
+		}
+	}
+	if len(quoted) > 0 {
+		r.pattern = regexp.MustCompile(`(?i)("[^"]*(?:` + strings.Join(quoted, "|") + `)[^"]*"\s*:\s*)("(?:[^"\\]|\\.)*"?|[^,}\]\s]+)`)
+	}
+	return r
+}
+
+func (r *redactor) sensitive(key string) bool {
+	key = strings.ToLower(key)
+	for _, field := range r.fields {
+		if strings.Contains(key, field) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *redactor) url(u *url.URL) string {
+	if u.RawQuery == "" {
+		return u.Path
+	}
+	return u.Path + "?" + r.scrub(r.values(u.RawQuery))
+}
+
+func (r *redactor) scrub(s string) string {
+	if r.tokens == nil {
+		return s
+	}
+	return r.tokens.ReplaceAllString(s, redactedValue)
+}
+
+func (r *redactor) values(raw string) string {
+	values, err := url.ParseQuery(raw)
+	if err != nil {
+		return redactedValue
+	}
+	for key := range values {
+		if r.sensitive(key) {
+			values[key] = []string{redactedValue}
+		}
+	}
+	// * Sin escapar el marcador para que se lea igual que en los cuerpos JSON
+	return strings.ReplaceAll(values.Encode(), url.QueryEscape(redactedValue), redactedValue)
+}
+
+func (r *redactor) body(contentType string, data []byte, truncated bool) string {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	var out string
+	switch {
+	case jsonMediaType(mediaType):
+		out = r.json(data)
+	case mediaType == "application/x-www-form-urlencoded":
+		out = r.values(string(data))
+	default:
+		out = string(data)
+	}
+
+	if truncated {
+		out += "…(recortado)"
+	}
+	return oneLine(r.scrub(out))
+}
+
+func (r *redactor) json(data []byte) string {
+	var doc any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		if r.pattern == nil {
+			return string(data)
+		}
+		return r.pattern.ReplaceAllString(string(data), `${1}"`+redactedValue+`"`)
+	}
+
+	out, err := json.Marshal(r.walk(doc))
+	if err != nil {
+		return redactedValue
+	}
+	return string(out)
+}
+
+func (r *redactor) walk(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, inner := range v {
+			if r.sensitive(key) {
+				v[key] = redactedValue
+			} else {
+				v[key] = r.walk(inner)
+			}
+		}
+	case []any:
+		for i, inner := range v {
+			v[i] = r.walk(inner)
+		}
+	}
+	return value
+}
+
+func oneLine(s string) string {
+	return strings.NewReplacer("\n", `\n`, "\r", `\r`).Replace(s)
+}
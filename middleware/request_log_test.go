@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestRedactorHidesTokens(t *testing.T) {
+	r := newRedactor([]string{"password", "key"}, "mwt_")
+	secret := "mwt_a1b2c3_0123456789abcdef0123456789abcdef01234567"
+
+	cases := []struct {
+		name string
+		got  string
+	}{
+		{"query key", r.url(&url.URL{Path: "/widget/deck", RawQuery: "key=" + secret + "&limit=5"})},
+		{"query sin campo sensible", r.url(&url.URL{Path: "/cats", RawQuery: "ref=" + secret})},
+		{"json", r.body("application/json", []byte(`{"user":"ana","note":"`+secret+`"}`), false)},
+		{"json recortado", r.body("application/json", []byte(`{"password":"x","note":"`+secret), true)},
+		{"texto", r.body("text/plain", []byte("token "+secret+" pegado"), false)},
+	}
+	for _, tc := range cases {
+		if strings.Contains(tc.got, "0123456789abcdef") {
+			t.Errorf("%s: el token quedó en el log: %s", tc.name, tc.got)
+		}
+		if !strings.Contains(tc.got, redactedValue) {
+			t.Errorf("%s: falta el marcador: %s", tc.name, tc.got)
+		}
+	}
+
+	if got := r.url(&url.URL{Path: "/cats", RawQuery: "limit=5"}); got != "/cats?limit=5" {
+		t.Errorf("query sin secretos cambió: %s", got)
+	}
+}
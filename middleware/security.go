@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/ChrisTheAbysswalker/meownder-backend/config"
+)
+
+func SecurityHeaders(cfg config.SecurityConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !cfg.Enabled {
+			c.Next()
+			return
+		}
+
+		header := c.Writer.Header()
+		setIfNotEmpty(header.Set, "X-Content-Type-Options", cfg.ContentTypeOptions)
+		setIfNotEmpty(header.Set, "X-Frame-Options", cfg.FrameOptions)
+		setIfNotEmpty(header.Set, "Referrer-Policy", cfg.ReferrerPolicy)
+		setIfNotEmpty(header.Set, "Content-Security-Policy", cfg.ContentSecurityPolicy)
+
+		c.Next()
+	}
+}
+
+// * Las rutas que sirven HTML necesitan una CSP menos estricta que la API JSON
+func HTMLSecurityPolicy(cfg config.SecurityConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if cfg.Enabled {
+			setIfNotEmpty(c.Writer.Header().Set, "Content-Security-Policy", cfg.HTMLSecurityPolicy)
+		}
+		c.Next()
+	}
+}
+
+func setIfNotEmpty(set func(key, value string), key, value string) {
+	if value != "" {
+		set(key, value)
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ChrisTheAbysswalker/meownder-backend/config"
+	"github.com/ChrisTheAbysswalker/meownder-backend/migrate"
+	"github.com/ChrisTheAbysswalker/meownder-backend/migrations"
+	"github.com/ChrisTheAbysswalker/meownder-backend/storage"
+)
+
+// * meownder migrate up | down [pasos] | status
+func runMigrateCommand(cfg config.DatabaseConfig, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("uso: migrate up | down [pasos] | status")
+	}
+
+	migrator, db, err := openMigrator(cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	switch args[0] {
+	case "up":
+		count, err := migrator.Up(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("✅ %d migraciones aplicadas\n", count)
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			if steps, err = strconv.Atoi(args[1]); err != nil || steps < 1 {
+				return fmt.Errorf("pasos inválidos: %s", args[1])
+			}
+		}
+		count, err := migrator.Down(ctx, steps)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("✅ %d migraciones revertidas\n", count)
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			applied := "pendiente"
+			if status.AppliedAt != nil {
+				applied = status.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Printf("   %04d_%-32s %s\n", status.Version, status.Name, applied)
+		}
+	default:
+		return fmt.Errorf("subcomando desconocido: %s", args[0])
+	}
+	return nil
+}
+
+func migrateOnStart(cfg config.DatabaseConfig) error {
+	migrator, db, err := openMigrator(cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	_, err = migrator.Up(ctx)
+	return err
+}
+
+// ! El driver tiene que estar registrado (import en blanco) para el DATABASE_DRIVER elegido
+func openMigrator(cfg config.DatabaseConfig) (*migrate.Migrator, *sql.DB, error) {
+	if cfg.URL == "" {
+		return nil, nil, fmt.Errorf("DATABASE_URL no está configurada")
+	}
+
+	db, err := storage.Open(cfg.Driver, cfg.URL)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	migrator, err := migrate.New(db, cfg.Driver, migrations.FS)
+	if err != nil {
+		db.Close()
+		return nil, nil, err
+	}
+	return migrator, db, nil
+}
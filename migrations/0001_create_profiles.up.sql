@@ -0,0 +1,16 @@
+CREATE TABLE profiles (
+    id          INTEGER PRIMARY KEY,
+    img         TEXT NOT NULL DEFAULT '',
+    name        TEXT NOT NULL,
+    age         INTEGER NOT NULL DEFAULT 0,
+    breed       TEXT NOT NULL DEFAULT '',
+    personality TEXT NOT NULL DEFAULT '',
+    hobbies     TEXT NOT NULL DEFAULT '[]',
+    bio         TEXT NOT NULL DEFAULT '',
+    status      TEXT NOT NULL DEFAULT 'active',
+    updated_at  TIMESTAMP NOT NULL,
+    deleted_at  TIMESTAMP NULL
+);
+
+CREATE INDEX idx_profiles_breed ON profiles (breed);
+CREATE INDEX idx_profiles_status ON profiles (status);
@@ -0,0 +1,19 @@
+CREATE TABLE swipes (
+    user_id    TEXT NOT NULL,
+    cat_id     INTEGER NOT NULL,
+    direction  TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (user_id, cat_id)
+);
+
+CREATE INDEX idx_swipes_user_created ON swipes (user_id, created_at);
+
+CREATE TABLE matches (
+    id         TEXT PRIMARY KEY,
+    user_id    TEXT NOT NULL,
+    cat_id     INTEGER NOT NULL,
+    cat_name   TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_matches_user_created ON matches (user_id, created_at);
@@ -0,0 +1,7 @@
+CREATE TABLE webhook_channels (
+    id      TEXT PRIMARY KEY,
+    name    TEXT NOT NULL,
+    kind    TEXT NOT NULL,
+    url     TEXT NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE
+);
@@ -0,0 +1,11 @@
+CREATE TABLE outbox (
+    id           TEXT PRIMARY KEY,
+    topic        TEXT NOT NULL,
+    payload      TEXT NOT NULL,
+    created_at   TIMESTAMP NOT NULL,
+    published_at TIMESTAMP NULL,
+    attempts     INTEGER NOT NULL DEFAULT 0,
+    last_error   TEXT NULL
+);
+
+CREATE INDEX idx_outbox_pending ON outbox (published_at, created_at);
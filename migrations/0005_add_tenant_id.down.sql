@@ -0,0 +1,28 @@
+CREATE TABLE swipes_single (
+    user_id    TEXT NOT NULL,
+    cat_id     INTEGER NOT NULL,
+    direction  TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (user_id, cat_id)
+);
+INSERT INTO swipes_single (user_id, cat_id, direction, created_at)
+    SELECT user_id, cat_id, direction, created_at FROM swipes WHERE tenant_id = 'default';
+DROP TABLE swipes;
+ALTER TABLE swipes_single RENAME TO swipes;
+CREATE INDEX idx_swipes_user_created ON swipes (user_id, created_at);
+
+CREATE TABLE matches_single (
+    id         TEXT PRIMARY KEY,
+    user_id    TEXT NOT NULL,
+    cat_id     INTEGER NOT NULL,
+    cat_name   TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
+INSERT INTO matches_single (id, user_id, cat_id, cat_name, created_at)
+    SELECT id, user_id, cat_id, cat_name, created_at FROM matches WHERE tenant_id = 'default';
+DROP TABLE matches;
+ALTER TABLE matches_single RENAME TO matches;
+CREATE INDEX idx_matches_user_created ON matches (user_id, created_at);
+
+ALTER TABLE outbox DROP COLUMN tenant_id;
+ALTER TABLE profiles DROP COLUMN tenant_id;
@@ -0,0 +1,32 @@
+ALTER TABLE profiles ADD COLUMN tenant_id TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE outbox ADD COLUMN tenant_id TEXT NOT NULL DEFAULT 'default';
+
+-- * Los contadores de match y los ids de gato son por tenant: la clave primaria también
+CREATE TABLE swipes_tenant (
+    tenant_id  TEXT NOT NULL DEFAULT 'default',
+    user_id    TEXT NOT NULL,
+    cat_id     INTEGER NOT NULL,
+    direction  TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (tenant_id, user_id, cat_id)
+);
+INSERT INTO swipes_tenant (user_id, cat_id, direction, created_at)
+    SELECT user_id, cat_id, direction, created_at FROM swipes;
+DROP TABLE swipes;
+ALTER TABLE swipes_tenant RENAME TO swipes;
+CREATE INDEX idx_swipes_tenant_user_created ON swipes (tenant_id, user_id, created_at);
+
+CREATE TABLE matches_tenant (
+    tenant_id  TEXT NOT NULL DEFAULT 'default',
+    id         TEXT NOT NULL,
+    user_id    TEXT NOT NULL,
+    cat_id     INTEGER NOT NULL,
+    cat_name   TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (tenant_id, id)
+);
+INSERT INTO matches_tenant (id, user_id, cat_id, cat_name, created_at)
+    SELECT id, user_id, cat_id, cat_name, created_at FROM matches;
+DROP TABLE matches;
+ALTER TABLE matches_tenant RENAME TO matches;
+CREATE INDEX idx_matches_tenant_user_created ON matches (tenant_id, user_id, created_at);
@@ -0,0 +1,4 @@
+ALTER TABLE matches DROP COLUMN mutual;
+ALTER TABLE matches DROP COLUMN with_cat_id;
+ALTER TABLE swipes DROP COLUMN from_cat_id;
+ALTER TABLE profiles DROP COLUMN owner_id;
@@ -0,0 +1,5 @@
+-- * Gatos registrados por usuarios y matches entre dos dueños
+ALTER TABLE profiles ADD COLUMN owner_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE swipes ADD COLUMN from_cat_id INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE matches ADD COLUMN with_cat_id INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE matches ADD COLUMN mutual BOOLEAN NOT NULL DEFAULT FALSE;
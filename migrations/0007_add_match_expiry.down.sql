@@ -0,0 +1,3 @@
+ALTER TABLE matches DROP COLUMN revived_at;
+ALTER TABLE matches DROP COLUMN archived_at;
+ALTER TABLE matches DROP COLUMN nudged_at;
@@ -0,0 +1,4 @@
+-- * Ciclo de matches sin mensajes: aviso, archivo y reactivación
+ALTER TABLE matches ADD COLUMN nudged_at TIMESTAMP;
+ALTER TABLE matches ADD COLUMN archived_at TIMESTAMP;
+ALTER TABLE matches ADD COLUMN revived_at TIMESTAMP;
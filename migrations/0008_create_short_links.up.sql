@@ -0,0 +1,13 @@
+-- * Enlaces cortos para compartir perfiles; el código es global (GET /l/:code no lleva tenant)
+CREATE TABLE short_links (
+    code          TEXT PRIMARY KEY,
+    tenant_id     TEXT NOT NULL,
+    cat_id        INTEGER NOT NULL DEFAULT 0,
+    target_url    TEXT NOT NULL,
+    clicks        INTEGER NOT NULL DEFAULT 0,
+    created_at    TIMESTAMP NOT NULL,
+    expires_at    TIMESTAMP,
+    last_click_at TIMESTAMP
+);
+
+CREATE INDEX idx_short_links_cat ON short_links (tenant_id, cat_id);
@@ -0,0 +1,10 @@
+-- * Una fila por ejecución programada: la réplica que inserta primero corre el job
+CREATE TABLE scheduler_runs (
+    job        TEXT NOT NULL,
+    slot       TIMESTAMP NOT NULL,
+    owner      TEXT NOT NULL,
+    claimed_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (job, slot)
+);
+
+CREATE INDEX idx_scheduler_runs_claimed ON scheduler_runs (claimed_at);
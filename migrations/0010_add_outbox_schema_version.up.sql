@@ -0,0 +1,2 @@
+-- * Versión del esquema del evento (ver /events/schemas); los eventos previos son v1
+ALTER TABLE outbox ADD COLUMN schema_version INTEGER NOT NULL DEFAULT 1;
@@ -0,0 +1,8 @@
+package migrations
+
+import "embed"
+
+// * Migraciones SQL embebidas en el binario: NNNN_nombre.up.sql / NNNN_nombre.down.sql
+//
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,52 @@
+package models
+
+import "time"
+
+const (
+	BlockIP   = "ip"
+	BlockCIDR = "cidr"
+	BlockASN  = "asn"
+)
+
+type BlockEntry struct {
+	ID        string     `json:"id"`
+	Kind      string     `json:"kind"`
+	Value     string     `json:"value"`
+	Reason    string     `json:"reason,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	// * Lo agregó el puntaje de abuso y no un admin
+	Auto bool `json:"auto,omitempty"`
+}
+
+// * Value: "203.0.113.7", "203.0.113.0/24" o "AS64500"; TTL 0 = permanente
+type BlockRequest struct {
+	Value      string `json:"value" binding:"required,max=64"`
+	Reason     string `json:"reason" binding:"max=200"`
+	TTLSeconds int    `json:"ttl_seconds" binding:"min=0"`
+}
+
+type AbuseSuspect struct {
+	IP                string  `json:"ip"`
+	ASN               string  `json:"asn,omitempty"`
+	Score             float64 `json:"score"`
+	RequestsPerMinute float64 `json:"requests_per_minute"`
+	Denied            int64   `json:"denied"`
+}
+
+type AbuseStats struct {
+	Tracked  int            `json:"tracked_ips"`
+	Denied   int64          `json:"denied"`
+	Blocked  int64          `json:"blocked"`
+	Suspects []AbuseSuspect `json:"suspects"`
+}
+
+// * Hashcash: encontrar un nonce tal que sha256(challenge + ":" + nonce) empiece con
+// * Difficulty bits en cero, y mandarlo como "challenge:nonce" en Header
+type PowChallenge struct {
+	Challenge  string    `json:"challenge"`
+	Difficulty int       `json:"difficulty"`
+	Algorithm  string    `json:"algorithm"`
+	Header     string    `json:"header"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+const (
+	AlertFiring   = "firing"
+	AlertResolved = "resolved"
+)
+
+// * Aviso del monitor de SLOs; es el cuerpo tal cual para webhooks genéricos
+type Alert struct {
+	Name      string    `json:"name"`
+	Status    string    `json:"status"`
+	Message   string    `json:"message"`
+	Value     float64   `json:"value"`
+	Threshold float64   `json:"threshold"`
+	Instance  string    `json:"instance"`
+	At        time.Time `json:"at"`
+}
@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+const (
+	ScopeWidget      = "widget"
+	ScopeProfiles    = "profiles"
+	ScopeCollections = "collections"
+	ScopeFeed        = "feed"
+)
+
+// * Token de solo lectura para el widget o integradores: un par de grupos de rutas,
+// * opcionalmente atado a orígenes. No sirve para rutas de usuario ni de admin
+type APIToken struct {
+	ID      string   `json:"id"`
+	Name    string   `json:"name"`
+	Tenant  string   `json:"tenant"`
+	Scopes  []string `json:"scopes"`
+	Origins []string `json:"origins,omitempty"`
+	// * sha256 del token; el token en claro solo se ve al crearlo
+	Hash       string     `json:"-"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	Uses       int64      `json:"uses"`
+}
+
+type APITokenRequest struct {
+	Name       string   `json:"name" binding:"required,max=80"`
+	Scopes     []string `json:"scopes" binding:"required,min=1,dive,oneof=widget profiles collections feed"`
+	Origins    []string `json:"origins" binding:"max=20,dive,required,max=200"`
+	TTLSeconds int      `json:"ttl_seconds" binding:"min=0"`
+}
+
+// * Única respuesta que incluye el token en claro
+type APITokenCreated struct {
+	APIToken
+	Token string `json:"token"`
+}
+
+// * Lo que el middleware deja en el contexto tras validar un token
+type TokenGrant struct {
+	TokenID string
+	Origins []string
+}
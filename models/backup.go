@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+const BackupFormatVersion = 1
+
+type BackupManifest struct {
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	Profiles  int       `json:"profiles"`
+	Swipes    int       `json:"swipes"`
+	Matches   int       `json:"matches"`
+}
@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+const (
+	BadgeFirstMatch       = "first_match"
+	BadgeHundredSwipes    = "swipes_100"
+	BadgeBreedConnoisseur = "breed_connoisseur"
+	BadgeWeekStreak       = "streak_7"
+)
+
+type Badge struct {
+	ID          string     `json:"id"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Earned      bool       `json:"earned"`
+	EarnedAt    *time.Time `json:"earned_at,omitempty"`
+}
+
+// * Días consecutivos (UTC) con al menos un swipe
+type Streak struct {
+	Current       int    `json:"current"`
+	Longest       int    `json:"longest"`
+	LastActiveDay string `json:"last_active_day,omitempty"`
+}
+
+type BadgesResponse struct {
+	Badges []Badge `json:"badges"`
+	Earned int     `json:"earned"`
+	Streak Streak  `json:"streak"`
+}
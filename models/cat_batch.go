@@ -0,0 +1,37 @@
+package models
+
+type CatBatch struct {
+	URLs      []string
+	Batch     int
+	Stale     bool
+	Meta      *BatchMeta
+	Requested int
+	// * Una por URL pedida que no llegó
+	Errors []BatchError
+	// * Venció el plazo de la petición con imágenes todavía en camino
+	Truncated bool
+}
+
+// * Metadatos de validación: permiten al cliente ver el costo en latencia de ?validated=true
+type BatchMeta struct {
+	Validated     bool  `json:"validated"`
+	FromPool      int   `json:"from_pool"`
+	ValidatedLive int   `json:"validated_live"`
+	ElapsedMs     int64 `json:"elapsed_ms"`
+	DeadlineMs    int64 `json:"deadline_ms"`
+}
+
+// * Posición del lote que quedó vacía y por qué
+type BatchError struct {
+	Index    int    `json:"index"`
+	Provider string `json:"provider,omitempty"`
+	Error    string `json:"error"`
+}
+
+// * Respuesta de ?partial=fail cuando el lote no se completó
+type PartialBatchError struct {
+	ErrorResponse
+	Requested int          `json:"requested"`
+	Delivered int          `json:"delivered"`
+	Errors    []BatchError `json:"errors"`
+}
@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 type CatProfile struct {
     ID          int      `json:"id"`
     Img         string   `json:"img"`
@@ -8,5 +10,44 @@ type CatProfile struct {
     Breed       string   `json:"breed"`
     Personality string   `json:"personality"`
     Hobbies     []string `json:"hobbies"`
+    // * Etiquetas libres para colecciones ("gato-de-regazo", "gato-de-oficina"); en minúsculas
+    Tags        []string `json:"tags,omitempty"`
     Bio         string   `json:"bio"`
-}
\ No newline at end of file
+    Status      string   `json:"status"`
+    // * Vacío para gatos del refugio; el X-User-ID del dueño para gatos registrados por usuarios
+    OwnerID     string   `json:"owner_id,omitempty"`
+    // * Promoción pagada por el refugio: lugar asegurado en los mazos (ver services.SponsorRotation)
+    Sponsored   *Sponsorship `json:"sponsored,omitempty"`
+    UpdatedAt   time.Time `json:"updated_at"`
+    // * Solo en perfiles creados desde que existe el campo (importación, gatos de usuarios)
+    CreatedAt   *time.Time `json:"created_at,omitempty"`
+    // * Solo si la imagen ya pasó por el proxy o el validador
+    ImageMeta   *ImageMeta `json:"image_meta,omitempty"`
+    // * Colores dominantes de la imagen ("#rrggbb"), para teñir la tarjeta mientras carga
+    Palette     []string   `json:"palette,omitempty"`
+    // * Borrado lógico: fuera de mazos y listados públicos, pero sigue en matches e historial
+    DeletedAt   *time.Time `json:"deleted_at,omitempty"`
+    // * Solo en los listados de admin (ver services.ProfileQualityOf)
+    Quality     *ProfileQuality `json:"quality,omitempty"`
+}
+
+// * Copia defensiva: los slices no se comparten con el estado interno del servicio
+func (c CatProfile) Clone() CatProfile {
+    c.Hobbies = append([]string(nil), c.Hobbies...)
+    if c.Tags != nil {
+        c.Tags = append([]string(nil), c.Tags...)
+    }
+    if c.Sponsored != nil {
+        sponsored := c.Sponsored.Clone()
+        c.Sponsored = &sponsored
+    }
+    if c.DeletedAt != nil {
+        deletedAt := *c.DeletedAt
+        c.DeletedAt = &deletedAt
+    }
+    if c.CreatedAt != nil {
+        createdAt := *c.CreatedAt
+        c.CreatedAt = &createdAt
+    }
+    return c
+}
@@ -0,0 +1,54 @@
+package models
+
+const (
+	// * Lote incompleto: se devuelve lo que hubo (por defecto) o falla entero
+	PartialAllow = "allow"
+	PartialFail  = "fail"
+)
+
+type CatsQuery struct {
+	Count int    `form:"count" binding:"omitempty,min=1,max=10"`
+	Tag   string `form:"tag" binding:"omitempty,alphanum,max=32"`
+	Size  string `form:"size" binding:"omitempty,oneof=xsmall small medium square"`
+	// * Garantiza que cada URL pasó un HEAD dentro del deadline de la petición
+	Validated bool   `form:"validated"`
+	Partial   string `form:"partial" binding:"omitempty,oneof=allow fail"`
+	// * Plazo en ms: al vencer se entrega lo que ya llegó y la respuesta va con truncated
+	MaxWaitMs int `form:"max_wait_ms" binding:"omitempty,min=1,max=30000"`
+}
+
+type ProfilesQuery struct {
+	// * Búsqueda de texto (nombre, raza, personalidad, hobbies, etiquetas, bio) con tolerancia
+	// * a errores de tipeo; los resultados salen por relevancia en vez de por id
+	Q     string `form:"q" binding:"omitempty,max=100"`
+	Breed string `form:"breed" binding:"omitempty,max=64"`
+	Hobby string `form:"hobby" binding:"omitempty,max=64"`
+	// * ?tags=a,b o ?tags=a&tags=b: perfiles con todas
+	Tags   []string `form:"tags" binding:"omitempty,max=10,dive,max=200"`
+	MinAge int      `form:"min_age" binding:"omitempty,min=0,max=30"`
+	MaxAge int      `form:"max_age" binding:"omitempty,min=0,max=30,gtefield=MinAge"`
+	Page   int      `form:"page" binding:"omitempty,min=1"`
+	Limit  int      `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+type AdminProfilesQuery struct {
+	ProfilesQuery
+	IncludeDeleted bool   `form:"include_deleted"`
+	Status         string `form:"status" binding:"omitempty,oneof=draft active paused adopted pending"`
+}
+
+type ProfileQualityQuery struct {
+	// * Solo perfiles con score menor o igual
+	MaxScore int    `form:"max_score" binding:"omitempty,min=1,max=100"`
+	Status   string `form:"status" binding:"omitempty,oneof=draft active paused adopted pending"`
+	Limit    int    `form:"limit" binding:"omitempty,min=1,max=500"`
+}
+
+type ProfileIDParam struct {
+	ID int `uri:"id" binding:"required,min=1"`
+}
+
+type QRQuery struct {
+	Size  int    `form:"size" binding:"omitempty,min=64,max=1024"`
+	Level string `form:"level" binding:"omitempty,oneof=L M Q H l m q h"`
+}
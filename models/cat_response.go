@@ -1,7 +1,20 @@
 package models
 
 type CatResponse struct {
-	URLs  []string `json:"urls"`
-	Count int      `json:"count"`
-	Batch int      `json:"batch"`
+	URLs  []string   `json:"urls"`
+	Count int        `json:"count"`
+	Batch int        `json:"batch"`
+	Stale bool       `json:"stale,omitempty"`
+	Meta  *BatchMeta `json:"meta,omitempty"`
+	// * Lote incompleto: delivered < requested y errors dice qué pasó con cada faltante
+	Requested int          `json:"requested"`
+	Delivered int          `json:"delivered"`
+	Partial   bool         `json:"partial,omitempty"`
+	Errors    []BatchError `json:"errors,omitempty"`
+	// * max_wait_ms (o el deadline de validated) cortó la espera
+	Truncated bool `json:"truncated,omitempty"`
+	// * Tema de temporada aplicado a las imágenes, para que la UI combine
+	Theme *ActiveTheme `json:"theme,omitempty"`
+	// * Por URL, solo las imágenes de las que ya se sabe algo (pool validado)
+	ImageMeta map[string]ImageMeta `json:"image_meta,omitempty"`
 }
@@ -0,0 +1,28 @@
+package models
+
+// * Fallas inyectadas con cierta probabilidad (0-1) en cada petición o llamada
+type ChaosFaults struct {
+	LatencyMs   int     `json:"latency_ms" binding:"min=0,max=30000"`
+	LatencyRate float64 `json:"latency_rate" binding:"min=0,max=1"`
+	ErrorRate   float64 `json:"error_rate" binding:"min=0,max=1"`
+}
+
+type ChaosUpstreamFaults struct {
+	ChaosFaults
+	// * Respuestas 200 con cuerpo roto (JSON cortado, bytes que no son imagen)
+	MalformedRate float64 `json:"malformed_rate" binding:"min=0,max=1"`
+	// * Vacío = todos los proveedores
+	Providers []string `json:"providers,omitempty"`
+}
+
+// * Cuerpo de PUT /admin/chaos; HTTP afecta a la API propia y Upstream a los proveedores
+type ChaosSettings struct {
+	Enabled  bool                `json:"enabled"`
+	HTTP     ChaosFaults         `json:"http"`
+	Upstream ChaosUpstreamFaults `json:"upstream"`
+}
+
+type ChaosStats struct {
+	Settings ChaosSettings    `json:"settings"`
+	Injected map[string]int64 `json:"injected"`
+}
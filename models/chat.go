@@ -0,0 +1,93 @@
+package models
+
+import "time"
+
+const (
+	ChatEventMessage  = "message"
+	ChatEventReaction = "reaction"
+	ChatEventRead     = "read"
+	ChatEventTyping   = "typing"
+	ChatEventPresence = "presence"
+	ChatEventNudge    = "nudge"
+	ChatEventBadge    = "badge"
+	ChatEventQuest    = "quest"
+)
+
+// * Un chat por par de gatos: los dos dueños lo ven desde su propio match
+type Conversation struct {
+	ID           string   `json:"id"`
+	Participants []string `json:"participants"`
+}
+
+type Message struct {
+	ID             string    `json:"id"`
+	ConversationID string    `json:"conversation_id,omitempty"`
+	SenderID       string    `json:"sender_id"`
+	Text           string    `json:"text"`
+	CreatedAt      time.Time `json:"created_at"`
+	// * emoji -> usuarios que reaccionaron
+	Reactions map[string][]string `json:"reactions,omitempty"`
+}
+
+type SendMessageRequest struct {
+	Text string `json:"text" binding:"required,max=2000"`
+}
+
+type ReactionRequest struct {
+	Emoji string `json:"emoji" binding:"required,max=16"`
+}
+
+type ReactionQuery struct {
+	Emoji string `form:"emoji" binding:"required,max=16"`
+}
+
+type ReadRequest struct {
+	LastReadMessageID string `json:"last_read_message_id" binding:"required,max=32"`
+}
+
+type MessageIDParam struct {
+	ID string `uri:"id" binding:"required,max=32"`
+}
+
+type MessagesResponse struct {
+	Conversation Conversation `json:"conversation"`
+	Messages     []Message    `json:"messages"`
+	Count        int          `json:"count"`
+	// * Último mensaje leído por cada participante
+	ReadBy map[string]string `json:"read_by"`
+}
+
+// * Lo que viaja por el WebSocket; solo se llena el campo del tipo de evento
+type ChatEvent struct {
+	Type              string    `json:"type"`
+	ConversationID    string    `json:"conversation_id,omitempty"`
+	Message           *Message  `json:"message,omitempty"`
+	UserID            string    `json:"user_id,omitempty"`
+	Emoji             string    `json:"emoji,omitempty"`
+	Removed           bool      `json:"removed,omitempty"`
+	MessageID         string    `json:"message_id,omitempty"`
+	MatchID           string    `json:"match_id,omitempty"`
+	Text              string    `json:"text,omitempty"`
+	LastReadMessageID string    `json:"last_read_message_id,omitempty"`
+	Typing            *bool     `json:"typing,omitempty"`
+	Presence          *Presence `json:"presence,omitempty"`
+	Badge             *Badge    `json:"badge,omitempty"`
+	Quest             *Quest    `json:"quest,omitempty"`
+	At                time.Time `json:"at"`
+}
+
+// * Efímero: vive solo en memoria mientras el proceso corre
+type Presence struct {
+	UserID   string     `json:"user_id"`
+	Online   bool       `json:"online"`
+	LastSeen *time.Time `json:"last_seen,omitempty"`
+	// * El usuario eligió no compartir su presencia
+	Hidden bool `json:"hidden,omitempty"`
+}
+
+// * Lo que el cliente puede mandar por el WebSocket
+type ChatClientEvent struct {
+	Type    string `json:"type"`
+	MatchID string `json:"match_id"`
+	Typing  bool   `json:"typing"`
+}
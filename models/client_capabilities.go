@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// * POST /me/capabilities: lo que el cliente sabe mostrar, una vez por sesión
+type CapabilitiesRequest struct {
+	Formats  []string `json:"formats" binding:"required,min=1,max=4,dive,oneof=jpeg png gif webp"`
+	MaxWidth int      `json:"max_width" binding:"omitempty,min=1,max=8192"`
+}
+
+// * Capacidades declaradas y lo que se deduce de ellas para las imágenes
+type MediaCapabilities struct {
+	Formats  []string `json:"formats"`
+	MaxWidth int      `json:"max_width,omitempty"`
+	// * Calidad que se aplica cuando la petición no trae ?quality ni Save-Data
+	Quality string `json:"quality"`
+	// * Sin gif entre los formatos: nada de GIFs en /cats
+	StaticOnly bool      `json:"static_only"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
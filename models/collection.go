@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+// * Colección curada por el admin ("Seniors adorables", "Gatitos juguetones"): los perfiles
+// * listados más los que tengan todas las etiquetas
+type Collection struct {
+	Slug        string `json:"slug"`
+	Tenant      string `json:"-"`
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	// * Portada propia; vacía = la imagen del primer perfil
+	Image      string    `json:"image,omitempty"`
+	ProfileIDs []int     `json:"profile_ids,omitempty"`
+	Tags       []string  `json:"tags,omitempty"`
+	Position   int       `json:"position"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+type CollectionRequest struct {
+	Title       string   `json:"title" binding:"required,max=80"`
+	Description string   `json:"description" binding:"max=500"`
+	Image       string   `json:"image" binding:"omitempty,url"`
+	ProfileIDs  []int    `json:"profile_ids" binding:"max=200,dive,min=1"`
+	Tags        []string `json:"tags" binding:"max=10,dive,required,max=32"`
+	// * Orden en GET /collections, de menor a mayor
+	Position int `json:"position"`
+}
+
+type CollectionParam struct {
+	Slug string `uri:"slug" binding:"required,max=64"`
+}
+
+// * Para el listado: portada resuelta y cuántos perfiles publicados tiene hoy
+type CollectionSummary struct {
+	Collection
+	Cover string `json:"cover,omitempty"`
+	Count int    `json:"count"`
+}
+
+type CollectionResponse struct {
+	CollectionSummary
+	Cats []CatProfile `json:"cats"`
+}
@@ -0,0 +1,17 @@
+package models
+
+type NameCompatibilityQuery struct {
+	A string `form:"a" binding:"required,max=40"`
+	B string `form:"b" binding:"required,max=40"`
+	// * Si falta se negocia con Accept-Language
+	Locale string `form:"locale" binding:"omitempty,oneof=es en"`
+}
+
+type NameCompatibility struct {
+	A           string `json:"a"`
+	B           string `json:"b"`
+	Score       int    `json:"score"`
+	Verdict     string `json:"verdict"`
+	Explanation string `json:"explanation"`
+	Locale      string `json:"locale"`
+}
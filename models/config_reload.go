@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+type ConfigChange struct {
+	Key  string `json:"key"`
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type ConfigReload struct {
+	// * "SIGHUP" o "api <ip>"
+	Source     string         `json:"source"`
+	ReloadedAt time.Time      `json:"reloaded_at"`
+	Changes    []ConfigChange `json:"changes"`
+	// * Claves de CONFIG_FILE que cambiaron pero solo se aplican al reiniciar
+	RestartRequired []string `json:"restart_required,omitempty"`
+}
+
+type RuntimeConfig struct {
+	File       string            `json:"file,omitempty"`
+	Values     map[string]string `json:"values"`
+	LastReload *ConfigReload     `json:"last_reload,omitempty"`
+}
@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+type SummaryCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// * Rango de edad cerrado; Max 0 = sin tope ("11+")
+type AgeBucket struct {
+	Label string `json:"label"`
+	Min   int    `json:"min"`
+	Max   int    `json:"max,omitempty"`
+	Count int    `json:"count"`
+}
+
+type AgeStats struct {
+	Min    int     `json:"min"`
+	Max    int     `json:"max"`
+	Mean   float64 `json:"mean"`
+	Median float64 `json:"median"`
+}
+
+// * Perfiles publicados con datos faltantes o repetidos: para detectar un cats.json roto
+type DatasetIssues struct {
+	MissingImage   int `json:"missing_image"`
+	MissingBio     int `json:"missing_bio"`
+	NoHobbies      int `json:"no_hobbies"`
+	DuplicateNames int `json:"duplicate_names"`
+}
+
+// * Resumen de los perfiles publicados; las listas van de mayor a menor
+type DatasetSummary struct {
+	Total         int            `json:"total"`
+	ByStatus      []SummaryCount `json:"by_status"`
+	Breeds        []SummaryCount `json:"breeds"`
+	Ages          []AgeBucket    `json:"age_histogram"`
+	AgeStats      AgeStats       `json:"age_stats"`
+	Hobbies       []SummaryCount `json:"hobbies"`
+	Personalities []SummaryCount `json:"personalities"`
+	Issues        DatasetIssues  `json:"issues"`
+	// * Cuándo se publicó el catálogo resumido; cambia con cada recarga o edición
+	GeneratedAt time.Time `json:"generated_at"`
+}
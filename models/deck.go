@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// * Semillas hasta 2^53 para que JavaScript las lea sin perder precisión
+const MaxDeckSeed = 1 << 53
+
+type DeckQuery struct {
+	Seed  *int64   `form:"seed" binding:"omitempty,min=0,max=9007199254740991"`
+	Limit int      `form:"limit" binding:"omitempty,min=1,max=50"`
+	Tags  []string `form:"tags" binding:"omitempty,max=10,dive,max=200"`
+	// * Solo perfiles cuya imagen respondió dentro del plazo; los que no alcanzaron a
+	// * revisarse quedan fuera y la respuesta va con truncated
+	MaxWaitMs int `form:"max_wait_ms" binding:"omitempty,min=1,max=30000"`
+}
+
+type DeckResponse struct {
+	Cats  []CatProfile `json:"cats"`
+	Count int          `json:"count"`
+	Seed  int64        `json:"seed"`
+	// * max_wait_ms venció antes de revisar todo el mazo
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// * POST /deck/prefetch: el mazo se arma en segundo plano y queda reservado hasta ExpiresAt
+type DeckPrefetchResponse struct {
+	// * "preparing" mientras se arma, "ready" si ya se puede pedir
+	Status    string    `json:"status"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
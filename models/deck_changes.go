@@ -0,0 +1,36 @@
+package models
+
+const (
+	// * Perfil nuevo que entra en el mazo del usuario
+	DeckChangeRecommended = "recommended"
+	// * Perfil que el cliente tiene que sacar (o reemplazar) en su mazo
+	DeckChangeInvalidated = "invalidated"
+
+	DeckChangeUpdated = "updated"
+	DeckChangeRemoved = "removed"
+	DeckChangeSwiped  = "swiped"
+)
+
+// * Sin since se devuelve el cursor actual sin esperar
+type DeckChangesQuery struct {
+	Since *int64 `form:"since" binding:"omitempty,min=0"`
+	// * Segundos que se sostiene la petición si no hay cambios (tope: DECK_CHANGES_HOLD)
+	Wait int `form:"wait" binding:"omitempty,min=1,max=30"`
+}
+
+type DeckChange struct {
+	Cursor int64  `json:"cursor"`
+	Type   string `json:"type"`
+	// * Solo en invalidated: updated, removed o swiped
+	Reason string `json:"reason,omitempty"`
+	CatID  int    `json:"cat_id"`
+	// * El perfil actual en recommended y en invalidated por updated
+	Cat *CatProfile `json:"cat,omitempty"`
+}
+
+type DeckChangesResponse struct {
+	Cursor  int64        `json:"cursor"`
+	Changes []DeckChange `json:"changes"`
+	// * since ya no está en el historial: hay que volver a pedir el mazo completo
+	Reset bool `json:"reset,omitempty"`
+}
@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+type DigestSubscribeRequest struct {
+	Email string `json:"email" binding:"required,email,max=254"`
+}
+
+type DigestUnsubscribeQuery struct {
+	Token string `form:"token" binding:"required,hexadecimal,len=32"`
+}
+
+type DigestSubscription struct {
+	UserID       string     `json:"user_id"`
+	Email        string     `json:"email"`
+	SubscribedAt time.Time  `json:"subscribed_at"`
+	LastSentAt   *time.Time `json:"last_sent_at,omitempty"`
+	// * Secreto del enlace de baja: nunca sale por la API
+	UnsubscribeToken string `json:"-"`
+	// * Gatos ya enviados como "nuevos", para no repetirlos semana a semana
+	SentCatIDs map[int]bool `json:"-"`
+}
+
+type DigestCat struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	Breed string `json:"breed"`
+	Img   string `json:"img"`
+	URL   string `json:"url"`
+	Likes int    `json:"likes,omitempty"`
+}
+
+type Digest struct {
+	UserID    string      `json:"user_id"`
+	WeekStart string      `json:"week_start"`
+	WeekEnd   string      `json:"week_end"`
+	NewCats   []DigestCat `json:"new_cats"`
+	TopCats   []DigestCat `json:"top_cats"`
+	// * Solo para la plantilla del correo
+	UnsubscribeURL string `json:"-"`
+}
+
+type DigestRunResult struct {
+	Sent    int `json:"sent"`
+	Skipped int `json:"skipped"`
+	Failed  int `json:"failed"`
+}
@@ -0,0 +1,7 @@
+package models
+
+// * Version es el número ("1", "v1") o "latest"
+type EventSchemaParam struct {
+	Topic   string `uri:"topic" binding:"required,max=64"`
+	Version string `uri:"version" binding:"required,max=8"`
+}
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+const (
+	HealthHealthy  = "healthy"
+	HealthDegraded = "degraded"
+)
+
+// * Frescura de los datos servidos: un servicio puede estar arriba y aun así degradado
+type Freshness struct {
+	DatasetUpdatedAt  *time.Time `json:"dataset_updated_at,omitempty"`
+	DatasetAgeSeconds int64      `json:"dataset_age_seconds"`
+	LastProbeOK       *time.Time `json:"last_probe_ok,omitempty"`
+	// * -1 si todavía no hubo ningún probe exitoso
+	ProbeAgeSeconds int64 `json:"probe_age_seconds"`
+	// * Motivos de degradación; vacío = sano
+	Degraded []string `json:"degraded,omitempty"`
+}
+
+func (f Freshness) Status() string {
+	if len(f.Degraded) > 0 {
+		return HealthDegraded
+	}
+	return HealthHealthy
+}
@@ -4,4 +4,5 @@ type HealthResponse struct {
 	Status    string `json:"status"`
 	Timestamp int64  `json:"timestamp"`
 	Batches   int    `json:"batches"`
+	Freshness Freshness `json:"freshness"`
 }
\ No newline at end of file
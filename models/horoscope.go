@@ -0,0 +1,29 @@
+package models
+
+type HoroscopeQuery struct {
+	// * Por defecto hoy (UTC)
+	Date string `form:"date" binding:"omitempty,datetime=2006-01-02"`
+	// * Si falta se negocia con Accept-Language
+	Locale string `form:"locale" binding:"omitempty,oneof=es en pt"`
+}
+
+type HoroscopeSign struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Symbol string `json:"symbol"`
+}
+
+type Horoscope struct {
+	CatID       int           `json:"cat_id"`
+	CatName     string        `json:"cat_name"`
+	Date        string        `json:"date"`
+	Locale      string        `json:"locale"`
+	Sign        HoroscopeSign `json:"sign"`
+	Overview    string        `json:"overview"`
+	Love        string        `json:"love"`
+	Naps        string        `json:"naps"`
+	Mood        string        `json:"mood"`
+	LuckyItem   string        `json:"lucky_item"`
+	LuckyNumber int           `json:"lucky_number"`
+	Reading     string        `json:"reading"`
+}
@@ -0,0 +1,8 @@
+package models
+
+type ImageData struct {
+	SourceURL   string
+	ContentType string
+	Data        []byte
+	Meta        ImageMeta
+}
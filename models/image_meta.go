@@ -0,0 +1,11 @@
+package models
+
+// * Lo que se sabe de una imagen: con HEAD solo tipo y tamaño; al descargarla, también
+// * las dimensiones. Sirve para reservar espacio en el layout antes de que cargue
+type ImageMeta struct {
+	Width       int    `json:"width,omitempty"`
+	Height      int    `json:"height,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Format      string `json:"format,omitempty"`
+	Bytes       int64  `json:"bytes,omitempty"`
+}
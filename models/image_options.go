@@ -0,0 +1,11 @@
+package models
+
+type ImageOptions struct {
+	Tag  string `json:"tag,omitempty"`
+	Size string `json:"size,omitempty"`
+	// * Decoración de cataas (temas de temporada); TheCatAPI la ignora
+	Says   string `json:"says,omitempty"`
+	Filter string `json:"filter,omitempty"`
+	// * Sin GIFs (calidad baja); cataas no lo garantiza, el proxy convierte lo que llegue
+	StaticOnly bool `json:"static_only,omitempty"`
+}
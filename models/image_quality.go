@@ -0,0 +1,11 @@
+package models
+
+const (
+	ImageQualityLow  = "low"
+	ImageQualityHigh = "high"
+)
+
+// * ?quality= gana sobre el header Save-Data
+type QualityQuery struct {
+	Quality string `form:"quality" binding:"omitempty,oneof=low high"`
+}
@@ -0,0 +1,49 @@
+package models
+
+import "time"
+
+const (
+	JobQueued    = "queued"
+	JobRunning   = "running"
+	JobSucceeded = "succeeded"
+	JobFailed    = "failed"
+)
+
+const (
+	JobImport        = "import"
+	JobRefreshImages = "refresh_images"
+	JobBackup        = "backup"
+	JobExport        = "export"
+)
+
+const (
+	JobEventUpdated  = "job.updated"
+	JobEventFinished = "job.finished"
+)
+
+type JobProgress struct {
+	Done  int `json:"done"`
+	Total int `json:"total"`
+}
+
+// * Operación larga de admin en segundo plano (ver services.JobManager)
+type Job struct {
+	ID       string      `json:"id"`
+	Kind     string      `json:"kind"`
+	Status   string      `json:"status"`
+	Tenant   string      `json:"tenant,omitempty"`
+	Progress JobProgress `json:"progress"`
+	// * Resumen del resultado (reporte de import, conteos del refresco…)
+	Result any    `json:"result,omitempty"`
+	Error  string `json:"error,omitempty"`
+	// * Ruta para bajar el archivo generado (respaldos, exports)
+	Download   string     `json:"download,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+type JobEvent struct {
+	Type string `json:"type"`
+	Job  Job    `json:"job"`
+}
@@ -0,0 +1,16 @@
+package models
+
+// * Campos cuyo valor es un mapa con claves de datos (IDs de usuario, emojis, nombres de
+// * proveedor o de caché): en camelCase se renombra el campo pero no las claves de adentro.
+// ! Un mapa nuevo con claves de datos en una respuesta tiene que sumarse acá
+var DataMapFields = []string{
+	"caches",
+	"image_meta",
+	"injected",
+	"provider_errors",
+	"reactions",
+	"read_by",
+	"rejected",
+	"scores",
+	"values",
+}
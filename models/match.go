@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+type Match struct {
+	ID      string `json:"id"`
+	UserID  string `json:"user_id"`
+	CatID   int    `json:"cat_id"`
+	CatName string `json:"cat_name"`
+	// * Solo en matches mutuos: el gato propio que participó
+	WithCatID int  `json:"with_cat_id,omitempty"`
+	Mutual    bool `json:"mutual,omitempty"`
+	// * Frases sugeridas para empezar a hablar; se generan al crear el match
+	Icebreakers []string `json:"icebreakers,omitempty"`
+	// * Ciclo de "ghosteo": aviso, archivo y reactivación manual
+	NudgedAt   *time.Time `json:"nudged_at,omitempty"`
+	ArchivedAt *time.Time `json:"archived_at,omitempty"`
+	RevivedAt  *time.Time `json:"revived_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type MatchIDParam struct {
+	ID string `uri:"id" binding:"required,max=32"`
+}
+
+type IcebreakersResponse struct {
+	MatchID     string   `json:"match_id"`
+	Icebreakers []string `json:"icebreakers"`
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+const (
+	TopicSwipeRecorded = "swipe.recorded"
+	TopicMatchCreated  = "match.created"
+)
+
+type OutboxEvent struct {
+	ID       string `json:"id"`
+	TenantID string `json:"tenant_id"`
+	Topic    string `json:"topic"`
+	// * Versión del esquema del tema con que se validó el payload (ver /events/schemas)
+	SchemaVersion int       `json:"schema_version"`
+	Payload       []byte    `json:"payload"`
+	CreatedAt     time.Time `json:"created_at"`
+	Attempts      int       `json:"attempts"`
+}
@@ -0,0 +1,23 @@
+package models
+
+// * Alta de un gato propio: la imagen y el estado los pone el servidor
+type OwnedCatRequest struct {
+	Name        string   `json:"name" binding:"required,max=64"`
+	Age         int      `json:"age" binding:"min=0,max=30"`
+	Breed       string   `json:"breed" binding:"required,max=64"`
+	Personality string   `json:"personality" binding:"max=120"`
+	Hobbies     []string `json:"hobbies" binding:"max=10,dive,max=64"`
+	Bio         string   `json:"bio" binding:"max=1000"`
+	// * A dónde se manda el enlace de confirmación; obligatorio con la verificación activa
+	Email string `json:"email" binding:"omitempty,email,max=254"`
+}
+
+type VerifyCatQuery struct {
+	Token string `form:"token" binding:"required,len=64,hexadecimal"`
+}
+
+// * 409 al registrar un gato que parece uno ya cargado
+type DuplicateResponse struct {
+	ErrorResponse
+	Duplicate DuplicateWarning `json:"duplicate"`
+}
@@ -0,0 +1,6 @@
+package models
+
+type PageQuery struct {
+	Cursor string `form:"cursor"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=100"`
+}
@@ -0,0 +1,12 @@
+package models
+
+const (
+	PairPending  = "pending"
+	PairMatched  = "matched"
+	PairDeclined = "declined"
+)
+
+type PairState struct {
+	Status  string `json:"status"`
+	LikedBy int    `json:"liked_by,omitempty"`
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// * Preferencias de emparejamiento guardadas en el servidor; el mazo las aplica solo
+type Preferences struct {
+	Breeds        []string `json:"breeds" binding:"max=20,dive,max=64"`
+	MinAge        int      `json:"min_age" binding:"min=0,max=30"`
+	MaxAge        int      `json:"max_age" binding:"omitempty,min=0,max=30,gtefield=MinAge"`
+	MaxDistanceKm int      `json:"max_distance_km" binding:"min=0,max=20000"`
+	MediaType     string   `json:"media_type" binding:"omitempty,oneof=image gif any"`
+	Personalities []string `json:"personalities" binding:"max=20,dive,max=64"`
+	// * Arquetipo del quiz: no filtra, solo adelanta en el mazo a los gatos compatibles
+	Archetype string `json:"archetype,omitempty" binding:"omitempty,oneof=adventurer homebody thinker clown"`
+	// * Privacidad del chat: ocultar "en línea/última vez" y el "escribiendo…"
+	HidePresence bool       `json:"hide_presence"`
+	HideTyping   bool       `json:"hide_typing"`
+	UpdatedAt    *time.Time `json:"updated_at,omitempty"`
+}
@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+const (
+	ProfileChangeCreated = "created"
+	ProfileChangeUpdated = "updated"
+	ProfileChangeDeleted = "deleted"
+)
+
+// * Sin since se devuelve el catálogo completo como created, desde el principio
+type ProfileChangesQuery struct {
+	Since string `form:"since"`
+	Limit int    `form:"limit" binding:"omitempty,min=1,max=500"`
+}
+
+type ProfileChange struct {
+	Type string `json:"type"`
+	ID   int    `json:"id"`
+	// * Para retomar justo después de este cambio
+	Cursor string    `json:"cursor"`
+	At     time.Time `json:"at"`
+	// * El perfil actual; nil en deleted
+	Profile *CatProfile `json:"profile,omitempty"`
+}
+
+type ProfileChangesResponse struct {
+	Changes []ProfileChange `json:"changes"`
+	// * El since de la próxima petición; igual al recibido si no hubo cambios
+	Cursor  string `json:"cursor"`
+	HasMore bool   `json:"has_more"`
+}
@@ -0,0 +1,16 @@
+package models
+
+type ProfileFilter struct {
+	Breed string
+	Hobby string
+	// * Tiene que tener todas (normalizadas, ver services.NormalizeTags)
+	Tags   []string
+	MinAge int
+	MaxAge int
+	Offset int
+	Limit  int
+	// * Vacío = cualquier estado
+	Status string
+	// * Solo para listados de admin
+	IncludeDeleted bool
+}
@@ -0,0 +1,61 @@
+package models
+
+type ProfileImportRow struct {
+	ID          int      `json:"id" binding:"omitempty,min=1"`
+	Name        string   `json:"name" binding:"required,max=64"`
+	Age         int      `json:"age" binding:"min=0,max=30"`
+	Breed       string   `json:"breed" binding:"required,max=64"`
+	Personality string   `json:"personality" binding:"max=120"`
+	Hobbies     []string `json:"hobbies" binding:"max=10,dive,max=64"`
+	Tags        []string `json:"tags" binding:"max=20,dive,max=32"`
+	Bio         string   `json:"bio" binding:"max=1000"`
+	Status      string   `json:"status" binding:"omitempty,oneof=draft active paused adopted"`
+	Img         string   `json:"img" binding:"omitempty,url"`
+	// * Línea del archivo de origen, para reportar errores
+	Line int `json:"-"`
+}
+
+type ImportRowError struct {
+	Line    int    `json:"line"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+type ImportQuery struct {
+	DryRun bool   `form:"dry_run"`
+	Format string `form:"format" binding:"omitempty,oneof=csv jsonl"`
+	// * Escribe bio y personalidad de las filas que las traen vacías
+	GenerateText bool `form:"generate_text"`
+	// * Aplica aunque haya perfiles que parecen repetidos (quedan como aviso en el reporte)
+	Force bool `form:"force"`
+}
+
+type ExportQuery struct {
+	Format         string `form:"format" binding:"omitempty,oneof=csv jsonl"`
+	IncludeDeleted bool   `form:"include_deleted"`
+}
+
+type ImportReport struct {
+	DryRun  bool             `json:"dry_run"`
+	Applied bool             `json:"applied"`
+	Rows    int              `json:"rows"`
+	Created int              `json:"created"`
+	Updated int              `json:"updated"`
+	Errors  []ImportRowError `json:"errors,omitempty"`
+	// * Altas que parecen un perfil existente; sin ?force=true frenan el import
+	Duplicates []DuplicateWarning `json:"duplicates,omitempty"`
+}
+
+const (
+	DuplicateSameIdentity = "same_name_breed_age"
+	DuplicateSimilarBio   = "similar_bio"
+)
+
+type DuplicateWarning struct {
+	// * Solo en imports
+	Line         int     `json:"line,omitempty"`
+	ExistingID   int     `json:"existing_id"`
+	ExistingName string  `json:"existing_name"`
+	Reason       string  `json:"reason"`
+	Similarity   float64 `json:"similarity,omitempty"`
+}
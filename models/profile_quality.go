@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// * Lo que le falta a un perfil para estar completo (ver services.ProfileQualityOf)
+const (
+	QualityMissingImage       = "missing_image"
+	QualityMissingBio         = "missing_bio"
+	QualityShortBio           = "short_bio"
+	QualityNoHobbies          = "no_hobbies"
+	QualityFewHobbies         = "few_hobbies"
+	QualityMissingBreed       = "missing_breed"
+	QualityMissingPersonality = "missing_personality"
+)
+
+// * Score de 0 a 100; 100 = nada que mejorar
+type ProfileQuality struct {
+	Score   int      `json:"score"`
+	Missing []string `json:"missing,omitempty"`
+}
+
+type QualityReportEntry struct {
+	ID     int    `json:"id"`
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	ProfileQuality
+}
+
+// * Peores primero; los promedios cubren todos los perfiles, no solo los listados
+type QualityReport struct {
+	Profiles     []QualityReportEntry `json:"profiles"`
+	Count        int                  `json:"count"`
+	Total        int                  `json:"total"`
+	AverageScore float64              `json:"average_score"`
+	Complete     int                  `json:"complete"`
+	// * Perfiles a los que les falta cada cosa
+	Missing     []SummaryCount `json:"missing"`
+	GeneratedAt time.Time      `json:"generated_at"`
+}
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+const (
+	StatusDraft   = "draft"
+	StatusActive  = "active"
+	StatusPaused  = "paused"
+	StatusAdopted = "adopted"
+	// * Gato de usuario esperando que el dueño confirme el correo (doble opt-in)
+	StatusPending = "pending"
+)
+
+type StatusRequest struct {
+	Status string `json:"status" binding:"required,oneof=draft active paused adopted"`
+}
+
+// * Evento emitido cada vez que un perfil cambia de estado
+type ProfileTransition struct {
+	CatID   int       `json:"cat_id"`
+	CatName string    `json:"cat_name"`
+	From    string    `json:"from"`
+	To      string    `json:"to"`
+	At      time.Time `json:"at"`
+}
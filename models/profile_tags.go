@@ -0,0 +1,13 @@
+package models
+
+type TagsRequest struct {
+	Tags []string `json:"tags" binding:"max=20,dive,required,max=32"`
+}
+
+type TagsQuery struct {
+	Limit int `form:"limit" binding:"omitempty,min=1,max=200"`
+}
+
+type TagParam struct {
+	Tag string `uri:"tag" binding:"required,max=32"`
+}
@@ -0,0 +1,27 @@
+package models
+
+type ProviderStats struct {
+	Name            string `json:"name"`
+	Weight          int    `json:"weight"`
+	EffectiveWeight int    `json:"effective_weight"`
+	Failures        int    `json:"failures"`
+}
+
+// * Presupuesto de peticiones salientes a un proveedor (ver PROVIDER_RATE_LIMITS)
+type OutboundStats struct {
+	Provider       string  `json:"provider"`
+	LimitPerSecond int     `json:"limit_per_second"`
+	Allowed        int64   `json:"allowed"`
+	Queued         int64   `json:"queued"`
+	Overflow       int64   `json:"overflow"`
+	QueueDepth     int     `json:"queue_depth"`
+	AvgWaitMs      float64 `json:"avg_wait_ms"`
+}
+
+type DNSCacheStats struct {
+	Entries  int    `json:"entries"`
+	Hits     int64  `json:"hits"`
+	Misses   int64  `json:"misses"`
+	Errors   int64  `json:"errors"`
+	IPFamily string `json:"ip_family"`
+}
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+type PruneStats struct {
+	Runs                int        `json:"runs"`
+	Checked             int        `json:"checked"`
+	Broken              int        `json:"broken"`
+	Replaced            int        `json:"replaced"`
+	LastRun             *time.Time `json:"last_run,omitempty"`
+	LastChecked         int        `json:"last_checked"`
+	LastReplaced        int        `json:"last_replaced"`
+	LastReplacementRate float64    `json:"last_replacement_rate"`
+}
@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+const (
+	QuestLikeBreed   = "like_breed"
+	QuestSwipes      = "swipes"
+	QuestSendMessage = "send_message"
+	QuestMatch       = "match"
+	QuestLikeSenior  = "like_senior"
+)
+
+// * Misiones del día: el servidor las elige, mide el progreso y entrega la recompensa
+type Quest struct {
+	ID       string `json:"id"`
+	Kind     string `json:"kind"`
+	Title    string `json:"title"`
+	Target   int    `json:"target"`
+	Progress int    `json:"progress"`
+	Done     bool   `json:"done"`
+	// * Super-likes que da al completarse; se acreditan solos
+	Reward   int  `json:"reward"`
+	Rewarded bool `json:"rewarded"`
+	// * Parámetro de la misión (p. ej. la raza); vacío si no aplica
+	Param string `json:"param,omitempty"`
+}
+
+type QuestsResponse struct {
+	Day        string    `json:"day"`
+	Quests     []Quest   `json:"quests"`
+	SuperLikes int       `json:"super_likes"`
+	ResetsAt   time.Time `json:"resets_at"`
+}
@@ -0,0 +1,44 @@
+package models
+
+type QuizQuery struct {
+	// * Si falta se negocia con Accept-Language
+	Locale string `form:"locale" binding:"omitempty,oneof=es en"`
+}
+
+type QuizOption struct {
+	ID   string `json:"id"`
+	Text string `json:"text"`
+}
+
+type QuizQuestion struct {
+	ID      string       `json:"id"`
+	Text    string       `json:"text"`
+	Options []QuizOption `json:"options"`
+}
+
+type QuizResponse struct {
+	Locale    string         `json:"locale"`
+	Questions []QuizQuestion `json:"questions"`
+}
+
+type QuizAnswer struct {
+	QuestionID string `json:"question_id" binding:"required,max=64"`
+	OptionID   string `json:"option_id" binding:"required,max=64"`
+}
+
+type QuizAnswersRequest struct {
+	Answers []QuizAnswer `json:"answers" binding:"required,min=1,max=20,dive"`
+	Locale  string       `json:"locale" binding:"omitempty,oneof=es en"`
+}
+
+type Archetype struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type QuizResult struct {
+	Archetype   Archetype      `json:"archetype"`
+	Scores      map[string]int `json:"scores"`
+	Preferences Preferences    `json:"preferences"`
+}
@@ -0,0 +1,19 @@
+package models
+
+// * Comparación acumulada entre el ranking en vivo y el candidato en sombra
+type ShadowRankingStats struct {
+	Enabled    bool    `json:"enabled"`
+	Live       string  `json:"live"`
+	Candidate  string  `json:"candidate,omitempty"`
+	SampleRate float64 `json:"sample_rate,omitempty"`
+	TopK       int     `json:"top_k,omitempty"`
+	Samples    int     `json:"samples"`
+	Failures   int     `json:"failures"`
+	// * Fracción de los top-k del candidato que también están en los top-k en vivo
+	AvgTopOverlap float64 `json:"avg_top_overlap"`
+	// * Fracción de mazos en los que ambos muestran el mismo primer gato
+	FirstMatchRate float64 `json:"first_match_rate"`
+	// * Posiciones que se mueve en promedio cada gato entre un orden y otro
+	AvgDisplacement float64 `json:"avg_displacement"`
+	AvgCandidateMs  float64 `json:"avg_candidate_ms"`
+}
@@ -0,0 +1,18 @@
+package models
+
+// * Sin filtros (o sin cuerpo) refresca todo el catálogo; los filtros se combinan
+type RefreshImagesRequest struct {
+	IDs   []int  `json:"ids,omitempty" binding:"omitempty,max=1000,dive,min=1"`
+	Breed string `json:"breed,omitempty" binding:"omitempty,max=64"`
+	// * Solo los perfiles cuya imagen ya no responde
+	OnlyBroken  bool `json:"only_broken"`
+	Concurrency int  `json:"concurrency,omitempty" binding:"omitempty,min=1,max=20"`
+}
+
+type RefreshResult struct {
+	Checked   int `json:"checked"`
+	Broken    int `json:"broken"`
+	Refreshed int `json:"refreshed"`
+	// * Sin reemplazo verificado: conservan la imagen anterior
+	Failed int `json:"failed"`
+}
@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// * Foto de GET /stats; los contadores son desde el arranque y suman todos los tenants
+type ServiceStats struct {
+	StartedAt     time.Time `json:"started_at"`
+	UptimeSeconds int64     `json:"uptime_seconds"`
+	Batches       int64     `json:"batches"`
+	URLsGenerated int64     `json:"urls_generated"`
+	// * URLs descartadas por repetidas antes de entrar en un lote
+	DedupHits      int64            `json:"dedup_hits"`
+	ProviderErrors map[string]int64 `json:"provider_errors"`
+	// * Entradas actuales de cada cache (proxy de imágenes, reserva, DNS...)
+	Caches     map[string]int `json:"caches"`
+	Requests   RequestStats   `json:"requests"`
+	Goroutines int            `json:"goroutines"`
+	HeapBytes  uint64         `json:"heap_bytes"`
+}
+
+// * Peticiones HTTP de la ventana reciente; los errores cuentan solo los 5xx
+type RequestStats struct {
+	WindowSeconds int     `json:"window_seconds"`
+	Requests      int64   `json:"requests"`
+	Errors        int64   `json:"errors"`
+	ErrorRate     float64 `json:"error_rate"`
+	P50Ms         float64 `json:"p50_ms"`
+	P99Ms         float64 `json:"p99_ms"`
+}
@@ -0,0 +1,13 @@
+package models
+
+import "html/template"
+
+type SharePage struct {
+	Name        string
+	Title       string
+	Description string
+	ImageURL    string
+	ShareURL    string
+	ShortURL    string
+	DeepLink    template.URL
+}
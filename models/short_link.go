@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+type ShortLink struct {
+	Code        string     `json:"code"`
+	ShortURL    string     `json:"short_url"`
+	TargetURL   string     `json:"target_url"`
+	TenantID    string     `json:"-"`
+	CatID       int        `json:"cat_id,omitempty"`
+	Clicks      int        `json:"clicks"`
+	CreatedAt   time.Time  `json:"created_at"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	LastClickAt *time.Time `json:"last_click_at,omitempty"`
+}
+
+// * cat_id o url (del propio sitio), no ambos
+type ShortLinkRequest struct {
+	CatID int    `json:"cat_id" binding:"omitempty,min=1"`
+	URL   string `json:"url" binding:"omitempty,url,max=2048"`
+	// * Sin expiración si falta
+	ExpiresInHours int `json:"expires_in_hours" binding:"omitempty,min=1,max=8760"`
+}
+
+type LinkCodeParam struct {
+	Code string `uri:"code" binding:"required,alphanum,max=16"`
+}
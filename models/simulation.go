@@ -0,0 +1,22 @@
+package models
+
+// * Resultado de reproducir un flujo de swipes con una configuración de matching
+type SimulationResult struct {
+	MatchProbability float64 `json:"match_probability"`
+	Ranking          string  `json:"ranking"`
+	Events           int     `json:"events"`
+	Applied          int     `json:"applied"`
+	// * Eventos rechazados por código de error (ya deslizado, perfil inexistente, etc.)
+	Rejected       map[string]int `json:"rejected,omitempty"`
+	Likes          int            `json:"likes"`
+	SuperLikes     int            `json:"super_likes"`
+	Passes         int            `json:"passes"`
+	ShelterMatches int            `json:"shelter_matches"`
+	MutualMatches  int            `json:"mutual_matches"`
+	PendingPairs   int            `json:"pending_pairs"`
+	// * Matches por like (incluye super-likes); cada match mutuo cuenta una vez
+	MatchRate float64 `json:"match_rate"`
+	// * Posición media (desde 1) del gato likeado en el mazo del usuario en ese momento:
+	// * más baja significa que el ranking adelanta lo que la gente termina eligiendo
+	AvgLikeRank float64 `json:"avg_like_rank,omitempty"`
+}
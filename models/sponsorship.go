@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+type Sponsorship struct {
+	Since time.Time `json:"since"`
+	// * nil = hasta que el admin la quite
+	Until *time.Time `json:"until,omitempty"`
+}
+
+func (sp Sponsorship) Clone() Sponsorship {
+	if sp.Until != nil {
+		until := *sp.Until
+		sp.Until = &until
+	}
+	return sp
+}
+
+func (sp Sponsorship) ActiveAt(now time.Time) bool {
+	return sp.Until == nil || now.Before(*sp.Until)
+}
+
+type SponsorRequest struct {
+	Until *time.Time `json:"until"`
+}
+
+// * Patrocinio de un perfil con sus impresiones desde el arranque
+type SponsoredStat struct {
+	ID          int        `json:"id"`
+	Name        string     `json:"name"`
+	Since       time.Time  `json:"since"`
+	Until       *time.Time `json:"until,omitempty"`
+	Active      bool       `json:"active"`
+	Impressions int        `json:"impressions"`
+	LastShownAt *time.Time `json:"last_shown_at,omitempty"`
+}
+
+type SponsoredReport struct {
+	// * Un lugar patrocinado cada Every tarjetas, como mucho MaxPerDeck por mazo
+	Every      int             `json:"every"`
+	MaxPerDeck int             `json:"max_per_deck"`
+	Profiles   []SponsoredStat `json:"profiles"`
+	Count      int             `json:"count"`
+}
@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+const (
+	SwipeLike = "like"
+	SwipePass = "pass"
+	// * Like reforzado: gasta un super-like y con gatos del refugio siempre hay match
+	SwipeSuperLike = "superlike"
+)
+
+func IsLike(direction string) bool {
+	return direction == SwipeLike || direction == SwipeSuperLike
+}
+
+type Swipe struct {
+	UserID    string    `json:"user_id"`
+	CatID     int       `json:"cat_id"`
+	FromCatID int       `json:"from_cat_id,omitempty"`
+	Direction string    `json:"direction"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type SwipeRequest struct {
+	CatID     int    `json:"cat_id" binding:"required,min=1"`
+	Direction string `json:"direction" binding:"required,oneof=like pass superlike"`
+	// * Con qué gato propio se desliza; obligatorio solo si el usuario tiene varios
+	FromCatID int `json:"from_cat_id" binding:"omitempty,min=1"`
+}
+
+type SwipeResult struct {
+	Swipe Swipe  `json:"swipe"`
+	Match *Match `json:"match,omitempty"`
+	// * Estado del par cuando el perfil deslizado es de otro usuario
+	Pair *PairState `json:"pair,omitempty"`
+}
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+const (
+	// * Más likes por minuto de los que da una persona
+	FraudReasonRate = "like_rate"
+	// * Intervalos entre swipes casi idénticos: un script con sleep fijo
+	FraudReasonUniform = "uniform_intervals"
+)
+
+// * Sesión marcada como no humana: sus swipes no cuentan en rankings, insignias ni misiones
+type SwipeFlag struct {
+	UserID    string    `json:"user_id"`
+	Reason    string    `json:"reason"`
+	Detail    string    `json:"detail"`
+	FlaggedAt time.Time `json:"flagged_at"`
+	// * Hasta cuándo se rechazan sus swipes con 429
+	ThrottledUntil time.Time `json:"throttled_until"`
+	// * Veces que volvió a disparar la detección
+	Hits int `json:"hits"`
+}
+
+type SwipeFlagParam struct {
+	UserID string `uri:"user_id" binding:"required,max=200"`
+}
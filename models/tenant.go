@@ -0,0 +1,9 @@
+package models
+
+type TenantSpec struct {
+	ID            string `json:"id"`
+	Name          string `json:"name"`
+	ProfilesFile  string `json:"profiles_file"`
+	AdminKey      string `json:"admin_key"`
+	SigningSecret string `json:"signing_secret"`
+}
@@ -0,0 +1,24 @@
+package models
+
+const (
+	TextFieldBio         = "bio"
+	TextFieldPersonality = "personality"
+)
+
+type GenerateTextRequest struct {
+	Fields []string `json:"fields" binding:"required,min=1,dive,oneof=bio personality"`
+	// * Sin overwrite solo se completan los campos vacíos
+	Overwrite bool `json:"overwrite"`
+}
+
+type GeneratedText struct {
+	Field    string `json:"field"`
+	Text     string `json:"text"`
+	Provider string `json:"provider"`
+	Cached   bool   `json:"cached,omitempty"`
+}
+
+type GenerateTextResponse struct {
+	Cat       CatProfile      `json:"cat"`
+	Generated []GeneratedText `json:"generated"`
+}
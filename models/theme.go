@@ -0,0 +1,33 @@
+package models
+
+// * Tema de temporada: decora las imágenes de cataas (texto y filtro) en ciertas fechas.
+// * Vale un rango MM-DD (puede cruzar el año) o un día de la semana
+type Theme struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	From    string `json:"from,omitempty"`
+	To      string `json:"to,omitempty"`
+	Weekday string `json:"weekday,omitempty"`
+	Says    string `json:"says,omitempty"`
+	Filter  string `json:"filter,omitempty"`
+	// * Color de acento para que la UI combine con las imágenes
+	Color string `json:"color,omitempty"`
+}
+
+// * Lo que viaja en las respuestas de imágenes
+type ActiveTheme struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Color string `json:"color,omitempty"`
+}
+
+type ThemeOverrideRequest struct {
+	// * "none" desactiva los temas hasta quitar el override
+	ThemeID string `json:"theme_id" binding:"required,max=64"`
+}
+
+type ThemesResponse struct {
+	Themes   []Theme `json:"themes"`
+	Active   *Theme  `json:"active,omitempty"`
+	Override string  `json:"override,omitempty"`
+}
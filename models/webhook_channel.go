@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+type WebhookChannel struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Kind    string `json:"kind"`
+	URL     string `json:"url"`
+	Enabled bool   `json:"enabled"`
+	// * Versión del secreto vigente; el anterior sigue firmando hasta PreviousSecretUntil
+	SecretVersion       int             `json:"secret_version"`
+	PreviousSecretUntil *time.Time      `json:"previous_secret_until,omitempty"`
+	Secrets             []WebhookSecret `json:"-"`
+}
+
+type WebhookChannelRequest struct {
+	Name    string `json:"name" binding:"required,max=64"`
+	Kind    string `json:"kind" binding:"required,oneof=discord slack"`
+	URL     string `json:"url" binding:"required,url,startswith=https://"`
+	Enabled *bool  `json:"enabled"`
+}
+
+// * Until nil = secreto vigente
+type WebhookSecret struct {
+	Version int        `json:"version"`
+	Secret  string     `json:"secret"`
+	Until   *time.Time `json:"until,omitempty"`
+}
+
+// * Sin grace_seconds el secreto anterior sigue firmando WEBHOOK_SECRET_GRACE
+type RotateSecretRequest struct {
+	GraceSeconds *int `json:"grace_seconds" binding:"omitempty,min=0,max=604800"`
+}
+
+// * Única respuesta que incluye el secreto en claro (al crear el canal y al rotarlo)
+type WebhookChannelSecret struct {
+	WebhookChannel
+	Secret string `json:"secret"`
+}
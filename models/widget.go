@@ -0,0 +1,29 @@
+package models
+
+type WidgetQuery struct {
+	Key string `form:"key" binding:"required,max=128"`
+	// * Segundos entre gatos; 0 usa el valor por defecto
+	Interval int `form:"interval" binding:"omitempty,min=5,max=3600"`
+}
+
+type WidgetCatQuery struct {
+	Key string `form:"key" binding:"required,max=128"`
+	// * ID del gato que ya se muestra, para no repetirlo
+	Except int `form:"except" binding:"omitempty,min=1"`
+}
+
+type WidgetCat struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	Age   int    `json:"age"`
+	Breed string `json:"breed"`
+	Img   string `json:"img"`
+	URL   string `json:"url"`
+}
+
+type WidgetPage struct {
+	Key      string
+	Interval int
+	Nonce    string
+	BaseURL  string
+}
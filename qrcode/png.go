@@ -0,0 +1,37 @@
+package qrcode
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+)
+
+const quietZone = 4
+
+// * Renderiza el código a exactamente size×size píxeles, con el margen que exige la spec
+func (q *Code) PNG(size int) ([]byte, error) {
+	total := q.Size + quietZone*2
+	if size < total {
+		size = total
+	}
+
+	palette := color.Palette{color.White, color.Black}
+	img := image.NewPaletted(image.Rect(0, 0, size, size), palette)
+
+	for py := 0; py < size; py++ {
+		y := py*total/size - quietZone
+		for px := 0; px < size; px++ {
+			x := px*total/size - quietZone
+			if x >= 0 && y >= 0 && x < q.Size && y < q.Size && q.Module(x, y) {
+				img.SetColorIndex(px, py, 1)
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
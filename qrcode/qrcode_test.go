@@ -0,0 +1,97 @@
+package qrcode
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// * Los archivos de testdata son la salida de rsc.io/qr/coding (con la misma versión,
+// * nivel y máscara) dibujada con '#' oscuro y '.' claro, una fila por línea
+const (
+	shortURL = "https://meownder.app/l/ME84ZYp"
+	longURL  = "https://meownder.app/share/12345?tenant=refugio-del-norte&utm_source=qr&utm_medium=print&utm_campaign=adopta-un-gato-senior-este-invierno&ref=afiche-veterinaria"
+)
+
+func render(code *Code) string {
+	var sb strings.Builder
+	for y := 0; y < code.Size; y++ {
+		for x := 0; x < code.Size; x++ {
+			if code.Module(x, y) {
+				sb.WriteByte('#')
+			} else {
+				sb.WriteByte('.')
+			}
+		}
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+func checkGolden(t *testing.T, name string, code *Code) {
+	t.Helper()
+	want, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := render(code)
+	if got == string(want) {
+		return
+	}
+	wantRows, gotRows := strings.Split(string(want), "\n"), strings.Split(got, "\n")
+	if len(wantRows) != len(gotRows) {
+		t.Fatalf("%s: %d filas, se esperaban %d", name, len(gotRows), len(wantRows))
+	}
+	for y := range wantRows {
+		if wantRows[y] != gotRows[y] {
+			t.Fatalf("%s: la fila %d difiere\n got %s\nwant %s", name, y, gotRows[y], wantRows[y])
+		}
+	}
+}
+
+// * Cada máscara por separado: cubre las ocho fórmulas y sus bits de formato
+func TestEncodeMasks(t *testing.T) {
+	for mask := 0; mask < 8; mask++ {
+		code, err := encode([]byte(shortURL), Medium, mask)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkGolden(t, fmt.Sprintf("corto-M-mascara%d.txt", mask), code)
+	}
+}
+
+// * De punta a punta con la máscara que elige Encode: nivel H, bloque de versión (v8),
+// * contador de 16 bits (v16) y bloques de distinto largo intercalados
+func TestEncodeKnownVectors(t *testing.T) {
+	cases := []struct {
+		golden  string
+		data    string
+		level   Level
+		version int
+	}{
+		{"corto-H.txt", shortURL, High, 4},
+		{"largo-L.txt", longURL, Low, 8},
+		{"largo-doble-Q.txt", longURL + longURL, Quartile, 16},
+	}
+	for _, tc := range cases {
+		code, err := Encode([]byte(tc.data), tc.level)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if code.version != tc.version {
+			t.Errorf("%s: versión %d, se esperaba %d", tc.golden, code.version, tc.version)
+		}
+		checkGolden(t, tc.golden, code)
+	}
+}
+
+func TestEncodeTooLong(t *testing.T) {
+	if _, err := Encode(make([]byte, 2954), Low); err != ErrDataTooLong {
+		t.Fatalf("err = %v, se esperaba ErrDataTooLong", err)
+	}
+	if _, err := Encode(make([]byte, 2953), Low); err != nil {
+		t.Fatalf("2953 bytes entran en la versión 40-L: %v", err)
+	}
+}
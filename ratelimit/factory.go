@@ -0,0 +1,94 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/ChrisTheAbysswalker/meownder-backend/redis"
+)
+
+// * Arma los limitadores de cada ruta contra el backend configurado
+type Factory struct {
+	client *redis.Client
+
+	limiters map[string]*Adjustable
+	mutex    sync.Mutex
+}
+
+// * backend "memory" o "redis"; client es nil cuando no hay REDIS_URL
+func NewFactory(backend string, client *redis.Client) (*Factory, error) {
+	switch backend {
+	case "", "memory":
+		return &Factory{limiters: make(map[string]*Adjustable)}, nil
+	case "redis":
+		if client == nil {
+			return nil, fmt.Errorf("RATE_LIMIT_BACKEND=redis requiere REDIS_URL")
+		}
+		return &Factory{client: client, limiters: make(map[string]*Adjustable)}, nil
+	}
+	return nil, fmt.Errorf("backend de límites desconocido: %q", backend)
+}
+
+// * Siempre devuelve un limitador (aunque perMinute <= 0 no limite) para poder
+// * cambiarle el límite en caliente con SetLimit
+func (f *Factory) New(name string, perMinute int) *Adjustable {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	limiter := &Adjustable{}
+	limiter.current.Store(&limiterBox{limiter: f.build(name, perMinute)})
+	f.limiters[name] = limiter
+	return limiter
+}
+
+// * false si no hay un limitador con ese nombre. En memoria cambiar el límite vacía
+// * los buckets; en Redis la ventana sigue siendo la misma
+func (f *Factory) SetLimit(name string, perMinute int) bool {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	limiter, ok := f.limiters[name]
+	if !ok {
+		return false
+	}
+	if limiter.Limit() != max(perMinute, 0) {
+		limiter.current.Store(&limiterBox{limiter: f.build(name, perMinute)})
+	}
+	return true
+}
+
+func (f *Factory) build(name string, perMinute int) Limiter {
+	if perMinute <= 0 {
+		return nil
+	}
+	if f.client != nil {
+		return NewRedis(f.client, "meownder:ratelimit:"+name, perMinute)
+	}
+	return NewLocal(perMinute)
+}
+
+type limiterBox struct {
+	limiter Limiter
+}
+
+// * Limitador intercambiable; sin límite (0) deja pasar todo
+type Adjustable struct {
+	current atomic.Pointer[limiterBox]
+}
+
+func (a *Adjustable) Limit() int {
+	if limiter := a.current.Load().limiter; limiter != nil {
+		return limiter.Limit()
+	}
+	return 0
+}
+
+func (a *Adjustable) Allow(ctx context.Context, key string) (Decision, error) {
+	limiter := a.current.Load().limiter
+	if limiter == nil {
+		return Decision{Allowed: true}, nil
+	}
+	return limiter.Allow(ctx, key)
+}
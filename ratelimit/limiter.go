@@ -0,0 +1,20 @@
+// Package ratelimit define los limitadores que usa el middleware RateLimit: uno local
+// por réplica y otro en Redis para que varias réplicas compartan el mismo límite.
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+type Decision struct {
+	Allowed    bool
+	Remaining  int
+	RetryAfter time.Duration
+}
+
+// * key ya viene armada por el llamador (ruta + IP); el límite es del limitador
+type Limiter interface {
+	Allow(ctx context.Context, key string) (Decision, error)
+	Limit() int
+}
@@ -0,0 +1,70 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// * Token bucket por clave: capacidad perMinute y recarga continua de perMinute por
+// * minuto, así una ráfaga corta pasa pero el promedio queda acotado
+type Local struct {
+	perMinute int
+	rate      float64 // tokens por segundo
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	mutex     sync.Mutex
+}
+
+func NewLocal(perMinute int) *Local {
+	return &Local{
+		perMinute: perMinute,
+		rate:      float64(perMinute) / 60,
+		buckets:   make(map[string]*bucket),
+		lastSweep: time.Now(),
+	}
+}
+
+func (l *Local) Limit() int { return l.perMinute }
+
+func (l *Local) Allow(ctx context.Context, key string) (Decision, error) {
+	now := time.Now()
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	// * Barrido perezoso: un bucket lleno equivale a no tener bucket
+	if now.Sub(l.lastSweep) > time.Minute {
+		for k, b := range l.buckets {
+			if l.refill(b, now) >= float64(l.perMinute) {
+				delete(l.buckets, k)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(l.perMinute), last: now}
+		l.buckets[key] = b
+	}
+	b.tokens = l.refill(b, now)
+	b.last = now
+
+	if b.tokens < 1 {
+		wait := time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+		return Decision{Allowed: false, RetryAfter: wait}, nil
+	}
+	b.tokens--
+	return Decision{Allowed: true, Remaining: int(math.Floor(b.tokens))}, nil
+}
+
+func (l *Local) refill(b *bucket, now time.Time) float64 {
+	return math.Min(float64(l.perMinute), b.tokens+now.Sub(b.last).Seconds()*l.rate)
+}
@@ -0,0 +1,66 @@
+package ratelimit
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/ChrisTheAbysswalker/meownder-backend/redis"
+)
+
+// * Ventana deslizante de un minuto con un sorted set por clave. Se usa la hora de
+// * Redis (TIME) y no la de cada réplica, así un reloj desfasado no regala peticiones
+const slidingWindowScript = `
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+local window = tonumber(ARGV[1])
+local limit = tonumber(ARGV[2])
+redis.call('ZREMRANGEBYSCORE', KEYS[1], 0, now - window)
+local count = redis.call('ZCARD', KEYS[1])
+if count < limit then
+	redis.call('ZADD', KEYS[1], now, now .. '-' .. ARGV[3])
+	redis.call('PEXPIRE', KEYS[1], window)
+	return {1, limit - count - 1, 0}
+end
+local oldest = redis.call('ZRANGE', KEYS[1], 0, 0, 'WITHSCORES')
+return {0, 0, tonumber(oldest[2]) + window - now}
+`
+
+type Redis struct {
+	client    *redis.Client
+	prefix    string
+	perMinute int
+}
+
+// * prefix separa los límites de cada ruta dentro del mismo Redis
+func NewRedis(client *redis.Client, prefix string, perMinute int) *Redis {
+	return &Redis{client: client, prefix: prefix, perMinute: perMinute}
+}
+
+func (r *Redis) Limit() int { return r.perMinute }
+
+func (r *Redis) Allow(ctx context.Context, key string) (Decision, error) {
+	nonce := make([]byte, 8)
+	rand.Read(nonce)
+
+	reply, err := r.client.Do(ctx, "EVAL", slidingWindowScript, 1, r.prefix+":"+key,
+		time.Minute.Milliseconds(), r.perMinute, hex.EncodeToString(nonce))
+	if err != nil {
+		return Decision{}, err
+	}
+
+	values, ok := reply.([]any)
+	if !ok || len(values) != 3 {
+		return Decision{}, fmt.Errorf("respuesta inesperada del script de límite: %v", reply)
+	}
+	allowed, _ := values[0].(int64)
+	remaining, _ := values[1].(int64)
+	retryMs, _ := values[2].(int64)
+	return Decision{
+		Allowed:    allowed == 1,
+		Remaining:  int(remaining),
+		RetryAfter: time.Duration(retryMs) * time.Millisecond,
+	}, nil
+}
@@ -0,0 +1,203 @@
+// Package redis es un cliente RESP2 mínimo: solo lo que necesitan los limitadores
+// compartidos entre réplicas (comandos sueltos y EVAL), sin dependencias externas.
+package redis
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// * Respuesta de error del servidor (-ERR ...); los errores de red son de otro tipo
+type Error string
+
+func (e Error) Error() string { return string(e) }
+
+type Client struct {
+	addr     string
+	password string
+	db       int
+	timeout  time.Duration
+	// * Pool simple: conexiones libres en un canal con capacidad fija
+	idle chan *conn
+}
+
+type conn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+// * rawURL con la forma redis://[:password@]host[:port][/db]
+func New(rawURL string, poolSize int, timeout time.Duration) (*Client, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Scheme != "redis" || parsed.Host == "" {
+		return nil, fmt.Errorf("URL de redis inválida: %q", rawURL)
+	}
+	client := &Client{
+		addr:    parsed.Host,
+		timeout: timeout,
+		idle:    make(chan *conn, max(poolSize, 1)),
+	}
+	if parsed.Port() == "" {
+		client.addr = net.JoinHostPort(parsed.Hostname(), "6379")
+	}
+	if password, ok := parsed.User.Password(); ok {
+		client.password = password
+	}
+	if db := strings.TrimPrefix(parsed.Path, "/"); db != "" {
+		if client.db, err = strconv.Atoi(db); err != nil {
+			return nil, fmt.Errorf("base de redis inválida: %q", db)
+		}
+	}
+	return client, nil
+}
+
+// * Ejecuta un comando y devuelve la respuesta como string, int64, []any o nil
+func (c *Client) Do(ctx context.Context, args ...any) (any, error) {
+	cn, err := c.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reply, err := cn.roundTrip(ctx, c.timeout, args)
+	var serverErr Error
+	if err != nil && !errors.As(err, &serverErr) {
+		// * Tras un error de red la conexión puede tener basura a medio leer
+		cn.Close()
+		return nil, err
+	}
+	c.put(cn)
+	return reply, err
+}
+
+func (c *Client) Ping(ctx context.Context) error {
+	_, err := c.Do(ctx, "PING")
+	return err
+}
+
+func (c *Client) Close() error {
+	for {
+		select {
+		case cn := <-c.idle:
+			cn.Close()
+		default:
+			return nil
+		}
+	}
+}
+
+func (c *Client) get(ctx context.Context) (*conn, error) {
+	select {
+	case cn := <-c.idle:
+		return cn, nil
+	default:
+	}
+
+	dialer := net.Dialer{Timeout: c.timeout}
+	netConn, err := dialer.DialContext(ctx, "tcp", c.addr)
+	if err != nil {
+		return nil, err
+	}
+	cn := &conn{Conn: netConn, reader: bufio.NewReader(netConn)}
+	if c.password != "" {
+		if _, err := cn.roundTrip(ctx, c.timeout, []any{"AUTH", c.password}); err != nil {
+			cn.Close()
+			return nil, err
+		}
+	}
+	if c.db != 0 {
+		if _, err := cn.roundTrip(ctx, c.timeout, []any{"SELECT", c.db}); err != nil {
+			cn.Close()
+			return nil, err
+		}
+	}
+	return cn, nil
+}
+
+func (c *Client) put(cn *conn) {
+	select {
+	case c.idle <- cn:
+	default:
+		cn.Close()
+	}
+}
+
+func (cn *conn) roundTrip(ctx context.Context, timeout time.Duration, args []any) (any, error) {
+	deadline := time.Now().Add(timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	cn.SetDeadline(deadline)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%d\r\n", len(args))
+	for _, arg := range args {
+		value := fmt.Sprint(arg)
+		fmt.Fprintf(&b, "$%d\r\n%s\r\n", len(value), value)
+	}
+	if _, err := io.WriteString(cn, b.String()); err != nil {
+		return nil, err
+	}
+	return readReply(cn.reader)
+}
+
+func readReply(reader *bufio.Reader) (any, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 || !strings.HasSuffix(line, "\r\n") {
+		return nil, fmt.Errorf("redis: respuesta mal formada %q", line)
+	}
+	kind, payload := line[0], line[1:len(line)-2]
+
+	switch kind {
+	case '+':
+		return payload, nil
+	case '-':
+		return nil, Error(payload)
+	case ':':
+		return strconv.ParseInt(payload, 10, 64)
+	case '$':
+		size, err := strconv.Atoi(payload)
+		if err != nil {
+			return nil, err
+		}
+		if size < 0 {
+			return nil, nil
+		}
+		data := make([]byte, size+2)
+		if _, err := io.ReadFull(reader, data); err != nil {
+			return nil, err
+		}
+		return string(data[:size]), nil
+	case '*':
+		count, err := strconv.Atoi(payload)
+		if err != nil {
+			return nil, err
+		}
+		if count < 0 {
+			return nil, nil
+		}
+		items := make([]any, count)
+		for i := range items {
+			// * Un error dentro de un arreglo (EXEC, scripts) va como valor, no corta la lectura
+			if items[i], err = readReply(reader); err != nil {
+				var serverErr Error
+				if !errors.As(err, &serverErr) {
+					return nil, err
+				}
+				items[i] = serverErr
+			}
+		}
+		return items, nil
+	}
+	return nil, fmt.Errorf("redis: tipo de respuesta desconocido %q", kind)
+}
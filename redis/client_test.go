@@ -0,0 +1,152 @@
+package redis
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadReply(t *testing.T) {
+	cases := []struct {
+		name string
+		raw  string
+		want any
+		err  error
+	}{
+		{"simple", "+OK\r\n", "OK", nil},
+		{"simple vacío", "+\r\n", "", nil},
+		{"entero", ":1000\r\n", int64(1000), nil},
+		{"entero negativo", ":-2\r\n", int64(-2), nil},
+		{"bulk", "$5\r\nhola!\r\n", "hola!", nil},
+		{"bulk con CRLF adentro", "$7\r\nab\r\ncde\r\n", "ab\r\ncde", nil},
+		{"bulk vacío", "$0\r\n\r\n", "", nil},
+		{"bulk nulo", "$-1\r\n", nil, nil},
+		{"arreglo nulo", "*-1\r\n", nil, nil},
+		{"arreglo vacío", "*0\r\n", []any{}, nil},
+		{"error", "-ERR wrong number of arguments\r\n", nil, Error("ERR wrong number of arguments")},
+		{
+			"arreglo anidado",
+			"*4\r\n:1\r\n$3\r\nfoo\r\n*2\r\n+a\r\n$-1\r\n-WRONGTYPE clave de otro tipo\r\n",
+			[]any{int64(1), "foo", []any{"a", nil}, Error("WRONGTYPE clave de otro tipo")},
+			nil,
+		},
+	}
+	for _, tc := range cases {
+		got, err := readReply(bufio.NewReader(strings.NewReader(tc.raw)))
+		if !errors.Is(err, tc.err) && !reflect.DeepEqual(err, tc.err) {
+			t.Errorf("%s: err = %v, se esperaba %v", tc.name, err, tc.err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: %#v, se esperaba %#v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestReadReplyMalformed(t *testing.T) {
+	for _, raw := range []string{
+		"OK\r\n",       // * sin tipo
+		"+OK\n",        // * sin CR
+		":uno\r\n",     // * entero ilegible
+		"$x\r\n",       // * largo ilegible
+		"$5\r\nhol",    // * bulk cortado
+		"*2\r\n:1\r\n", // * arreglo cortado
+		"?raro\r\n",    // * tipo desconocido
+		"",             // * conexión cerrada
+	} {
+		if _, err := readReply(bufio.NewReader(strings.NewReader(raw))); err == nil {
+			t.Errorf("%q se aceptó", raw)
+		}
+	}
+}
+
+// * Servidor falso: guarda cada comando recibido y contesta con la respuesta que le toque
+func fakeServer(t *testing.T, replies map[string]string) (addr string, commands <-chan []string) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	received := make(chan []string, 16)
+	go func() {
+		for {
+			netConn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer netConn.Close()
+				reader := bufio.NewReader(netConn)
+				for {
+					command, err := readReply(reader)
+					if err != nil {
+						return
+					}
+					var args []string
+					for _, arg := range command.([]any) {
+						args = append(args, arg.(string))
+					}
+					received <- args
+					if _, err := io.WriteString(netConn, replies[args[0]]); err != nil {
+						return
+					}
+				}
+			}()
+		}
+	}()
+	return listener.Addr().String(), received
+}
+
+func TestClientDo(t *testing.T) {
+	addr, commands := fakeServer(t, map[string]string{
+		"AUTH":   "+OK\r\n",
+		"SELECT": "+OK\r\n",
+		"INCRBY": ":42\r\n",
+		"EVAL":   "*2\r\n:1\r\n$2\r\nok\r\n",
+		"BAD":    "-ERR unknown command\r\n",
+	})
+	client, err := New("redis://:clave@"+addr+"/3", 1, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	ctx := context.Background()
+
+	if reply, err := client.Do(ctx, "INCRBY", "rl:ip:1.2.3.4", 5); err != nil || reply != int64(42) {
+		t.Fatalf("INCRBY = %v, %v", reply, err)
+	}
+	for _, want := range [][]string{{"AUTH", "clave"}, {"SELECT", "3"}, {"INCRBY", "rl:ip:1.2.3.4", "5"}} {
+		if got := <-commands; !reflect.DeepEqual(got, want) {
+			t.Fatalf("comando %v, se esperaba %v", got, want)
+		}
+	}
+
+	// * Un error del servidor no descarta la conexión: no hay un segundo AUTH
+	if _, err := client.Do(ctx, "BAD"); !errors.As(err, new(Error)) {
+		t.Fatalf("BAD: %v", err)
+	}
+	if reply, err := client.Do(ctx, "EVAL", "return {1, 'ok'}", 0); err != nil || !reflect.DeepEqual(reply, []any{int64(1), "ok"}) {
+		t.Fatalf("EVAL = %#v, %v", reply, err)
+	}
+	for _, want := range []string{"BAD", "EVAL"} {
+		if got := <-commands; got[0] != want {
+			t.Fatalf("comando %v, se esperaba %s", got, want)
+		}
+	}
+}
+
+func TestNewRejectsURL(t *testing.T) {
+	for _, raw := range []string{"http://localhost:6379", "redis://", "redis://localhost/base"} {
+		if _, err := New(raw, 1, time.Second); err == nil {
+			t.Errorf("%q se aceptó", raw)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"sync/atomic"
+	"time"
+)
+
+type Policy struct {
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+	Jitter      float64
+	MaxAttempts int
+	metrics     *Metrics
+}
+
+type Metrics struct {
+	calls     atomic.Int64
+	retries   atomic.Int64
+	exhausted atomic.Int64
+}
+
+type Stats struct {
+	Calls     int64 `json:"calls"`
+	Retries   int64 `json:"retries"`
+	Exhausted int64 `json:"exhausted"`
+}
+
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// * Marca un error como no reintentable (ej. 404 o respuesta malformada)
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+func NewPolicy(base, max time.Duration, jitter float64, attempts int) *Policy {
+	if attempts < 1 {
+		attempts = 1
+	}
+	return &Policy{
+		BaseDelay:   base,
+		MaxDelay:    max,
+		Jitter:      jitter,
+		MaxAttempts: attempts,
+		metrics:     &Metrics{},
+	}
+}
+
+// * Backoff exponencial con tope y jitter proporcional: base·2^n ± jitter
+func (p *Policy) Delay(attempt int) time.Duration {
+	delay := p.BaseDelay << attempt
+	if delay <= 0 || delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	if p.Jitter > 0 {
+		spread := float64(delay) * p.Jitter
+		delay = time.Duration(float64(delay) - spread + rand.Float64()*2*spread)
+	}
+	return delay
+}
+
+func (p *Policy) Do(ctx context.Context, fn func(attempt int) error) error {
+	p.metrics.calls.Add(1)
+
+	var err error
+	for attempt := 0; attempt < p.MaxAttempts; attempt++ {
+		if attempt > 0 {
+			p.metrics.retries.Add(1)
+			timer := time.NewTimer(p.Delay(attempt - 1))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return ctx.Err()
+			case <-timer.C:
+			}
+		}
+
+		if err = fn(attempt); err == nil {
+			return nil
+		}
+
+		var permanent *permanentError
+		if errors.As(err, &permanent) {
+			return permanent.err
+		}
+	}
+
+	p.metrics.exhausted.Add(1)
+	return err
+}
+
+func (p *Policy) Stats() Stats {
+	return Stats{
+		Calls:     p.metrics.calls.Load(),
+		Retries:   p.metrics.retries.Load(),
+		Exhausted: p.metrics.exhausted.Load(),
+	}
+}
@@ -0,0 +1,46 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ChrisTheAbysswalker/meownder-backend/redis"
+)
+
+// * Con varias réplicas cada una corre su scheduler; antes de ejecutar se reclama la
+// * ejecución (job + hora programada) y solo la réplica que la consigue corre el job.
+// * No es un lock que se suelta al terminar: una réplica con el timer un poco atrasado
+// * volvería a correr el mismo job justo después
+type Locker interface {
+	Claim(ctx context.Context, job string, slot time.Time) (bool, error)
+}
+
+// * Identifica a la réplica en los reclamos, útil para saber quién corrió qué
+func Owner() string {
+	host, _ := os.Hostname()
+	return fmt.Sprintf("%s-%d", host, os.Getpid())
+}
+
+// * SET NX con vencimiento: el reclamo vive lo suficiente para cubrir relojes desfasados
+type RedisLocker struct {
+	client *redis.Client
+	prefix string
+	ttl    time.Duration
+	owner  string
+}
+
+func NewRedisLocker(client *redis.Client, prefix string, ttl time.Duration) *RedisLocker {
+	return &RedisLocker{client: client, prefix: prefix, ttl: ttl, owner: Owner()}
+}
+
+func (l *RedisLocker) Claim(ctx context.Context, job string, slot time.Time) (bool, error) {
+	key := fmt.Sprintf("%s:%s:%d", l.prefix, job, slot.Unix())
+	reply, err := l.client.Do(ctx, "SET", key, l.owner, "NX", "PX", l.ttl.Milliseconds())
+	if err != nil {
+		return false, err
+	}
+	// * Sin NX cumplido Redis responde nil
+	return reply == "OK", nil
+}
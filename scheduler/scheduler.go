@@ -0,0 +1,158 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/ChrisTheAbysswalker/meownder-backend/sentry"
+)
+
+type Job func(ctx context.Context) error
+
+type Scheduler struct {
+	entries  []entry
+	locker   Locker
+	reporter *sentry.Client
+	wg       sync.WaitGroup
+}
+
+type entry struct {
+	name string
+	next func(now time.Time) time.Time
+	// * Solo para Every: con locker las ejecuciones se alinean a múltiplos del intervalo
+	interval time.Duration
+	job      Job
+}
+
+func New() *Scheduler {
+	return &Scheduler{}
+}
+
+// * Para varias réplicas; sin locker cada instancia corre todos sus jobs
+func (s *Scheduler) SetLocker(locker Locker) {
+	s.locker = locker
+}
+
+// * Fallos y panics de los jobs se reportan con el nombre del job como tag
+func (s *Scheduler) SetReporter(reporter *sentry.Client) {
+	s.reporter = reporter
+}
+
+func (s *Scheduler) Every(name string, interval time.Duration, job Job) {
+	s.entries = append(s.entries, entry{
+		name:     name,
+		next:     func(now time.Time) time.Time { return now.Add(interval) },
+		interval: interval,
+		job:      job,
+	})
+}
+
+// * Ejecuta el job una vez al día a la hora indicada (hora local del servidor)
+func (s *Scheduler) Daily(name string, hour, minute int, job Job) {
+	s.entries = append(s.entries, entry{
+		name: name,
+		next: func(now time.Time) time.Time {
+			next := time.Date(now.Year(), now.Month(), now.Day(), hour, minute, 0, 0, now.Location())
+			if !next.After(now) {
+				next = next.AddDate(0, 0, 1)
+			}
+			return next
+		},
+		job: job,
+	})
+}
+
+// * Una vez por semana el día y la hora indicados (hora local del servidor)
+func (s *Scheduler) Weekly(name string, weekday time.Weekday, hour, minute int, job Job) {
+	s.entries = append(s.entries, entry{
+		name: name,
+		next: func(now time.Time) time.Time {
+			days := (int(weekday) - int(now.Weekday()) + 7) % 7
+			next := time.Date(now.Year(), now.Month(), now.Day()+days, hour, minute, 0, 0, now.Location())
+			if !next.After(now) {
+				next = next.AddDate(0, 0, 7)
+			}
+			return next
+		},
+		job: job,
+	})
+}
+
+func (s *Scheduler) Start(ctx context.Context) {
+	for _, e := range s.entries {
+		s.wg.Add(1)
+		go s.run(ctx, e)
+	}
+	log.Printf("⏰ Scheduler iniciado con %d jobs", len(s.entries))
+}
+
+func (s *Scheduler) Wait() {
+	s.wg.Wait()
+}
+
+func (s *Scheduler) run(ctx context.Context, e entry) {
+	defer s.wg.Done()
+
+	for {
+		slot := s.nextSlot(e, time.Now())
+		timer := time.NewTimer(time.Until(slot))
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		if !s.claim(ctx, e, slot) {
+			continue
+		}
+
+		start := time.Now()
+		if err := s.execute(ctx, e); err != nil {
+			log.Printf("⚠️ Job %s falló: %v", e.name, err)
+			s.reporter.CaptureError(err, map[string]string{"job": e.name})
+			continue
+		}
+		log.Printf("✅ Job %s completado en %s", e.name, time.Since(start).Round(time.Millisecond))
+	}
+}
+
+// ! Un panic en un job no debe matar su goroutine: el job dejaría de correr para siempre
+func (s *Scheduler) execute(ctx context.Context, e entry) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			s.reporter.CapturePanic(recovered, map[string]string{"job": e.name})
+			err = fmt.Errorf("panic: %v", recovered)
+		}
+	}()
+	return e.job(ctx)
+}
+
+// * Cada réplica arranca en un momento distinto: para que todas calculen el mismo slot
+// * los jobs periódicos se alinean al reloj (p. ej. cada 30m: :00 y :30)
+func (s *Scheduler) nextSlot(e entry, now time.Time) time.Time {
+	if s.locker != nil && e.interval > 0 {
+		return now.Truncate(e.interval).Add(e.interval)
+	}
+	return e.next(now)
+}
+
+// ! Si el locker falla no se corre: un resumen duplicado es peor que uno atrasado
+func (s *Scheduler) claim(ctx context.Context, e entry, slot time.Time) bool {
+	if s.locker == nil {
+		return true
+	}
+	ok, err := s.locker.Claim(ctx, e.name, slot)
+	if err != nil {
+		log.Printf("⚠️ Job %s omitido: no se pudo reclamar la ejecución: %v", e.name, err)
+		return false
+	}
+	if !ok {
+		log.Printf("⏭️ Job %s ya lo corre otra réplica", e.name)
+	}
+	return ok
+}
@@ -0,0 +1,291 @@
+package schemas
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// * JSON Schema de los eventos: <tema>/v<N>.json. Política de compatibilidad: una versión
+// * nueva solo agrega campos; lo que era obligatorio lo sigue siendo con el mismo tipo y los
+// * enums no crecen. Un cambio que rompe va en un tema nuevo. Del estándar solo se usa
+// * type, properties, required, items y enum
+//
+//go:embed */*.json
+var files embed.FS
+
+var (
+	ErrUnknownTopic    = errors.New("tema sin esquema registrado")
+	ErrSchemaViolation = errors.New("el payload no cumple el esquema")
+)
+
+type Topic struct {
+	Topic    string `json:"topic"`
+	Versions []int  `json:"versions"`
+	Latest   int    `json:"latest"`
+	// * Ruta del esquema de la última versión
+	URL string `json:"url"`
+}
+
+type schema struct {
+	Type       schemaType         `json:"type"`
+	Properties map[string]*schema `json:"properties"`
+	Required   []string           `json:"required"`
+	Items      *schema            `json:"items"`
+	Enum       []any              `json:"enum"`
+}
+
+// * "type" puede ser un string o una lista ("string" o ["string", "null"])
+type schemaType []string
+
+func (t *schemaType) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*t = schemaType{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*t = list
+	return nil
+}
+
+type version struct {
+	number   int
+	document []byte
+	schema   *schema
+}
+
+var (
+	registry     map[string][]version
+	registryErr  error
+	registryOnce sync.Once
+)
+
+func load() (map[string][]version, error) {
+	registryOnce.Do(func() {
+		registry = make(map[string][]version)
+		paths, _ := fs.Glob(files, "*/*.json")
+		for _, file := range paths {
+			topic, name := path.Split(file)
+			topic = strings.TrimSuffix(topic, "/")
+			number, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(name, "v"), ".json"))
+			if err != nil || number < 1 {
+				registryErr = fmt.Errorf("esquema %s: el archivo debe llamarse v<N>.json", file)
+				return
+			}
+			document, _ := files.ReadFile(file)
+			var parsed schema
+			if err := json.Unmarshal(document, &parsed); err != nil {
+				registryErr = fmt.Errorf("esquema %s inválido: %w", file, err)
+				return
+			}
+			registry[topic] = append(registry[topic], version{number: number, document: document, schema: &parsed})
+		}
+		for _, versions := range registry {
+			sort.Slice(versions, func(a, b int) bool { return versions[a].number < versions[b].number })
+		}
+	})
+	return registry, registryErr
+}
+
+// * Carga los esquemas y revisa que cada versión sea compatible con la anterior; se llama
+// * al arrancar para no publicar con un registro roto
+func Check() error {
+	topics, err := load()
+	if err != nil {
+		return err
+	}
+	for topic, versions := range topics {
+		for i := 1; i < len(versions); i++ {
+			if err := compatible(versions[i-1].schema, versions[i].schema, ""); err != nil {
+				return fmt.Errorf("%s v%d rompe la v%d: %w", topic, versions[i].number, versions[i-1].number, err)
+			}
+		}
+	}
+	return nil
+}
+
+func Topics() []Topic {
+	topics, _ := load()
+	list := make([]Topic, 0, len(topics))
+	for name, versions := range topics {
+		topic := Topic{Topic: name}
+		for _, v := range versions {
+			topic.Versions = append(topic.Versions, v.number)
+		}
+		topic.Latest = topic.Versions[len(topic.Versions)-1]
+		topic.URL = fmt.Sprintf("/events/schemas/%s/%d", name, topic.Latest)
+		list = append(list, topic)
+	}
+	sort.Slice(list, func(a, b int) bool { return list[a].Topic < list[b].Topic })
+	return list
+}
+
+// * Versión con la que se publica el tema
+func Latest(topic string) (int, bool) {
+	topics, _ := load()
+	versions := topics[topic]
+	if len(versions) == 0 {
+		return 0, false
+	}
+	return versions[len(versions)-1].number, true
+}
+
+// * El JSON Schema tal cual está en el archivo
+func Document(topic string, number int) ([]byte, bool) {
+	v, ok := find(topic, number)
+	if !ok {
+		return nil, false
+	}
+	return v.document, true
+}
+
+func Validate(topic string, number int, payload []byte) error {
+	v, ok := find(topic, number)
+	if !ok {
+		return fmt.Errorf("%w: %s v%d", ErrUnknownTopic, topic, number)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return fmt.Errorf("%w: %v", ErrSchemaViolation, err)
+	}
+	if err := v.schema.validate(value, ""); err != nil {
+		return fmt.Errorf("%w: %v", ErrSchemaViolation, err)
+	}
+	return nil
+}
+
+func find(topic string, number int) (version, bool) {
+	topics, _ := load()
+	i := slices.IndexFunc(topics[topic], func(v version) bool { return v.number == number })
+	if i < 0 {
+		return version{}, false
+	}
+	return topics[topic][i], true
+}
+
+func (s *schema) validate(value any, at string) error {
+	if len(s.Type) > 0 && !slices.Contains(s.Type, jsonType(value)) &&
+		!(jsonType(value) == "integer" && slices.Contains(s.Type, "number")) {
+		return fmt.Errorf("%s: se esperaba %s y llegó %s", field(at), strings.Join(s.Type, " o "), jsonType(value))
+	}
+	if len(s.Enum) > 0 && !slices.ContainsFunc(s.Enum, func(allowed any) bool { return fmt.Sprint(allowed) == fmt.Sprint(value) }) {
+		return fmt.Errorf("%s: %v no es uno de %v", field(at), value, s.Enum)
+	}
+
+	switch typed := value.(type) {
+	case map[string]any:
+		for _, name := range s.Required {
+			if _, ok := typed[name]; !ok {
+				return fmt.Errorf("%s: falta", field(join(at, name)))
+			}
+		}
+		for name, property := range s.Properties {
+			if inner, ok := typed[name]; ok {
+				if err := property.validate(inner, join(at, name)); err != nil {
+					return err
+				}
+			}
+		}
+	case []any:
+		if s.Items != nil {
+			for i, item := range typed {
+				if err := s.Items.validate(item, fmt.Sprintf("%s[%d]", at, i)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// * Lo que un consumidor de old puede dar por hecho tiene que seguir valiendo en next
+func compatible(old, next *schema, at string) error {
+	if len(old.Type) > 0 && len(next.Type) == 0 {
+		return fmt.Errorf("%s: perdió su tipo", field(at))
+	}
+	for _, t := range next.Type {
+		if len(old.Type) > 0 && !slices.Contains(old.Type, t) {
+			return fmt.Errorf("%s: el tipo %s no existía", field(at), t)
+		}
+	}
+	if len(old.Enum) > 0 {
+		for _, value := range next.Enum {
+			if !slices.ContainsFunc(old.Enum, func(allowed any) bool { return fmt.Sprint(allowed) == fmt.Sprint(value) }) {
+				return fmt.Errorf("%s: el valor %v no existía en el enum", field(at), value)
+			}
+		}
+		if len(next.Enum) == 0 {
+			return fmt.Errorf("%s: el enum dejó de existir", field(at))
+		}
+	}
+	for _, name := range old.Required {
+		if !slices.Contains(next.Required, name) {
+			return fmt.Errorf("%s: dejó de ser obligatorio", field(join(at, name)))
+		}
+	}
+	for name, property := range old.Properties {
+		nextProperty, ok := next.Properties[name]
+		if !ok {
+			return fmt.Errorf("%s: se quitó", field(join(at, name)))
+		}
+		if err := compatible(property, nextProperty, join(at, name)); err != nil {
+			return err
+		}
+	}
+	if old.Items != nil {
+		if next.Items == nil {
+			return fmt.Errorf("%s: los elementos perdieron su esquema", field(at))
+		}
+		return compatible(old.Items, next.Items, at+"[]")
+	}
+	return nil
+}
+
+func jsonType(value any) string {
+	switch typed := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case json.Number:
+		if _, err := typed.Int64(); err == nil {
+			return "integer"
+		}
+		return "number"
+	case []any:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+func join(at, name string) string {
+	if at == "" {
+		return name
+	}
+	return at + "." + name
+}
+
+func field(at string) string {
+	if at == "" {
+		return "el evento"
+	}
+	return "campo " + at
+}
@@ -0,0 +1,235 @@
+package search
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// * Cliente mínimo de Elasticsearch (u OpenSearch): solo crear el índice, _bulk y _search.
+// * El índice se crea al primer uso con un analizador que pasa a minúsculas y quita tildes,
+// * igual que Tokenize, y la búsqueda es un multi_match con los mismos pesos y fuzziness
+type Elasticsearch struct {
+	base      string
+	username  string
+	password  string
+	index     string
+	boosts    Boosts
+	fuzziness Fuzziness
+	http      *http.Client
+
+	created bool
+	mutex   sync.Mutex
+}
+
+// * rawURL con la forma http[s]://[usuario:clave@]host[:puerto][/prefijo]
+func NewElasticsearch(rawURL, index string, boosts Boosts, fuzziness Fuzziness, timeout time.Duration) (*Elasticsearch, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Host == "" || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return nil, fmt.Errorf("URL de elasticsearch inválida")
+	}
+	// * Los nombres de índice de Elasticsearch van en minúsculas
+	index = strings.ToLower(index)
+	if index == "" || strings.ContainsAny(index, `/\*?"<>| ,#`) {
+		return nil, fmt.Errorf("nombre de índice de elasticsearch inválido: %q", index)
+	}
+	client := &Elasticsearch{
+		base:      fmt.Sprintf("%s://%s%s", parsed.Scheme, parsed.Host, strings.TrimSuffix(parsed.Path, "/")),
+		index:     index,
+		boosts:    boosts,
+		fuzziness: fuzziness,
+		http:      &http.Client{Timeout: timeout},
+	}
+	if parsed.User != nil {
+		client.username = parsed.User.Username()
+		client.password, _ = parsed.User.Password()
+	}
+	return client, nil
+}
+
+func (e *Elasticsearch) Backend() string {
+	return "elasticsearch"
+}
+
+func (e *Elasticsearch) Upsert(ctx context.Context, docs []Document) error {
+	if len(docs) == 0 {
+		return nil
+	}
+	var body bytes.Buffer
+	encoder := json.NewEncoder(&body)
+	for _, doc := range docs {
+		encoder.Encode(map[string]any{"index": map[string]string{"_index": e.index, "_id": strconv.Itoa(doc.ID)}})
+		encoder.Encode(doc)
+	}
+	return e.bulk(ctx, &body)
+}
+
+func (e *Elasticsearch) Delete(ctx context.Context, ids []int) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	var body bytes.Buffer
+	encoder := json.NewEncoder(&body)
+	for _, id := range ids {
+		encoder.Encode(map[string]any{"delete": map[string]string{"_index": e.index, "_id": strconv.Itoa(id)}})
+	}
+	return e.bulk(ctx, &body)
+}
+
+func (e *Elasticsearch) Search(ctx context.Context, text string, limit int) ([]Hit, error) {
+	if err := e.ensureIndex(ctx); err != nil {
+		return nil, err
+	}
+	fields := make([]string, 0, len(e.boosts))
+	for _, field := range Fields {
+		if boost := e.boosts[field]; boost > 0 {
+			fields = append(fields, field+"^"+strconv.FormatFloat(boost, 'f', -1, 64))
+		}
+	}
+	fuzziness := "AUTO"
+	if e.fuzziness != FuzzinessAuto {
+		fuzziness = strconv.Itoa(int(e.fuzziness))
+	}
+	query := map[string]any{
+		"size":    limit,
+		"_source": false,
+		"query": map[string]any{
+			"multi_match": map[string]any{
+				"query":       text,
+				"fields":      fields,
+				"fuzziness":   fuzziness,
+				"tie_breaker": 0.3,
+			},
+		},
+	}
+	payload, _ := json.Marshal(query)
+
+	var result struct {
+		Hits struct {
+			Hits []struct {
+				ID    string  `json:"_id"`
+				Score float64 `json:"_score"`
+			} `json:"hits"`
+		} `json:"hits"`
+	}
+	if err := e.do(ctx, http.MethodPost, "/"+e.index+"/_search", "application/json", bytes.NewReader(payload), &result); err != nil {
+		return nil, err
+	}
+	hits := make([]Hit, 0, len(result.Hits.Hits))
+	for _, hit := range result.Hits.Hits {
+		id, err := strconv.Atoi(hit.ID)
+		if err != nil {
+			continue
+		}
+		hits = append(hits, Hit{ID: id, Score: hit.Score})
+	}
+	return hits, nil
+}
+
+func (e *Elasticsearch) bulk(ctx context.Context, body *bytes.Buffer) error {
+	if err := e.ensureIndex(ctx); err != nil {
+		return err
+	}
+	var result struct {
+		Errors bool `json:"errors"`
+		Items  []map[string]struct {
+			Error *struct {
+				Type   string `json:"type"`
+				Reason string `json:"reason"`
+			} `json:"error"`
+		} `json:"items"`
+	}
+	if err := e.do(ctx, http.MethodPost, "/_bulk", "application/x-ndjson", body, &result); err != nil {
+		return err
+	}
+	if !result.Errors {
+		return nil
+	}
+	for _, item := range result.Items {
+		for action, outcome := range item {
+			if outcome.Error != nil {
+				return fmt.Errorf("elasticsearch: %s falló: %s (%s)", action, outcome.Error.Reason, outcome.Error.Type)
+			}
+		}
+	}
+	return fmt.Errorf("elasticsearch: _bulk con errores")
+}
+
+// * Crea el índice con su mapeo si no existe. Si otra instancia lo creó primero, Elasticsearch
+// * contesta resource_already_exists_exception y se sigue igual
+func (e *Elasticsearch) ensureIndex(ctx context.Context) error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	if e.created {
+		return nil
+	}
+
+	text := map[string]any{"type": "text", "analyzer": "meownder"}
+	properties := make(map[string]any, len(Fields)+1)
+	for _, field := range Fields {
+		properties[field] = text
+	}
+	properties["id"] = map[string]any{"type": "integer"}
+	mapping := map[string]any{
+		"settings": map[string]any{
+			"analysis": map[string]any{
+				"analyzer": map[string]any{
+					"meownder": map[string]any{
+						"type":      "custom",
+						"tokenizer": "standard",
+						"filter":    []string{"lowercase", "asciifolding"},
+					},
+				},
+			},
+		},
+		"mappings": map[string]any{"properties": properties},
+	}
+	payload, _ := json.Marshal(mapping)
+
+	err := e.do(ctx, http.MethodPut, "/"+e.index, "application/json", bytes.NewReader(payload), nil)
+	if err != nil && !strings.Contains(err.Error(), "resource_already_exists_exception") {
+		return err
+	}
+	e.created = true
+	return nil
+}
+
+func (e *Elasticsearch) do(ctx context.Context, method, path, contentType string, body io.Reader, out any) error {
+	req, err := http.NewRequestWithContext(ctx, method, e.base+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	if e.username != "" {
+		req.SetBasicAuth(e.username, e.password)
+	}
+
+	resp, err := e.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("elasticsearch: %w", err)
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, 16<<20))
+	if err != nil {
+		return fmt.Errorf("elasticsearch: %w", err)
+	}
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("elasticsearch: %s %s devolvió %d: %s", method, path, resp.StatusCode, bytes.TrimSpace(raw))
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(raw, out); err != nil {
+		return fmt.Errorf("elasticsearch: respuesta inválida: %w", err)
+	}
+	return nil
+}
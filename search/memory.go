@@ -0,0 +1,233 @@
+package search
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+)
+
+// * Parámetros de BM25: saturación de la frecuencia y cuánto castiga un campo largo
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+	// * Cada letra de diferencia con la palabra buscada resta esto del puntaje
+	fuzzyPenalty = 0.3
+)
+
+// * Índice invertido en memoria, uno por campo. Para el tamaño del catálogo alcanza de
+// * sobra y no necesita nada corriendo al lado; se reconstruye desde el feed de cambios
+// * en cada arranque
+type Memory struct {
+	boosts    Boosts
+	fuzziness Fuzziness
+
+	mutex sync.RWMutex
+	// * campo -> término -> id -> apariciones
+	postings map[string]map[string]map[int]int
+	// * campo -> id -> cantidad de términos
+	lengths map[string]map[int]int
+	total   map[string]int
+	// * id -> términos indexados, para poder sacar el documento
+	docs map[int]map[string][]string
+	// * término -> en cuántos (campo, documento) aparece; es el vocabulario para los errores de tipeo
+	vocabulary map[string]int
+}
+
+func NewMemory(boosts Boosts, fuzziness Fuzziness) *Memory {
+	memory := &Memory{
+		boosts:     boosts,
+		fuzziness:  fuzziness,
+		postings:   make(map[string]map[string]map[int]int),
+		lengths:    make(map[string]map[int]int),
+		total:      make(map[string]int),
+		docs:       make(map[int]map[string][]string),
+		vocabulary: make(map[string]int),
+	}
+	for _, field := range Fields {
+		memory.postings[field] = make(map[string]map[int]int)
+		memory.lengths[field] = make(map[int]int)
+	}
+	return memory
+}
+
+func (i *Memory) Backend() string {
+	return "memory"
+}
+
+func (i *Memory) Upsert(_ context.Context, docs []Document) error {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	for _, doc := range docs {
+		i.remove(doc.ID)
+		fields := make(map[string][]string, len(Fields))
+		for _, field := range Fields {
+			terms := Tokenize(doc.field(field))
+			if len(terms) == 0 {
+				continue
+			}
+			fields[field] = terms
+			for _, term := range terms {
+				if i.postings[field][term] == nil {
+					i.postings[field][term] = make(map[int]int)
+				}
+				if i.postings[field][term][doc.ID] == 0 {
+					i.vocabulary[term]++
+				}
+				i.postings[field][term][doc.ID]++
+			}
+			i.lengths[field][doc.ID] = len(terms)
+			i.total[field] += len(terms)
+		}
+		i.docs[doc.ID] = fields
+	}
+	return nil
+}
+
+func (i *Memory) Delete(_ context.Context, ids []int) error {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	for _, id := range ids {
+		i.remove(id)
+	}
+	return nil
+}
+
+// ! Llamar con mutex tomado
+func (i *Memory) remove(id int) {
+	fields, ok := i.docs[id]
+	if !ok {
+		return
+	}
+	for field, terms := range fields {
+		for _, term := range terms {
+			postings := i.postings[field][term]
+			if _, ok := postings[id]; !ok {
+				continue
+			}
+			delete(postings, id)
+			if len(postings) == 0 {
+				delete(i.postings[field], term)
+			}
+			if i.vocabulary[term]--; i.vocabulary[term] <= 0 {
+				delete(i.vocabulary, term)
+			}
+		}
+		i.total[field] -= i.lengths[field][id]
+		delete(i.lengths[field], id)
+	}
+	delete(i.docs, id)
+}
+
+// * BM25 por campo multiplicado por el peso del campo. Cada palabra de la búsqueda vale
+// * por su mejor variante (exacta o con errores de tipeo) y un perfil que coincide con más
+// * palabras queda por encima de uno que repite mucho una sola
+func (i *Memory) Search(_ context.Context, text string, limit int) ([]Hit, error) {
+	terms := Tokenize(text)
+	if len(terms) == 0 {
+		return []Hit{}, nil
+	}
+
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+
+	count := float64(len(i.docs))
+	scores := make(map[int]float64)
+	matched := make(map[int]int)
+	for _, term := range terms {
+		best := make(map[int]float64)
+		for variant, distance := range i.expand(term) {
+			weight := 1 - fuzzyPenalty*float64(distance)
+			for field, boost := range i.boosts {
+				postings := i.postings[field][variant]
+				if len(postings) == 0 || boost == 0 {
+					continue
+				}
+				df := float64(len(postings))
+				idf := math.Log(1 + (count-df+0.5)/(df+0.5))
+				average := float64(i.total[field]) / float64(len(i.lengths[field]))
+				for id, tf := range postings {
+					length := float64(i.lengths[field][id])
+					saturated := float64(tf) * (bm25K1 + 1) / (float64(tf) + bm25K1*(1-bm25B+bm25B*length/average))
+					best[id] += boost * idf * saturated * weight
+				}
+			}
+		}
+		for id, score := range best {
+			scores[id] += score
+			matched[id]++
+		}
+	}
+
+	hits := make([]Hit, 0, len(scores))
+	for id, score := range scores {
+		hits = append(hits, Hit{ID: id, Score: score * float64(matched[id]) / float64(len(terms))})
+	}
+	sort.Slice(hits, func(a, b int) bool {
+		if hits[a].Score != hits[b].Score {
+			return hits[a].Score > hits[b].Score
+		}
+		return hits[a].ID < hits[b].ID
+	})
+	if limit > 0 && len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits, nil
+}
+
+// * Términos del vocabulario a la distancia permitida de term, con su distancia.
+// ! Llamar con mutex tomado
+func (i *Memory) expand(term string) map[string]int {
+	variants := make(map[string]int)
+	if _, ok := i.vocabulary[term]; ok {
+		variants[term] = 0
+	}
+	maxEdits := i.fuzziness.edits(term)
+	if maxEdits == 0 {
+		return variants
+	}
+	query := []rune(term)
+	for candidate := range i.vocabulary {
+		if candidate == term {
+			continue
+		}
+		if distance := levenshtein(query, []rune(candidate), maxEdits); distance <= maxEdits {
+			variants[candidate] = distance
+		}
+	}
+	return variants
+}
+
+// * Distancia de edición (con transposiciones, "siamse" está a 1 de "siames"). Corta en
+// * cuanto se pasa de limit y devuelve limit+1
+func levenshtein(a, b []rune, limit int) int {
+	if diff := len(a) - len(b); diff > limit || -diff > limit {
+		return limit + 1
+	}
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	before := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for x := 1; x <= len(a); x++ {
+		current[0] = x
+		lowest := current[0]
+		for y := 1; y <= len(b); y++ {
+			cost := 1
+			if a[x-1] == b[y-1] {
+				cost = 0
+			}
+			current[y] = min(previous[y]+1, current[y-1]+1, previous[y-1]+cost)
+			if x > 1 && y > 1 && a[x-1] == b[y-2] && a[x-2] == b[y-1] {
+				current[y] = min(current[y], before[y-2]+1)
+			}
+			lowest = min(lowest, current[y])
+		}
+		if lowest > limit {
+			return limit + 1
+		}
+		before, previous, current = previous, current, before
+	}
+	return previous[len(b)]
+}
@@ -0,0 +1,158 @@
+// Package search indexa los perfiles para búsqueda de texto con tolerancia a errores de
+// tipeo y relevancia ajustable por campo. Hay dos motores con la misma interfaz: uno
+// embebido en memoria (por defecto) y un cliente HTTP mínimo de Elasticsearch, sin
+// dependencias externas.
+package search
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// * Campos indexados; los pesos de Boosts usan estos nombres
+const (
+	FieldName        = "name"
+	FieldBreed       = "breed"
+	FieldPersonality = "personality"
+	FieldHobbies     = "hobbies"
+	FieldTags        = "tags"
+	FieldBio         = "bio"
+)
+
+var Fields = []string{FieldName, FieldBreed, FieldPersonality, FieldHobbies, FieldTags, FieldBio}
+
+// * Pesos por defecto: un nombre o una raza que coincide pesa más que una palabra de la bio
+var DefaultBoosts = Boosts{
+	FieldName:        3,
+	FieldBreed:       2,
+	FieldTags:        2,
+	FieldHobbies:     1.5,
+	FieldPersonality: 1,
+	FieldBio:         0.5,
+}
+
+// * Perfil tal como se indexa: solo el texto buscable
+type Document struct {
+	ID          int      `json:"id"`
+	Name        string   `json:"name"`
+	Breed       string   `json:"breed"`
+	Personality string   `json:"personality"`
+	Hobbies     []string `json:"hobbies"`
+	Tags        []string `json:"tags"`
+	Bio         string   `json:"bio"`
+}
+
+func (d Document) field(name string) string {
+	switch name {
+	case FieldName:
+		return d.Name
+	case FieldBreed:
+		return d.Breed
+	case FieldPersonality:
+		return d.Personality
+	case FieldHobbies:
+		return strings.Join(d.Hobbies, " ")
+	case FieldTags:
+		return strings.Join(d.Tags, " ")
+	case FieldBio:
+		return d.Bio
+	}
+	return ""
+}
+
+type Hit struct {
+	ID    int
+	Score float64
+}
+
+// * Un índice por tenant. Search devuelve los ids de más a menos relevante, como mucho limit
+type Index interface {
+	Upsert(ctx context.Context, docs []Document) error
+	Delete(ctx context.Context, ids []int) error
+	Search(ctx context.Context, text string, limit int) ([]Hit, error)
+	Backend() string
+}
+
+type Boosts map[string]float64
+
+// * "name:3,breed:2": los campos que no aparecen quedan con su peso por defecto
+func ParseBoosts(specs []string) (Boosts, error) {
+	boosts := Boosts{}
+	for field, boost := range DefaultBoosts {
+		boosts[field] = boost
+	}
+	for _, spec := range specs {
+		field, raw, ok := strings.Cut(strings.TrimSpace(spec), ":")
+		if !ok {
+			return nil, fmt.Errorf("peso inválido %q: usa campo:peso", spec)
+		}
+		if _, known := DefaultBoosts[field]; !known {
+			return nil, fmt.Errorf("campo %q desconocido (%s)", field, strings.Join(Fields, ", "))
+		}
+		boost, err := strconv.ParseFloat(raw, 64)
+		if err != nil || boost < 0 {
+			return nil, fmt.Errorf("peso inválido para %s: %q", field, raw)
+		}
+		boosts[field] = boost
+	}
+	return boosts, nil
+}
+
+// * Errores de tipeo tolerados por palabra: "auto" (0 hasta 2 letras, 1 hasta 5, 2 después)
+// * o un número fijo
+type Fuzziness int
+
+const FuzzinessAuto Fuzziness = -1
+
+func ParseFuzziness(raw string) (Fuzziness, error) {
+	if raw == "" || strings.EqualFold(raw, "auto") {
+		return FuzzinessAuto, nil
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < 0 || n > 2 {
+		return 0, fmt.Errorf("fuzziness inválido %q: auto, 0, 1 o 2", raw)
+	}
+	return Fuzziness(n), nil
+}
+
+func (f Fuzziness) edits(term string) int {
+	if f != FuzzinessAuto {
+		return int(f)
+	}
+	switch n := len([]rune(term)); {
+	case n <= 2:
+		return 0
+	case n <= 5:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// * Minúsculas y sin tildes, cortado en letras y dígitos: "Maúllo" y "maullo" son lo mismo
+func Tokenize(text string) []string {
+	var tokens []string
+	var current strings.Builder
+	flush := func() {
+		if current.Len() > 1 {
+			tokens = append(tokens, current.String())
+		}
+		current.Reset()
+	}
+	for _, r := range norm.NFD.String(strings.ToLower(text)) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			current.WriteRune(r)
+		default:
+			flush()
+		}
+	}
+	flush()
+	return tokens
+}
@@ -0,0 +1,195 @@
+// Package sentry reporta errores y panics a Sentry o a cualquier servidor compatible
+// con su API de eventos (GlitchTip, self-hosted), sin depender del SDK oficial.
+package sentry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	queueSize = 64
+	// * El mismo error repetido (p. ej. un proveedor caído en cada lote) se envía una
+	// * vez por ventana; el resto solo suma al contador de descartados
+	throttleWindow = time.Minute
+)
+
+// * Todos los métodos aceptan un cliente nil: sin SENTRY_DSN no se reporta nada
+type Client struct {
+	endpoint    string
+	auth        string
+	environment string
+	release     string
+	serverName  string
+	http        *http.Client
+
+	queue   chan *event
+	pending atomic.Int64
+	dropped atomic.Int64
+
+	recent      map[string]time.Time
+	recentMutex sync.Mutex
+}
+
+// * dsn con la forma https://<clave>[:<secreto>]@<host>[/<prefijo>]/<proyecto>
+func New(dsn, environment, release string) (*Client, error) {
+	parsed, err := url.Parse(dsn)
+	if err != nil || parsed.Host == "" || parsed.User == nil || parsed.User.Username() == "" {
+		return nil, fmt.Errorf("DSN de sentry inválido")
+	}
+	path := strings.Trim(parsed.Path, "/")
+	slash := strings.LastIndex(path, "/")
+	project, prefix := path[slash+1:], ""
+	if slash >= 0 {
+		prefix = "/" + path[:slash]
+	}
+	if project == "" {
+		return nil, fmt.Errorf("DSN de sentry sin proyecto")
+	}
+
+	auth := "Sentry sentry_version=7, sentry_client=meownder/1.0, sentry_key=" + parsed.User.Username()
+	if secret, ok := parsed.User.Password(); ok {
+		auth += ", sentry_secret=" + secret
+	}
+
+	hostname, _ := os.Hostname()
+	if release == "" {
+		release = Release()
+	}
+	client := &Client{
+		endpoint:    fmt.Sprintf("%s://%s%s/api/%s/store/", parsed.Scheme, parsed.Host, prefix, project),
+		auth:        auth,
+		environment: environment,
+		release:     release,
+		serverName:  hostname,
+		http:        &http.Client{Timeout: 5 * time.Second},
+		queue:       make(chan *event, queueSize),
+		recent:      make(map[string]time.Time),
+	}
+
+	go client.loop()
+	return client, nil
+}
+
+func (c *Client) Release() string {
+	if c == nil {
+		return ""
+	}
+	return c.release
+}
+
+// * Reporta un error con la pila de quien llama; tags se copia tal cual al evento
+func (c *Client) CaptureError(err error, tags map[string]string) {
+	if c == nil || err == nil {
+		return
+	}
+	c.enqueue(newEvent(levelError, fmt.Sprintf("%T", unwrapped(err)), err.Error(), tags, stacktrace(3)))
+}
+
+// * Para usar dentro de un recover: la pila del goroutine todavía incluye el panic
+func (c *Client) CapturePanic(recovered any, tags map[string]string) {
+	if c == nil {
+		return
+	}
+	c.enqueue(newEvent(levelFatal, "panic", fmt.Sprint(recovered), tags, stacktrace(3)))
+}
+
+// * Espera a que salgan los eventos encolados (p. ej. antes de un log.Fatal)
+func (c *Client) Flush(timeout time.Duration) {
+	if c == nil {
+		return
+	}
+	deadline := time.Now().Add(timeout)
+	for c.pending.Load() > 0 && time.Now().Before(deadline) {
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+func (c *Client) enqueue(e *event) {
+	if c.throttled(e.fingerprint()) {
+		c.dropped.Add(1)
+		return
+	}
+
+	e.Environment = c.environment
+	e.Release = c.release
+	e.ServerName = c.serverName
+
+	// ! Nunca bloquear al que reporta: con la cola llena el evento se pierde
+	c.pending.Add(1)
+	select {
+	case c.queue <- e:
+	default:
+		c.pending.Add(-1)
+		c.dropped.Add(1)
+	}
+}
+
+func (c *Client) throttled(fingerprint string) bool {
+	c.recentMutex.Lock()
+	defer c.recentMutex.Unlock()
+
+	now := time.Now()
+	if last, ok := c.recent[fingerprint]; ok && now.Sub(last) < throttleWindow {
+		return true
+	}
+	c.recent[fingerprint] = now
+
+	// * Limpieza perezosa para que errores con mensajes únicos no crezcan sin límite
+	if len(c.recent) > 1000 {
+		for key, seen := range c.recent {
+			if now.Sub(seen) >= throttleWindow {
+				delete(c.recent, key)
+			}
+		}
+	}
+	return false
+}
+
+func (c *Client) loop() {
+	for e := range c.queue {
+		if dropped := c.dropped.Swap(0); dropped > 0 {
+			e.Extra = map[string]any{"dropped_since_last": dropped}
+		}
+		if err := c.send(e); err != nil {
+			log.Printf("⚠️ No se pudo reportar el error a sentry: %v", err)
+		}
+		c.pending.Add(-1)
+	}
+}
+
+func (c *Client) send(e *event) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.http.Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Sentry-Auth", c.auth)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("sentry respondió %d", resp.StatusCode)
+	}
+	return nil
+}
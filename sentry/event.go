@@ -0,0 +1,181 @@
+package sentry
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"time"
+)
+
+const (
+	levelError = "error"
+	levelFatal = "fatal"
+
+	modulePath = "github.com/ChrisTheAbysswalker/meownder-backend"
+	maxFrames  = 50
+)
+
+// * Subconjunto del formato de eventos de sentry que entienden también los compatibles
+type event struct {
+	EventID     string            `json:"event_id"`
+	Timestamp   string            `json:"timestamp"`
+	Level       string            `json:"level"`
+	Platform    string            `json:"platform"`
+	Environment string            `json:"environment,omitempty"`
+	Release     string            `json:"release,omitempty"`
+	ServerName  string            `json:"server_name,omitempty"`
+	Message     string            `json:"message"`
+	Exception   *exceptionList    `json:"exception,omitempty"`
+	Tags        map[string]string `json:"tags,omitempty"`
+	Extra       map[string]any    `json:"extra,omitempty"`
+}
+
+type exceptionList struct {
+	Values []exception `json:"values"`
+}
+
+type exception struct {
+	Type       string      `json:"type"`
+	Value      string      `json:"value"`
+	Stacktrace *stackTrace `json:"stacktrace,omitempty"`
+}
+
+type stackTrace struct {
+	Frames []frame `json:"frames"`
+}
+
+type frame struct {
+	Function string `json:"function"`
+	Module   string `json:"module,omitempty"`
+	Filename string `json:"filename"`
+	AbsPath  string `json:"abs_path"`
+	Lineno   int    `json:"lineno"`
+	InApp    bool   `json:"in_app"`
+}
+
+func newEvent(level, kind, message string, tags map[string]string, frames []frame) *event {
+	id := make([]byte, 16)
+	rand.Read(id)
+
+	return &event{
+		EventID:   hex.EncodeToString(id),
+		Timestamp: time.Now().UTC().Format(time.RFC3339Nano),
+		Level:     level,
+		Platform:  "go",
+		Message:   message,
+		Exception: &exceptionList{Values: []exception{{
+			Type:       kind,
+			Value:      message,
+			Stacktrace: &stackTrace{Frames: frames},
+		}}},
+		Tags: tags,
+	}
+}
+
+// * Agrupa por tipo, mensaje y el frame propio más cercano al error
+func (e *event) fingerprint() string {
+	var site string
+	frames := e.Exception.Values[0].Stacktrace.Frames
+	for i := len(frames) - 1; i >= 0; i-- {
+		if frames[i].InApp {
+			site = frames[i].Function
+			break
+		}
+	}
+	return e.Exception.Values[0].Type + "|" + e.Message + "|" + site
+}
+
+// * Frames del más antiguo al más reciente, como los espera sentry
+func stacktrace(skip int) []frame {
+	pcs := make([]uintptr, maxFrames)
+	n := runtime.Callers(skip, pcs)
+	callers := runtime.CallersFrames(pcs[:n])
+
+	var frames []frame
+	for {
+		caller, more := callers.Next()
+		module, function := splitFunction(caller.Function)
+		frames = append(frames, frame{
+			Function: function,
+			Module:   module,
+			Filename: shortFile(caller.File),
+			AbsPath:  caller.File,
+			Lineno:   caller.Line,
+			InApp:    module == "main" || strings.HasPrefix(module, modulePath),
+		})
+		if !more {
+			break
+		}
+	}
+
+	for i, j := 0, len(frames)-1; i < j; i, j = i+1, j-1 {
+		frames[i], frames[j] = frames[j], frames[i]
+	}
+	return frames
+}
+
+// * "github.com/x/y/services.(*CatService).Load" -> ("github.com/x/y/services", "(*CatService).Load")
+func splitFunction(name string) (string, string) {
+	slash := strings.LastIndex(name, "/")
+	dot := strings.Index(name[slash+1:], ".")
+	if dot < 0 {
+		return "", name
+	}
+	return name[:slash+1+dot], name[slash+2+dot:]
+}
+
+func shortFile(path string) string {
+	parts := strings.Split(path, "/")
+	if len(parts) > 2 {
+		parts = parts[len(parts)-2:]
+	}
+	return strings.Join(parts, "/")
+}
+
+// * El tipo del error de origen dice más que el *fmt.wrapError que lo envuelve
+func unwrapped(err error) error {
+	for {
+		inner := errors.Unwrap(err)
+		if inner == nil {
+			return err
+		}
+		err = inner
+	}
+}
+
+// * Revisión de git embebida por go build (con -dirty si había cambios sin commitear);
+// * si el binario no la tiene, la versión del módulo
+func Release() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+
+	var revision string
+	var modified bool
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			revision = setting.Value
+		case "vcs.modified":
+			modified = setting.Value == "true"
+		}
+	}
+	if revision == "" {
+		if info.Main.Version == "(devel)" {
+			return ""
+		}
+		return info.Main.Version
+	}
+
+	if len(revision) > 12 {
+		revision = revision[:12]
+	}
+	if modified {
+		revision += "-dirty"
+	}
+	return revision
+}
@@ -0,0 +1,129 @@
+package server
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"golang.org/x/crypto/acme/autocert"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+
+	"github.com/ChrisTheAbysswalker/meownder-backend/config"
+)
+
+func Run(handler http.Handler, cfg *config.Config) error {
+	srv := newHTTPServer(handler, cfg)
+
+	ln, err := listen(cfg)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case cfg.TLS.AutocertEnabled():
+		return runAutocert(srv, ln, cfg.TLS)
+	case cfg.TLS.Enabled():
+		if cfg.TLS.RedirectHTTP {
+			go runRedirect(cfg.TLS.HTTPPort, http.HandlerFunc(redirectToHTTPS))
+		}
+		log.Printf("🔒 HTTPS habilitado con certificado %s", cfg.TLS.CertFile)
+		return srv.ServeTLS(ln, cfg.TLS.CertFile, cfg.TLS.KeyFile)
+	default:
+		return srv.Serve(ln)
+	}
+}
+
+func newHTTPServer(handler http.Handler, cfg *config.Config) *http.Server {
+	// * h2c solo tiene sentido sin TLS (HTTP/2 en texto plano para clientes internos)
+	if cfg.Server.H2C && !cfg.TLS.Enabled() {
+		handler = h2c.NewHandler(handler, &http2.Server{
+			IdleTimeout: cfg.Server.IdleTimeout,
+		})
+		log.Println("⚡ HTTP/2 sin TLS (h2c) habilitado")
+	}
+
+	return &http.Server{
+		Addr:              ":" + cfg.Port,
+		Handler:           handler,
+		ReadTimeout:       cfg.Server.ReadTimeout,
+		ReadHeaderTimeout: cfg.Server.ReadHeaderTimeout,
+		WriteTimeout:      cfg.Server.WriteTimeout,
+		IdleTimeout:       cfg.Server.IdleTimeout,
+		MaxHeaderBytes:    cfg.Server.MaxHeaderBytes,
+	}
+}
+
+func listen(cfg *config.Config) (net.Listener, error) {
+	if cfg.Server.SocketPath == "" {
+		return net.Listen("tcp", ":"+cfg.Port)
+	}
+
+	// ! Un socket huérfano de una ejecución anterior impide hacer bind
+	if err := os.Remove(cfg.Server.SocketPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("error eliminando socket previo: %w", err)
+	}
+
+	ln, err := net.Listen("unix", cfg.Server.SocketPath)
+	if err != nil {
+		return nil, fmt.Errorf("error escuchando en socket unix: %w", err)
+	}
+
+	if err := os.Chmod(cfg.Server.SocketPath, cfg.Server.SocketMode); err != nil {
+		ln.Close()
+		return nil, fmt.Errorf("error ajustando permisos del socket: %w", err)
+	}
+
+	log.Printf("🔌 Escuchando en socket unix %s", cfg.Server.SocketPath)
+	return ln, nil
+}
+
+func runAutocert(srv *http.Server, ln net.Listener, cfg config.TLSConfig) error {
+	manager := &autocert.Manager{
+		Prompt:     autocert.AcceptTOS,
+		HostPolicy: autocert.HostWhitelist(cfg.AutocertDomains...),
+		Cache:      autocert.DirCache(cfg.AutocertCache),
+		Email:      cfg.AutocertEmail,
+	}
+
+	srv.TLSConfig = manager.TLSConfig()
+	srv.TLSConfig.MinVersion = tls.VersionTLS12
+
+	// * El listener HTTP es obligatorio para el challenge http-01 de Let's Encrypt
+	var fallback http.Handler
+	if cfg.RedirectHTTP {
+		fallback = http.HandlerFunc(redirectToHTTPS)
+	}
+	go runRedirect(cfg.HTTPPort, manager.HTTPHandler(fallback))
+
+	log.Printf("🔒 HTTPS con Let's Encrypt para: %v", cfg.AutocertDomains)
+	return srv.ServeTLS(ln, "", "")
+}
+
+func runRedirect(port string, handler http.Handler) {
+	redirectSrv := &http.Server{
+		Addr:              ":" + port,
+		Handler:           handler,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	log.Printf("↪️ Listener HTTP en :%s", port)
+	if err := redirectSrv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		log.Printf("⚠️ Error en listener HTTP: %v", err)
+	}
+}
+
+func redirectToHTTPS(w http.ResponseWriter, r *http.Request) {
+	host := r.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+
+	target := "https://" + host + r.URL.RequestURI()
+	http.Redirect(w, r, target, http.StatusMovedPermanently)
+}
@@ -0,0 +1,214 @@
+package services
+
+import (
+	"log"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+)
+
+const (
+	abuseWindow     = time.Minute
+	abuseIdleExpiry = 10 * time.Minute
+)
+
+// * User-Agents de herramientas y librerías: un navegador o la app nunca los mandan
+var botAgents = []string{
+	"curl", "wget", "python", "scrapy", "go-http-client", "java/", "libwww", "httpclient",
+	"headless", "phantomjs", "aiohttp", "node-fetch", "axios", "spider", "crawler",
+}
+
+type AbusePolicy struct {
+	// * Peticiones (ponderadas por costo) por minuto que llevan el puntaje de velocidad a 100
+	MaxPerMinute int
+	// * Desde este puntaje se responde 429
+	BlockScore float64
+	// * Tras tantos 429 seguidos la IP pasa a la lista de bloqueo; 0 = nunca
+	AutoBlockAfter int
+	AutoBlockTTL   time.Duration
+}
+
+// * Puntaje de abuso por IP: velocidad (ventana deslizante de un minuto) más cabeceras
+// * que delatan a un script. Liviano a propósito: no reemplaza a un WAF
+type AbuseGuard struct {
+	policy    AbusePolicy
+	blocklist *Blocklist
+
+	clients   map[string]*abuseClient
+	lastSweep time.Time
+	mutex     sync.Mutex
+
+	denied  atomic.Int64
+	blocked atomic.Int64
+}
+
+type abuseClient struct {
+	windowStart time.Time
+	// * Costo acumulado en el minuto actual y en el anterior
+	current  float64
+	previous float64
+	headers  float64
+	// * 429 seguidos; vuelve a 0 con una petición aceptada
+	denied   int64
+	lastSeen time.Time
+}
+
+func NewAbuseGuard(policy AbusePolicy, blocklist *Blocklist) *AbuseGuard {
+	return &AbuseGuard{
+		policy:    policy,
+		blocklist: blocklist,
+		clients:   make(map[string]*abuseClient),
+		lastSweep: time.Now(),
+	}
+}
+
+// * status 0 deja pasar; 403 si la IP está bloqueada y 429 si el puntaje pasa el umbral
+func (g *AbuseGuard) Inspect(ip string, header http.Header, cost int) (int, time.Duration) {
+	if _, ok := g.blocklist.Match(ip); ok {
+		g.blocked.Add(1)
+		return http.StatusForbidden, 0
+	}
+	if g.policy.MaxPerMinute <= 0 {
+		return 0, 0
+	}
+
+	now := time.Now()
+	g.mutex.Lock()
+	g.sweep(now)
+	client, ok := g.clients[ip]
+	if !ok {
+		client = &abuseClient{windowStart: now}
+		g.clients[ip] = client
+	}
+	client.advance(now)
+	client.current += float64(max(cost, 1))
+	client.headers = headerScore(header)
+	client.lastSeen = now
+
+	score := g.score(client, now)
+	if score < g.policy.BlockScore {
+		client.denied = 0
+		g.mutex.Unlock()
+		return 0, 0
+	}
+	client.denied++
+	autoBlock := g.policy.AutoBlockAfter > 0 && client.denied >= int64(g.policy.AutoBlockAfter)
+	if autoBlock {
+		client.denied = 0
+	}
+	retryAfter := client.windowStart.Add(abuseWindow).Sub(now)
+	g.mutex.Unlock()
+
+	g.denied.Add(1)
+	if autoBlock {
+		entry := g.blocklist.addAuto(ip, "puntaje de abuso sostenido", g.policy.AutoBlockTTL)
+		log.Printf("🚫 IP %s bloqueada automáticamente hasta %s (puntaje %.0f)", ip, entry.ExpiresAt.Format(time.RFC3339), score)
+	}
+	return http.StatusTooManyRequests, retryAfter
+}
+
+// * Pasado un minuto el actual pasa a ser el anterior; pasados dos, ambos quedan en cero
+func (c *abuseClient) advance(now time.Time) {
+	elapsed := now.Sub(c.windowStart)
+	if elapsed < abuseWindow {
+		return
+	}
+	if elapsed < 2*abuseWindow {
+		c.previous = c.current
+	} else {
+		c.previous = 0
+	}
+	c.current = 0
+	c.windowStart = now.Truncate(time.Second)
+}
+
+// * Estimación de ventana deslizante: el minuto anterior pesa lo que falta del actual
+func (c *abuseClient) rate(now time.Time) float64 {
+	elapsed := min(now.Sub(c.windowStart), abuseWindow)
+	return c.previous*(1-float64(elapsed)/float64(abuseWindow)) + c.current
+}
+
+func (g *AbuseGuard) score(client *abuseClient, now time.Time) float64 {
+	velocity := 100 * client.rate(now) / float64(g.policy.MaxPerMinute)
+	return math.Round(velocity + client.headers)
+}
+
+func headerScore(header http.Header) float64 {
+	score := 0.0
+	agent := strings.ToLower(header.Get("User-Agent"))
+	switch {
+	case agent == "":
+		score += 30
+	case containsAny(agent, botAgents):
+		score += 20
+	}
+	if header.Get("Accept") == "" {
+		score += 10
+	}
+	if header.Get("Accept-Language") == "" {
+		score += 10
+	}
+	return score
+}
+
+func containsAny(s string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if strings.Contains(s, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// * Barrido perezoso de IPs inactivas; llamar con mutex tomado
+func (g *AbuseGuard) sweep(now time.Time) {
+	if now.Sub(g.lastSweep) < abuseWindow {
+		return
+	}
+	for ip, client := range g.clients {
+		if now.Sub(client.lastSeen) > abuseIdleExpiry {
+			delete(g.clients, ip)
+		}
+	}
+	g.lastSweep = now
+}
+
+// * Las limit IPs con mayor puntaje en este momento
+func (g *AbuseGuard) Stats(limit int) m.AbuseStats {
+	now := time.Now()
+
+	g.mutex.Lock()
+	suspects := make([]m.AbuseSuspect, 0, len(g.clients))
+	for ip, client := range g.clients {
+		client.advance(now)
+		suspects = append(suspects, m.AbuseSuspect{
+			IP:                ip,
+			Score:             g.score(client, now),
+			RequestsPerMinute: math.Round(client.rate(now)*10) / 10,
+			Denied:            client.denied,
+		})
+	}
+	tracked := len(g.clients)
+	g.mutex.Unlock()
+
+	sort.Slice(suspects, func(i, j int) bool { return suspects[i].Score > suspects[j].Score })
+	if len(suspects) > limit {
+		suspects = suspects[:limit]
+	}
+	for i := range suspects {
+		suspects[i].ASN = g.blocklist.ASN(suspects[i].IP)
+	}
+
+	return m.AbuseStats{
+		Tracked:  tracked,
+		Denied:   g.denied.Load(),
+		Blocked:  g.blocked.Load(),
+		Suspects: suspects,
+	}
+}
@@ -0,0 +1,283 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+)
+
+type AlertThresholds struct {
+	// * Fracción de 5xx en la ventana; 0 apaga la alerta
+	ErrorRate float64
+	// * Por debajo de este volumen la tasa de errores no es significativa
+	MinRequests int64
+	// * 0 apaga la alerta de latencia
+	P99    time.Duration
+	Window time.Duration
+}
+
+// * Evalúa umbrales sobre el StatsCollector y avisa por webhook al cruzarlos y al
+// * recuperarse. Mientras una alerta sigue activa se repite como mucho una vez por cooldown
+type AlertMonitor struct {
+	stats      *StatsCollector
+	circuits   func() []string
+	thresholds AlertThresholds
+	notifier   *AlertNotifier
+	cooldown   time.Duration
+	instance   string
+
+	// * Solo lo toca el loop del monitor
+	states map[string]*alertState
+}
+
+type alertState struct {
+	firing bool
+	// * Se avisó del disparo actual (un disparo suprimido por cooldown no se resuelve)
+	notified  bool
+	lastFired time.Time
+}
+
+type alertCheck struct {
+	name      string
+	breached  bool
+	value     float64
+	threshold float64
+	message   string
+}
+
+func NewAlertMonitor(stats *StatsCollector, circuits func() []string, thresholds AlertThresholds, notifier *AlertNotifier, cooldown time.Duration) *AlertMonitor {
+	instance, _ := os.Hostname()
+	return &AlertMonitor{
+		stats:      stats,
+		circuits:   circuits,
+		thresholds: thresholds,
+		notifier:   notifier,
+		cooldown:   cooldown,
+		instance:   instance,
+		states:     make(map[string]*alertState),
+	}
+}
+
+func (a *AlertMonitor) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			a.Evaluate(ctx, time.Now())
+		}
+	}
+}
+
+func (a *AlertMonitor) Evaluate(ctx context.Context, now time.Time) {
+	for _, check := range a.checks() {
+		state, ok := a.states[check.name]
+		if !ok {
+			state = &alertState{}
+			a.states[check.name] = state
+		}
+
+		switch {
+		case check.breached && !state.firing:
+			state.firing = true
+			// ! Si se resolvió y vuelve a cruzar dentro del cooldown no se avisa de nuevo
+			if now.Sub(state.lastFired) < a.cooldown {
+				continue
+			}
+			a.fire(ctx, check, state, now)
+		case check.breached && now.Sub(state.lastFired) >= a.cooldown:
+			// * Recordatorio mientras siga activa
+			a.fire(ctx, check, state, now)
+		case !check.breached && state.firing:
+			state.firing = false
+			if state.notified {
+				state.notified = false
+				a.send(ctx, check, m.AlertResolved, now)
+			}
+		}
+	}
+}
+
+func (a *AlertMonitor) fire(ctx context.Context, check alertCheck, state *alertState, now time.Time) {
+	state.lastFired = now
+	state.notified = true
+	a.send(ctx, check, m.AlertFiring, now)
+}
+
+func (a *AlertMonitor) checks() []alertCheck {
+	requests := a.stats.Requests(a.thresholds.Window)
+	window := a.thresholds.Window.Round(time.Second)
+
+	var checks []alertCheck
+	if a.thresholds.ErrorRate > 0 {
+		checks = append(checks, alertCheck{
+			name:      "error_rate",
+			breached:  requests.Requests >= a.thresholds.MinRequests && requests.ErrorRate > a.thresholds.ErrorRate,
+			value:     requests.ErrorRate,
+			threshold: a.thresholds.ErrorRate,
+			message: fmt.Sprintf("Tasa de errores 5xx %.1f%% en %s (%d de %d peticiones, umbral %.1f%%)",
+				requests.ErrorRate*100, window, requests.Errors, requests.Requests, a.thresholds.ErrorRate*100),
+		})
+	}
+	if a.thresholds.P99 > 0 {
+		threshold := float64(a.thresholds.P99.Milliseconds())
+		checks = append(checks, alertCheck{
+			name:      "p99_latency",
+			breached:  requests.Requests >= a.thresholds.MinRequests && requests.P99Ms > threshold,
+			value:     requests.P99Ms,
+			threshold: threshold,
+			message:   fmt.Sprintf("Latencia p99 %.0fms en %s (umbral %s)", requests.P99Ms, window, a.thresholds.P99),
+		})
+	}
+
+	// * Un check por proveedor conocido: así también se detecta cuando un circuito se cierra
+	open := make(map[string]bool)
+	if a.circuits != nil {
+		for _, name := range a.circuits() {
+			open[name] = true
+		}
+	}
+	for name := range a.states {
+		if provider, ok := strings.CutPrefix(name, "circuit:"); ok && !open[provider] {
+			checks = append(checks, circuitCheck(provider, false))
+		}
+	}
+	for provider := range open {
+		checks = append(checks, circuitCheck(provider, true))
+	}
+	return checks
+}
+
+func circuitCheck(provider string, open bool) alertCheck {
+	check := alertCheck{
+		name:      "circuit:" + provider,
+		breached:  open,
+		threshold: providerDownThreshold,
+		message:   fmt.Sprintf("Circuito del proveedor %s abierto: %d fallos seguidos", provider, providerDownThreshold),
+	}
+	if open {
+		check.value = 1
+	} else {
+		check.message = fmt.Sprintf("Circuito del proveedor %s cerrado", provider)
+	}
+	return check
+}
+
+func (a *AlertMonitor) send(ctx context.Context, check alertCheck, status string, now time.Time) {
+	alert := m.Alert{
+		Name:      check.name,
+		Status:    status,
+		Message:   check.message,
+		Value:     check.value,
+		Threshold: check.threshold,
+		Instance:  a.instance,
+		At:        now,
+	}
+
+	log.Printf("🚨 Alerta %s (%s): %s", alert.Name, alert.Status, alert.Message)
+	if err := a.notifier.Notify(ctx, alert); err != nil {
+		log.Printf("⚠️ Error enviando alerta %s: %v", alert.Name, err)
+	}
+}
+
+// * Webhook de alertas: Slack y Discord reciben un mensaje de texto, cualquier otro
+// * destino el JSON de m.Alert
+type AlertNotifier struct {
+	url    string
+	kind   string
+	client *http.Client
+}
+
+// * kind vacío = se deduce del host de la URL
+func NewAlertNotifier(webhookURL, kind string) (*AlertNotifier, error) {
+	parsed, err := url.Parse(webhookURL)
+	if err != nil || parsed.Host == "" {
+		return nil, fmt.Errorf("URL de webhook inválida: %q", webhookURL)
+	}
+
+	if kind == "" {
+		switch {
+		case strings.HasSuffix(parsed.Host, "slack.com"):
+			kind = "slack"
+		case strings.HasSuffix(parsed.Host, "discord.com"), strings.HasSuffix(parsed.Host, "discordapp.com"):
+			kind = "discord"
+		default:
+			kind = "generic"
+		}
+	}
+	if kind != "slack" && kind != "discord" && kind != "generic" {
+		return nil, fmt.Errorf("tipo de webhook desconocido: %s", kind)
+	}
+
+	return &AlertNotifier{
+		url:    webhookURL,
+		kind:   kind,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+func (n *AlertNotifier) Kind() string {
+	return n.kind
+}
+
+func (n *AlertNotifier) Notify(ctx context.Context, alert m.Alert) error {
+	icon := "🚨"
+	if alert.Status == m.AlertResolved {
+		icon = "✅"
+	}
+	return n.deliver(ctx, fmt.Sprintf("%s [%s] %s", icon, alert.Instance, alert.Message), alert)
+}
+
+// * Aviso de trabajo terminado (ver JobManager.OnFinish); generic recibe el m.JobEvent
+func (n *AlertNotifier) NotifyJob(ctx context.Context, job m.Job) error {
+	text := fmt.Sprintf("✅ Trabajo %s (%s) terminado", job.ID, job.Kind)
+	if job.Status == m.JobFailed {
+		text = fmt.Sprintf("❌ Trabajo %s (%s) falló: %s", job.ID, job.Kind, job.Error)
+	}
+	return n.deliver(ctx, text, m.JobEvent{Type: m.JobEventFinished, Job: job})
+}
+
+func (n *AlertNotifier) deliver(ctx context.Context, text string, generic any) error {
+	var payload any
+	switch n.kind {
+	case "slack":
+		payload = map[string]string{"text": text}
+	case "discord":
+		payload = map[string]string{"content": text}
+	default:
+		payload = generic
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("el webhook respondió %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,265 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+)
+
+// * Prefijo de los tokens: así el middleware los distingue de la clave de admin en Authorization
+const APITokenPrefix = "mwt_"
+
+var (
+	ErrTokenNotFound      = errors.New("token no encontrado")
+	ErrInvalidTokenOrigin = errors.New("origen inválido: usa esquema y host, ej. https://blog.com")
+	ErrWidgetNeedsOrigins = errors.New("un token con alcance widget necesita origins")
+)
+
+// * Rutas (por FullPath) de cada alcance; todas de lectura
+var tokenScopeRoutes = map[string][]string{
+	m.ScopeWidget:      {"/widget", "/api/widget/cat", "/api/profiles/:id/image"},
+	m.ScopeProfiles:    {"/api/profiles", "/api/profiles/count", "/api/profiles/summary", "/api/profiles/:id", "/api/profiles/:id/image", "/api/tags", "/api/cat-of-the-day", "/api/changes"},
+	m.ScopeCollections: {"/api/collections", "/api/collections/:slug"},
+	m.ScopeFeed:        {"/feed.xml"},
+}
+
+// * Tokens de alcance acotado emitidos por el admin de cada refugio. Se guardan solo los
+// * hashes; con path sobreviven reinicios (los contadores de uso se guardan al cambiar la lista)
+type APITokens struct {
+	path   string
+	tokens []m.APIToken
+	mutex  sync.Mutex
+}
+
+func NewAPITokens(path string) *APITokens {
+	t := &APITokens{path: path}
+
+	if err := t.load(); err != nil {
+		log.Printf("⚠️ Error cargando tokens: %v", err)
+	} else if len(t.tokens) > 0 {
+		log.Printf("🔑 Tokens de integración cargados: %d", len(t.tokens))
+	}
+	return t
+}
+
+func (t *APITokens) Create(tenant string, req m.APITokenRequest) (m.APITokenCreated, error) {
+	scopes := slices.Compact(slices.Sorted(slices.Values(req.Scopes)))
+	origins := make([]string, 0, len(req.Origins))
+	for _, origin := range req.Origins {
+		parsed, err := url.Parse(origin)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" || parsed.Path != "" {
+			return m.APITokenCreated{}, fmt.Errorf("%w: %q", ErrInvalidTokenOrigin, origin)
+		}
+		origins = append(origins, strings.ToLower(origin))
+	}
+	if slices.Contains(scopes, m.ScopeWidget) && len(origins) == 0 {
+		return m.APITokenCreated{}, ErrWidgetNeedsOrigins
+	}
+
+	id := randomHex(8)
+	secret := APITokenPrefix + id + "_" + randomHex(24)
+	token := m.APIToken{
+		ID:        id,
+		Name:      req.Name,
+		Tenant:    tenant,
+		Scopes:    scopes,
+		Origins:   origins,
+		Hash:      hashToken(secret),
+		CreatedAt: time.Now(),
+	}
+	if req.TTLSeconds > 0 {
+		expires := token.CreatedAt.Add(time.Duration(req.TTLSeconds) * time.Second)
+		token.ExpiresAt = &expires
+	}
+
+	t.mutex.Lock()
+	t.tokens = append(t.tokens, token)
+	t.mutex.Unlock()
+
+	t.persist()
+	return m.APITokenCreated{APIToken: token, Token: secret}, nil
+}
+
+// * Del refugio, más nuevos primero; sin los vencidos
+func (t *APITokens) List(tenant string) []m.APIToken {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.prune(time.Now())
+	tokens := make([]m.APIToken, 0)
+	for _, token := range t.tokens {
+		if token.Tenant == tenant {
+			tokens = append(tokens, token)
+		}
+	}
+	sort.Slice(tokens, func(i, j int) bool { return tokens[i].CreatedAt.After(tokens[j].CreatedAt) })
+	return tokens
+}
+
+func (t *APITokens) Revoke(tenant, id string) error {
+	t.mutex.Lock()
+	before := len(t.tokens)
+	t.tokens = slices.DeleteFunc(t.tokens, func(token m.APIToken) bool {
+		return token.Tenant == tenant && token.ID == id
+	})
+	removed := len(t.tokens) < before
+	t.mutex.Unlock()
+
+	if !removed {
+		return ErrTokenNotFound
+	}
+	t.persist()
+	return nil
+}
+
+// * Implementa middleware.TokenVerifier. 401 si el token no existe o venció; 403 si es
+// * de otro refugio, la ruta no está en sus alcances, no es de lectura o el origen no está
+// * permitido (sin Origin, como en servidor a servidor, no se chequea)
+func (t *APITokens) VerifyToken(secret, tenant, method, route, origin string) (m.TokenGrant, int, error) {
+	id, _, ok := strings.Cut(strings.TrimPrefix(secret, APITokenPrefix), "_")
+	if !ok {
+		return m.TokenGrant{}, http.StatusUnauthorized, errors.New("token inválido")
+	}
+	hash := hashToken(secret)
+	now := time.Now()
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	i := slices.IndexFunc(t.tokens, func(token m.APIToken) bool { return token.ID == id })
+	if i < 0 || subtle.ConstantTimeCompare([]byte(t.tokens[i].Hash), []byte(hash)) != 1 {
+		return m.TokenGrant{}, http.StatusUnauthorized, errors.New("token inválido o revocado")
+	}
+	token := &t.tokens[i]
+	if token.ExpiresAt != nil && now.After(*token.ExpiresAt) {
+		return m.TokenGrant{}, http.StatusUnauthorized, errors.New("el token venció")
+	}
+	if token.Tenant != tenant {
+		return m.TokenGrant{}, http.StatusForbidden, errors.New("el token es de otro refugio")
+	}
+	if method != http.MethodGet && method != http.MethodHead {
+		return m.TokenGrant{}, http.StatusForbidden, errors.New("el token es de solo lectura")
+	}
+	if !tokenAllows(token.Scopes, route) {
+		return m.TokenGrant{}, http.StatusForbidden, errors.New("la ruta no está en los alcances del token")
+	}
+	if origin != "" && len(token.Origins) > 0 && !slices.Contains(token.Origins, strings.ToLower(origin)) {
+		return m.TokenGrant{}, http.StatusForbidden, errors.New("este origen no puede usar el token")
+	}
+
+	token.Uses++
+	token.LastUsedAt = &now
+	return m.TokenGrant{TokenID: token.ID, Origins: slices.Clone(token.Origins)}, 0, nil
+}
+
+func tokenAllows(scopes []string, route string) bool {
+	for _, scope := range scopes {
+		if slices.Contains(tokenScopeRoutes[scope], route) {
+			return true
+		}
+	}
+	return false
+}
+
+// ! Llamar con mutex tomado
+func (t *APITokens) prune(now time.Time) {
+	t.tokens = slices.DeleteFunc(t.tokens, func(token m.APIToken) bool {
+		return token.ExpiresAt != nil && now.After(*token.ExpiresAt)
+	})
+}
+
+func (t *APITokens) persist() {
+	if err := t.save(); err != nil {
+		log.Printf("⚠️ Error guardando tokens: %v", err)
+	}
+}
+
+func (t *APITokens) save() error {
+	if t.path == "" {
+		return nil
+	}
+
+	t.mutex.Lock()
+	t.prune(time.Now())
+	stored := make([]storedToken, len(t.tokens))
+	for i, token := range t.tokens {
+		stored[i] = storedToken{APIToken: token, Hash: token.Hash}
+	}
+	t.mutex.Unlock()
+
+	data, err := json.MarshalIndent(stored, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(t.path), 0o755); err != nil {
+		return fmt.Errorf("error creando directorio de tokens: %w", err)
+	}
+
+	// ! Escribir a un temporal y renombrar para no dejar el archivo a medias
+	tmp := t.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, t.path)
+}
+
+func (t *APITokens) load() error {
+	if t.path == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(t.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var stored []storedToken
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return fmt.Errorf("lista de tokens corrupta: %w", err)
+	}
+	for _, entry := range stored {
+		entry.APIToken.Hash = entry.Hash
+		t.tokens = append(t.tokens, entry.APIToken)
+	}
+	t.prune(time.Now())
+	return nil
+}
+
+// * En disco el hash sí se guarda (en las respuestas no viaja)
+type storedToken struct {
+	m.APIToken
+	Hash string `json:"hash"`
+}
+
+func hashToken(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomHex(n int) string {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		// * Improbable; igual debe ser único
+		return fmt.Sprintf("%0*x", n*2, time.Now().UnixNano())
+	}
+	return hex.EncodeToString(buf)
+}
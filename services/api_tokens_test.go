@@ -0,0 +1,86 @@
+package services
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+)
+
+func TestAPITokensVerify(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "api-tokens.json")
+	tokens := NewAPITokens(path)
+	created, err := tokens.Create("norte", m.APITokenRequest{
+		Name:    "blog de Ana",
+		Scopes:  []string{m.ScopeWidget, m.ScopeProfiles},
+		Origins: []string{"https://Blog.com"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(created.Token, APITokenPrefix+created.ID+"_") {
+		t.Fatalf("token = %s", created.Token)
+	}
+
+	// * En disco solo queda el hash
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), created.Token) || !strings.Contains(string(data), hashToken(created.Token)) {
+		t.Fatalf("archivo de tokens: %s", data)
+	}
+
+	if _, status, err := tokens.VerifyToken(created.Token, "norte", "GET", "/api/widget/cat", "https://blog.com"); err != nil {
+		t.Fatalf("token válido rechazado: %d %v", status, err)
+	}
+
+	cases := []struct {
+		name                          string
+		secret, tenant, method, route string
+		origin                        string
+		status                        int
+	}{
+		{"otro secreto con el mismo id", APITokenPrefix + created.ID + "_" + strings.Repeat("0", 48), "norte", "GET", "/api/widget/cat", "", http.StatusUnauthorized},
+		{"sin id", APITokenPrefix + "roto", "norte", "GET", "/api/widget/cat", "", http.StatusUnauthorized},
+		{"otro refugio", created.Token, "sur", "GET", "/api/widget/cat", "", http.StatusForbidden},
+		{"escritura", created.Token, "norte", "POST", "/api/widget/cat", "", http.StatusForbidden},
+		{"fuera de alcance", created.Token, "norte", "GET", "/api/collections", "", http.StatusForbidden},
+		{"otro origen", created.Token, "norte", "GET", "/api/widget/cat", "https://otro.com", http.StatusForbidden},
+	}
+	for _, tc := range cases {
+		if _, status, err := tokens.VerifyToken(tc.secret, tc.tenant, tc.method, tc.route, tc.origin); err == nil || status != tc.status {
+			t.Errorf("%s: %d %v, se esperaba %d", tc.name, status, err, tc.status)
+		}
+	}
+
+	// * Sobrevive un reinicio y una revocación lo corta al instante
+	reloaded := NewAPITokens(path)
+	if _, _, err := reloaded.VerifyToken(created.Token, "norte", "GET", "/api/profiles", ""); err != nil {
+		t.Fatalf("tras recargar: %v", err)
+	}
+	if err := reloaded.Revoke("sur", created.ID); err != ErrTokenNotFound {
+		t.Fatalf("revocar desde otro refugio: %v", err)
+	}
+	if err := reloaded.Revoke("norte", created.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, status, _ := reloaded.VerifyToken(created.Token, "norte", "GET", "/api/profiles", ""); status != http.StatusUnauthorized {
+		t.Fatalf("token revocado: %d", status)
+	}
+}
+
+func TestAPITokensCreateRejects(t *testing.T) {
+	tokens := NewAPITokens("")
+	if _, err := tokens.Create("norte", m.APITokenRequest{Name: "x", Scopes: []string{m.ScopeWidget}}); err != ErrWidgetNeedsOrigins {
+		t.Errorf("widget sin orígenes: %v", err)
+	}
+	for _, origin := range []string{"blog.com", "https://blog.com/pagina", "ftp://blog.com"} {
+		if _, err := tokens.Create("norte", m.APITokenRequest{Name: "x", Scopes: []string{m.ScopeFeed}, Origins: []string{origin}}); err == nil {
+			t.Errorf("origen %q aceptado", origin)
+		}
+	}
+}
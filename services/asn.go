@@ -0,0 +1,86 @@
+package services
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/netip"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// * Rangos IP → ASN desde el TSV de iptoasn.com (ip2asn-combined.tsv, también .gz):
+// * "inicio\tfin\tasn\tpaís\tdescripción". ASN 0 es espacio no anunciado y se descarta
+type ASNIndex struct {
+	ranges []asnRange
+}
+
+type asnRange struct {
+	start, end netip.Addr
+	asn        uint32
+}
+
+func LoadASNIndex(path string) (*ASNIndex, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var reader io.Reader = file
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		defer gz.Close()
+		reader = gz
+	}
+
+	index := &ASNIndex{}
+	scanner := bufio.NewScanner(reader)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Split(scanner.Text(), "\t")
+		if len(fields) < 3 {
+			continue
+		}
+		start, err1 := netip.ParseAddr(fields[0])
+		end, err2 := netip.ParseAddr(fields[1])
+		asn, err3 := strconv.ParseUint(fields[2], 10, 32)
+		if err1 != nil || err2 != nil || err3 != nil {
+			return nil, fmt.Errorf("%s:%d: línea inválida", path, line)
+		}
+		if asn == 0 {
+			continue
+		}
+		index.ranges = append(index.ranges, asnRange{start: start.Unmap(), end: end.Unmap(), asn: uint32(asn)})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(index.ranges, func(i, j int) bool { return index.ranges[i].start.Less(index.ranges[j].start) })
+	return index, nil
+}
+
+func (x *ASNIndex) Len() int {
+	return len(x.ranges)
+}
+
+// * 0 si la IP no cae en ningún rango anunciado
+func (x *ASNIndex) Lookup(addr netip.Addr) uint32 {
+	addr = addr.Unmap()
+	// * Primer rango que empieza después de addr; el candidato es el anterior
+	i := sort.Search(len(x.ranges), func(i int) bool { return addr.Less(x.ranges[i].start) })
+	if i == 0 {
+		return 0
+	}
+	r := x.ranges[i-1]
+	if addr.Less(r.start) || r.end.Less(addr) {
+		return 0
+	}
+	return r.asn
+}
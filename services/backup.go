@@ -0,0 +1,209 @@
+package services
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+)
+
+const (
+	backupManifestFile = "manifest.json"
+	backupProfilesFile = "profiles.json"
+	backupSwipesFile   = "swipes.json"
+	backupMatchesFile  = "matches.json"
+	backupFilePrefix   = "meownder-backup-"
+	maxBackupEntry     = 64 << 20
+)
+
+var ErrRestoreUnsupported = errors.New("con base de datos configurada la restauración se hace con las herramientas de la base")
+
+// * Respaldo completo del estado en memoria como .tar.gz con un JSON por colección
+type BackupService struct {
+	cats   *CatService
+	swipes *SwipeService
+	dir    string
+	keep   int
+}
+
+func NewBackupService(cats *CatService, swipes *SwipeService, dir string, keep int) *BackupService {
+	return &BackupService{
+		cats:   cats,
+		swipes: swipes,
+		dir:    dir,
+		keep:   keep,
+	}
+}
+
+func BackupFileName(at time.Time) string {
+	return backupFilePrefix + at.UTC().Format("20060102-150405") + ".tar.gz"
+}
+
+func (b *BackupService) Write(w io.Writer) (m.BackupManifest, error) {
+	profiles := b.cats.snapshot().clone()
+	swipes, matches := b.swipes.export()
+	manifest := m.BackupManifest{
+		Version:   m.BackupFormatVersion,
+		CreatedAt: time.Now().UTC(),
+		Profiles:  len(profiles),
+		Swipes:    len(swipes),
+		Matches:   len(matches),
+	}
+
+	gz := gzip.NewWriter(w)
+	archive := tar.NewWriter(gz)
+	entries := []struct {
+		name string
+		data any
+	}{
+		{backupManifestFile, manifest},
+		{backupProfilesFile, profiles},
+		{backupSwipesFile, swipes},
+		{backupMatchesFile, matches},
+	}
+	for _, entry := range entries {
+		data, err := json.MarshalIndent(entry.data, "", "  ")
+		if err != nil {
+			return manifest, err
+		}
+		header := &tar.Header{
+			Name:    entry.name,
+			Mode:    0o644,
+			Size:    int64(len(data)),
+			ModTime: manifest.CreatedAt,
+		}
+		if err := archive.WriteHeader(header); err != nil {
+			return manifest, err
+		}
+		if _, err := archive.Write(data); err != nil {
+			return manifest, err
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return manifest, err
+	}
+	return manifest, gz.Close()
+}
+
+// * Valida el archivo completo antes de tocar nada; recién entonces reemplaza el estado
+func (b *BackupService) Restore(r io.Reader) (m.BackupManifest, error) {
+	var manifest m.BackupManifest
+	if b.swipes.store != nil {
+		return manifest, ErrRestoreUnsupported
+	}
+
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return manifest, fmt.Errorf("el respaldo no es un .tar.gz válido: %w", err)
+	}
+	defer gz.Close()
+
+	files := make(map[string][]byte)
+	archive := tar.NewReader(gz)
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return manifest, fmt.Errorf("respaldo corrupto: %w", err)
+		}
+		data, err := io.ReadAll(io.LimitReader(archive, maxBackupEntry))
+		if err != nil {
+			return manifest, err
+		}
+		files[header.Name] = data
+	}
+
+	var profiles []m.CatProfile
+	var swipes []m.Swipe
+	var matches []m.Match
+	for name, dst := range map[string]any{
+		backupManifestFile: &manifest,
+		backupProfilesFile: &profiles,
+		backupSwipesFile:   &swipes,
+		backupMatchesFile:  &matches,
+	} {
+		data, ok := files[name]
+		if !ok {
+			return manifest, fmt.Errorf("al respaldo le falta %s", name)
+		}
+		if err := json.Unmarshal(data, dst); err != nil {
+			return manifest, fmt.Errorf("%s inválido: %w", name, err)
+		}
+	}
+	if manifest.Version != m.BackupFormatVersion {
+		return manifest, fmt.Errorf("versión de respaldo no soportada: %d", manifest.Version)
+	}
+
+	b.cats.replaceProfiles(profiles)
+	b.swipes.replaceState(swipes, matches)
+	log.Printf("♻️ Respaldo del %s restaurado: %d perfiles, %d swipes, %d matches",
+		manifest.CreatedAt.Format(time.RFC3339), len(profiles), len(swipes), len(matches))
+	return manifest, nil
+}
+
+// * Job programado: escribe el respaldo en el directorio y conserva solo los últimos keep
+func (b *BackupService) SaveToDir() (string, error) {
+	if err := os.MkdirAll(b.dir, 0o755); err != nil {
+		return "", fmt.Errorf("error creando directorio de respaldos: %w", err)
+	}
+
+	path := filepath.Join(b.dir, BackupFileName(time.Now()))
+	tmp := path + ".tmp"
+	file, err := os.Create(tmp)
+	if err != nil {
+		return "", err
+	}
+	if _, err := b.Write(file); err != nil {
+		file.Close()
+		os.Remove(tmp)
+		return "", err
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(tmp)
+		return "", err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return "", err
+	}
+
+	b.prune()
+	log.Printf("💾 Respaldo guardado en %s", path)
+	return path, nil
+}
+
+func (b *BackupService) prune() {
+	if b.keep <= 0 {
+		return
+	}
+	entries, err := os.ReadDir(b.dir)
+	if err != nil {
+		return
+	}
+
+	var backups []string
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), backupFilePrefix) && strings.HasSuffix(entry.Name(), ".tar.gz") {
+			backups = append(backups, entry.Name())
+		}
+	}
+	// * El nombre lleva la fecha, así que el orden alfabético es el cronológico
+	sort.Strings(backups)
+	for len(backups) > b.keep {
+		if err := os.Remove(filepath.Join(b.dir, backups[0])); err != nil {
+			log.Printf("⚠️ Error borrando respaldo viejo %s: %v", backups[0], err)
+		}
+		backups = backups[1:]
+	}
+}
@@ -0,0 +1,156 @@
+package services
+
+import (
+	"sort"
+	"time"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+)
+
+const (
+	hundredSwipes     = 100
+	connoisseurBreeds = 5
+	weekStreakDays    = 7
+	streakDayLayout   = "2006-01-02"
+)
+
+var badgeDefinitions = []m.Badge{
+	{ID: m.BadgeFirstMatch, Name: "Primer match", Description: "Consigue tu primer match"},
+	{ID: m.BadgeHundredSwipes, Name: "Pulgar incansable", Description: "Desliza 100 perfiles"},
+	{ID: m.BadgeBreedConnoisseur, Name: "Conocedor de razas", Description: "Dale like a gatos de 5 razas distintas"},
+	{ID: m.BadgeWeekStreak, Name: "Racha felina", Description: "Desliza al menos un gato 7 días seguidos"},
+}
+
+// * Las insignias y rachas se derivan del historial de swipes y matches, así sobreviven a
+// * reinicios y restauraciones sin guardar nada propio. Una insignia se "gana" en el
+// * instante del swipe o match que cumplió la regla, y solo ese evento la anuncia
+type BadgeService struct {
+	swipes *SwipeService
+	cats   *CatService
+	chat   *ChatService
+}
+
+func NewBadgeService(swipes *SwipeService, cats *CatService, chat *ChatService) *BadgeService {
+	service := &BadgeService{swipes: swipes, cats: cats, chat: chat}
+	swipes.OnRecord(service.evaluate)
+	return service
+}
+
+func (s *BadgeService) Badges(userID string) m.BadgesResponse {
+	earned, streak := s.progress(userID, time.Now())
+
+	response := m.BadgesResponse{Badges: make([]m.Badge, 0, len(badgeDefinitions)), Streak: streak}
+	for _, badge := range badgeDefinitions {
+		if at, ok := earned[badge.ID]; ok {
+			badge.Earned = true
+			badge.EarnedAt = &at
+			response.Earned++
+		}
+		response.Badges = append(response.Badges, badge)
+	}
+	return response
+}
+
+// * Listener de SwipeService: evalúa a quien deslizó y a quien recibió un match
+func (s *BadgeService) evaluate(swipe m.Swipe, matches []m.Match) {
+	users := []string{swipe.UserID}
+	for _, match := range matches {
+		if match.UserID != swipe.UserID {
+			users = append(users, match.UserID)
+		}
+	}
+
+	for _, userID := range users {
+		earned, _ := s.progress(userID, swipe.CreatedAt)
+		for _, badge := range badgeDefinitions {
+			at, ok := earned[badge.ID]
+			if !ok || !at.Equal(swipe.CreatedAt) {
+				continue
+			}
+			badge.Earned = true
+			badge.EarnedAt = &at
+			s.chat.Notify(userID, m.ChatEvent{
+				Type:  m.ChatEventBadge,
+				Badge: &badge,
+				At:    at,
+			})
+		}
+	}
+}
+
+func (s *BadgeService) progress(userID string, now time.Time) (map[string]time.Time, m.Streak) {
+	earned := make(map[string]time.Time)
+
+	matches := s.swipes.allMatches(userID)
+	if len(matches) > 0 {
+		first := matches[0].CreatedAt
+		for _, match := range matches[1:] {
+			if match.CreatedAt.Before(first) {
+				first = match.CreatedAt
+			}
+		}
+		earned[m.BadgeFirstMatch] = first
+	}
+
+	history := s.swipes.History(userID)
+	sort.SliceStable(history, func(i, j int) bool { return history[i].CreatedAt.Before(history[j].CreatedAt) })
+
+	breeds := make(map[string]bool)
+	breedOf := make(map[int]string)
+	var streak m.Streak
+	var lastDay time.Time
+	run := 0
+
+	for i, swipe := range history {
+		if i+1 == hundredSwipes {
+			earned[m.BadgeHundredSwipes] = swipe.CreatedAt
+		}
+
+		if m.IsLike(swipe.Direction) {
+			breed, ok := breedOf[swipe.CatID]
+			if !ok {
+				if cat, err := s.cats.AdminGetCatProfile(swipe.CatID); err == nil {
+					breed = normalizeKey(cat.Breed)
+				}
+				breedOf[swipe.CatID] = breed
+			}
+			if breed != "" && !breeds[breed] {
+				breeds[breed] = true
+				if len(breeds) == connoisseurBreeds {
+					earned[m.BadgeBreedConnoisseur] = swipe.CreatedAt
+				}
+			}
+		}
+
+		day := utcDay(swipe.CreatedAt)
+		switch {
+		case run == 0 || day.Sub(lastDay) > 24*time.Hour:
+			run = 1
+		case day.After(lastDay):
+			run++
+		default:
+			continue
+		}
+		lastDay = day
+		streak.Longest = max(streak.Longest, run)
+		if run == weekStreakDays {
+			if _, ok := earned[m.BadgeWeekStreak]; !ok {
+				earned[m.BadgeWeekStreak] = swipe.CreatedAt
+			}
+		}
+	}
+
+	// * La racha sigue viva si el último día activo fue hoy o ayer
+	if run > 0 {
+		streak.LastActiveDay = lastDay.Format(streakDayLayout)
+		if utcDay(now).Sub(lastDay) <= 24*time.Hour {
+			streak.Current = run
+		}
+	}
+	return earned, streak
+}
+
+func utcDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,303 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+)
+
+var (
+	ErrBlockNotFound  = errors.New("entrada de bloqueo no encontrada")
+	ErrInvalidBlock   = errors.New("valor inválido: usa una IP, un rango CIDR o un ASN como AS64500")
+	ErrASNUnavailable = errors.New("para bloquear por ASN configura ABUSE_ASN_FILE")
+)
+
+// * Bloqueos por IP, rango o ASN. Con path se guardan en disco y sobreviven reinicios;
+// * las entradas con vencimiento se descartan solas
+type Blocklist struct {
+	path string
+	asn  *ASNIndex
+
+	entries []blockEntry
+	mutex   sync.RWMutex
+}
+
+type blockEntry struct {
+	m.BlockEntry
+	prefix netip.Prefix
+	asn    uint32
+}
+
+// * asn puede ser nil: entonces no se aceptan bloqueos por ASN
+func NewBlocklist(path string, asn *ASNIndex) *Blocklist {
+	b := &Blocklist{path: path, asn: asn}
+
+	if err := b.load(); err != nil {
+		log.Printf("⚠️ Error cargando lista de bloqueo: %v", err)
+	} else if len(b.entries) > 0 {
+		log.Printf("🚫 Lista de bloqueo cargada: %d entradas", len(b.entries))
+	}
+
+	return b
+}
+
+func (b *Blocklist) Add(req m.BlockRequest) (m.BlockEntry, error) {
+	entry, err := b.parse(req.Value)
+	if err != nil {
+		return m.BlockEntry{}, err
+	}
+	entry.ID = newBlockID()
+	entry.Reason = req.Reason
+	entry.CreatedAt = time.Now()
+	if req.TTLSeconds > 0 {
+		expires := entry.CreatedAt.Add(time.Duration(req.TTLSeconds) * time.Second)
+		entry.ExpiresAt = &expires
+	}
+
+	b.mutex.Lock()
+	b.entries = append(b.entries, entry)
+	b.mutex.Unlock()
+
+	b.persist()
+	return entry.BlockEntry, nil
+}
+
+func (b *Blocklist) addAuto(ip, reason string, ttl time.Duration) m.BlockEntry {
+	entry, err := b.parse(ip)
+	if err != nil {
+		return m.BlockEntry{}
+	}
+	entry.ID = newBlockID()
+	entry.Reason = reason
+	entry.Auto = true
+	entry.CreatedAt = time.Now()
+	expires := entry.CreatedAt.Add(ttl)
+	entry.ExpiresAt = &expires
+
+	b.mutex.Lock()
+	b.entries = append(b.entries, entry)
+	b.mutex.Unlock()
+
+	b.persist()
+	return entry.BlockEntry
+}
+
+func (b *Blocklist) Remove(id string) error {
+	b.mutex.Lock()
+	found := false
+	for i, entry := range b.entries {
+		if entry.ID == id {
+			b.entries = append(b.entries[:i], b.entries[i+1:]...)
+			found = true
+			break
+		}
+	}
+	b.mutex.Unlock()
+
+	if !found {
+		return ErrBlockNotFound
+	}
+	b.persist()
+	return nil
+}
+
+func (b *Blocklist) List() []m.BlockEntry {
+	b.mutex.Lock()
+	b.prune(time.Now())
+	entries := make([]m.BlockEntry, 0, len(b.entries))
+	for _, entry := range b.entries {
+		entries = append(entries, entry.BlockEntry)
+	}
+	b.mutex.Unlock()
+
+	return entries
+}
+
+// * La entrada que bloquea a ip, si hay alguna vigente
+func (b *Blocklist) Match(ip string) (m.BlockEntry, bool) {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return m.BlockEntry{}, false
+	}
+	addr = addr.Unmap()
+	now := time.Now()
+
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	if len(b.entries) == 0 {
+		return m.BlockEntry{}, false
+	}
+	var asn uint32
+	if b.asn != nil {
+		asn = b.asn.Lookup(addr)
+	}
+	for _, entry := range b.entries {
+		if entry.ExpiresAt != nil && now.After(*entry.ExpiresAt) {
+			continue
+		}
+		if entry.Kind == m.BlockASN {
+			if asn != 0 && asn == entry.asn {
+				return entry.BlockEntry, true
+			}
+		} else if entry.prefix.Contains(addr) {
+			return entry.BlockEntry, true
+		}
+	}
+	return m.BlockEntry{}, false
+}
+
+// * "AS64500" o vacío si no hay índice o la IP no está anunciada
+func (b *Blocklist) ASN(ip string) string {
+	if b.asn == nil {
+		return ""
+	}
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return ""
+	}
+	if asn := b.asn.Lookup(addr); asn != 0 {
+		return fmt.Sprintf("AS%d", asn)
+	}
+	return ""
+}
+
+func (b *Blocklist) parse(value string) (blockEntry, error) {
+	value = strings.TrimSpace(value)
+
+	if number, ok := strings.CutPrefix(strings.ToUpper(value), "AS"); ok {
+		asn, err := strconv.ParseUint(number, 10, 32)
+		if err != nil || asn == 0 {
+			return blockEntry{}, ErrInvalidBlock
+		}
+		if b.asn == nil {
+			return blockEntry{}, ErrASNUnavailable
+		}
+		return blockEntry{
+			BlockEntry: m.BlockEntry{Kind: m.BlockASN, Value: fmt.Sprintf("AS%d", asn)},
+			asn:        uint32(asn),
+		}, nil
+	}
+
+	if strings.Contains(value, "/") {
+		prefix, err := netip.ParsePrefix(value)
+		if err != nil {
+			return blockEntry{}, ErrInvalidBlock
+		}
+		prefix = prefix.Masked()
+		return blockEntry{
+			BlockEntry: m.BlockEntry{Kind: m.BlockCIDR, Value: prefix.String()},
+			prefix:     prefix,
+		}, nil
+	}
+
+	addr, err := netip.ParseAddr(value)
+	if err != nil {
+		return blockEntry{}, ErrInvalidBlock
+	}
+	addr = addr.Unmap()
+	return blockEntry{
+		BlockEntry: m.BlockEntry{Kind: m.BlockIP, Value: addr.String()},
+		prefix:     netip.PrefixFrom(addr, addr.BitLen()),
+	}, nil
+}
+
+// * Llamar con mutex tomado
+func (b *Blocklist) prune(now time.Time) {
+	kept := b.entries[:0]
+	for _, entry := range b.entries {
+		if entry.ExpiresAt == nil || now.Before(*entry.ExpiresAt) {
+			kept = append(kept, entry)
+		}
+	}
+	b.entries = kept
+}
+
+func (b *Blocklist) persist() {
+	if err := b.save(); err != nil {
+		log.Printf("⚠️ Error guardando lista de bloqueo: %v", err)
+	}
+}
+
+func (b *Blocklist) save() error {
+	if b.path == "" {
+		return nil
+	}
+
+	b.mutex.Lock()
+	b.prune(time.Now())
+	entries := make([]m.BlockEntry, 0, len(b.entries))
+	for _, entry := range b.entries {
+		entries = append(entries, entry.BlockEntry)
+	}
+	b.mutex.Unlock()
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(b.path), 0o755); err != nil {
+		return fmt.Errorf("error creando directorio de la lista de bloqueo: %w", err)
+	}
+
+	// ! Escribir a un temporal y renombrar para no dejar el archivo a medias
+	tmp := b.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, b.path)
+}
+
+func (b *Blocklist) load() error {
+	if b.path == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(b.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var saved []m.BlockEntry
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return fmt.Errorf("lista de bloqueo corrupta: %w", err)
+	}
+
+	now := time.Now()
+	for _, stored := range saved {
+		if stored.ExpiresAt != nil && now.After(*stored.ExpiresAt) {
+			continue
+		}
+		entry, err := b.parse(stored.Value)
+		if err != nil {
+			// * Por ejemplo un ASN guardado cuando había índice y ahora no lo hay
+			log.Printf("⚠️ Bloqueo %s (%s) ignorado: %v", stored.ID, stored.Value, err)
+			continue
+		}
+		entry.BlockEntry = stored
+		b.entries = append(b.entries, entry)
+	}
+	return nil
+}
+
+func newBlockID() string {
+	id := make([]byte, 6)
+	rand.Read(id)
+	return hex.EncodeToString(id)
+}
@@ -1,48 +1,91 @@
 package services
 
 import (
-	"crypto/rand"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"hash/fnv"
 	"log"
-	"math/big"
 	"net/http"
 	"os"
+	"slices"
+	"sort"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+	"github.com/ChrisTheAbysswalker/meownder-backend/retry"
+	"github.com/ChrisTheAbysswalker/meownder-backend/sentry"
 )
 
 type CatService struct {
-	recentURLs map[string]bool
-	cacheMutex sync.RWMutex
 	batchCount int
 	countMutex sync.Mutex
-	catProfiles []m.CatProfile 
-	profilesMutex sync.RWMutex
+	profiles    atomic.Pointer[profileSnapshot]
+	writeMutex  sync.Mutex
+	reloadListeners []func()
+	transitionListeners []func(m.ProfileTransition)
+	listenersMutex  sync.Mutex
+	validatedURLs   []m.CatURL
+	poolMutex       sync.Mutex
+	reservoir       *URLReservoir
+	providerFailures atomic.Int32
+	retry           *retry.Policy
+	providers       *ProviderMix
+	profilesPath    string
+	pruneStats      m.PruneStats
+	pruneMutex      sync.Mutex
+	imageMeta       *ImageMetaIndex
+	stableImages    *StableImages
+	stableTenant    string
+	stats           *StatsCollector
+	reporter        *sentry.Client
+	createdAt       time.Time
+	// * UnixNano del último HEAD contra el proveedor que respondió 200
+	lastProbeOK     atomic.Int64
+	// * nil si la búsqueda de texto no está configurada (ver SetSearch)
+	search          *ProfileSearch
 }
 
-func NewCatService() *CatService {
+// * Tras estos fallos de validación seguidos se considera que el proveedor está caído
+const providerDownThreshold = 5
+
+var (
+	errDuplicateURL       = errors.New("URL duplicada")
+	errProvidersExhausted = errors.New("ningún proveedor devolvió imágenes")
+)
+
+func NewCatService(profilesPath string, reservoir *URLReservoir, retryPolicy *retry.Policy, providers *ProviderMix, imageMeta *ImageMetaIndex, stats *StatsCollector, reporter *sentry.Client) *CatService {
 	service := &CatService{
-		recentURLs: make(map[string]bool),
+		profilesPath: profilesPath,
+		imageMeta:    imageMeta,
+		stats:        stats,
+		reporter:     reporter,
+		createdAt:    time.Now(),
 		batchCount: 0,
+		reservoir:  reservoir,
+		retry:      retryPolicy,
+		providers:  providers,
 	}
 	
 	// * Cargar perfiles de gatos al iniciar
 	if err := service.loadCatProfiles(); err != nil {
 		log.Printf("⚠️ Error cargando perfiles de gatos: %v", err)
+		reporter.CaptureError(err, map[string]string{"profiles": profilesPath})
 	} else {
-		log.Printf("✅ Perfiles de gatos cargados: %d", len(service.catProfiles))
+		log.Printf("✅ Perfiles de gatos cargados: %d", len(service.snapshot().profiles))
 	}
 	
 	return service
 }
 
 func (s *CatService) loadCatProfiles() error {
-	data, err := os.ReadFile("cats.json")
+	data, err := os.ReadFile(s.profilesPath)
 	if err != nil {
-		return fmt.Errorf("error leyendo cats.json: %w", err)
+		return fmt.Errorf("error leyendo %s: %w", s.profilesPath, err)
 	}
 
 	var catsData struct {
@@ -54,150 +97,442 @@ func (s *CatService) loadCatProfiles() error {
 	}
 
 	// * Llenar imágenes desde Cat as a Service
+	now := time.Now()
 	for i := range catsData.Cats {
+		if catsData.Cats[i].Status == "" {
+			catsData.Cats[i].Status = m.StatusActive
+		}
+		if url, ok := s.stableImages.URL(s.stableTenant, catsData.Cats[i].ID); ok {
+			catsData.Cats[i].Img = url
+			continue
+		}
 		catURL := s.generateCatURL()
 		catsData.Cats[i].Img = catURL.URL
+		catsData.Cats[i].UpdatedAt = now
 		log.Printf("🖼️ Imagen asignada a %s: %s", catsData.Cats[i].Name, catURL.URL)
 	}
 
-	s.profilesMutex.Lock()
-	s.catProfiles = catsData.Cats
-	s.profilesMutex.Unlock()
+	s.writeMutex.Lock()
+	s.storeProfiles(catsData.Cats)
+	s.writeMutex.Unlock()
 
+	s.notifyReload()
 	return nil
 }
 
+// * Modo de imágenes fijas: los perfiles ya cargados toman la imagen guardada (o fijan la
+// * que tienen) y desde acá cada cambio de imagen queda guardado
+func (s *CatService) SetStableImages(stable *StableImages, tenant string) {
+	s.writeMutex.Lock()
+	s.stableImages = stable
+	s.stableTenant = tenant
+	s.writeMutex.Unlock()
+
+	s.updateProfiles(func(profiles []m.CatProfile) {
+		for i := range profiles {
+			if url, ok := stable.URL(tenant, profiles[i].ID); ok {
+				profiles[i].Img = url
+			}
+		}
+	})
+	s.notifyReload()
+}
+
+// * Permite a otros servicios (feed, caches) reaccionar cuando cambian los perfiles
+func (s *CatService) OnReload(listener func()) {
+	s.listenersMutex.Lock()
+	defer s.listenersMutex.Unlock()
+	s.reloadListeners = append(s.reloadListeners, listener)
+}
+
+// ! Nunca llamar con writeMutex tomado: los listeners pueden disparar escrituras
+func (s *CatService) notifyReload() {
+	s.listenersMutex.Lock()
+	listeners := append([]func(){}, s.reloadListeners...)
+	s.listenersMutex.Unlock()
+
+	for _, listener := range listeners {
+		listener()
+	}
+}
+
 func (s *CatService) GetCatProfiles() []m.CatProfile {
-	s.profilesMutex.RLock()
-	defer s.profilesMutex.RUnlock()
-	return s.catProfiles
+	return s.withImageMeta(s.snapshot().cloneActive())
 }
 
-func (s *CatService) GetCatProfileByID(id int) (*m.CatProfile, error) {
-	s.profilesMutex.RLock()
-	defer s.profilesMutex.RUnlock()
+// * Adjunta lo que se sepa de la imagen actual de cada perfil (ver ImageMetaIndex)
+func (s *CatService) withImageMeta(profiles []m.CatProfile) []m.CatProfile {
+	for i := range profiles {
+		if meta, ok := s.imageMeta.Get(profiles[i].Img); ok {
+			profiles[i].ImageMeta = &meta
+		}
+		if palette, ok := s.imageMeta.Palette(profiles[i].Img); ok {
+			profiles[i].Palette = palette
+		}
+	}
+	return profiles
+}
+
+func (s *CatService) ImageMeta(url string) (m.ImageMeta, bool) {
+	return s.imageMeta.Get(url)
+}
+
+func (s *CatService) FilterCatProfiles(filter m.ProfileFilter) ([]m.CatProfile, int) {
+	snap := s.snapshot()
 
-	for _, cat := range s.catProfiles {
-		if cat.ID == id {
-			return &cat, nil
+	matched := make([]m.CatProfile, 0, len(snap.profiles))
+	if positions := snap.index.candidates(filter); positions != nil {
+		for _, i := range positions {
+			if cat := snap.profiles[i]; matchesFilter(cat, filter) {
+				matched = append(matched, cat.Clone())
+			}
+		}
+	} else {
+		for _, cat := range snap.profiles {
+			if matchesFilter(cat, filter) {
+				matched = append(matched, cat.Clone())
+			}
 		}
 	}
 
-	return nil, fmt.Errorf("gato con ID %d no encontrado", id)
+	total := len(matched)
+	if filter.Offset >= total {
+		return []m.CatProfile{}, total
+	}
+	matched = matched[filter.Offset:]
+	if filter.Limit > 0 && filter.Limit < len(matched) {
+		matched = matched[:filter.Limit]
+	}
+
+	return s.withImageMeta(matched), total
 }
 
-func (s *CatService) RefreshCatImages() error {
-	s.profilesMutex.Lock()
-	defer s.profilesMutex.Unlock()
+// * Como FilterCatProfiles pero sin copiar perfiles: Offset y Limit se ignoran
+func (s *CatService) CountCatProfiles(filter m.ProfileFilter) int {
+	snap := s.snapshot()
 
-	for i := range s.catProfiles {
-		catURL := s.generateCatURL()
-		s.catProfiles[i].Img = catURL.URL
+	count := 0
+	if positions := snap.index.candidates(filter); positions != nil {
+		for _, i := range positions {
+			if matchesFilter(snap.profiles[i], filter) {
+				count++
+			}
+		}
+		return count
+	}
+	for _, cat := range snap.profiles {
+		if matchesFilter(cat, filter) {
+			count++
+		}
+	}
+	return count
+}
+
+func matchesFilter(cat m.CatProfile, filter m.ProfileFilter) bool {
+	if cat.DeletedAt != nil && !filter.IncludeDeleted {
+		return false
+	}
+	if filter.Status != "" && cat.Status != filter.Status {
+		return false
+	}
+	if filter.Breed != "" && !strings.EqualFold(cat.Breed, filter.Breed) {
+		return false
+	}
+	if filter.MinAge > 0 && cat.Age < filter.MinAge {
+		return false
+	}
+	if filter.MaxAge > 0 && cat.Age > filter.MaxAge {
+		return false
+	}
+	if filter.Hobby != "" {
+		found := false
+		for _, hobby := range cat.Hobbies {
+			if strings.EqualFold(strings.TrimSpace(hobby), strings.TrimSpace(filter.Hobby)) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return hasTags(cat, filter.Tags)
+}
+
+func (s *CatService) GetCatProfileByID(id int) (*m.CatProfile, error) {
+	snap := s.snapshot()
+
+	i, ok := snap.index.byID[id]
+	// * Pausados y adoptados siguen accesibles por enlace directo; los borradores y pendientes no
+	if !ok || snap.profiles[i].DeletedAt != nil || snap.profiles[i].Status == m.StatusDraft || snap.profiles[i].Status == m.StatusPending {
+		return nil, profileNotFound(id)
+	}
+
+	cat := s.withImageMeta([]m.CatProfile{snap.profiles[i].Clone()})[0]
+	return &cat, nil
+}
+
+// * Determinista por fecha: todas las instancias eligen el mismo gato el mismo día
+func (s *CatService) CatOfTheDay(date time.Time) (*m.CatProfile, error) {
+	snap := s.snapshot()
+
+	if len(snap.active) == 0 {
+		return nil, fmt.Errorf("no hay perfiles cargados")
 	}
 
+	hash := fnv.New32a()
+	hash.Write([]byte(date.Format("2006-01-02")))
+	cat := s.withImageMeta([]m.CatProfile{snap.profiles[snap.active[int(hash.Sum32()%uint32(len(snap.active)))]].Clone()})[0]
+
+	return &cat, nil
+}
+
+// * Copy-on-write: se arma un slice nuevo fuera de cualquier lock de lectura y se
+// * publica con un swap atómico, así los lectores nunca esperan
+func (s *CatService) RefreshCatImages() error {
+	s.updateProfiles(func(profiles []m.CatProfile) {
+		now := time.Now()
+		for i := range profiles {
+			profiles[i].Img = s.generateCatURL().URL
+			profiles[i].UpdatedAt = now
+		}
+	})
+
 	log.Println("🔄 Imágenes de perfiles actualizadas")
+	s.notifyReload()
 	return nil
 }
 
-func (s *CatService) GenerateCatURLs(count int) ([]string, int, error) {
+// * Sin deadline en ctx espera a todas las imágenes; con deadline entrega lo que llegó a
+// * tiempo y las rezagadas se descartan aunque respondan después
+func (s *CatService) GenerateCatURLs(ctx context.Context, count int, opts m.ImageOptions) (*m.CatBatch, error) {
 	s.countMutex.Lock()
 	s.batchCount++
 	currentBatch := s.batchCount
 	s.countMutex.Unlock()
 
+	if s.ProviderDown() {
+		if batch, err := s.staleBatch(count, currentBatch); err == nil {
+			log.Printf("⚠️ Proveedor caído, sirviendo lote %d desde la reserva", currentBatch)
+			return batch, nil
+		}
+	}
+
 	log.Printf("🐱 Generando lote %d con %d imágenes", currentBatch, count)
 
 	urls := make([]string, 0, count)
+	var failures []m.BatchError
 	var wg sync.WaitGroup
 	var urlMutex sync.Mutex
+	// * Posiciones que ya terminaron (con imagen o con error) y si el plazo ya cerró el lote
+	settled := make([]bool, count)
+	closed := false
 
-	for i := 0; i < count; i++ {
+	mix := s.providers.compose(count, opts)
+	for i, provider := range mix {
 		wg.Add(1)
-		go func(index int) {
+		go func(index int, provider *weightedProvider) {
 			defer wg.Done()
 
-			maxRetries := 3
-			for retry := 0; retry < maxRetries; retry++ {
-				catURL := s.generateCatURL()
-
-				s.cacheMutex.RLock()
-				isDuplicate := s.recentURLs[catURL.URL]
-				s.cacheMutex.RUnlock()
-
-				if !isDuplicate {
-					s.cacheMutex.Lock()
-					s.recentURLs[catURL.URL] = true
-					s.cacheMutex.Unlock()
+			current := provider
+			err := s.retry.Do(ctx, func(attempt int) error {
+				catURL, err := current.NewURL(ctx, opts)
+				current.reportCall(err)
+				if err != nil {
+					s.stats.ProviderError(current.Name())
+					// * El reintento va al proveedor más sano que quede
+					current = s.providers.fallback(current, opts)
+					return err
+				}
 
-					urlMutex.Lock()
-					urls = append(urls, catURL.URL)
-					urlMutex.Unlock()
-					break
-				} else {
+				if !current.markSeen(catURL.URL) {
+					s.stats.DedupHit()
 					log.Printf("🔄 URL duplicada detectada, generando nueva...")
+					return errDuplicateURL
 				}
 
-				time.Sleep(100 * time.Millisecond)
+				urlMutex.Lock()
+				if !closed {
+					urls = append(urls, catURL.URL)
+					settled[index] = true
+				}
+				urlMutex.Unlock()
+				return nil
+			})
+			if err != nil {
+				urlMutex.Lock()
+				if !closed {
+					log.Printf("⚠️ Imagen %d (%s) descartada: %v", index, provider.Name(), err)
+					failures = append(failures, m.BatchError{Index: index, Provider: current.Name(), Error: err.Error()})
+					settled[index] = true
+				}
+				urlMutex.Unlock()
 			}
-		}(i)
+		}(i, provider)
 	}
 
-	wg.Wait()
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	truncated := false
+	select {
+	case <-done:
+	case <-ctx.Done():
+		urlMutex.Lock()
+		closed = true
+		for index, ok := range settled {
+			if !ok {
+				truncated = true
+				failures = append(failures, m.BatchError{Index: index, Provider: mix[index].Name(), Error: "sin respuesta antes de max_wait_ms"})
+			}
+		}
+		urlMutex.Unlock()
+	}
 
 	if currentBatch%10 == 0 {
 		go s.cleanCache()
 	}
 
+	urlMutex.Lock()
+	urls = slices.Clone(urls)
+	urlMutex.Unlock()
+
 	if len(urls) == 0 {
-		return nil, 0, fmt.Errorf("no se pudieron obtener imágenes de gatos")
+		// * Todos los proveedores agotaron sus reintentos en el mismo lote
+		if !truncated {
+			s.reporter.CaptureError(errProvidersExhausted, map[string]string{"path": "batch"})
+		}
+		batch, err := s.staleBatch(count, currentBatch)
+		if batch != nil {
+			batch.Truncated = truncated
+		}
+		return batch, err
 	}
 
-	log.Printf("✅ Lote %d completado: %d imágenes enviadas", currentBatch, len(urls))
-	return urls, currentBatch, nil
+	s.stats.BatchServed(len(urls))
+	if truncated {
+		log.Printf("⏱️ Lote %d truncado por max_wait_ms: %d de %d imágenes enviadas", currentBatch, len(urls), count)
+	} else {
+		log.Printf("✅ Lote %d completado: %d de %d imágenes enviadas", currentBatch, len(urls), count)
+	}
+	sort.Slice(failures, func(i, j int) bool { return failures[i].Index < failures[j].Index })
+	return &m.CatBatch{URLs: urls, Batch: currentBatch, Requested: count, Errors: failures, Truncated: truncated}, nil
 }
 
-func (s *CatService) generateCatURL() m.CatURL {
-	timestamp := time.Now().UnixNano()
-	baseURL := "https://cataas.com/cat"
+func (s *CatService) staleBatch(count, batch int) (*m.CatBatch, error) {
+	var urls []string
+	if s.reservoir != nil {
+		urls = s.reservoir.Sample(count)
+	}
+	if len(urls) == 0 {
+		return nil, fmt.Errorf("no se pudieron obtener imágenes de gatos")
+	}
+	s.stats.BatchServed(len(urls))
+	return &m.CatBatch{
+		URLs:      urls,
+		Batch:     batch,
+		Stale:     true,
+		Requested: count,
+		Errors:    missingErrors(len(urls), count, "la reserva no tenía más imágenes"),
+	}, nil
+}
 
-	randNum, err := rand.Int(rand.Reader, big.NewInt(1000000))
-	if err != nil {
-		log.Printf("Error generando número aleatorio: %v", err)
-		randNum = big.NewInt(0)
+// * Un error por cada posición entre delivered y requested, todas por la misma causa
+func missingErrors(delivered, requested int, reason string) []m.BatchError {
+	var errs []m.BatchError
+	for i := delivered; i < requested; i++ {
+		errs = append(errs, m.BatchError{Index: i, Error: reason})
 	}
+	return errs
+}
 
-	url := fmt.Sprintf("%s?timestamp=%d&rand=%s", baseURL, timestamp, randNum.String())
-	id := fmt.Sprintf("cat-%d-%s", timestamp, randNum.String())
+func (s *CatService) RetryStats() retry.Stats {
+	return s.retry.Stats()
+}
+
+func (s *CatService) ProviderDown() bool {
+	return s.providerFailures.Load() >= providerDownThreshold
+}
 
-	return m.CatURL{
-		URL:       url,
-		ID:        id,
-		Timestamp: timestamp,
+// * Circuitos abiertos: los proveedores del mix y la validación HEAD de cataas, que es
+// * la que hace pasar al servicio a servir desde la reserva
+func (s *CatService) OpenCircuits() []string {
+	var open []string
+	if s.providers != nil {
+		open = s.providers.OpenCircuits()
+	}
+	if s.ProviderDown() {
+		open = append(open, "cataas-validation")
 	}
+	return open
 }
 
-// ! valida que la imagen sea accesible (no implementado por ahora)
-func (s *CatService) validateCatURL(catURL m.CatURL, timeout time.Duration) bool {
-	client := &http.Client{
-		Timeout: timeout,
+func (s *CatService) generateCatURL() m.CatURL {
+	return s.generateCatURLWith(m.ImageOptions{})
+}
+
+// * Las imágenes de perfiles y del pool validado salen de cataas: es el único proveedor
+// * remoto que arma la URL sin pedir nada por red. Con el proveedor offline (dev) salen de él
+func (s *CatService) generateCatURLWith(opts m.ImageOptions) m.CatURL {
+	if s.providers != nil && s.providers.offline != nil {
+		catURL, _ := s.providers.offline.NewURL(context.Background(), opts)
+		return catURL
 	}
+	return newCataasURL(opts)
+}
+
+func (s *CatService) ProviderStats() []m.ProviderStats {
+	return s.providers.Stats()
+}
+
+func (s *CatService) OutboundStats() []m.OutboundStats {
+	return s.providers.Transport().OutboundStats()
+}
+
+func (s *CatService) DNSStats() *m.DNSCacheStats {
+	return s.providers.Transport().DNSStats()
+}
+
+// * valida que la imagen sea accesible con un HEAD
+func (s *CatService) validateCatURL(catURL m.CatURL, timeout time.Duration) bool {
+	client := s.providers.Transport().Client(timeout)
 
 	resp, err := client.Head(catURL.URL)
+	if errors.Is(err, ErrOutboundBudget) {
+		return false
+	}
 	if err != nil {
+		s.providerFailures.Add(1)
+		s.stats.ProviderError(s.providers.report(catURL.URL, false))
 		return false
 	}
 	defer resp.Body.Close()
 
-	return resp.StatusCode == http.StatusOK
-}
-func (s *CatService) cleanCache() {
-	s.cacheMutex.Lock()
-	defer s.cacheMutex.Unlock()
+	if resp.StatusCode != http.StatusOK {
+		s.providerFailures.Add(1)
+		s.stats.ProviderError(s.providers.report(catURL.URL, false))
+		return false
+	}
 
-	if len(s.recentURLs) > 50 {
-		s.recentURLs = make(map[string]bool)
-		log.Println("🧹 Cache limpiado")
+	s.providerFailures.Store(0)
+	s.lastProbeOK.Store(time.Now().UnixNano())
+	s.providers.report(catURL.URL, true)
+	// * El HEAD no trae dimensiones, pero sí tipo y tamaño
+	s.imageMeta.Record(catURL.URL, m.ImageMeta{
+		ContentType: resp.Header.Get("Content-Type"),
+		Format:      formatFromContentType(resp.Header.Get("Content-Type")),
+		Bytes:       max(resp.ContentLength, 0),
+	})
+	if s.reservoir != nil {
+		s.reservoir.Add(catURL.URL)
 	}
+	return true
+}
+func (s *CatService) cleanCache() {
+	s.providers.cleanRecent()
 }
 
 func (s *CatService) GetBatchCount() int {
@@ -0,0 +1,184 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+)
+
+var ErrChaosInjected = errors.New("falla inyectada por el modo caos")
+
+// * Inyección de fallas para probar fallbacks y circuitos (solo con CHAOS_ENABLED).
+// * Arranca apagado: hasta el primer PUT /admin/chaos no cambia nada
+type Chaos struct {
+	settings m.ChaosSettings
+	injected map[string]int64
+	mutex    sync.RWMutex
+}
+
+func NewChaos() *Chaos {
+	return &Chaos{injected: make(map[string]int64)}
+}
+
+func (c *Chaos) Update(settings m.ChaosSettings) {
+	c.mutex.Lock()
+	c.settings = settings
+	c.mutex.Unlock()
+	log.Printf("🐒 Modo caos actualizado: enabled=%t http=%+v upstream=%+v", settings.Enabled, settings.HTTP, settings.Upstream)
+}
+
+func (c *Chaos) Reset() {
+	c.mutex.Lock()
+	c.settings = m.ChaosSettings{}
+	c.injected = make(map[string]int64)
+	c.mutex.Unlock()
+	log.Println("🐒 Modo caos apagado")
+}
+
+func (c *Chaos) Stats() m.ChaosStats {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	injected := make(map[string]int64, len(c.injected))
+	for kind, count := range c.injected {
+		injected[kind] = count
+	}
+	return m.ChaosStats{Settings: c.settings, Injected: injected}
+}
+
+func (c *Chaos) count(kind string) {
+	c.mutex.Lock()
+	c.injected[kind]++
+	c.mutex.Unlock()
+}
+
+func (c *Chaos) current() m.ChaosSettings {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.settings
+}
+
+// * Para el middleware: cuánto demorar la petición y con qué status cortarla (0 = seguir)
+func (c *Chaos) HTTPFault() (time.Duration, int) {
+	settings := c.current()
+	if !settings.Enabled {
+		return 0, 0
+	}
+
+	delay := c.delay(settings.HTTP, "http_latency")
+	if hit(settings.HTTP.ErrorRate) {
+		c.count("http_error")
+		return delay, http.StatusServiceUnavailable
+	}
+	return delay, 0
+}
+
+func (c *Chaos) delay(faults m.ChaosFaults, kind string) time.Duration {
+	if faults.LatencyMs <= 0 || !hit(faults.LatencyRate) {
+		return 0
+	}
+	c.count(kind)
+	return time.Duration(faults.LatencyMs) * time.Millisecond
+}
+
+// * Latencia y error para una llamada al proveedor; malformed solo aplica a respuestas HTTP
+func (c *Chaos) upstream(ctx context.Context, provider string) (bool, error) {
+	settings := c.current()
+	faults := settings.Upstream
+	if !settings.Enabled || (len(faults.Providers) > 0 && !slices.Contains(faults.Providers, provider)) {
+		return false, nil
+	}
+
+	if delay := c.delay(faults.ChaosFaults, "upstream_latency"); delay > 0 {
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return false, ctx.Err()
+		case <-timer.C:
+		}
+	}
+	if hit(faults.ErrorRate) {
+		c.count("upstream_error")
+		return false, ErrChaosInjected
+	}
+	if hit(faults.MalformedRate) {
+		c.count("upstream_malformed")
+		return true, nil
+	}
+	return false, nil
+}
+
+func hit(rate float64) bool {
+	return rate > 0 && rand.Float64() < rate
+}
+
+// * Mismo status y Content-Type, cuerpo inservible: JSON cortado o bytes que no decodifican
+func malformResponse(resp *http.Response) *http.Response {
+	resp.Body.Close()
+
+	body := []byte(`{"id":"chaos","url":`)
+	if !strings.Contains(resp.Header.Get("Content-Type"), "json") {
+		body = make([]byte, 512)
+		for i := range body {
+			body[i] = byte(rand.IntN(256))
+		}
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	resp.ContentLength = int64(len(body))
+	resp.Header.Set("Content-Length", strconv.Itoa(len(body)))
+	return resp
+}
+
+// * Envuelve los proveedores que arman la URL sin red (cataas): los remotos ya pasan
+// * por el transporte y recibirían la falla dos veces
+type chaosProvider struct {
+	ImageProvider
+	chaos *Chaos
+}
+
+func (p chaosProvider) NewURL(ctx context.Context, opts m.ImageOptions) (m.CatURL, error) {
+	if !p.Remote() {
+		if _, err := p.chaos.upstream(ctx, p.Name()); err != nil {
+			return m.CatURL{}, err
+		}
+	}
+	return p.ImageProvider.NewURL(ctx, opts)
+}
+
+// ! Llamar antes de servir: reemplaza los proveedores del mix sin lock
+func (mix *ProviderMix) SetChaos(chaos *Chaos) {
+	if mix == nil || chaos == nil {
+		return
+	}
+	for _, p := range mix.providers {
+		p.ImageProvider = chaosProvider{ImageProvider: p.ImageProvider, chaos: chaos}
+	}
+}
+
+func (t *ProviderTransport) SetChaos(chaos *Chaos) {
+	if t == nil || chaos == nil {
+		return
+	}
+	t.chaos = chaos
+}
+
+func providerForHost(host string) string {
+	for provider, hosts := range providerHosts {
+		if slices.Contains(hosts, host) {
+			return provider
+		}
+	}
+	return ""
+}
@@ -0,0 +1,348 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+)
+
+var (
+	ErrChatUnavailable = errors.New("el chat solo está disponible en matches mutuos")
+	ErrMessageNotFound = errors.New("mensaje no encontrado")
+	ErrInvalidEmoji    = errors.New("la reacción debe ser un emoji")
+	ErrReadBackwards   = errors.New("last_read_message_id es anterior al último leído")
+)
+
+// * Buffer por suscriptor; si un cliente no lee a tiempo se pierden eventos, no se bloquea el chat
+const chatEventBuffer = 32
+
+type conversation struct {
+	info     m.Conversation
+	messages []m.Message
+	byID     map[string]int
+	readBy   map[string]string
+}
+
+// * Chat en memoria entre los dueños de un match mutuo, con eventos en tiempo real
+type ChatService struct {
+	swipes        *SwipeService
+	conversations map[string]*conversation
+	// * Mensaje -> conversación, para las rutas que solo reciben el ID del mensaje
+	messageIndex map[string]string
+	messageCount int
+	subscribers  map[string]map[chan m.ChatEvent]struct{}
+	lastSeen     map[string]time.Time
+	listeners    []func(m.Message)
+	mutex        sync.RWMutex
+}
+
+func NewChatService(swipes *SwipeService) *ChatService {
+	return &ChatService{
+		swipes:        swipes,
+		conversations: make(map[string]*conversation),
+		messageIndex:  make(map[string]string),
+		subscribers:   make(map[string]map[chan m.ChatEvent]struct{}),
+		lastSeen:      make(map[string]time.Time),
+	}
+}
+
+func (s *ChatService) Send(userID, matchID, text string) (m.Message, error) {
+	info, err := s.swipes.Conversation(userID, matchID)
+	if err != nil {
+		return m.Message{}, err
+	}
+
+	s.mutex.Lock()
+	conv := s.conversation(info)
+	s.messageCount++
+	message := m.Message{
+		ID:             fmt.Sprintf("msg-%d", s.messageCount),
+		ConversationID: info.ID,
+		SenderID:       userID,
+		Text:           strings.TrimSpace(text),
+		CreatedAt:      time.Now(),
+	}
+	conv.byID[message.ID] = len(conv.messages)
+	conv.messages = append(conv.messages, message)
+	s.messageIndex[message.ID] = info.ID
+	// * Quien escribe ya leyó todo hasta su propio mensaje
+	conv.readBy[userID] = message.ID
+	s.mutex.Unlock()
+
+	s.publish(info.Participants, m.ChatEvent{
+		Type:           m.ChatEventMessage,
+		ConversationID: info.ID,
+		Message:        &message,
+		At:             message.CreatedAt,
+	})
+
+	s.mutex.RLock()
+	listeners := append([]func(m.Message){}, s.listeners...)
+	s.mutex.RUnlock()
+	for _, listener := range listeners {
+		listener(message)
+	}
+	return message, nil
+}
+
+// * Se llama después de cada mensaje enviado, fuera del lock
+func (s *ChatService) OnMessage(listener func(message m.Message)) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.listeners = append(s.listeners, listener)
+}
+
+// * Mensajes enviados por userID desde since, en todas sus conversaciones
+func (s *ChatService) SentSince(userID string, since time.Time) int {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	sent := 0
+	for _, conv := range s.conversations {
+		for _, message := range conv.messages {
+			if message.SenderID == userID && !message.CreatedAt.Before(since) {
+				sent++
+			}
+		}
+	}
+	return sent
+}
+
+func (s *ChatService) Messages(userID, matchID string) (m.MessagesResponse, error) {
+	info, err := s.swipes.Conversation(userID, matchID)
+	if err != nil {
+		return m.MessagesResponse{}, err
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	response := m.MessagesResponse{
+		Conversation: info,
+		Messages:     []m.Message{},
+		ReadBy:       map[string]string{},
+	}
+	if conv, ok := s.conversations[info.ID]; ok {
+		for _, message := range conv.messages {
+			response.Messages = append(response.Messages, cloneMessage(message))
+		}
+		for user, messageID := range conv.readBy {
+			response.ReadBy[user] = messageID
+		}
+	}
+	response.Count = len(response.Messages)
+	return response, nil
+}
+
+// * Idempotente: reaccionar dos veces con el mismo emoji no duplica
+func (s *ChatService) React(userID, messageID, emoji string, remove bool) (m.Message, error) {
+	emoji = strings.TrimSpace(emoji)
+	if !validEmoji(emoji) {
+		return m.Message{}, ErrInvalidEmoji
+	}
+
+	s.mutex.Lock()
+	conv, i, err := s.findMessage(userID, messageID)
+	if err != nil {
+		s.mutex.Unlock()
+		return m.Message{}, err
+	}
+
+	message := &conv.messages[i]
+	users := message.Reactions[emoji]
+	at := -1
+	for j, user := range users {
+		if user == userID {
+			at = j
+			break
+		}
+	}
+	changed := false
+	switch {
+	case remove && at >= 0:
+		users = append(users[:at:at], users[at+1:]...)
+		changed = true
+	case !remove && at < 0:
+		users = append(users, userID)
+		changed = true
+	}
+	if changed {
+		if message.Reactions == nil {
+			message.Reactions = make(map[string][]string)
+		}
+		if len(users) == 0 {
+			delete(message.Reactions, emoji)
+		} else {
+			message.Reactions[emoji] = users
+		}
+	}
+	result := cloneMessage(*message)
+	participants := conv.info.Participants
+	s.mutex.Unlock()
+
+	if changed {
+		s.publish(participants, m.ChatEvent{
+			Type:           m.ChatEventReaction,
+			ConversationID: conv.info.ID,
+			MessageID:      messageID,
+			UserID:         userID,
+			Emoji:          emoji,
+			Removed:        remove,
+			At:             time.Now(),
+		})
+	}
+	return result, nil
+}
+
+// * Los recibos de lectura solo avanzan; repetir el mismo ID no emite otro evento
+func (s *ChatService) MarkRead(userID, matchID, messageID string) (m.MessagesResponse, error) {
+	info, err := s.swipes.Conversation(userID, matchID)
+	if err != nil {
+		return m.MessagesResponse{}, err
+	}
+
+	s.mutex.Lock()
+	conv := s.conversation(info)
+	i, ok := conv.byID[messageID]
+	if !ok {
+		s.mutex.Unlock()
+		return m.MessagesResponse{}, fmt.Errorf("%w: %s", ErrMessageNotFound, messageID)
+	}
+	changed := true
+	if previous, ok := conv.byID[conv.readBy[userID]]; ok {
+		if previous > i {
+			s.mutex.Unlock()
+			return m.MessagesResponse{}, ErrReadBackwards
+		}
+		changed = previous != i
+	}
+	conv.readBy[userID] = messageID
+	s.mutex.Unlock()
+
+	if changed {
+		s.publish(info.Participants, m.ChatEvent{
+			Type:              m.ChatEventRead,
+			ConversationID:    info.ID,
+			UserID:            userID,
+			LastReadMessageID: messageID,
+			At:                time.Now(),
+		})
+	}
+	return s.Messages(userID, matchID)
+}
+
+// * El canal se cierra al cancelar; hay que cancelar siempre para no filtrar suscriptores
+func (s *ChatService) Subscribe(userID string) (<-chan m.ChatEvent, func()) {
+	events := make(chan m.ChatEvent, chatEventBuffer)
+
+	s.mutex.Lock()
+	if s.subscribers[userID] == nil {
+		s.subscribers[userID] = make(map[chan m.ChatEvent]struct{})
+	}
+	s.subscribers[userID][events] = struct{}{}
+	// * Con varias pestañas abiertas solo la primera conexión pasa a "en línea"
+	firstConnection := len(s.subscribers[userID]) == 1
+	s.mutex.Unlock()
+
+	if firstConnection {
+		s.announcePresence(userID)
+	}
+
+	var once sync.Once
+	return events, func() {
+		once.Do(func() {
+			s.mutex.Lock()
+			delete(s.subscribers[userID], events)
+			lastConnection := len(s.subscribers[userID]) == 0
+			if lastConnection {
+				delete(s.subscribers, userID)
+				s.lastSeen[userID] = time.Now()
+			}
+			s.mutex.Unlock()
+			close(events)
+
+			if lastConnection {
+				s.announcePresence(userID)
+			}
+		})
+	}
+}
+
+// * Evento suelto para un usuario (insignias, avisos) fuera de cualquier conversación
+func (s *ChatService) Notify(userID string, event m.ChatEvent) {
+	s.publish([]string{userID}, event)
+}
+
+func (s *ChatService) publish(participants []string, event m.ChatEvent) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	for _, userID := range participants {
+		for events := range s.subscribers[userID] {
+			select {
+			case events <- event:
+			default:
+				log.Printf("⚠️ Evento de chat descartado para %s: suscriptor lento", userID)
+			}
+		}
+	}
+}
+
+// ! Requiere s.mutex tomado
+func (s *ChatService) conversation(info m.Conversation) *conversation {
+	conv, ok := s.conversations[info.ID]
+	if !ok {
+		conv = &conversation{
+			info:   info,
+			byID:   make(map[string]int),
+			readBy: make(map[string]string),
+		}
+		s.conversations[info.ID] = conv
+	}
+	return conv
+}
+
+// ! Requiere s.mutex tomado
+func (s *ChatService) findMessage(userID, messageID string) (*conversation, int, error) {
+	conv, ok := s.conversations[s.messageIndex[messageID]]
+	if !ok {
+		return nil, 0, fmt.Errorf("%w: %s", ErrMessageNotFound, messageID)
+	}
+	// * A quien no participa se le responde igual que si no existiera
+	for _, participant := range conv.info.Participants {
+		if participant == userID {
+			return conv, conv.byID[messageID], nil
+		}
+	}
+	return nil, 0, fmt.Errorf("%w: %s", ErrMessageNotFound, messageID)
+}
+
+func cloneMessage(message m.Message) m.Message {
+	if message.Reactions != nil {
+		reactions := make(map[string][]string, len(message.Reactions))
+		for emoji, users := range message.Reactions {
+			reactions[emoji] = append([]string(nil), users...)
+		}
+		message.Reactions = reactions
+	}
+	return message
+}
+
+// * Sin letras ni dígitos ASCII: evita que "reacciones" se usen como mensajes de texto
+func validEmoji(emoji string) bool {
+	if emoji == "" {
+		return false
+	}
+	for _, r := range emoji {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsSpace(r)) {
+			return false
+		}
+	}
+	return true
+}
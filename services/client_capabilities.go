@@ -0,0 +1,86 @@
+package services
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+)
+
+var ErrNoCapabilities = errors.New("el cliente no declaró capacidades en esta sesión")
+
+// * Capacidades de imagen por usuario: se declaran una vez y duran ttl desde la última
+// * declaración, así el mazo y /cats ajustan tamaño y formato sin parámetros por petición
+type ClientCapabilities struct {
+	quality *ImageQualityPolicy
+	ttl     time.Duration
+	// * tenant|usuario -> capacidades
+	entries map[string]m.MediaCapabilities
+	mutex   sync.Mutex
+}
+
+func NewClientCapabilities(quality *ImageQualityPolicy, ttl time.Duration) *ClientCapabilities {
+	return &ClientCapabilities{
+		quality: quality,
+		ttl:     ttl,
+		entries: make(map[string]m.MediaCapabilities),
+	}
+}
+
+func capabilitiesKey(tenantID, userID string) string {
+	return tenantID + "|" + userID
+}
+
+func (c *ClientCapabilities) Declare(tenantID, userID string, req m.CapabilitiesRequest) m.MediaCapabilities {
+	now := time.Now()
+	formats := slices.Compact(slices.Sorted(slices.Values(req.Formats)))
+	caps := m.MediaCapabilities{
+		Formats:    formats,
+		MaxWidth:   req.MaxWidth,
+		Quality:    c.quality.QualityFor(req.MaxWidth),
+		StaticOnly: !slices.Contains(formats, "gif"),
+		ExpiresAt:  now.Add(c.ttl),
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.prune(now)
+	c.entries[capabilitiesKey(tenantID, userID)] = caps
+	return caps
+}
+
+// * Nil-safe: sin registro ningún cliente tiene capacidades declaradas
+func (c *ClientCapabilities) Get(tenantID, userID string) (m.MediaCapabilities, bool) {
+	if c == nil || userID == "" {
+		return m.MediaCapabilities{}, false
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	caps, ok := c.entries[capabilitiesKey(tenantID, userID)]
+	if !ok || time.Now().After(caps.ExpiresAt) {
+		return m.MediaCapabilities{}, false
+	}
+	return caps, true
+}
+
+func (c *ClientCapabilities) Forget(tenantID, userID string) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	key := capabilitiesKey(tenantID, userID)
+	if _, ok := c.entries[key]; !ok {
+		return ErrNoCapabilities
+	}
+	delete(c.entries, key)
+	return nil
+}
+
+// ! Llamar con mutex tomado
+func (c *ClientCapabilities) prune(now time.Time) {
+	for key, caps := range c.entries {
+		if now.After(caps.ExpiresAt) {
+			delete(c.entries, key)
+		}
+	}
+}
@@ -0,0 +1,265 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+)
+
+var (
+	ErrCollectionNotFound = errors.New("colección no encontrada")
+	ErrInvalidSlug        = errors.New("slug inválido: minúsculas, números y guiones")
+	ErrEmptyCollection    = errors.New("la colección necesita profile_ids o tags")
+)
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// * Colecciones por tenant; con path se guardan en disco
+type CollectionService struct {
+	path        string
+	collections []m.Collection
+	listeners   []func()
+	mutex       sync.RWMutex
+}
+
+func NewCollectionService(path string) *CollectionService {
+	c := &CollectionService{path: path}
+
+	if err := c.load(); err != nil {
+		log.Printf("⚠️ Error cargando colecciones: %v", err)
+	} else if len(c.collections) > 0 {
+		log.Printf("📚 Colecciones cargadas: %d", len(c.collections))
+	}
+	return c
+}
+
+// * Para invalidar caches de respuestas al editar colecciones
+func (c *CollectionService) OnChange(listener func()) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.listeners = append(c.listeners, listener)
+}
+
+// * Ordenadas por Position y después por slug
+func (c *CollectionService) List(tenant string) []m.Collection {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	collections := make([]m.Collection, 0)
+	for _, collection := range c.collections {
+		if collection.Tenant == tenant {
+			collections = append(collections, cloneCollection(collection))
+		}
+	}
+	sort.SliceStable(collections, func(i, j int) bool {
+		if collections[i].Position != collections[j].Position {
+			return collections[i].Position < collections[j].Position
+		}
+		return collections[i].Slug < collections[j].Slug
+	})
+	return collections
+}
+
+func (c *CollectionService) Get(tenant, slug string) (m.Collection, error) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	if i := c.index(tenant, slug); i >= 0 {
+		return cloneCollection(c.collections[i]), nil
+	}
+	return m.Collection{}, ErrCollectionNotFound
+}
+
+// * Crea o reemplaza; created indica si no existía
+func (c *CollectionService) Put(tenant, slug string, req m.CollectionRequest) (collection m.Collection, created bool, err error) {
+	if !slugPattern.MatchString(slug) {
+		return m.Collection{}, false, ErrInvalidSlug
+	}
+	tags := NormalizeTags(req.Tags)
+	if len(req.ProfileIDs) == 0 && len(tags) == 0 {
+		return m.Collection{}, false, ErrEmptyCollection
+	}
+
+	now := time.Now()
+	collection = m.Collection{
+		Slug:        slug,
+		Tenant:      tenant,
+		Title:       req.Title,
+		Description: req.Description,
+		Image:       req.Image,
+		ProfileIDs:  uniqueIDs(req.ProfileIDs),
+		Tags:        tags,
+		Position:    req.Position,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	c.mutex.Lock()
+	if i := c.index(tenant, slug); i >= 0 {
+		collection.CreatedAt = c.collections[i].CreatedAt
+		c.collections[i] = collection
+	} else {
+		c.collections = append(c.collections, collection)
+		created = true
+	}
+	c.mutex.Unlock()
+
+	c.changed()
+	return cloneCollection(collection), created, nil
+}
+
+func (c *CollectionService) Delete(tenant, slug string) error {
+	c.mutex.Lock()
+	i := c.index(tenant, slug)
+	if i >= 0 {
+		c.collections = slices.Delete(c.collections, i, i+1)
+	}
+	c.mutex.Unlock()
+
+	if i < 0 {
+		return ErrCollectionNotFound
+	}
+	c.changed()
+	return nil
+}
+
+// * Filtro de perfiles de la colección (para el mazo temático)
+func CollectionMatcher(collection m.Collection) func(m.CatProfile) bool {
+	return func(cat m.CatProfile) bool {
+		if slices.Contains(collection.ProfileIDs, cat.ID) {
+			return true
+		}
+		return len(collection.Tags) > 0 && hasTags(cat, collection.Tags)
+	}
+}
+
+// * Perfiles publicados de la colección: primero los listados, en su orden, después los
+// * que entran por etiquetas
+func (s *CatService) CollectionProfiles(collection m.Collection) []m.CatProfile {
+	profiles := s.GetCatProfiles()
+	byID := make(map[int]m.CatProfile, len(profiles))
+	for _, cat := range profiles {
+		byID[cat.ID] = cat
+	}
+
+	result := make([]m.CatProfile, 0)
+	listed := make(map[int]bool, len(collection.ProfileIDs))
+	for _, id := range collection.ProfileIDs {
+		if cat, ok := byID[id]; ok {
+			result = append(result, cat)
+			listed[id] = true
+		}
+	}
+	if len(collection.Tags) > 0 {
+		for _, cat := range profiles {
+			if !listed[cat.ID] && hasTags(cat, collection.Tags) {
+				result = append(result, cat)
+			}
+		}
+	}
+	return result
+}
+
+// ! Llamar con mutex tomado
+func (c *CollectionService) index(tenant, slug string) int {
+	return slices.IndexFunc(c.collections, func(collection m.Collection) bool {
+		return collection.Tenant == tenant && collection.Slug == slug
+	})
+}
+
+func (c *CollectionService) changed() {
+	if err := c.save(); err != nil {
+		log.Printf("⚠️ Error guardando colecciones: %v", err)
+	}
+
+	c.mutex.RLock()
+	listeners := slices.Clone(c.listeners)
+	c.mutex.RUnlock()
+	for _, listener := range listeners {
+		listener()
+	}
+}
+
+// * En disco el tenant sí se guarda (en las respuestas no viaja)
+type storedCollection struct {
+	m.Collection
+	Tenant string `json:"tenant"`
+}
+
+func (c *CollectionService) save() error {
+	if c.path == "" {
+		return nil
+	}
+
+	c.mutex.RLock()
+	stored := make([]storedCollection, len(c.collections))
+	for i, collection := range c.collections {
+		stored[i] = storedCollection{Collection: collection, Tenant: collection.Tenant}
+	}
+	c.mutex.RUnlock()
+
+	data, err := json.MarshalIndent(stored, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return fmt.Errorf("error creando directorio de colecciones: %w", err)
+	}
+
+	// ! Escribir a un temporal y renombrar para no dejar el archivo a medias
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, c.path)
+}
+
+func (c *CollectionService) load() error {
+	if c.path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(c.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var stored []storedCollection
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return fmt.Errorf("%s inválido: %w", c.path, err)
+	}
+	c.collections = make([]m.Collection, len(stored))
+	for i, entry := range stored {
+		c.collections[i] = entry.Collection
+		c.collections[i].Tenant = entry.Tenant
+	}
+	return nil
+}
+
+func cloneCollection(collection m.Collection) m.Collection {
+	collection.ProfileIDs = slices.Clone(collection.ProfileIDs)
+	collection.Tags = slices.Clone(collection.Tags)
+	return collection
+}
+
+func uniqueIDs(ids []int) []int {
+	var unique []int
+	for _, id := range ids {
+		if !slices.Contains(unique, id) {
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
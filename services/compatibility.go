@@ -0,0 +1,123 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/ChrisTheAbysswalker/meownder-backend/content"
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+)
+
+const compatibilitySection = "compatibility"
+
+var ErrInvalidName = errors.New("el nombre debe tener al menos una letra")
+
+type compatibilityContent struct {
+	// * Ordenadas por min ascendente
+	Bands []struct {
+		Min          int      `json:"min"`
+		Verdict      string   `json:"verdict"`
+		Explanations []string `json:"explanations"`
+	} `json:"bands"`
+	Shared     string `json:"shared"`
+	NoneShared string `json:"none_shared"`
+}
+
+func CompatibilityLocale(requested, acceptLanguage string) string {
+	if requested != "" {
+		return requested
+	}
+	return content.Negotiate(compatibilitySection, acceptLanguage)
+}
+
+// * Puro y simétrico: (Luna, Whiskers) y (whiskers, LUNA) dan el mismo puntaje, así la
+// * respuesta se puede cachear sin miedo
+func NameCompatibility(a, b, locale string) (*m.NameCompatibility, error) {
+	keyA, keyB := nameKey(a), nameKey(b)
+	if keyA == "" || keyB == "" {
+		return nil, ErrInvalidName
+	}
+	if keyA > keyB {
+		keyA, keyB = keyB, keyA
+	}
+
+	text, err := content.Load[compatibilityContent](compatibilitySection, locale)
+	if err != nil {
+		return nil, err
+	}
+	if len(text.Bands) == 0 {
+		return nil, fmt.Errorf("contenido de compatibilidad vacío para %s", locale)
+	}
+
+	hash := fnv.New32a()
+	fmt.Fprintf(hash, "%s|%s", keyA, keyB)
+	sum := hash.Sum32()
+	score := int(sum % 101)
+
+	band := text.Bands[0]
+	for _, candidate := range text.Bands {
+		if score >= candidate.Min {
+			band = candidate
+		}
+	}
+
+	vars := struct{ A, B, Shared string }{
+		A:      strings.TrimSpace(a),
+		B:      strings.TrimSpace(b),
+		Shared: strings.Join(sharedLetters(keyA, keyB), ", "),
+	}
+	explanation := ""
+	if len(band.Explanations) > 0 {
+		// * Otra parte del hash para que la frase no dependa solo de la banda
+		if explanation, err = content.Render(band.Explanations[int(sum>>8)%len(band.Explanations)], vars); err != nil {
+			return nil, err
+		}
+	}
+	detail := text.NoneShared
+	if vars.Shared != "" {
+		if detail, err = content.Render(text.Shared, vars); err != nil {
+			return nil, err
+		}
+	}
+
+	return &m.NameCompatibility{
+		A:           vars.A,
+		B:           vars.B,
+		Score:       score,
+		Verdict:     band.Verdict,
+		Explanation: strings.TrimSpace(explanation + " " + detail),
+		Locale:      locale,
+	}, nil
+}
+
+// * Solo letras, en minúsculas
+func nameKey(name string) string {
+	var builder strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) {
+			builder.WriteRune(r)
+		}
+	}
+	return builder.String()
+}
+
+func sharedLetters(a, b string) []string {
+	inA := make(map[rune]bool)
+	for _, r := range a {
+		inA[r] = true
+	}
+	seen := make(map[rune]bool)
+	var shared []string
+	for _, r := range b {
+		if inA[r] && !seen[r] {
+			seen[r] = true
+			shared = append(shared, string(r))
+		}
+	}
+	sort.Strings(shared)
+	return shared
+}
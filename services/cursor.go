@@ -0,0 +1,62 @@
+package services
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrInvalidCursor = errors.New("cursor inválido")
+
+// * Posición opaca en una lista ordenada de más nuevo a más viejo: al anclarse en el
+// * último elemento visto, lo que llega mientras el cliente scrollea no desplaza páginas
+type Cursor struct {
+	Time time.Time
+	ID   int
+}
+
+func (c Cursor) Encode() string {
+	raw := fmt.Sprintf("%d:%d", c.Time.UnixNano(), c.ID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func DecodeCursor(encoded string) (*Cursor, error) {
+	if encoded == "" {
+		return nil, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var nanos int64
+	var id int
+	if _, err := fmt.Sscanf(string(raw), "%d:%d", &nanos, &id); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return &Cursor{Time: time.Unix(0, nanos), ID: id}, nil
+}
+
+func (c Cursor) after(other Cursor) bool {
+	if !c.Time.Equal(other.Time) {
+		return c.Time.After(other.Time)
+	}
+	return c.ID > other.ID
+}
+
+// * items llega en orden de inserción (más viejo primero); devuelve la página más nueva
+// * estrictamente anterior al cursor y el cursor de la siguiente, nil si no hay más
+func paginateDesc[T any](items []T, key func(T) Cursor, cursor *Cursor, limit int) ([]T, *Cursor) {
+	page := make([]T, 0, limit)
+	for i := len(items) - 1; i >= 0; i-- {
+		if cursor != nil && !cursor.after(key(items[i])) {
+			continue
+		}
+		if len(page) == limit {
+			next := key(page[len(page)-1])
+			return page, &next
+		}
+		page = append(page, items[i])
+	}
+	return page, nil
+}
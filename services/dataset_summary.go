@@ -0,0 +1,152 @@
+package services
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"strings"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+)
+
+var ageBuckets = []m.AgeBucket{
+	{Label: "0-1", Min: 0, Max: 1},
+	{Label: "2-3", Min: 2, Max: 3},
+	{Label: "4-6", Min: 4, Max: 6},
+	{Label: "7-10", Min: 7, Max: 10},
+	{Label: "11+", Min: 11},
+}
+
+// * Resumen del catálogo publicado. Se calcula una vez por snapshot, así que se renueva
+// * solo con cada recarga o edición y el resto de las veces sale de memoria
+func (s *CatService) Summary() m.DatasetSummary {
+	snap := s.snapshot()
+	snap.summaryOnce.Do(func() {
+		snap.summary = summarize(snap)
+	})
+
+	// * Copia: el resumen del snapshot es compartido
+	summary := snap.summary
+	summary.ByStatus = slices.Clone(summary.ByStatus)
+	summary.Breeds = slices.Clone(summary.Breeds)
+	summary.Ages = slices.Clone(summary.Ages)
+	summary.Hobbies = slices.Clone(summary.Hobbies)
+	summary.Personalities = slices.Clone(summary.Personalities)
+	return summary
+}
+
+func summarize(snap *profileSnapshot) m.DatasetSummary {
+	statuses := newValueCounter()
+	for _, cat := range snap.profiles {
+		if cat.DeletedAt == nil {
+			statuses.add(cat.Status)
+		}
+	}
+
+	breeds, hobbies, personalities, names := newValueCounter(), newValueCounter(), newValueCounter(), newValueCounter()
+	ages := slices.Clone(ageBuckets)
+	agesSeen := make([]int, 0, len(snap.active))
+	var issues m.DatasetIssues
+	for _, pos := range snap.active {
+		cat := snap.profiles[pos]
+		breeds.add(cat.Breed)
+		personalities.add(cat.Personality)
+		names.add(cat.Name)
+		for _, hobby := range cat.Hobbies {
+			hobbies.add(hobby)
+		}
+
+		agesSeen = append(agesSeen, cat.Age)
+		for i := range ages {
+			if cat.Age >= ages[i].Min && (ages[i].Max == 0 || cat.Age <= ages[i].Max) {
+				ages[i].Count++
+				break
+			}
+		}
+
+		if strings.TrimSpace(cat.Img) == "" {
+			issues.MissingImage++
+		}
+		if strings.TrimSpace(cat.Bio) == "" {
+			issues.MissingBio++
+		}
+		if len(cat.Hobbies) == 0 {
+			issues.NoHobbies++
+		}
+	}
+	for _, name := range names.sorted() {
+		if name.Count > 1 {
+			issues.DuplicateNames += name.Count
+		}
+	}
+
+	return m.DatasetSummary{
+		Total:         len(snap.active),
+		ByStatus:      statuses.sorted(),
+		Breeds:        breeds.sorted(),
+		Ages:          ages,
+		AgeStats:      ageStats(agesSeen),
+		Hobbies:       hobbies.sorted(),
+		Personalities: personalities.sorted(),
+		Issues:        issues,
+		GeneratedAt:   snap.publishedAt,
+	}
+}
+
+func ageStats(ages []int) m.AgeStats {
+	if len(ages) == 0 {
+		return m.AgeStats{}
+	}
+	slices.Sort(ages)
+
+	sum := 0
+	for _, age := range ages {
+		sum += age
+	}
+	median := float64(ages[len(ages)/2])
+	if len(ages)%2 == 0 {
+		median = float64(ages[len(ages)/2-1]+ages[len(ages)/2]) / 2
+	}
+	return m.AgeStats{
+		Min:    ages[0],
+		Max:    ages[len(ages)-1],
+		Mean:   math.Round(float64(sum)/float64(len(ages))*10) / 10,
+		Median: median,
+	}
+}
+
+// * Cuenta sin distinguir mayúsculas ni espacios y muestra la primera grafía vista
+type valueCounter struct {
+	counts map[string]*m.SummaryCount
+	order  []*m.SummaryCount
+}
+
+func newValueCounter() *valueCounter {
+	return &valueCounter{counts: make(map[string]*m.SummaryCount)}
+}
+
+func (c *valueCounter) add(value string) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return
+	}
+	key := strings.ToLower(value)
+	entry, ok := c.counts[key]
+	if !ok {
+		entry = &m.SummaryCount{Value: value}
+		c.counts[key] = entry
+		c.order = append(c.order, entry)
+	}
+	entry.Count++
+}
+
+func (c *valueCounter) sorted() []m.SummaryCount {
+	counts := make([]m.SummaryCount, len(c.order))
+	for i, entry := range c.order {
+		counts[i] = *entry
+	}
+	slices.SortStableFunc(counts, func(a, b m.SummaryCount) int {
+		return cmp.Or(cmp.Compare(b.Count, a.Count), strings.Compare(a.Value, b.Value))
+	})
+	return counts
+}
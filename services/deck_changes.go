@@ -0,0 +1,173 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+)
+
+// * Historial de cambios del mazo para GET /deck/changes (long-polling). Se alimenta de los
+// * mismos avisos que el resto de los módulos: OnReload del catálogo (perfiles nuevos,
+// * modificados o que dejaron de estar) y OnRecord de los swipes (un gato swipeado desde
+// * otro dispositivo sale del mazo). Guarda los últimos retain cambios; un cursor más viejo
+// * que eso pide volver a cargar el mazo completo
+type DeckChanges struct {
+	swipes *SwipeService
+	retain int
+
+	mutex   sync.Mutex
+	cursor  int64
+	entries []deckChange
+	// * id -> UpdatedAt del perfil visible la última vez que se miró el catálogo
+	known map[int]time.Time
+	// * Se cierra y se reemplaza con cada cambio: despierta a todos los que esperan
+	wake chan struct{}
+}
+
+type deckChange struct {
+	cursor int64
+	kind   string
+	reason string
+	catID  int
+	// * Solo para cambios de un usuario (swipes); vacío = para todos
+	userID string
+}
+
+func NewDeckChanges(swipes *SwipeService, retain int) *DeckChanges {
+	d := &DeckChanges{
+		swipes: swipes,
+		retain: retain,
+		known:  make(map[int]time.Time),
+		wake:   make(chan struct{}),
+	}
+	for _, cat := range swipes.catService.GetCatProfiles() {
+		d.known[cat.ID] = cat.UpdatedAt
+	}
+	swipes.catService.OnReload(d.catalogChanged)
+	swipes.OnRecord(func(swipe m.Swipe, _ []m.Match) {
+		d.append(deckChange{kind: m.DeckChangeInvalidated, reason: m.DeckChangeSwiped, catID: swipe.CatID, userID: swipe.UserID})
+	})
+	return d
+}
+
+// * Compara el catálogo visible con el anterior
+func (d *DeckChanges) catalogChanged() {
+	profiles := d.swipes.catService.GetCatProfiles()
+	current := make(map[int]time.Time, len(profiles))
+	for _, cat := range profiles {
+		current[cat.ID] = cat.UpdatedAt
+	}
+
+	d.mutex.Lock()
+	previous := d.known
+	d.known = current
+	d.mutex.Unlock()
+
+	var changes []deckChange
+	for _, cat := range profiles {
+		updatedAt, ok := previous[cat.ID]
+		switch {
+		case !ok:
+			changes = append(changes, deckChange{kind: m.DeckChangeRecommended, catID: cat.ID})
+		case !updatedAt.Equal(cat.UpdatedAt):
+			changes = append(changes, deckChange{kind: m.DeckChangeInvalidated, reason: m.DeckChangeUpdated, catID: cat.ID})
+		}
+	}
+	for id := range previous {
+		if _, ok := current[id]; !ok {
+			changes = append(changes, deckChange{kind: m.DeckChangeInvalidated, reason: m.DeckChangeRemoved, catID: id})
+		}
+	}
+	d.append(changes...)
+}
+
+func (d *DeckChanges) append(changes ...deckChange) {
+	if len(changes) == 0 {
+		return
+	}
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	for _, change := range changes {
+		d.cursor++
+		change.cursor = d.cursor
+		d.entries = append(d.entries, change)
+	}
+	if extra := len(d.entries) - d.retain; extra > 0 {
+		d.entries = append([]deckChange(nil), d.entries[extra:]...)
+	}
+	close(d.wake)
+	d.wake = make(chan struct{})
+}
+
+// * Cambios para userID después de since. Si no hay, espera hasta hold (o hasta que se
+// * corte ctx) y devuelve lo que haya llegado; sin since responde al instante con el cursor
+// * actual para que el cliente empiece desde ahí
+func (s *SwipeService) DeckChanges(ctx context.Context, userID string, since *int64, hold time.Duration) m.DeckChangesResponse {
+	d := s.changes
+	if d == nil {
+		return m.DeckChangesResponse{Changes: []m.DeckChange{}}
+	}
+	if since == nil {
+		d.mutex.Lock()
+		defer d.mutex.Unlock()
+		return m.DeckChangesResponse{Cursor: d.cursor, Changes: []m.DeckChange{}}
+	}
+
+	timer := time.NewTimer(hold)
+	defer timer.Stop()
+	cursor := *since
+	for {
+		response, wake := d.after(userID, cursor)
+		if len(response.Changes) > 0 || response.Reset {
+			return response
+		}
+		cursor = response.Cursor
+		select {
+		case <-wake:
+		case <-timer.C:
+			return response
+		case <-ctx.Done():
+			return response
+		}
+	}
+}
+
+func (d *DeckChanges) after(userID string, since int64) (m.DeckChangesResponse, <-chan struct{}) {
+	d.mutex.Lock()
+	response := m.DeckChangesResponse{Cursor: d.cursor, Changes: []m.DeckChange{}}
+	wake := d.wake
+	// * Un cursor del futuro (reinicio del servidor) o ya fuera del historial
+	oldest := d.cursor - int64(len(d.entries))
+	if since > d.cursor || since < oldest {
+		d.mutex.Unlock()
+		response.Reset = true
+		return response, wake
+	}
+	entries := d.entries[len(d.entries)-int(d.cursor-since):]
+	entries = append([]deckChange(nil), entries...)
+	d.mutex.Unlock()
+
+	for _, entry := range entries {
+		if entry.userID != "" && entry.userID != userID {
+			continue
+		}
+		change := m.DeckChange{Cursor: entry.cursor, Type: entry.kind, Reason: entry.reason, CatID: entry.catID}
+		if entry.kind == m.DeckChangeRecommended || entry.reason == m.DeckChangeUpdated {
+			cat, err := d.swipes.catService.GetCatProfileByID(entry.catID)
+			if err != nil {
+				continue
+			}
+			// * Los recomendados tienen que entrar en el mazo de este usuario
+			if entry.kind == m.DeckChangeRecommended {
+				if candidates, _ := d.swipes.candidates(userID, []m.CatProfile{*cat}); len(candidates) == 0 {
+					continue
+				}
+			}
+			change.Cat = cat
+		}
+		response.Changes = append(response.Changes, change)
+	}
+	return response, wake
+}
@@ -0,0 +1,148 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+)
+
+var ErrPrefetchDisabled = errors.New("el prefetch del mazo no está habilitado")
+
+const (
+	prefetchPreparing = "preparing"
+	prefetchReady     = "ready"
+)
+
+// * Mazos pedidos por adelantado con POST /deck/prefetch: el ranking y el HEAD de las
+// * imágenes se hacen en segundo plano y el siguiente GET /deck con los mismos parámetros
+// * se lo lleva sin esperar. Una reserva por usuario (la nueva reemplaza a la anterior) y
+// * cada una vence a los ttl aunque nadie la pida
+type DeckPrefetch struct {
+	ttl        time.Duration
+	validation time.Duration
+	// * usuario -> reserva
+	reservations map[string]*deckReservation
+	mutex        sync.Mutex
+}
+
+type deckReservation struct {
+	seed      *int64
+	limit     int
+	tags      []string
+	expiresAt time.Time
+	// * Se cierra cuando el mazo está listo; después solo se leen los campos de abajo
+	ready     chan struct{}
+	cats      []m.CatProfile
+	usedSeed  int64
+	truncated bool
+}
+
+func NewDeckPrefetch(ttl, validation time.Duration) *DeckPrefetch {
+	return &DeckPrefetch{
+		ttl:          ttl,
+		validation:   validation,
+		reservations: make(map[string]*deckReservation),
+	}
+}
+
+// * Reserva el próximo mazo del usuario y lo arma en segundo plano. Si ya hay uno en
+// * preparación con los mismos parámetros no se arma otro
+func (s *SwipeService) PrefetchDeck(userID string, seed *int64, limit int, tags []string) (m.DeckPrefetchResponse, error) {
+	p := s.prefetch
+	if p == nil {
+		return m.DeckPrefetchResponse{}, ErrPrefetchDisabled
+	}
+
+	now := time.Now()
+	p.mutex.Lock()
+	p.prune(now)
+	if current, ok := p.reservations[userID]; ok && current.matches(seed, limit, tags) {
+		p.mutex.Unlock()
+		return current.view(), nil
+	}
+	reservation := &deckReservation{
+		seed:      seed,
+		limit:     limit,
+		tags:      tags,
+		expiresAt: now.Add(p.ttl),
+		ready:     make(chan struct{}),
+	}
+	p.reservations[userID] = reservation
+	p.mutex.Unlock()
+
+	go func() {
+		defer close(reservation.ready)
+		cats, usedSeed := s.placedDeck(userID, seed, limit, TagMatcher(tags), time.Now())
+		ctx, cancel := context.WithTimeout(context.Background(), p.validation)
+		defer cancel()
+		reservation.cats, reservation.truncated = s.ValidateDeck(ctx, cats)
+		reservation.usedSeed = usedSeed
+	}()
+
+	return reservation.view(), nil
+}
+
+// * Toma el mazo reservado si coincide con lo que se pide; si todavía se está armando
+// * espera a que termine (o a que se corte ctx). Los perfiles que el usuario vio desde el
+// * prefetch se quitan y las impresiones de patrocinados se cuentan ahora
+func (s *SwipeService) PrefetchedDeck(ctx context.Context, userID string, seed *int64, limit int, tags []string) ([]m.CatProfile, int64, bool, bool) {
+	p := s.prefetch
+	if p == nil {
+		return nil, 0, false, false
+	}
+
+	p.mutex.Lock()
+	p.prune(time.Now())
+	reservation, ok := p.reservations[userID]
+	if !ok || !reservation.matches(seed, limit, tags) {
+		p.mutex.Unlock()
+		return nil, 0, false, false
+	}
+	delete(p.reservations, userID)
+	p.mutex.Unlock()
+
+	select {
+	case <-reservation.ready:
+	case <-ctx.Done():
+		return nil, 0, false, false
+	}
+	s.mutex.RLock()
+	seen := s.seen[userID]
+	cats := slices.DeleteFunc(slices.Clone(reservation.cats), func(cat m.CatProfile) bool { return seen[cat.ID] })
+	s.mutex.RUnlock()
+
+	s.sponsors.Record(cats, time.Now())
+	return cats, reservation.usedSeed, reservation.truncated, true
+}
+
+// * Sin semilla vale cualquier reserva con el mismo límite y etiquetas; con semilla solo
+// * la reserva que se pidió con esa misma
+func (r *deckReservation) matches(seed *int64, limit int, tags []string) bool {
+	if r.limit != limit || !slices.Equal(r.tags, tags) {
+		return false
+	}
+	return seed == nil || (r.seed != nil && *r.seed == *seed)
+}
+
+func (r *deckReservation) view() m.DeckPrefetchResponse {
+	status := prefetchPreparing
+	select {
+	case <-r.ready:
+		status = prefetchReady
+	default:
+	}
+	return m.DeckPrefetchResponse{Status: status, ExpiresAt: r.expiresAt}
+}
+
+// ! Llamar con mutex tomado
+func (p *DeckPrefetch) prune(now time.Time) {
+	for userID, reservation := range p.reservations {
+		if now.After(reservation.expiresAt) {
+			delete(p.reservations, userID)
+		}
+	}
+}
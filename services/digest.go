@@ -0,0 +1,323 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html/template"
+	"log"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+)
+
+var (
+	ErrNotSubscribed           = errors.New("no estás suscrito al resumen semanal")
+	ErrInvalidUnsubscribeToken = errors.New("enlace de baja inválido o ya usado")
+)
+
+const digestCatsPerSection = 5
+
+// * Resumen semanal por correo. Las suscripciones viven en memoria y se guardan por
+// * (tenant, usuario): el enlace de baja no lleva tenant, así que el servicio es global
+type DigestService struct {
+	tenants       *TenantRegistry
+	mailer        Mailer
+	template      *template.Template
+	baseURL       string
+	subscriptions map[string]*m.DigestSubscription
+	tokens        map[string]string
+	mutex         sync.Mutex
+}
+
+func NewDigestService(tenants *TenantRegistry, mailer Mailer, tmpl *template.Template, baseURL string) *DigestService {
+	return &DigestService{
+		tenants:       tenants,
+		mailer:        mailer,
+		template:      tmpl,
+		baseURL:       strings.TrimRight(baseURL, "/"),
+		subscriptions: make(map[string]*m.DigestSubscription),
+		tokens:        make(map[string]string),
+	}
+}
+
+func digestKey(tenantID, userID string) string {
+	return tenantID + "|" + userID
+}
+
+// * Suscribirse de nuevo solo cambia el correo: el token de baja y el historial se mantienen
+func (s *DigestService) Subscribe(tenantID, userID, email string) m.DigestSubscription {
+	key := digestKey(tenantID, userID)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	sub, ok := s.subscriptions[key]
+	if !ok {
+		sub = &m.DigestSubscription{
+			UserID:           userID,
+			SubscribedAt:     time.Now(),
+			UnsubscribeToken: newUnsubscribeToken(),
+			SentCatIDs:       make(map[int]bool),
+		}
+		s.subscriptions[key] = sub
+		s.tokens[sub.UnsubscribeToken] = key
+		log.Printf("📧 %s se suscribió al resumen semanal", userID)
+	}
+	sub.Email = email
+	return *sub
+}
+
+func (s *DigestService) Subscription(tenantID, userID string) (m.DigestSubscription, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	sub, ok := s.subscriptions[digestKey(tenantID, userID)]
+	if !ok {
+		return m.DigestSubscription{}, ErrNotSubscribed
+	}
+	return *sub, nil
+}
+
+func (s *DigestService) Unsubscribe(tenantID, userID string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.remove(digestKey(tenantID, userID))
+}
+
+// * Baja desde el enlace del correo: no requiere X-User-ID
+func (s *DigestService) UnsubscribeByToken(token string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	key, ok := s.tokens[token]
+	if !ok {
+		return ErrInvalidUnsubscribeToken
+	}
+	return s.remove(key)
+}
+
+// ! Llamar con el mutex tomado
+func (s *DigestService) remove(key string) error {
+	sub, ok := s.subscriptions[key]
+	if !ok {
+		return ErrNotSubscribed
+	}
+	delete(s.tokens, sub.UnsubscribeToken)
+	delete(s.subscriptions, key)
+	log.Printf("📧 %s canceló el resumen semanal", sub.UserID)
+	return nil
+}
+
+// * Vista previa del resumen que recibiría el usuario ahora; no marca nada como enviado
+func (s *DigestService) Preview(tenantID, userID string, now time.Time) (m.Digest, error) {
+	tenant, ok := s.tenants.Get(tenantID)
+	if !ok {
+		return m.Digest{}, fmt.Errorf("tenant desconocido: %s", tenantID)
+	}
+
+	s.mutex.Lock()
+	var sent map[int]bool
+	if sub, ok := s.subscriptions[digestKey(tenantID, userID)]; ok {
+		sent = sub.SentCatIDs
+	}
+	digest := s.compile(tenant, userID, sent, now)
+	s.mutex.Unlock()
+
+	return digest, nil
+}
+
+// * Semana que termina en now: gatos nuevos para el usuario (aún no deslizados, según sus
+// * preferencias y nunca incluidos en otro resumen) y los más likeados del tenant.
+// ! Llamar con el mutex tomado (lee SentCatIDs)
+func (s *DigestService) compile(tenant *Tenant, userID string, sent map[int]bool, now time.Time) m.Digest {
+	weekStart := now.AddDate(0, 0, -7)
+
+	unseen, _ := tenant.Swipes.candidates(userID, tenant.Cats.GetCatProfiles())
+	sort.SliceStable(unseen, func(i, j int) bool { return unseen[i].UpdatedAt.After(unseen[j].UpdatedAt) })
+	newCats := make([]m.DigestCat, 0, digestCatsPerSection)
+	for _, cat := range unseen {
+		if len(newCats) == digestCatsPerSection {
+			break
+		}
+		if !sent[cat.ID] {
+			newCats = append(newCats, s.digestCat(tenant.ID, cat, 0))
+		}
+	}
+
+	topCats := make([]m.DigestCat, 0, digestCatsPerSection)
+	for _, liked := range tenant.Swipes.topLiked(weekStart, digestCatsPerSection) {
+		if cat, err := tenant.Cats.GetCatProfileByID(liked.CatID); err == nil && cat.Status == m.StatusActive {
+			topCats = append(topCats, s.digestCat(tenant.ID, *cat, liked.Likes))
+		}
+	}
+
+	return m.Digest{
+		UserID:    userID,
+		WeekStart: weekStart.Format("2006-01-02"),
+		WeekEnd:   now.Format("2006-01-02"),
+		NewCats:   newCats,
+		TopCats:   topCats,
+	}
+}
+
+func (s *DigestService) digestCat(tenantID string, cat m.CatProfile, likes int) m.DigestCat {
+	return m.DigestCat{
+		ID:    cat.ID,
+		Name:  cat.Name,
+		Breed: cat.Breed,
+		Img:   TenantURL(s.baseURL, fmt.Sprintf("/api/profiles/%d/image", cat.ID), tenantID),
+		URL:   TenantURL(s.baseURL, fmt.Sprintf("/share/%d", cat.ID), tenantID),
+		Likes: likes,
+	}
+}
+
+// * Job semanal: un correo por suscripción; si no hay nada que contar se omite
+func (s *DigestService) SendAll(ctx context.Context, now time.Time) m.DigestRunResult {
+	return s.send(ctx, "", now)
+}
+
+// * Envío manual desde el admin de un tenant: solo sus suscriptores
+func (s *DigestService) SendTenant(ctx context.Context, tenantID string, now time.Time) m.DigestRunResult {
+	return s.send(ctx, tenantID, now)
+}
+
+func (s *DigestService) send(ctx context.Context, tenantID string, now time.Time) m.DigestRunResult {
+	s.mutex.Lock()
+	keys := make([]string, 0, len(s.subscriptions))
+	for key := range s.subscriptions {
+		if tenantID == "" || strings.HasPrefix(key, tenantID+"|") {
+			keys = append(keys, key)
+		}
+	}
+	s.mutex.Unlock()
+	sort.Strings(keys)
+
+	var result m.DigestRunResult
+	for _, key := range keys {
+		if ctx.Err() != nil {
+			break
+		}
+		switch err := s.sendOne(ctx, key, now); {
+		case errors.Is(err, errEmptyDigest), errors.Is(err, ErrNotSubscribed):
+			result.Skipped++
+		case err != nil:
+			log.Printf("⚠️ Resumen semanal no enviado (%s): %v", key, err)
+			result.Failed++
+		default:
+			result.Sent++
+		}
+	}
+	log.Printf("📧 Resumen semanal: %d enviados, %d omitidos, %d fallidos", result.Sent, result.Skipped, result.Failed)
+	return result
+}
+
+var errEmptyDigest = errors.New("resumen vacío")
+
+func (s *DigestService) sendOne(ctx context.Context, key string, now time.Time) error {
+	tenantID, userID, _ := strings.Cut(key, "|")
+	tenant, ok := s.tenants.Get(tenantID)
+	if !ok {
+		return fmt.Errorf("tenant desconocido: %s", tenantID)
+	}
+
+	// * Se compila con el lock y se envía sin él: el SMTP puede tardar
+	s.mutex.Lock()
+	sub, ok := s.subscriptions[key]
+	if !ok {
+		s.mutex.Unlock()
+		return ErrNotSubscribed
+	}
+	digest := s.compile(tenant, userID, sub.SentCatIDs, now)
+	email, token := sub.Email, sub.UnsubscribeToken
+	s.mutex.Unlock()
+
+	if len(digest.NewCats) == 0 && len(digest.TopCats) == 0 {
+		return errEmptyDigest
+	}
+
+	unsubscribeURL := fmt.Sprintf("%s/api/digest/unsubscribe?token=%s", s.baseURL, url.QueryEscape(token))
+	digest.UnsubscribeURL = unsubscribeURL
+	var body bytes.Buffer
+	if err := s.template.Execute(&body, digest); err != nil {
+		return err
+	}
+
+	err := s.mailer.Send(ctx, Email{
+		To:      email,
+		Subject: fmt.Sprintf("🐱 Tu semana en %s", tenant.Name),
+		HTML:    body.String(),
+		Headers: map[string]string{
+			"List-Unsubscribe":      "<" + unsubscribeURL + ">",
+			"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	if sub, ok := s.subscriptions[key]; ok {
+		sentAt := now
+		sub.LastSentAt = &sentAt
+		for _, cat := range digest.NewCats {
+			sub.SentCatIDs[cat.ID] = true
+		}
+	}
+	s.mutex.Unlock()
+	return nil
+}
+
+func newUnsubscribeToken() string {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		// * Improbable; igual debe ser único
+		return fmt.Sprintf("%032x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(buf)
+}
+
+type likedCat struct {
+	CatID int
+	Likes int
+}
+
+// * Gatos con más likes (y super-likes) desde since; empate por ID
+func (s *SwipeService) topLiked(since time.Time, limit int) []likedCat {
+	s.mutex.RLock()
+	counts := make(map[int]int)
+	for userID, swipes := range s.swipes {
+		// * Sesiones marcadas como no humanas (ver SwipeFraudDetector) no inflan el ranking
+		if s.fraud.Flagged(userID) {
+			continue
+		}
+		for _, swipe := range swipes {
+			if m.IsLike(swipe.Direction) && !swipe.CreatedAt.Before(since) {
+				counts[swipe.CatID]++
+			}
+		}
+	}
+	s.mutex.RUnlock()
+
+	liked := make([]likedCat, 0, len(counts))
+	for catID, likes := range counts {
+		liked = append(liked, likedCat{CatID: catID, Likes: likes})
+	}
+	sort.Slice(liked, func(i, j int) bool {
+		if liked[i].Likes != liked[j].Likes {
+			return liked[i].Likes > liked[j].Likes
+		}
+		return liked[i].CatID < liked[j].CatID
+	})
+	if limit > 0 && len(liked) > limit {
+		liked = liked[:limit]
+	}
+	return liked
+}
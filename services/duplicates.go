@@ -0,0 +1,119 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"unicode"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+)
+
+const (
+	// * Parecido de bios (Jaccard de palabras) desde el que se avisa
+	bioSimilarityThreshold = 0.7
+	// * Bios más cortas se parecen de casualidad ("Me gusta dormir")
+	minBioWordsToCompare = 6
+)
+
+var ErrLikelyDuplicate = errors.New("ya existe un perfil muy parecido")
+
+// * Envuelve ErrLikelyDuplicate con el perfil que ya existe
+type DuplicateError struct {
+	Duplicate m.DuplicateWarning
+}
+
+func (e *DuplicateError) Error() string {
+	return fmt.Sprintf("%s: %d (%s)", ErrLikelyDuplicate, e.Duplicate.ExistingID, e.Duplicate.ExistingName)
+}
+
+func (e *DuplicateError) Unwrap() error { return ErrLikelyDuplicate }
+
+// * Perfiles contra los que se compara un alta: los no borrados del catálogo y los que
+// * ya se agregaron en el mismo import
+type duplicateIndex struct {
+	identity map[string]m.CatProfile
+	bios     []indexedBio
+}
+
+type indexedBio struct {
+	cat   m.CatProfile
+	words map[string]bool
+}
+
+func newDuplicateIndex(profiles []m.CatProfile) *duplicateIndex {
+	idx := &duplicateIndex{identity: make(map[string]m.CatProfile)}
+	for _, cat := range profiles {
+		if cat.DeletedAt == nil {
+			idx.add(cat)
+		}
+	}
+	return idx
+}
+
+func (idx *duplicateIndex) add(cat m.CatProfile) {
+	if _, ok := idx.identity[identityKey(cat)]; !ok {
+		idx.identity[identityKey(cat)] = cat
+	}
+	if words := bioWords(cat.Bio); len(words) >= minBioWordsToCompare {
+		idx.bios = append(idx.bios, indexedBio{cat: cat, words: words})
+	}
+}
+
+// * Mismo nombre, raza y edad, o una bio casi igual
+func (idx *duplicateIndex) find(cat m.CatProfile) (m.DuplicateWarning, bool) {
+	if existing, ok := idx.identity[identityKey(cat)]; ok {
+		return m.DuplicateWarning{
+			ExistingID:   existing.ID,
+			ExistingName: existing.Name,
+			Reason:       m.DuplicateSameIdentity,
+		}, true
+	}
+
+	words := bioWords(cat.Bio)
+	if len(words) < minBioWordsToCompare {
+		return m.DuplicateWarning{}, false
+	}
+	best := m.DuplicateWarning{}
+	for _, candidate := range idx.bios {
+		if similarity := jaccard(words, candidate.words); similarity >= bioSimilarityThreshold && similarity > best.Similarity {
+			best = m.DuplicateWarning{
+				ExistingID:   candidate.cat.ID,
+				ExistingName: candidate.cat.Name,
+				Reason:       m.DuplicateSimilarBio,
+				Similarity:   math.Round(similarity*100) / 100,
+			}
+		}
+	}
+	return best, best.Reason != ""
+}
+
+func identityKey(cat m.CatProfile) string {
+	return fmt.Sprintf("%s|%s|%d", normalizeKey(cat.Name), normalizeKey(cat.Breed), cat.Age)
+}
+
+func bioWords(bio string) map[string]bool {
+	fields := strings.FieldsFunc(strings.ToLower(bio), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	words := make(map[string]bool, len(fields))
+	for _, word := range fields {
+		words[word] = true
+	}
+	return words
+}
+
+func jaccard(a, b map[string]bool) float64 {
+	shared := 0
+	for word := range a {
+		if b[word] {
+			shared++
+		}
+	}
+	union := len(a) + len(b) - shared
+	if union == 0 {
+		return 0
+	}
+	return float64(shared) / float64(union)
+}
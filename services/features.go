@@ -0,0 +1,90 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// * Funciones opcionales que se pueden apagar sin desplegar; todas vienen encendidas
+var knownFeatures = []string{"chat", "compatibility", "feed", "horoscope", "links", "quiz", "widget"}
+
+type FeatureFlags struct {
+	flags atomic.Pointer[map[string]bool]
+}
+
+func NewFeatureFlags(spec string) (*FeatureFlags, error) {
+	features := &FeatureFlags{}
+	if err := features.Set(spec); err != nil {
+		return nil, err
+	}
+	return features, nil
+}
+
+// * Formato: "chat=off,widget=false"; las que no aparecen quedan encendidas
+func ParseFeatureFlags(spec string) (map[string]bool, error) {
+	flags := make(map[string]bool, len(knownFeatures))
+	for _, name := range knownFeatures {
+		flags[name] = true
+	}
+
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, rawValue, found := strings.Cut(part, "=")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if _, ok := flags[name]; !ok {
+			return nil, fmt.Errorf("feature desconocida: %q", name)
+		}
+		enabled := true
+		if found {
+			value, err := parseSwitch(strings.TrimSpace(rawValue))
+			if err != nil {
+				return nil, fmt.Errorf("valor inválido para %s: %s", name, rawValue)
+			}
+			enabled = value
+		}
+		flags[name] = enabled
+	}
+	return flags, nil
+}
+
+func parseSwitch(value string) (bool, error) {
+	switch strings.ToLower(value) {
+	case "on", "yes":
+		return true, nil
+	case "off", "no":
+		return false, nil
+	}
+	return strconv.ParseBool(value)
+}
+
+func (f *FeatureFlags) Set(spec string) error {
+	flags, err := ParseFeatureFlags(spec)
+	if err != nil {
+		return err
+	}
+	f.flags.Store(&flags)
+	return nil
+}
+
+func (f *FeatureFlags) Enabled(name string) bool {
+	enabled, ok := (*f.flags.Load())[name]
+	return !ok || enabled
+}
+
+// * Las apagadas, ordenadas; vacío = todo encendido
+func (f *FeatureFlags) Disabled() []string {
+	var disabled []string
+	for name, enabled := range *f.flags.Load() {
+		if !enabled {
+			disabled = append(disabled, name)
+		}
+	}
+	sort.Strings(disabled)
+	return disabled
+}
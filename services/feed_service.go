@@ -0,0 +1,105 @@
+package services
+
+import (
+	"encoding/xml"
+	"fmt"
+	"html"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+)
+
+const feedMaxEntries = 20
+
+type FeedService struct {
+	catService *CatService
+	baseURL    string
+	feed       []byte
+	feedMutex  sync.RWMutex
+}
+
+func NewFeedService(catService *CatService, baseURL string) *FeedService {
+	service := &FeedService{
+		catService: catService,
+		baseURL:    strings.TrimRight(baseURL, "/"),
+	}
+
+	service.Regenerate()
+	catService.OnReload(service.Regenerate)
+
+	return service
+}
+
+func (s *FeedService) Feed() []byte {
+	s.feedMutex.RLock()
+	defer s.feedMutex.RUnlock()
+	return s.feed
+}
+
+func (s *FeedService) Regenerate() {
+	profiles := append([]m.CatProfile{}, s.catService.GetCatProfiles()...)
+
+	// * Más recientes primero; a igual fecha, los IDs más altos son los más nuevos
+	sort.Slice(profiles, func(i, j int) bool {
+		if !profiles[i].UpdatedAt.Equal(profiles[j].UpdatedAt) {
+			return profiles[i].UpdatedAt.After(profiles[j].UpdatedAt)
+		}
+		return profiles[i].ID > profiles[j].ID
+	})
+	if len(profiles) > feedMaxEntries {
+		profiles = profiles[:feedMaxEntries]
+	}
+
+	updated := time.Now()
+	if len(profiles) > 0 {
+		updated = profiles[0].UpdatedAt
+	}
+
+	feed := m.AtomFeed{
+		Title:   "Meownder · Nuevos gatos",
+		ID:      s.baseURL + "/feed.xml",
+		Updated: updated.UTC().Format(time.RFC3339),
+		Links: []m.AtomLink{
+			{Href: s.baseURL + "/feed.xml", Rel: "self", Type: "application/atom+xml"},
+			{Href: s.baseURL + "/"},
+		},
+		Author: m.AtomAuthor{Name: "Meownder"},
+	}
+
+	for _, cat := range profiles {
+		shareURL := fmt.Sprintf("%s/share/%d", s.baseURL, cat.ID)
+		imageURL := fmt.Sprintf("%s/api/profiles/%d/image", s.baseURL, cat.ID)
+
+		feed.Entries = append(feed.Entries, m.AtomEntry{
+			Title:   fmt.Sprintf("%s (%s, %d años)", cat.Name, cat.Breed, cat.Age),
+			ID:      shareURL,
+			Updated: cat.UpdatedAt.UTC().Format(time.RFC3339),
+			Links: []m.AtomLink{
+				{Href: shareURL, Rel: "alternate", Type: "text/html"},
+				{Href: imageURL, Rel: "enclosure", Type: "image/jpeg"},
+			},
+			Summary: cat.Bio,
+			Content: m.AtomContent{
+				Type: "html",
+				Body: fmt.Sprintf(`<img src="%s" alt="%s"/><p>%s</p><p><a href="%s">Adoptar a %s</a></p>`,
+					imageURL, html.EscapeString(cat.Name), html.EscapeString(cat.Bio), shareURL, html.EscapeString(cat.Name)),
+			},
+		})
+	}
+
+	data, err := xml.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		log.Printf("⚠️ Error generando feed: %v", err)
+		return
+	}
+
+	s.feedMutex.Lock()
+	s.feed = append([]byte(xml.Header), data...)
+	s.feedMutex.Unlock()
+
+	log.Printf("📰 Feed regenerado con %d entradas", len(feed.Entries))
+}
@@ -0,0 +1,50 @@
+package services
+
+import (
+	"time"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+)
+
+// * Umbrales a partir de los cuales /health pasa a "degraded"; 0 desactiva el chequeo
+type FreshnessPolicy struct {
+	DatasetMaxAge time.Duration
+	ProbeMaxAge   time.Duration
+}
+
+// * Degradado no es caído: la instancia sigue lista y sirviendo, pero con datos viejos,
+// * sin perfiles o con el proveedor de imágenes sin responder
+func (s *CatService) Freshness(policy FreshnessPolicy) m.Freshness {
+	now := time.Now()
+	snap := s.snapshot()
+	freshness := m.Freshness{ProbeAgeSeconds: -1}
+
+	if len(snap.profiles) == 0 {
+		freshness.Degraded = append(freshness.Degraded, "no_profiles")
+	}
+	if !snap.publishedAt.IsZero() {
+		updatedAt := snap.publishedAt
+		freshness.DatasetUpdatedAt = &updatedAt
+		freshness.DatasetAgeSeconds = int64(now.Sub(updatedAt).Seconds())
+		if policy.DatasetMaxAge > 0 && now.Sub(updatedAt) > policy.DatasetMaxAge {
+			freshness.Degraded = append(freshness.Degraded, "stale_dataset")
+		}
+	}
+
+	// * Sin probes todavía se cuenta desde el arranque, para no degradar durante el warm-up
+	probeAt := s.createdAt
+	if nanos := s.lastProbeOK.Load(); nanos > 0 {
+		lastProbe := time.Unix(0, nanos)
+		freshness.LastProbeOK = &lastProbe
+		freshness.ProbeAgeSeconds = int64(now.Sub(lastProbe).Seconds())
+		probeAt = lastProbe
+	}
+	if policy.ProbeMaxAge > 0 && now.Sub(probeAt) > policy.ProbeMaxAge {
+		freshness.Degraded = append(freshness.Degraded, "stale_probe")
+	}
+
+	if s.ProviderDown() {
+		freshness.Degraded = append(freshness.Degraded, "provider_down")
+	}
+	return freshness
+}
@@ -0,0 +1,85 @@
+package services
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/ChrisTheAbysswalker/meownder-backend/content"
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+)
+
+const horoscopeSection = "horoscope"
+
+type horoscopeContent struct {
+	Signs    []m.HoroscopeSign `json:"signs"`
+	Overview []string          `json:"overview"`
+	Love     []string          `json:"love"`
+	Naps     []string          `json:"naps"`
+	Items    []string          `json:"items"`
+	Moods    []string          `json:"moods"`
+	Labels   struct {
+		Reading string `json:"reading"`
+	} `json:"labels"`
+}
+
+// * Locale a usar: el pedido explícitamente o el mejor de Accept-Language
+func HoroscopeLocale(requested, acceptLanguage string) string {
+	if requested != "" {
+		return requested
+	}
+	return content.Negotiate(horoscopeSection, acceptLanguage)
+}
+
+// * Determinista: el signo sale del ID y la lectura de (ID, fecha, personalidad), así que
+// * el mismo gato ve lo mismo todo el día en cualquier instancia y en cualquier idioma
+func (s *CatService) Horoscope(id int, date time.Time, locale string) (*m.Horoscope, error) {
+	cat, err := s.GetCatProfileByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	text, err := content.Load[horoscopeContent](horoscopeSection, locale)
+	if err != nil {
+		return nil, err
+	}
+	if len(text.Signs) == 0 || len(text.Overview) == 0 || len(text.Love) == 0 ||
+		len(text.Naps) == 0 || len(text.Items) == 0 || len(text.Moods) == 0 {
+		return nil, fmt.Errorf("contenido de horóscopo incompleto para %s", locale)
+	}
+
+	day := date.UTC().Format("2006-01-02")
+	hash := fnv.New64a()
+	fmt.Fprintf(hash, "%d|%s|%s", cat.ID, day, strings.ToLower(strings.TrimSpace(cat.Personality)))
+	rng := rand.New(rand.NewSource(int64(hash.Sum64())))
+
+	horoscope := &m.Horoscope{
+		CatID:       cat.ID,
+		CatName:     cat.Name,
+		Date:        day,
+		Locale:      locale,
+		Sign:        text.Signs[cat.ID%len(text.Signs)],
+		LuckyNumber: rng.Intn(99) + 1,
+	}
+	vars := struct {
+		Name, Overview, Love, Naps, Item string
+		Number                           int
+	}{Name: cat.Name, Number: horoscope.LuckyNumber}
+
+	// * Siempre el mismo orden de sorteos: cambiarlo cambiaría todas las lecturas
+	if horoscope.Overview, err = content.Render(text.Overview[rng.Intn(len(text.Overview))], vars); err != nil {
+		return nil, err
+	}
+	horoscope.Love = text.Love[rng.Intn(len(text.Love))]
+	horoscope.Naps = text.Naps[rng.Intn(len(text.Naps))]
+	horoscope.LuckyItem = text.Items[rng.Intn(len(text.Items))]
+	horoscope.Mood = text.Moods[rng.Intn(len(text.Moods))]
+
+	vars.Overview, vars.Love, vars.Naps, vars.Item = horoscope.Overview, horoscope.Love, horoscope.Naps, horoscope.LuckyItem
+	if horoscope.Reading, err = content.Render(text.Labels.Reading, vars); err != nil {
+		return nil, err
+	}
+	return horoscope, nil
+}
@@ -0,0 +1,209 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"strings"
+	"time"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+)
+
+const icebreakerCount = 3
+
+// * Hook para generadores externos (LLM); si fallan se usan las plantillas
+type IcebreakerGenerator interface {
+	Icebreakers(ctx context.Context, cat m.CatProfile, with *m.CatProfile, count int) ([]string, error)
+}
+
+type IcebreakerService struct {
+	llm     IcebreakerGenerator
+	timeout time.Duration
+}
+
+// * llm puede ser nil: entonces solo plantillas
+func NewIcebreakerService(llm IcebreakerGenerator, timeout time.Duration) *IcebreakerService {
+	return &IcebreakerService{llm: llm, timeout: timeout}
+}
+
+// * Frases para abrir la conversación sobre cat; with es el gato propio en matches mutuos
+func (s *IcebreakerService) Generate(ctx context.Context, matchID string, cat m.CatProfile, with *m.CatProfile) []string {
+	if s.llm != nil {
+		llmCtx, cancel := context.WithTimeout(ctx, s.timeout)
+		openers, err := s.llm.Icebreakers(llmCtx, cat, with, icebreakerCount)
+		cancel()
+		if err == nil && len(openers) >= icebreakerCount {
+			return openers[:icebreakerCount]
+		}
+		log.Printf("⚠️ Icebreakers del LLM no disponibles para %s, usando plantillas: %v", matchID, err)
+	}
+	return templateIcebreakers(matchID, cat, with)
+}
+
+// * Deterministas por match: regenerarlas da siempre las mismas frases
+func templateIcebreakers(matchID string, cat m.CatProfile, with *m.CatProfile) []string {
+	var preferred, pool []string
+
+	if with != nil {
+		for _, hobby := range sharedHobbies(cat, *with) {
+			preferred = append(preferred, fmt.Sprintf("¡%s y %s comparten la pasión por %s! ¿Organizamos una sesión juntos?", cat.Name, with.Name, hobby))
+		}
+	}
+	hash := fnv.New32a()
+	hash.Write([]byte(matchID))
+	seed := int(hash.Sum32() & 0x7fffffff)
+
+	// * Una sola pregunta por hobby para no llenar las tres frases con lo mismo
+	if len(cat.Hobbies) > 0 {
+		if hobby := strings.TrimSpace(cat.Hobbies[seed%len(cat.Hobbies)]); hobby != "" {
+			pool = append(pool, fmt.Sprintf("Vi que a %s le encanta %s, ¿cuál es su momento favorito para hacerlo?", cat.Name, strings.ToLower(hobby)))
+		}
+	}
+	if sentence := firstSentence(cat.Bio); sentence != "" {
+		pool = append(pool, fmt.Sprintf("Leí que «%s» ¡Cuéntame más!", sentence))
+	}
+	if cat.Personality != "" {
+		pool = append(pool, fmt.Sprintf("%s parece %s, ¿siempre fue así?", cat.Name, strings.ToLower(cat.Personality)))
+	}
+	if cat.Breed != "" {
+		pool = append(pool, fmt.Sprintf("Nunca conocí a un %s en persona, ¿cómo es convivir con %s?", cat.Breed, cat.Name))
+	}
+	pool = append(pool,
+		fmt.Sprintf("¿Cuál es la siesta más épica que se ha echado %s?", cat.Name),
+		fmt.Sprintf("Si %s pudiera pedir cualquier premio, ¿cuál sería?", cat.Name),
+		fmt.Sprintf("¿%s es más de cajas de cartón o de mantas calentitas?", cat.Name),
+	)
+
+	offset := seed % len(pool)
+
+	openers := make([]string, 0, icebreakerCount)
+	for _, opener := range preferred {
+		if len(openers) == icebreakerCount {
+			return openers
+		}
+		openers = append(openers, opener)
+	}
+	for i := 0; len(openers) < icebreakerCount && i < len(pool); i++ {
+		openers = append(openers, pool[(offset+i)%len(pool)])
+	}
+	return openers
+}
+
+func sharedHobbies(a, b m.CatProfile) []string {
+	theirs := make(map[string]bool, len(b.Hobbies))
+	for _, hobby := range b.Hobbies {
+		theirs[normalizeKey(hobby)] = true
+	}
+	var shared []string
+	for _, hobby := range a.Hobbies {
+		if theirs[normalizeKey(hobby)] {
+			shared = append(shared, strings.ToLower(strings.TrimSpace(hobby)))
+		}
+	}
+	return shared
+}
+
+func firstSentence(bio string) string {
+	bio = strings.TrimSpace(bio)
+	if i := strings.IndexAny(bio, ".!?"); i >= 0 {
+		bio = bio[:i+1]
+	}
+	return BioSnippet(bio, 120)
+}
+
+// * Icebreakers generados por un LLM compatible con OpenAI
+type LLMIcebreakers struct {
+	client *OpenAIClient
+}
+
+func NewLLMIcebreakers(client *OpenAIClient) *LLMIcebreakers {
+	return &LLMIcebreakers{client: client}
+}
+
+func (l *LLMIcebreakers) Icebreakers(ctx context.Context, cat m.CatProfile, with *m.CatProfile, count int) ([]string, error) {
+	prompt := fmt.Sprintf("Escribe %d frases cortas y simpáticas en español para iniciar una conversación sobre el gato %s (%s, %d años). Personalidad: %s. Hobbies: %s. Bio: %s.",
+		count, cat.Name, cat.Breed, cat.Age, cat.Personality, strings.Join(cat.Hobbies, ", "), cat.Bio)
+	if with != nil {
+		prompt += fmt.Sprintf(" Quien escribe es el dueño de %s, cuyos hobbies son: %s.", with.Name, strings.Join(with.Hobbies, ", "))
+	}
+	prompt += " Responde solo con las frases, una por línea."
+
+	content, err := l.client.Complete(ctx, prompt)
+	if err != nil {
+		return nil, err
+	}
+
+	var openers []string
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), "-*•0123456789.) "))
+		if line != "" {
+			openers = append(openers, line)
+		}
+	}
+	return openers, nil
+}
+
+var ErrMatchNotFound = errors.New("match no encontrado")
+
+// * matches viene de record: el primero es del usuario que deslizó y, si es mutuo, el
+// * segundo del dueño del otro gato (con los gatos al revés)
+func (s *SwipeService) attachIcebreakers(ctx context.Context, matches []m.Match, cat m.CatProfile, fromCat *m.CatProfile) {
+	for i := range matches {
+		if i == 0 {
+			matches[i].Icebreakers = s.icebreakers.Generate(ctx, matches[i].ID, cat, fromCat)
+		} else {
+			matches[i].Icebreakers = s.icebreakers.Generate(ctx, matches[i].ID, *fromCat, &cat)
+		}
+		s.setIcebreakers(matches[i])
+	}
+}
+
+func (s *SwipeService) setIcebreakers(match m.Match) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for i, stored := range s.matches[match.UserID] {
+		if stored.ID == match.ID {
+			s.matches[match.UserID][i].Icebreakers = match.Icebreakers
+			return
+		}
+	}
+}
+
+// * Los matches restaurados desde la base de datos no traen frases: se regeneran aquí
+func (s *SwipeService) MatchIcebreakers(ctx context.Context, userID, matchID string) ([]string, error) {
+	s.mutex.RLock()
+	var match *m.Match
+	for _, stored := range s.matches[userID] {
+		if stored.ID == matchID {
+			match = &stored
+			break
+		}
+	}
+	s.mutex.RUnlock()
+
+	if match == nil {
+		return nil, fmt.Errorf("%w: %s", ErrMatchNotFound, matchID)
+	}
+	if len(match.Icebreakers) > 0 {
+		return append([]string(nil), match.Icebreakers...), nil
+	}
+
+	cat, err := s.catService.AdminGetCatProfile(match.CatID)
+	if err != nil {
+		return nil, err
+	}
+	var with *m.CatProfile
+	if match.WithCatID != 0 {
+		if with, err = s.catService.AdminGetCatProfile(match.WithCatID); err != nil {
+			return nil, err
+		}
+	}
+
+	match.Icebreakers = s.icebreakers.Generate(ctx, match.ID, *cat, with)
+	s.setIcebreakers(*match)
+	return match.Icebreakers, nil
+}
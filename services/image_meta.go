@@ -0,0 +1,154 @@
+package services
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"mime"
+	"strings"
+	"sync"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+)
+
+// * Metadatos por URL, compartidos entre el proxy (descarga completa) y el validador (HEAD)
+type ImageMetaIndex struct {
+	entries    map[string]m.ImageMeta
+	palettes   map[string][]string
+	mutex      sync.RWMutex
+	maxEntries int
+}
+
+func NewImageMetaIndex(maxEntries int) *ImageMetaIndex {
+	return &ImageMetaIndex{
+		entries:    make(map[string]m.ImageMeta),
+		palettes:   make(map[string][]string),
+		maxEntries: maxEntries,
+	}
+}
+
+// * Fusiona con lo que ya se sabía: un HEAD posterior no borra las dimensiones
+func (idx *ImageMetaIndex) Record(url string, meta m.ImageMeta) {
+	if idx == nil || url == "" {
+		return
+	}
+	idx.mutex.Lock()
+	defer idx.mutex.Unlock()
+
+	if previous, ok := idx.entries[url]; ok {
+		if meta.Width == 0 {
+			meta.Width, meta.Height = previous.Width, previous.Height
+		}
+		if meta.Format == "" {
+			meta.Format = previous.Format
+		}
+		if meta.Bytes == 0 {
+			meta.Bytes = previous.Bytes
+		}
+		if meta.ContentType == "" {
+			meta.ContentType = previous.ContentType
+		}
+	} else if len(idx.entries) >= idx.maxEntries {
+		idx.entries = make(map[string]m.ImageMeta)
+	}
+	idx.entries[url] = meta
+}
+
+func (idx *ImageMetaIndex) Size() int {
+	idx.mutex.RLock()
+	defer idx.mutex.RUnlock()
+	return len(idx.entries)
+}
+
+func (idx *ImageMetaIndex) Get(url string) (m.ImageMeta, bool) {
+	if idx == nil {
+		return m.ImageMeta{}, false
+	}
+	idx.mutex.RLock()
+	defer idx.mutex.RUnlock()
+	meta, ok := idx.entries[url]
+	return meta, ok
+}
+
+func (idx *ImageMetaIndex) RecordPalette(url string, palette []string) {
+	if idx == nil || url == "" {
+		return
+	}
+	idx.mutex.Lock()
+	defer idx.mutex.Unlock()
+
+	if len(idx.palettes) >= idx.maxEntries {
+		idx.palettes = make(map[string][]string)
+	}
+	idx.palettes[url] = palette
+}
+
+// * Se calcula en segundo plano tras la primera descarga: al principio no hay
+func (idx *ImageMetaIndex) Palette(url string) ([]string, bool) {
+	if idx == nil {
+		return nil, false
+	}
+	idx.mutex.RLock()
+	defer idx.mutex.RUnlock()
+	palette, ok := idx.palettes[url]
+	return append([]string(nil), palette...), ok
+}
+
+// * Solo lee la cabecera de la imagen (image.DecodeConfig), no la decodifica entera
+func ExtractImageMeta(contentType string, data []byte) m.ImageMeta {
+	meta := m.ImageMeta{
+		ContentType: contentType,
+		Format:      formatFromContentType(contentType),
+		Bytes:       int64(len(data)),
+	}
+	if config, format, err := image.DecodeConfig(bytes.NewReader(data)); err == nil {
+		meta.Width, meta.Height, meta.Format = config.Width, config.Height, format
+	} else if width, height, ok := webpSize(data); ok {
+		meta.Width, meta.Height, meta.Format = width, height, "webp"
+	}
+	return meta
+}
+
+func formatFromContentType(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ""
+	}
+	format, found := strings.CutPrefix(mediaType, "image/")
+	if !found {
+		return ""
+	}
+	return format
+}
+
+// * La librería estándar no trae WebP; basta con leer el primer chunk (VP8, VP8L o VP8X)
+func webpSize(data []byte) (int, int, bool) {
+	if len(data) < 30 || string(data[0:4]) != "RIFF" || string(data[8:12]) != "WEBP" {
+		return 0, 0, false
+	}
+	chunk := data[12:]
+	switch string(chunk[0:4]) {
+	case "VP8 ":
+		// * Frame clave: firma 9d 01 2a y luego ancho/alto de 14 bits
+		if chunk[11] != 0x9d || chunk[12] != 0x01 || chunk[13] != 0x2a {
+			return 0, 0, false
+		}
+		width := int(binary.LittleEndian.Uint16(chunk[14:16]) & 0x3fff)
+		height := int(binary.LittleEndian.Uint16(chunk[16:18]) & 0x3fff)
+		return width, height, true
+	case "VP8L":
+		if chunk[8] != 0x2f {
+			return 0, 0, false
+		}
+		bits := binary.LittleEndian.Uint32(chunk[9:13])
+		return int(bits&0x3fff) + 1, int((bits>>14)&0x3fff) + 1, true
+	case "VP8X":
+		width := int(chunk[12]) | int(chunk[13])<<8 | int(chunk[14])<<16
+		height := int(chunk[15]) | int(chunk[16])<<8 | int(chunk[17])<<16
+		return width + 1, height + 1, true
+	}
+	return 0, 0, false
+}
@@ -0,0 +1,214 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ChrisTheAbysswalker/meownder-backend/bufpool"
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+	"github.com/ChrisTheAbysswalker/meownder-backend/retry"
+)
+
+const maxImageBytes = 10 << 20
+
+type ImageProxy struct {
+	client     *http.Client
+	retry      *retry.Policy
+	cache      map[string]*m.ImageData
+	cacheMutex sync.RWMutex
+	maxEntries int
+	meta       *ImageMetaIndex
+	quality    *ImageQualityPolicy
+	stable     *StableImages
+	// * Imágenes esperando su paleta; acotada, así que no retiene bytes sin límite
+	paletteQueue chan *m.ImageData
+}
+
+func NewImageProxy(retryPolicy *retry.Policy, meta *ImageMetaIndex, quality *ImageQualityPolicy, transport *ProviderTransport) *ImageProxy {
+	p := &ImageProxy{
+		client:       transport.Client(10 * time.Second),
+		retry:        retryPolicy,
+		cache:        make(map[string]*m.ImageData),
+		maxEntries:   200,
+		meta:         meta,
+		quality:      quality,
+		paletteQueue: make(chan *m.ImageData, paletteQueueSize),
+	}
+	for range paletteWorkers {
+		go p.paletteWorker()
+	}
+	return p
+}
+
+// * Con imágenes fijas los bytes de las imágenes de perfil sobreviven a los reinicios
+func (p *ImageProxy) SetStableImages(stable *StableImages) {
+	p.stable = stable
+}
+
+// * cataas devuelve un gato distinto en cada petición, así que se guardan los bytes
+// * para que la imagen "actual" de un perfil sea estable mientras no se refresque
+func (p *ImageProxy) Prefetch(ctx context.Context, urls []string) int {
+	sem := make(chan struct{}, 5)
+	var wg sync.WaitGroup
+	var fetched int
+	var fetchedMutex sync.Mutex
+
+	for _, url := range urls {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return fetched
+		}
+
+		wg.Add(1)
+		go func(url string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if _, err := p.fetch(ctx, url); err != nil {
+				log.Printf("⚠️ Error precargando imagen: %v", err)
+				return
+			}
+			fetchedMutex.Lock()
+			fetched++
+			fetchedMutex.Unlock()
+		}(url)
+	}
+
+	wg.Wait()
+	log.Printf("🖼️ Imágenes precargadas: %d/%d", fetched, len(urls))
+	return fetched
+}
+
+func (p *ImageProxy) Fetch(url string) (*m.ImageData, error) {
+	return p.fetch(context.Background(), url)
+}
+
+// * La variante de calidad baja se cachea aparte, con la URL original más un sufijo
+func (p *ImageProxy) FetchQuality(url, quality string) (*m.ImageData, error) {
+	if quality != m.ImageQualityLow || p.quality == nil {
+		return p.Fetch(url)
+	}
+
+	key := url + "#low"
+	p.cacheMutex.RLock()
+	cached, ok := p.cache[key]
+	p.cacheMutex.RUnlock()
+	if ok {
+		return cached, nil
+	}
+
+	original, err := p.Fetch(url)
+	if err != nil {
+		return nil, err
+	}
+	variant, err := p.quality.Variant(original)
+	if err != nil {
+		return nil, err
+	}
+	p.store(key, variant)
+	return variant, nil
+}
+
+func (p *ImageProxy) fetch(ctx context.Context, url string) (*m.ImageData, error) {
+	p.cacheMutex.RLock()
+	cached, ok := p.cache[url]
+	p.cacheMutex.RUnlock()
+	if ok {
+		return cached, nil
+	}
+	if image, ok := p.stable.Load(url); ok {
+		p.meta.Record(url, image.Meta)
+		p.queuePalette(image)
+		p.store(url, image)
+		return image, nil
+	}
+
+	var contentType string
+	var data []byte
+	err := p.retry.Do(ctx, func(attempt int) error {
+		var err error
+		contentType, data, err = p.download(ctx, url)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	image := &m.ImageData{
+		SourceURL:   url,
+		ContentType: contentType,
+		Data:        data,
+		Meta:        ExtractImageMeta(contentType, data),
+	}
+	p.meta.Record(url, image.Meta)
+	p.queuePalette(image)
+
+	p.stable.Store(image)
+	p.store(url, image)
+	return image, nil
+}
+
+func (p *ImageProxy) CacheSize() int {
+	p.cacheMutex.RLock()
+	defer p.cacheMutex.RUnlock()
+	return len(p.cache)
+}
+
+func (p *ImageProxy) store(key string, image *m.ImageData) {
+	p.cacheMutex.Lock()
+	defer p.cacheMutex.Unlock()
+
+	if len(p.cache) >= p.maxEntries {
+		p.cache = make(map[string]*m.ImageData)
+		log.Println("🧹 Cache de imágenes limpiado")
+	}
+	p.cache[key] = image
+}
+
+// * Los 5xx y errores de red se reintentan; el resto de fallos son permanentes
+func (p *ImageProxy) download(ctx context.Context, url string) (string, []byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", nil, retry.Permanent(err)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", nil, fmt.Errorf("error descargando imagen: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 500 {
+		return "", nil, fmt.Errorf("el proveedor respondió %d", resp.StatusCode)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", nil, retry.Permanent(fmt.Errorf("el proveedor respondió %d", resp.StatusCode))
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	if !strings.HasPrefix(contentType, "image/") {
+		return "", nil, retry.Permanent(fmt.Errorf("tipo de contenido inesperado: %s", contentType))
+	}
+
+	// * Leer sobre un buffer del pool y copiar al tamaño exacto: el cache guarda la copia
+	buf := bufpool.Default.Get()
+	defer bufpool.Default.Put(buf)
+
+	if resp.ContentLength > 0 && resp.ContentLength <= maxImageBytes {
+		buf.Grow(int(resp.ContentLength))
+	}
+	if _, err := buf.ReadFrom(io.LimitReader(resp.Body, maxImageBytes)); err != nil {
+		return "", nil, fmt.Errorf("error leyendo imagen: %w", err)
+	}
+
+	return contentType, bytes.Clone(buf.Bytes()), nil
+}
@@ -0,0 +1,186 @@
+package services
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+)
+
+// * Revisa las imágenes asignadas a los perfiles y reemplaza las que ya no responden
+func (s *CatService) PruneBrokenImages(ctx context.Context, timeout time.Duration) error {
+	start := time.Now()
+	profiles := s.snapshot().profiles
+
+	jobs := make(chan m.CatProfile)
+	var wg sync.WaitGroup
+	var mutex sync.Mutex
+	replacements := make(map[int]string)
+	// * Solo los que llegaron a revisarse: si ctx vence a mitad, el resto queda para la próxima
+	checked, broken := 0, 0
+
+	for w := 0; w < validationWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for cat := range jobs {
+				valid := s.validateCatURL(m.CatURL{URL: cat.Img}, timeout)
+				mutex.Lock()
+				checked++
+				if !valid {
+					broken++
+				}
+				mutex.Unlock()
+				if valid {
+					continue
+				}
+
+				// ! Solo reemplazar con una URL verificada; si el proveedor está caído
+				// ! cambiar una URL rota por otra igual de rota no sirve de nada
+				replacement, ok := s.validatedReplacement(timeout)
+				if !ok {
+					continue
+				}
+				mutex.Lock()
+				replacements[cat.ID] = replacement.URL
+				mutex.Unlock()
+			}
+		}()
+	}
+
+feed:
+	for _, cat := range profiles {
+		select {
+		case jobs <- cat:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if len(replacements) > 0 {
+		now := time.Now()
+		s.updateProfiles(func(profiles []m.CatProfile) {
+			for i := range profiles {
+				if url, ok := replacements[profiles[i].ID]; ok {
+					profiles[i].Img = url
+					profiles[i].UpdatedAt = now
+				}
+			}
+		})
+		s.notifyReload()
+	}
+
+	s.pruneMutex.Lock()
+	s.pruneStats.Runs++
+	s.pruneStats.Checked += checked
+	s.pruneStats.Broken += broken
+	s.pruneStats.Replaced += len(replacements)
+	s.pruneStats.LastRun = &start
+	s.pruneStats.LastChecked = checked
+	s.pruneStats.LastReplaced = len(replacements)
+	s.pruneStats.LastReplacementRate = 0
+	if checked > 0 {
+		s.pruneStats.LastReplacementRate = float64(len(replacements)) / float64(checked)
+	}
+	s.pruneMutex.Unlock()
+
+	log.Printf("🧹 Poda de imágenes: %d/%d revisadas, %d rotas, %d reemplazadas en %s",
+		checked, len(profiles), broken, len(replacements), time.Since(start).Round(time.Millisecond))
+	return ctx.Err()
+}
+
+// * Mazo con plazo: revisa la imagen de cada perfil hasta que vence ctx y devuelve, en el
+// * mismo orden, los que respondieron. Las imágenes ya conocidas (pool validado o HEAD
+// * previo) no se vuelven a pedir. truncated indica que quedaron perfiles sin revisar
+func (s *CatService) ValidateProfileImages(ctx context.Context, cats []m.CatProfile) ([]m.CatProfile, bool) {
+	deadline, _ := ctx.Deadline()
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	var mutex sync.Mutex
+	// * 0 sin revisar, 1 imagen válida, -1 rota
+	checked := make([]int, len(cats))
+	closed := false
+
+	for w := 0; w < min(len(cats), validationWorkers); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				result := -1
+				if _, ok := s.ImageMeta(cats[i].Img); ok {
+					result = 1
+				} else {
+					timeout := 2 * time.Second
+					if !deadline.IsZero() {
+						timeout = min(timeout, time.Until(deadline))
+					}
+					if timeout > 0 && s.validateCatURL(m.CatURL{URL: cats[i].Img}, timeout) {
+						result = 1
+					}
+				}
+				mutex.Lock()
+				if !closed {
+					checked[i] = result
+				}
+				mutex.Unlock()
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+		for i := range cats {
+			select {
+			case jobs <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	closed = true
+	valid := make([]m.CatProfile, 0, len(cats))
+	truncated := false
+	for i, result := range checked {
+		switch result {
+		case 1:
+			valid = append(valid, cats[i])
+		case 0:
+			truncated = true
+		}
+	}
+	return valid, truncated
+}
+
+func (s *CatService) validatedReplacement(timeout time.Duration) (m.CatURL, bool) {
+	if catURL, ok := s.TakeValidatedURL(); ok {
+		return catURL, true
+	}
+	catURL := s.generateCatURL()
+	if !s.validateCatURL(catURL, timeout) {
+		return m.CatURL{}, false
+	}
+	return catURL, true
+}
+
+func (s *CatService) PruneStats() m.PruneStats {
+	s.pruneMutex.Lock()
+	defer s.pruneMutex.Unlock()
+	return s.pruneStats
+}
@@ -0,0 +1,152 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"net/url"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+)
+
+// * Orden de los tamaños de cataas, de menor a mayor; "square" es un recorte y no entra
+var imageSizeRank = map[string]int{
+	"xsmall": 0,
+	"small":  1,
+	"medium": 2,
+}
+
+// * Punto único donde se decide qué significa "calidad baja" para URLs, mazo y proxy
+type ImageQualityPolicy struct {
+	lowSize        string
+	lowMaxWidth    int
+	lowJPEGQuality int
+}
+
+func NewImageQualityPolicy(lowSize string, lowMaxWidth, lowJPEGQuality int) (*ImageQualityPolicy, error) {
+	if _, ok := imageSizeRank[lowSize]; !ok {
+		return nil, fmt.Errorf("tamaño %q inválido (xsmall, small o medium)", lowSize)
+	}
+	if lowMaxWidth <= 0 {
+		return nil, fmt.Errorf("el ancho máximo debe ser positivo")
+	}
+	if lowJPEGQuality < 1 || lowJPEGQuality > 100 {
+		return nil, fmt.Errorf("la calidad JPEG debe estar entre 1 y 100")
+	}
+	return &ImageQualityPolicy{
+		lowSize:        lowSize,
+		lowMaxWidth:    lowMaxWidth,
+		lowJPEGQuality: lowJPEGQuality,
+	}, nil
+}
+
+// * Con calidad baja el tamaño pedido nunca supera lowSize y no se piden GIFs
+func (p *ImageQualityPolicy) Options(opts m.ImageOptions, quality string) m.ImageOptions {
+	if p == nil || quality != m.ImageQualityLow {
+		return opts
+	}
+	if rank, ok := imageSizeRank[opts.Size]; opts.Size == "" || (ok && rank > imageSizeRank[p.lowSize]) {
+		opts.Size = p.lowSize
+	}
+	if opts.Tag == "gif" {
+		opts.Tag = ""
+	}
+	opts.StaticOnly = true
+	return opts
+}
+
+// * Un cliente que no muestra más que lowMaxWidth de ancho no gana nada con la imagen grande
+func (p *ImageQualityPolicy) QualityFor(maxWidth int) string {
+	if p != nil && maxWidth > 0 && maxWidth <= p.lowMaxWidth {
+		return m.ImageQualityLow
+	}
+	return m.ImageQualityHigh
+}
+
+// * Las URLs de perfiles son de cataas: basta con pedir el tamaño chico. Las de otros
+// * hosts (imágenes subidas, TheCatAPI) quedan igual y el proxy se encarga
+func (p *ImageQualityPolicy) ProfileURL(rawURL, quality string) string {
+	if p == nil || quality != m.ImageQualityLow {
+		return rawURL
+	}
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Host != "cataas.com" {
+		return rawURL
+	}
+	query := parsed.Query()
+	if rank, ok := imageSizeRank[query.Get("type")]; ok && rank <= imageSizeRank[p.lowSize] {
+		return rawURL
+	}
+	query.Set("type", p.lowSize)
+	parsed.RawQuery = query.Encode()
+	return parsed.String()
+}
+
+// * Los metadatos describen la imagen original: si la URL cambió dejan de valer
+func (p *ImageQualityPolicy) Profiles(profiles []m.CatProfile, quality string) []m.CatProfile {
+	for i := range profiles {
+		if variant := p.ProfileURL(profiles[i].Img, quality); variant != profiles[i].Img {
+			profiles[i].Img = variant
+			profiles[i].ImageMeta = nil
+		}
+	}
+	return profiles
+}
+
+// * Reduce al ancho máximo y recodifica como JPEG; de un GIF queda el primer cuadro.
+// * Si no se puede decodificar (WebP) se devuelve la original
+func (p *ImageQualityPolicy) Variant(original *m.ImageData) (*m.ImageData, error) {
+	img, _, err := image.Decode(bytes.NewReader(original.Data))
+	if err != nil {
+		return original, nil
+	}
+	if img.Bounds().Dx() > p.lowMaxWidth {
+		img = downscale(img, p.lowMaxWidth)
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: p.lowJPEGQuality}); err != nil {
+		return nil, fmt.Errorf("error recodificando imagen: %w", err)
+	}
+	// * Una imagen ya chica y muy comprimida puede crecer al recodificarla
+	if buf.Len() >= len(original.Data) && original.Meta.Format != "gif" {
+		return original, nil
+	}
+
+	data := buf.Bytes()
+	return &m.ImageData{
+		SourceURL:   original.SourceURL,
+		ContentType: "image/jpeg",
+		Data:        data,
+		Meta:        ExtractImageMeta("image/jpeg", data),
+	}, nil
+}
+
+// * Promedio por área: cada píxel destino promedia el bloque de origen que cubre
+func downscale(src image.Image, width int) image.Image {
+	bounds := src.Bounds()
+	height := max(1, bounds.Dy()*width/bounds.Dx())
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	for y := 0; y < height; y++ {
+		y0 := bounds.Min.Y + y*bounds.Dy()/height
+		y1 := max(y0+1, bounds.Min.Y+(y+1)*bounds.Dy()/height)
+		for x := 0; x < width; x++ {
+			x0 := bounds.Min.X + x*bounds.Dx()/width
+			x1 := max(x0+1, bounds.Min.X+(x+1)*bounds.Dx()/width)
+
+			var r, g, b, a, n uint32
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := src.At(sx, sy).RGBA()
+					r, g, b, a = r+cr, g+cg, b+cb, a+ca
+					n++
+				}
+			}
+			dst.Set(x, y, color.RGBA64{R: uint16(r / n), G: uint16(g / n), B: uint16(b / n), A: uint16(a / n)})
+		}
+	}
+	return dst
+}
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+)
+
+var ErrNothingToRefresh = errors.New("ningún perfil coincide con el filtro")
+
+// * Perfiles no borrados que cumplen todos los filtros dados; se llama antes de encolar el
+// * trabajo para responder 422 en vez de un trabajo vacío
+func (s *CatService) RefreshTargets(req m.RefreshImagesRequest) ([]m.CatProfile, error) {
+	targets := refreshTargets(s.snapshot().profiles, req)
+	if len(targets) == 0 {
+		return nil, ErrNothingToRefresh
+	}
+	return targets, nil
+}
+
+// * Refresco selectivo: revisa los perfiles con un pool acotado y solo reemplaza por URLs
+// * verificadas. Pensado para correr como trabajo (ver JobManager): avanza el progreso por perfil
+func (s *CatService) RefreshImagesSelective(ctx context.Context, targets []m.CatProfile, req m.RefreshImagesRequest, timeout time.Duration, job *JobHandle) (m.RefreshResult, error) {
+	workers := req.Concurrency
+	if workers == 0 {
+		workers = validationWorkers
+	}
+	workers = min(workers, len(targets))
+	job.SetTotal(len(targets))
+
+	queue := make(chan m.CatProfile)
+	replacements := make(map[int]string)
+	var result m.RefreshResult
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for cat := range queue {
+				broken := req.OnlyBroken && !s.validateCatURL(m.CatURL{URL: cat.Img}, timeout)
+				if req.OnlyBroken && !broken {
+					mutex.Lock()
+					result.Checked++
+					mutex.Unlock()
+					job.Advance(1)
+					continue
+				}
+
+				replacement, ok := s.validatedReplacement(timeout)
+				mutex.Lock()
+				result.Checked++
+				if broken {
+					result.Broken++
+				}
+				if ok {
+					result.Refreshed++
+					replacements[cat.ID] = replacement.URL
+				} else {
+					result.Failed++
+				}
+				mutex.Unlock()
+				job.Advance(1)
+			}
+		}()
+	}
+enqueue:
+	for _, cat := range targets {
+		select {
+		case queue <- cat:
+		case <-ctx.Done():
+			break enqueue
+		}
+	}
+	close(queue)
+	wg.Wait()
+
+	if len(replacements) > 0 {
+		now := time.Now()
+		s.updateProfiles(func(profiles []m.CatProfile) {
+			for i := range profiles {
+				if url, ok := replacements[profiles[i].ID]; ok {
+					profiles[i].Img = url
+					profiles[i].UpdatedAt = now
+				}
+			}
+		})
+		s.notifyReload()
+	}
+
+	log.Printf("🔄 Refresco de imágenes: %d revisados, %d rotos, %d reemplazados, %d sin reemplazo",
+		result.Checked, result.Broken, result.Refreshed, result.Failed)
+	if result.Failed > 0 && result.Refreshed == 0 {
+		return result, errors.New("no se consiguió ninguna imagen verificada: ¿proveedor caído?")
+	}
+	return result, ctx.Err()
+}
+
+func refreshTargets(profiles []m.CatProfile, req m.RefreshImagesRequest) []m.CatProfile {
+	targets := make([]m.CatProfile, 0, len(profiles))
+	for _, cat := range profiles {
+		if cat.DeletedAt != nil {
+			continue
+		}
+		if len(req.IDs) > 0 && !slices.Contains(req.IDs, cat.ID) {
+			continue
+		}
+		if req.Breed != "" && !strings.EqualFold(cat.Breed, req.Breed) {
+			continue
+		}
+		targets = append(targets, cat)
+	}
+	return targets
+}
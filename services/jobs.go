@@ -0,0 +1,328 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+)
+
+const (
+	jobEventBuffer = 32
+	// * Como mucho un job.updated por trabajo en este intervalo: el progreso no inunda a nadie
+	jobEventInterval = 500 * time.Millisecond
+	maxFinishedJobs  = 200
+)
+
+var (
+	ErrJobNotFound = errors.New("trabajo no encontrado")
+	ErrJobRunning  = errors.New("ya hay un trabajo del mismo tipo en curso")
+	ErrNoArtifact  = errors.New("el trabajo no generó un archivo o todavía no terminó")
+)
+
+// * Cuerpo de un trabajo: informa el avance por job y devuelve un resumen para Job.Result
+type JobFunc func(ctx context.Context, job *JobHandle) (any, error)
+
+// * Operaciones largas de admin (import, refresco de imágenes, respaldos, exports) en
+// * segundo plano: la petición responde 202 con el trabajo y el avance se consulta o se
+// * escucha por websocket. Como mucho workers trabajos a la vez y uno por tipo y tenant;
+// * los terminados (y sus archivos) se borran pasada la retención
+type JobManager struct {
+	dir            string
+	retention      time.Duration
+	downloadPrefix string
+	slots          chan struct{}
+
+	records     map[string]*jobRecord
+	subscribers map[chan m.JobEvent]struct{}
+	listeners   []func(m.Job)
+	mutex       sync.Mutex
+}
+
+type jobRecord struct {
+	job       m.Job
+	artifact  *jobArtifact
+	lastEvent time.Time
+}
+
+type jobArtifact struct {
+	path        string
+	filename    string
+	contentType string
+}
+
+// * dir guarda los archivos generados; downloadPrefix + id + "/download" es la ruta para bajarlos
+func NewJobManager(workers int, dir string, retention time.Duration, downloadPrefix string) *JobManager {
+	// * Archivos de una ejecución anterior: sus trabajos ya no existen
+	if leftovers, _ := filepath.Glob(filepath.Join(dir, "job-*")); len(leftovers) > 0 {
+		for _, path := range leftovers {
+			os.Remove(path)
+		}
+		log.Printf("🧹 %d archivos de trabajos anteriores borrados", len(leftovers))
+	}
+
+	return &JobManager{
+		dir:            dir,
+		retention:      retention,
+		downloadPrefix: downloadPrefix,
+		slots:          make(chan struct{}, max(workers, 1)),
+		records:        make(map[string]*jobRecord),
+		subscribers:    make(map[chan m.JobEvent]struct{}),
+	}
+}
+
+// * Se llama al terminar cada trabajo, bien o mal (webhook de avisos)
+func (j *JobManager) OnFinish(listener func(m.Job)) {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	j.listeners = append(j.listeners, listener)
+}
+
+func (j *JobManager) Start(kind, tenant string, run JobFunc) (m.Job, error) {
+	j.mutex.Lock()
+	j.prune(time.Now())
+	for _, record := range j.records {
+		if record.job.Kind == kind && record.job.Tenant == tenant && !jobFinished(record.job) {
+			j.mutex.Unlock()
+			return m.Job{}, fmt.Errorf("%w: %s", ErrJobRunning, record.job.ID)
+		}
+	}
+	record := &jobRecord{job: m.Job{
+		ID:        newJobID(),
+		Kind:      kind,
+		Status:    m.JobQueued,
+		Tenant:    tenant,
+		CreatedAt: time.Now(),
+	}}
+	j.records[record.job.ID] = record
+	job := record.job
+	j.publish(record, m.JobEventUpdated)
+	j.mutex.Unlock()
+
+	go j.execute(record, run)
+	return job, nil
+}
+
+func (j *JobManager) execute(record *jobRecord, run JobFunc) {
+	j.slots <- struct{}{}
+	defer func() { <-j.slots }()
+
+	j.update(record, func(job *m.Job) {
+		started := time.Now()
+		job.StartedAt = &started
+		job.Status = m.JobRunning
+	}, true)
+
+	handle := &JobHandle{manager: j, record: record}
+	result, err := j.protect(handle, run)
+
+	var job m.Job
+	j.mutex.Lock()
+	finished := time.Now()
+	record.job.FinishedAt = &finished
+	record.job.Result = result
+	if err != nil {
+		record.job.Status = m.JobFailed
+		record.job.Error = err.Error()
+		if record.artifact != nil {
+			os.Remove(record.artifact.path)
+			record.artifact = nil
+		}
+	} else {
+		record.job.Status = m.JobSucceeded
+		if record.artifact != nil {
+			record.job.Download = j.downloadPrefix + record.job.ID + "/download"
+		}
+	}
+	job = record.job
+	j.publish(record, m.JobEventFinished)
+	listeners := slices.Clone(j.listeners)
+	j.mutex.Unlock()
+
+	log.Printf("📦 Trabajo %s (%s) %s en %s", job.ID, job.Kind, job.Status, finished.Sub(*job.StartedAt).Round(time.Millisecond))
+	for _, listener := range listeners {
+		listener(job)
+	}
+}
+
+// * Un panic en un trabajo no tumba el servidor: el trabajo queda fallido
+func (j *JobManager) protect(handle *JobHandle, run JobFunc) (result any, err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("panic: %v", recovered)
+		}
+	}()
+	return run(context.Background(), handle)
+}
+
+func (j *JobManager) Get(id string) (m.Job, error) {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	record, ok := j.records[id]
+	if !ok {
+		return m.Job{}, ErrJobNotFound
+	}
+	return record.job, nil
+}
+
+// * Más recientes primero; tenant vacío = todos
+func (j *JobManager) List(tenant string) []m.Job {
+	j.mutex.Lock()
+	jobs := make([]m.Job, 0, len(j.records))
+	for _, record := range j.records {
+		if tenant == "" || record.job.Tenant == tenant {
+			jobs = append(jobs, record.job)
+		}
+	}
+	j.mutex.Unlock()
+
+	sort.Slice(jobs, func(a, b int) bool { return jobs[a].CreatedAt.After(jobs[b].CreatedAt) })
+	return jobs
+}
+
+// * Ruta, nombre sugerido y tipo del archivo de un trabajo terminado bien
+func (j *JobManager) Artifact(id string) (path, filename, contentType string, err error) {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	record, ok := j.records[id]
+	if !ok {
+		return "", "", "", ErrJobNotFound
+	}
+	if record.artifact == nil || record.job.Status != m.JobSucceeded {
+		return "", "", "", ErrNoArtifact
+	}
+	return record.artifact.path, record.artifact.filename, record.artifact.contentType, nil
+}
+
+func (j *JobManager) Subscribe() (<-chan m.JobEvent, func()) {
+	events := make(chan m.JobEvent, jobEventBuffer)
+
+	j.mutex.Lock()
+	j.subscribers[events] = struct{}{}
+	j.mutex.Unlock()
+
+	var once sync.Once
+	return events, func() {
+		once.Do(func() {
+			j.mutex.Lock()
+			delete(j.subscribers, events)
+			j.mutex.Unlock()
+			close(events)
+		})
+	}
+}
+
+func (j *JobManager) update(record *jobRecord, mutate func(job *m.Job), force bool) {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	mutate(&record.job)
+	if force || time.Since(record.lastEvent) >= jobEventInterval {
+		j.publish(record, m.JobEventUpdated)
+	}
+}
+
+// ! Llamar con mutex tomado
+func (j *JobManager) publish(record *jobRecord, eventType string) {
+	record.lastEvent = time.Now()
+	event := m.JobEvent{Type: eventType, Job: record.job}
+	for events := range j.subscribers {
+		select {
+		case events <- event:
+		default:
+			log.Printf("⚠️ Evento de trabajo %s descartado: suscriptor lento", record.job.ID)
+		}
+	}
+}
+
+// ! Llamar con mutex tomado
+func (j *JobManager) prune(now time.Time) {
+	var finished []*jobRecord
+	for id, record := range j.records {
+		if !jobFinished(record.job) {
+			continue
+		}
+		if now.Sub(*record.job.FinishedAt) > j.retention {
+			j.drop(id, record)
+			continue
+		}
+		finished = append(finished, record)
+	}
+
+	if len(finished) < maxFinishedJobs {
+		return
+	}
+	sort.Slice(finished, func(a, b int) bool { return finished[a].job.FinishedAt.Before(*finished[b].job.FinishedAt) })
+	for _, record := range finished[:len(finished)-maxFinishedJobs+1] {
+		j.drop(record.job.ID, record)
+	}
+}
+
+func (j *JobManager) drop(id string, record *jobRecord) {
+	if record.artifact != nil {
+		os.Remove(record.artifact.path)
+	}
+	delete(j.records, id)
+}
+
+func jobFinished(job m.Job) bool {
+	return job.Status == m.JobSucceeded || job.Status == m.JobFailed
+}
+
+// * Lo que ve el cuerpo de un trabajo. Un handle nil no hace nada: el mismo código
+// * sirve para la versión síncrona de la operación
+type JobHandle struct {
+	manager *JobManager
+	record  *jobRecord
+}
+
+func (h *JobHandle) SetTotal(total int) {
+	if h == nil {
+		return
+	}
+	h.manager.update(h.record, func(job *m.Job) { job.Progress.Total = total }, true)
+}
+
+func (h *JobHandle) Advance(done int) {
+	if h == nil {
+		return
+	}
+	h.manager.update(h.record, func(job *m.Job) { job.Progress.Done += done }, false)
+}
+
+// * Archivo resultado del trabajo; si el trabajo falla se borra
+func (h *JobHandle) Artifact(filename, contentType string) (io.WriteCloser, error) {
+	if err := os.MkdirAll(h.manager.dir, 0o755); err != nil {
+		return nil, fmt.Errorf("error creando directorio de trabajos: %w", err)
+	}
+	file, err := os.CreateTemp(h.manager.dir, "job-"+h.record.job.ID+"-*")
+	if err != nil {
+		return nil, err
+	}
+
+	h.manager.mutex.Lock()
+	h.record.artifact = &jobArtifact{path: file.Name(), filename: filename, contentType: contentType}
+	h.manager.mutex.Unlock()
+	return file, nil
+}
+
+func newJobID() string {
+	buf := make([]byte, 6)
+	if _, err := rand.Read(buf); err != nil {
+		return fmt.Sprintf("job-%d", time.Now().UnixNano())
+	}
+	return "job-" + hex.EncodeToString(buf)
+}
@@ -0,0 +1,243 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+)
+
+var (
+	ErrLinkNotFound     = errors.New("enlace no encontrado")
+	ErrLinkExpired      = errors.New("el enlace expiró")
+	ErrForeignLinkURL   = errors.New("solo se pueden acortar enlaces de este sitio")
+	ErrLinkTarget       = errors.New("indica cat_id o url, no ambos")
+	errLinkCodeConflict = errors.New("código de enlace repetido")
+)
+
+const (
+	linkCodeLength   = 7
+	linkCodeAlphabet = "abcdefghijkmnopqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+)
+
+// * Persistencia opcional de enlaces cortos
+type LinkStore interface {
+	LoadLinks(ctx context.Context) ([]m.ShortLink, error)
+	SaveLink(ctx context.Context, link m.ShortLink) error
+	RecordClick(ctx context.Context, code string, at time.Time) error
+}
+
+// * Enlaces cortos /l/:code. Los de perfil son permanentes y uno por gato (ProfileLink los
+// * reutiliza), así los clics de QR, webhooks y página de compartir suman en el mismo lugar
+type LinkService struct {
+	baseURL  string
+	store    LinkStore
+	links    map[string]*m.ShortLink
+	profiles map[string]string
+	mutex    sync.Mutex
+}
+
+// * store puede ser nil: entonces los enlaces viven solo en memoria
+func NewLinkService(baseURL string, store LinkStore) *LinkService {
+	return &LinkService{
+		baseURL:  strings.TrimRight(baseURL, "/"),
+		store:    store,
+		links:    make(map[string]*m.ShortLink),
+		profiles: make(map[string]string),
+	}
+}
+
+func (s *LinkService) Restore(ctx context.Context) error {
+	if s.store == nil {
+		return nil
+	}
+	links, err := s.store.LoadLinks(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	for i := range links {
+		// * Los enlaces de perfil guardados antes de llevar el refugio en la URL
+		if link := &links[i]; link.CatID > 0 && link.TargetURL == fmt.Sprintf("%s/share/%d", s.baseURL, link.CatID) {
+			link.TargetURL = s.ShareURL(link.TenantID, link.CatID)
+		}
+		s.index(&links[i])
+	}
+	s.mutex.Unlock()
+
+	log.Printf("🔗 Enlaces cortos restaurados: %d", len(links))
+	return nil
+}
+
+// ! Llamar con el mutex tomado
+func (s *LinkService) index(link *m.ShortLink) {
+	link.ShortURL = s.baseURL + "/l/" + link.Code
+	s.links[link.Code] = link
+	if link.CatID > 0 && link.ExpiresAt == nil {
+		s.profiles[profileLinkKey(link.TenantID, link.CatID)] = link.Code
+	}
+}
+
+func profileLinkKey(tenantID string, catID int) string {
+	return fmt.Sprintf("%s|%d", tenantID, catID)
+}
+
+func (s *LinkService) ShareURL(tenantID string, catID int) string {
+	return TenantURL(s.baseURL, fmt.Sprintf("/share/%d", catID), tenantID)
+}
+
+// * URL absoluta de path en el refugio tenantID. El refugio va en ?tenant= porque quien
+// * abre el enlace no manda X-Tenant; el refugio por defecto no lo necesita
+func TenantURL(baseURL, path, tenantID string) string {
+	target := strings.TrimRight(baseURL, "/") + path
+	if tenantID == "" || tenantID == DefaultTenantID {
+		return target
+	}
+	return target + "?" + TenantQueryParam + "=" + url.QueryEscape(tenantID)
+}
+
+// * Enlace permanente del perfil; se crea la primera vez que se pide
+func (s *LinkService) ProfileLink(ctx context.Context, tenantID string, catID int) (m.ShortLink, error) {
+	s.mutex.Lock()
+	if code, ok := s.profiles[profileLinkKey(tenantID, catID)]; ok {
+		link := *s.links[code]
+		s.mutex.Unlock()
+		return link, nil
+	}
+	s.mutex.Unlock()
+
+	return s.create(ctx, tenantID, catID, s.ShareURL(tenantID, catID), nil)
+}
+
+// * Enlace a medida: con expiración crea uno nuevo; sin ella, para un gato, reutiliza el del perfil
+func (s *LinkService) Create(ctx context.Context, tenantID string, req m.ShortLinkRequest) (m.ShortLink, error) {
+	if (req.CatID > 0) == (req.URL != "") {
+		return m.ShortLink{}, ErrLinkTarget
+	}
+
+	var expiresAt *time.Time
+	if req.ExpiresInHours > 0 {
+		at := time.Now().Add(time.Duration(req.ExpiresInHours) * time.Hour)
+		expiresAt = &at
+	}
+
+	if req.CatID > 0 {
+		if expiresAt == nil {
+			return s.ProfileLink(ctx, tenantID, req.CatID)
+		}
+		return s.create(ctx, tenantID, req.CatID, s.ShareURL(tenantID, req.CatID), expiresAt)
+	}
+
+	// ! Solo URLs propias: si no, /l/ sería un redirect abierto
+	target, err := url.Parse(req.URL)
+	base, _ := url.Parse(s.baseURL)
+	if err != nil || base == nil || !strings.EqualFold(target.Scheme, base.Scheme) || !strings.EqualFold(target.Host, base.Host) {
+		return m.ShortLink{}, ErrForeignLinkURL
+	}
+	return s.create(ctx, tenantID, 0, target.String(), expiresAt)
+}
+
+func (s *LinkService) create(ctx context.Context, tenantID string, catID int, target string, expiresAt *time.Time) (m.ShortLink, error) {
+	for attempt := 0; attempt < 5; attempt++ {
+		link := &m.ShortLink{
+			Code:      newLinkCode(),
+			TargetURL: target,
+			TenantID:  tenantID,
+			CatID:     catID,
+			CreatedAt: time.Now(),
+			ExpiresAt: expiresAt,
+		}
+
+		s.mutex.Lock()
+		if _, taken := s.links[link.Code]; taken {
+			s.mutex.Unlock()
+			continue
+		}
+		// * Otra petición pudo crear el enlace del perfil mientras tanto
+		if catID > 0 && expiresAt == nil {
+			if code, ok := s.profiles[profileLinkKey(tenantID, catID)]; ok {
+				existing := *s.links[code]
+				s.mutex.Unlock()
+				return existing, nil
+			}
+		}
+		s.index(link)
+		created := *link
+		s.mutex.Unlock()
+
+		if s.store != nil {
+			if err := s.store.SaveLink(ctx, created); err != nil {
+				s.mutex.Lock()
+				delete(s.links, link.Code)
+				if catID > 0 && expiresAt == nil {
+					delete(s.profiles, profileLinkKey(tenantID, catID))
+				}
+				s.mutex.Unlock()
+				return m.ShortLink{}, err
+			}
+		}
+		return created, nil
+	}
+	return m.ShortLink{}, errLinkCodeConflict
+}
+
+func (s *LinkService) Get(code string) (m.ShortLink, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	link, ok := s.links[code]
+	if !ok {
+		return m.ShortLink{}, ErrLinkNotFound
+	}
+	return *link, nil
+}
+
+// * Cuenta el clic y devuelve el destino; los expirados no cuentan
+func (s *LinkService) Resolve(ctx context.Context, code string) (string, error) {
+	now := time.Now()
+
+	s.mutex.Lock()
+	link, ok := s.links[code]
+	if !ok {
+		s.mutex.Unlock()
+		return "", ErrLinkNotFound
+	}
+	if link.ExpiresAt != nil && now.After(*link.ExpiresAt) {
+		s.mutex.Unlock()
+		return "", ErrLinkExpired
+	}
+	link.Clicks++
+	link.LastClickAt = &now
+	target := link.TargetURL
+	s.mutex.Unlock()
+
+	// * Un fallo al contar no debe romper la redirección
+	if s.store != nil {
+		if err := s.store.RecordClick(ctx, code, now); err != nil {
+			log.Printf("⚠️ Error guardando clic de /l/%s: %v", code, err)
+		}
+	}
+	return target, nil
+}
+
+func newLinkCode() string {
+	code := make([]byte, linkCodeLength)
+	max := big.NewInt(int64(len(linkCodeAlphabet)))
+	for i := range code {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			n = big.NewInt(time.Now().UnixNano() % int64(len(linkCodeAlphabet)))
+		}
+		code[i] = linkCodeAlphabet[n.Int64()]
+	}
+	return string(code)
+}
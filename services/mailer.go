@@ -0,0 +1,91 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"mime"
+	"net/mail"
+	"net/smtp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+type Email struct {
+	To      string
+	Subject string
+	HTML    string
+	// * Cabeceras extra (List-Unsubscribe, etc.)
+	Headers map[string]string
+}
+
+type Mailer interface {
+	Send(ctx context.Context, email Email) error
+}
+
+// * SMTP con STARTTLS si el servidor lo ofrece (net/smtp lo negocia solo)
+type SMTPMailer struct {
+	addr string
+	auth smtp.Auth
+	from *mail.Address
+}
+
+func NewSMTPMailer(host string, port int, username, password, from string) (*SMTPMailer, error) {
+	sender, err := mail.ParseAddress(from)
+	if err != nil {
+		return nil, fmt.Errorf("remitente inválido %q: %w", from, err)
+	}
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+	return &SMTPMailer{
+		addr: host + ":" + strconv.Itoa(port),
+		auth: auth,
+		from: sender,
+	}, nil
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, email Email) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return smtp.SendMail(m.addr, m.auth, m.from.Address, []string{email.To}, buildMessage(m.from.String(), email))
+}
+
+func buildMessage(from string, email Email) []byte {
+	headers := map[string]string{
+		"From":         from,
+		"To":           email.To,
+		"Subject":      mime.QEncoding.Encode("utf-8", email.Subject),
+		"Date":         time.Now().Format(time.RFC1123Z),
+		"MIME-Version": "1.0",
+		"Content-Type": "text/html; charset=UTF-8",
+	}
+	for key, value := range email.Headers {
+		headers[key] = value
+	}
+	keys := make([]string, 0, len(headers))
+	for key := range headers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var msg bytes.Buffer
+	for _, key := range keys {
+		fmt.Fprintf(&msg, "%s: %s\r\n", key, headers[key])
+	}
+	msg.WriteString("\r\n")
+	msg.WriteString(email.HTML)
+	return msg.Bytes()
+}
+
+// * Sin SMTP_HOST: los correos solo se registran en el log (desarrollo)
+type LogMailer struct{}
+
+func (LogMailer) Send(ctx context.Context, email Email) error {
+	log.Printf("📧 [sin SMTP] Correo a %s: %s (%d bytes)", email.To, email.Subject, len(email.HTML))
+	return nil
+}
@@ -0,0 +1,186 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+)
+
+var (
+	ErrMatchArchived    = errors.New("el match está archivado: reactívalo para volver a chatear")
+	ErrMatchNotArchived = errors.New("el match no está archivado")
+)
+
+// * Solo los matches mutuos pueden quedar "ghosteados": los del refugio no tienen chat
+func (s *SwipeService) activeMutualMatches() []m.Match {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	var matches []m.Match
+	for _, userMatches := range s.matches {
+		for _, match := range userMatches {
+			if match.Mutual && match.ArchivedAt == nil {
+				matches = append(matches, match)
+			}
+		}
+	}
+	return matches
+}
+
+func (s *SwipeService) ArchivedMatches(userID string) []m.Match {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	archived := make([]m.Match, 0)
+	for _, match := range s.matches[userID] {
+		if match.ArchivedAt != nil {
+			archived = append(archived, match)
+		}
+	}
+	return archived
+}
+
+// * Reactiva los dos lados del par: el chat es compartido
+func (s *SwipeService) ReviveMatch(ctx context.Context, userID, matchID string) (m.Match, error) {
+	s.mutex.RLock()
+	match, ok := s.findMatch(userID, matchID)
+	s.mutex.RUnlock()
+	if !ok {
+		return m.Match{}, fmt.Errorf("%w: %s", ErrMatchNotFound, matchID)
+	}
+	if match.ArchivedAt == nil {
+		return m.Match{}, ErrMatchNotArchived
+	}
+
+	now := time.Now()
+	revive := func(match *m.Match) {
+		match.ArchivedAt = nil
+		match.NudgedAt = nil
+		match.RevivedAt = &now
+	}
+	revived, err := s.updateMatch(ctx, userID, matchID, revive)
+	if err != nil {
+		return m.Match{}, err
+	}
+	if counterpart, ok := s.counterpartMatch(match); ok {
+		if _, err := s.updateMatch(ctx, counterpart.UserID, counterpart.ID, revive); err != nil {
+			return m.Match{}, err
+		}
+	}
+
+	log.Printf("🔁 Match %s reactivado por %s", matchID, userID)
+	return revived, nil
+}
+
+// * Primero el store y después la memoria, igual que Record
+func (s *SwipeService) updateMatch(ctx context.Context, userID, matchID string, mutate func(match *m.Match)) (m.Match, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for i := range s.matches[userID] {
+		if s.matches[userID][i].ID != matchID {
+			continue
+		}
+		updated := s.matches[userID][i]
+		mutate(&updated)
+		if s.store != nil {
+			if err := s.store.UpdateMatch(ctx, updated); err != nil {
+				return m.Match{}, err
+			}
+		}
+		s.matches[userID][i] = updated
+		return updated, nil
+	}
+	return m.Match{}, fmt.Errorf("%w: %s", ErrMatchNotFound, matchID)
+}
+
+// ! Requiere s.mutex tomado (lectura basta)
+func (s *SwipeService) findMatch(userID, matchID string) (m.Match, bool) {
+	for _, match := range s.matches[userID] {
+		if match.ID == matchID {
+			return match, true
+		}
+	}
+	return m.Match{}, false
+}
+
+// * El match del otro dueño tiene los gatos al revés
+func (s *SwipeService) counterpartMatch(match m.Match) (m.Match, bool) {
+	if !match.Mutual {
+		return m.Match{}, false
+	}
+	cat, err := s.catService.AdminGetCatProfile(match.CatID)
+	if err != nil {
+		return m.Match{}, false
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	for _, other := range s.matches[cat.OwnerID] {
+		if other.Mutual && other.CatID == match.WithCatID && other.WithCatID == match.CatID {
+			return other, true
+		}
+	}
+	return m.Match{}, false
+}
+
+// * Matches mutuos sin mensajes: tras nudgeAfter se avisa por el WebSocket y tras
+// * archiveAfter se archivan. El reloj arranca al crear el match o al reactivarlo
+func (s *ChatService) ExpireStaleMatches(ctx context.Context, nudgeAfter, archiveAfter time.Duration) error {
+	now := time.Now()
+	var nudged, archived int
+
+	for _, match := range s.swipes.activeMutualMatches() {
+		since := match.CreatedAt
+		if match.RevivedAt != nil {
+			since = *match.RevivedAt
+		}
+		idle := now.Sub(since)
+		if idle < nudgeAfter || s.hasMessages(match) {
+			continue
+		}
+
+		if archiveAfter > 0 && idle >= archiveAfter {
+			if _, err := s.swipes.updateMatch(ctx, match.UserID, match.ID, func(match *m.Match) {
+				match.ArchivedAt = &now
+			}); err != nil {
+				return err
+			}
+			archived++
+			continue
+		}
+
+		if match.NudgedAt != nil {
+			continue
+		}
+		updated, err := s.swipes.updateMatch(ctx, match.UserID, match.ID, func(match *m.Match) {
+			match.NudgedAt = &now
+		})
+		if err != nil {
+			return err
+		}
+		s.publish([]string{match.UserID}, m.ChatEvent{
+			Type:    m.ChatEventNudge,
+			MatchID: updated.ID,
+			Text:    fmt.Sprintf("¡%s sigue esperando tu primer mensaje! 🐾", updated.CatName),
+			At:      now,
+		})
+		nudged++
+	}
+
+	if nudged > 0 || archived > 0 {
+		log.Printf("👻 Matches sin mensajes: %d avisados, %d archivados", nudged, archived)
+	}
+	return nil
+}
+
+func (s *ChatService) hasMessages(match m.Match) bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	conv, ok := s.conversations[conversationID(match)]
+	return ok && len(conv.messages) > 0
+}
@@ -0,0 +1,115 @@
+package services
+
+import (
+	"fmt"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+)
+
+// * Máquina de estados de dos lados entre gatos de usuarios:
+// * (nada) --like--> pending --like del otro--> matched
+// * cualquier pass deja el par en declined; matched y declined son finales
+type MatchService struct {
+	pairs map[[2]int]m.PairState
+}
+
+func NewMatchService() *MatchService {
+	return &MatchService{pairs: make(map[[2]int]m.PairState)}
+}
+
+func pairKey(a, b int) [2]int {
+	if a > b {
+		a, b = b, a
+	}
+	return [2]int{a, b}
+}
+
+// * Calcula el siguiente estado sin aplicarlo, para poder persistir antes de confirmar
+// ! Se usa con el mutex de SwipeService tomado
+func (s *MatchService) next(fromCatID, toCatID int, like bool) m.PairState {
+	current, ok := s.pairs[pairKey(fromCatID, toCatID)]
+	if !ok {
+		if !like {
+			return m.PairState{Status: m.PairDeclined}
+		}
+		return m.PairState{Status: m.PairPending, LikedBy: fromCatID}
+	}
+
+	if current.Status != m.PairPending || current.LikedBy == fromCatID {
+		return current
+	}
+	if !like {
+		return m.PairState{Status: m.PairDeclined}
+	}
+	return m.PairState{Status: m.PairMatched}
+}
+
+func (s *MatchService) set(fromCatID, toCatID int, state m.PairState) {
+	s.pairs[pairKey(fromCatID, toCatID)] = state
+}
+
+func (s *MatchService) State(catA, catB int) (m.PairState, bool) {
+	state, ok := s.pairs[pairKey(catA, catB)]
+	return state, ok
+}
+
+// * Solo los matches mutuos tienen chat: del otro lado hay un dueño, no un refugio
+func (s *SwipeService) Conversation(userID, matchID string) (m.Conversation, error) {
+	s.mutex.RLock()
+	var match *m.Match
+	for _, stored := range s.matches[userID] {
+		if stored.ID == matchID {
+			match = &stored
+			break
+		}
+	}
+	s.mutex.RUnlock()
+
+	if match == nil {
+		return m.Conversation{}, fmt.Errorf("%w: %s", ErrMatchNotFound, matchID)
+	}
+	if !match.Mutual {
+		return m.Conversation{}, ErrChatUnavailable
+	}
+	if match.ArchivedAt != nil {
+		return m.Conversation{}, ErrMatchArchived
+	}
+
+	cat, err := s.catService.AdminGetCatProfile(match.CatID)
+	if err != nil {
+		return m.Conversation{}, err
+	}
+	return m.Conversation{
+		ID:           conversationID(*match),
+		Participants: []string{userID, cat.OwnerID},
+	}, nil
+}
+
+func conversationID(match m.Match) string {
+	key := pairKey(match.CatID, match.WithCatID)
+	return fmt.Sprintf("c-%d-%d", key[0], key[1])
+}
+
+// * Dueños con los que userID tiene al menos un match mutuo
+func (s *SwipeService) MutualContacts(userID string) []string {
+	s.mutex.RLock()
+	var catIDs []int
+	for _, match := range s.matches[userID] {
+		if match.Mutual {
+			catIDs = append(catIDs, match.CatID)
+		}
+	}
+	s.mutex.RUnlock()
+
+	seen := make(map[string]bool)
+	contacts := make([]string, 0, len(catIDs))
+	for _, catID := range catIDs {
+		cat, err := s.catService.AdminGetCatProfile(catID)
+		if err != nil || cat.OwnerID == "" || seen[cat.OwnerID] {
+			continue
+		}
+		seen[cat.OwnerID] = true
+		contacts = append(contacts, cat.OwnerID)
+	}
+	return contacts
+}
@@ -0,0 +1,135 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"fmt"
+	"hash/fnv"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+)
+
+const (
+	OfflineCatsPath = "/offline/cats/"
+	offlineCatSize  = 400
+)
+
+// * Gatos dibujados por el propio servidor (un PNG fijo por semilla) para trabajar sin
+// * red; es el proveedor por defecto con APP_ENV=dev. Las URLs apuntan a BaseURL, así
+// * el navegador las pide al servidor y el transporte las resuelve sin salir a la red
+type offlineProvider struct {
+	base *url.URL
+}
+
+func newOfflineProvider(baseURL string) (*offlineProvider, error) {
+	base, err := url.Parse(baseURL)
+	if err != nil || base.Host == "" {
+		return nil, fmt.Errorf("el proveedor offline necesita una URL base válida: %q", baseURL)
+	}
+	return &offlineProvider{base: base}, nil
+}
+
+func (p *offlineProvider) Name() string { return "offline" }
+
+func (p *offlineProvider) Host() string { return p.base.Host }
+
+func (p *offlineProvider) Remote() bool { return false }
+
+// * Ignora tags, textos y filtros: siempre es un gato genérico
+func (p *offlineProvider) Supports(opts m.ImageOptions) bool { return true }
+
+func (p *offlineProvider) NewURL(ctx context.Context, opts m.ImageOptions) (m.CatURL, error) {
+	seed := make([]byte, 6)
+	rand.Read(seed)
+	name := fmt.Sprintf("%x", seed)
+
+	return m.CatURL{
+		URL:       p.base.JoinPath(OfflineCatsPath, name+".png").String(),
+		ID:        "offline-" + name,
+		Timestamp: time.Now().UnixNano(),
+	}, nil
+}
+
+func (p *offlineProvider) owns(u *url.URL) bool {
+	return u.Host == p.base.Host && strings.HasPrefix(u.Path, OfflineCatsPath)
+}
+
+// * Respuesta en proceso para el validador, el proxy y las sondas
+func (p *offlineProvider) respond(req *http.Request) *http.Response {
+	data := OfflineCatPNG(strings.TrimSuffix(strings.TrimPrefix(req.URL.Path, OfflineCatsPath), ".png"))
+	body := io.NopCloser(bytes.NewReader(data))
+	if req.Method == http.MethodHead {
+		body = http.NoBody
+	}
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": {"image/png"}, "Content-Length": {strconv.Itoa(len(data))}},
+		Body:          body,
+		ContentLength: int64(len(data)),
+		Request:       req,
+	}
+}
+
+// * Cabeza, orejas y ojos con colores sacados de la semilla: misma semilla, mismo gato
+func OfflineCatPNG(seed string) []byte {
+	hash := fnv.New64a()
+	hash.Write([]byte(seed))
+	bits := hash.Sum64()
+
+	hue := func(shift uint) color.RGBA {
+		v := bits >> shift
+		return color.RGBA{R: uint8(80 + v%150), G: uint8(60 + (v>>8)%140), B: uint8(40 + (v>>16)%120), A: 255}
+	}
+	background := color.RGBA{R: 245, G: 240, B: 230, A: 255}
+	fur, eyes := hue(0), hue(24)
+	eyes.G = 200
+
+	img := image.NewRGBA(image.Rect(0, 0, offlineCatSize, offlineCatSize))
+	const cx, cy, radius = 200.0, 230.0, 120.0
+	for y := 0; y < offlineCatSize; y++ {
+		for x := 0; x < offlineCatSize; x++ {
+			fx, fy := float64(x), float64(y)
+			pixel := background
+			switch {
+			case math.Hypot(fx-150, fy-190) < 18, math.Hypot(fx-250, fy-190) < 18:
+				pixel = eyes
+			case math.Hypot(fx-150, fy-190) < 24, math.Hypot(fx-250, fy-190) < 24:
+				pixel = color.RGBA{A: 255}
+			case math.Hypot(fx-cx, fy-cy) < radius:
+				pixel = fur
+			case inEar(fx, fy, 100, 190), inEar(fx, fy, 210, 300):
+				pixel = fur
+			}
+			img.SetRGBA(x, y, pixel)
+		}
+	}
+
+	var buf bytes.Buffer
+	png.Encode(&buf, img)
+	return buf.Bytes()
+}
+
+// * Triángulo con la base sobre la cabeza (y=150) entre left y right y la punta arriba
+func inEar(x, y, left, right float64) bool {
+	if y < 60 || y > 150 {
+		return false
+	}
+	middle := (left + right) / 2
+	halfWidth := (right - left) / 2 * (y - 60) / 90
+	return math.Abs(x-middle) < halfWidth
+}
@@ -0,0 +1,72 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// * Cliente mínimo para cualquier API compatible con /chat/completions de OpenAI
+type OpenAIClient struct {
+	url    string
+	apiKey string
+	model  string
+	client *http.Client
+}
+
+func NewOpenAIClient(url, apiKey, model string) *OpenAIClient {
+	return &OpenAIClient{
+		url:    url,
+		apiKey: apiKey,
+		model:  model,
+		client: &http.Client{},
+	}
+}
+
+func (o *OpenAIClient) Complete(ctx context.Context, prompt string) (string, error) {
+	body, err := json.Marshal(map[string]any{
+		"model": o.model,
+		"messages": []map[string]string{
+			{"role": "user", "content": prompt},
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.url, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if o.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+o.apiKey)
+	}
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return "", fmt.Errorf("el LLM respondió %d", resp.StatusCode)
+	}
+
+	var completion struct {
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&completion); err != nil {
+		return "", fmt.Errorf("respuesta del LLM inválida: %w", err)
+	}
+	if len(completion.Choices) == 0 {
+		return "", fmt.Errorf("el LLM no devolvió respuestas")
+	}
+	return completion.Choices[0].Message.Content, nil
+}
@@ -0,0 +1,131 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+)
+
+// * No es culpa del proveedor: no debe contar como fallo ni bajar su peso
+var ErrOutboundBudget = errors.New("presupuesto de peticiones al proveedor agotado")
+
+// * Espaciado uniforme (sin ráfagas): cada petición reserva el siguiente hueco libre y
+// * espera hasta él. Con la cola llena se rechaza en el acto en lugar de acumular
+type outboundBudget struct {
+	provider  string
+	perSecond int
+	interval  time.Duration
+	maxQueue  int
+
+	next     time.Time
+	waiting  int
+	allowed  int64
+	queued   int64
+	overflow int64
+	waitSum  time.Duration
+	mutex    sync.Mutex
+}
+
+func newOutboundBudget(provider string, perSecond, maxQueue int) *outboundBudget {
+	return &outboundBudget{
+		provider:  provider,
+		perSecond: perSecond,
+		interval:  time.Second / time.Duration(perSecond),
+		maxQueue:  maxQueue,
+	}
+}
+
+func (b *outboundBudget) wait(ctx context.Context) error {
+	b.mutex.Lock()
+	now := time.Now()
+	if b.next.Before(now) {
+		b.next = now
+	}
+	delay := b.next.Sub(now)
+	if delay > 0 && b.waiting >= b.maxQueue {
+		b.overflow++
+		b.mutex.Unlock()
+		return fmt.Errorf("%w (%s)", ErrOutboundBudget, b.provider)
+	}
+	b.next = b.next.Add(b.interval)
+	b.allowed++
+	if delay > 0 {
+		b.waiting++
+		b.queued++
+		b.waitSum += delay
+	}
+	b.mutex.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	defer func() {
+		b.mutex.Lock()
+		b.waiting--
+		b.mutex.Unlock()
+	}()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func (b *outboundBudget) stats() m.OutboundStats {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	stats := m.OutboundStats{
+		Provider:       b.provider,
+		LimitPerSecond: b.perSecond,
+		Allowed:        b.allowed,
+		Queued:         b.queued,
+		Overflow:       b.overflow,
+		QueueDepth:     b.waiting,
+	}
+	if b.queued > 0 {
+		stats.AvgWaitMs = float64(b.waitSum.Microseconds()) / 1000 / float64(b.queued)
+	}
+	return stats
+}
+
+// * Límites por proveedor con el formato de IMAGE_PROVIDERS ("cataas=10,thecatapi=5")
+func (t *ProviderTransport) SetBudgets(limits []ProviderWeight, maxQueue int) error {
+	for _, limit := range limits {
+		hosts, ok := providerHosts[limit.Name]
+		if !ok {
+			return fmt.Errorf("proveedor desconocido: %s", limit.Name)
+		}
+		if limit.Weight <= 0 {
+			continue
+		}
+		budget := newOutboundBudget(limit.Name, limit.Weight, maxQueue)
+		t.budgetList = append(t.budgetList, budget)
+		// * API y CDN comparten presupuesto: para el proveedor es el mismo cliente
+		for _, host := range hosts {
+			t.budgets[host] = budget
+		}
+	}
+	return nil
+}
+
+func (t *ProviderTransport) OutboundStats() []m.OutboundStats {
+	if t == nil {
+		return nil
+	}
+	stats := make([]m.OutboundStats, 0, len(t.budgetList))
+	for _, budget := range t.budgetList {
+		stats = append(stats, budget.stats())
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Provider < stats[j].Provider })
+	return stats
+}
@@ -0,0 +1,206 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"html/template"
+	"log"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+)
+
+var (
+	ErrEmailRequired        = errors.New("indica un email para confirmar el registro")
+	ErrInvalidVerifyToken   = errors.New("enlace de confirmación inválido o vencido")
+	ErrVerificationNotSent  = errors.New("no se pudo enviar el correo de confirmación")
+	errVerificationFinished = errors.New("el gato ya no está pendiente")
+	errNothingPending       = errors.New("no hay gatos pendientes que borrar")
+)
+
+// * Doble opt-in para gatos de usuarios: el gato queda pending (fuera del mazo y de los
+// * listados) hasta que el dueño abre el enlace que le llega por correo. Con review el gato
+// * confirmado pasa a draft y lo publica un admin. Los pendientes que no se confirman en
+// * ttl se borran. Los tokens viven en memoria y el enlace no lleva tenant, así que el
+// * servicio es global (como DigestService)
+type OwnedCatVerifier struct {
+	tenants  *TenantRegistry
+	mailer   Mailer
+	template *template.Template
+	baseURL  string
+	ttl      time.Duration
+	review   bool
+	pending  map[string]pendingCat
+	mutex    sync.Mutex
+}
+
+type pendingCat struct {
+	tenantID  string
+	catID     int
+	expiresAt time.Time
+}
+
+type verifyEmail struct {
+	Name      string
+	Breed     string
+	URL       string
+	ExpiresAt string
+}
+
+func NewOwnedCatVerifier(tenants *TenantRegistry, mailer Mailer, tmpl *template.Template, baseURL string, ttl time.Duration, review bool) *OwnedCatVerifier {
+	return &OwnedCatVerifier{
+		tenants:  tenants,
+		mailer:   mailer,
+		template: tmpl,
+		baseURL:  strings.TrimRight(baseURL, "/"),
+		ttl:      ttl,
+		review:   review,
+		pending:  make(map[string]pendingCat),
+	}
+}
+
+// * Registra el gato como pending y manda el enlace; si el correo falla el gato no queda
+func (v *OwnedCatVerifier) Submit(ctx context.Context, tenantID, ownerID string, req m.OwnedCatRequest, force bool) (*m.CatProfile, error) {
+	if req.Email == "" {
+		return nil, ErrEmailRequired
+	}
+	tenant, ok := v.tenants.Get(tenantID)
+	if !ok {
+		return nil, fmt.Errorf("tenant desconocido: %s", tenantID)
+	}
+
+	cat, err := tenant.Cats.AddOwnedCat(ownerID, req, force, m.StatusPending)
+	if err != nil {
+		return nil, err
+	}
+
+	token := newUnsubscribeToken() + newUnsubscribeToken()
+	expiresAt := time.Now().Add(v.ttl)
+	if err := v.send(ctx, req.Email, *cat, token, expiresAt); err != nil {
+		log.Printf("⚠️ Error enviando confirmación del gato %d: %v", cat.ID, err)
+		tenant.Cats.DiscardPendingCat(cat.ID)
+		return nil, fmt.Errorf("%w: %v", ErrVerificationNotSent, err)
+	}
+
+	v.mutex.Lock()
+	v.pending[token] = pendingCat{tenantID: tenantID, catID: cat.ID, expiresAt: expiresAt}
+	v.mutex.Unlock()
+
+	log.Printf("✉️ Gato %d pendiente de confirmación por %s", cat.ID, ownerID)
+	return cat, nil
+}
+
+// * Desde el enlace del correo: no requiere X-User-ID. Cada token sirve una vez
+func (v *OwnedCatVerifier) Confirm(token string) (*m.CatProfile, error) {
+	v.mutex.Lock()
+	entry, ok := v.pending[token]
+	delete(v.pending, token)
+	v.mutex.Unlock()
+
+	if !ok || time.Now().After(entry.expiresAt) {
+		return nil, ErrInvalidVerifyToken
+	}
+	tenant, ok := v.tenants.Get(entry.tenantID)
+	if !ok {
+		return nil, ErrInvalidVerifyToken
+	}
+
+	status := m.StatusActive
+	if v.review {
+		status = m.StatusDraft
+	}
+	cat, err := tenant.Cats.TransitionCatProfile(entry.catID, status)
+	if errors.Is(err, ErrInvalidTransition) || errors.Is(err, ErrProfileNotFound) {
+		// * Borrado o movido por un admin mientras esperaba
+		return nil, fmt.Errorf("%w: %w", ErrInvalidVerifyToken, errVerificationFinished)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("✅ Gato %d confirmado por su dueño (%s)", cat.ID, status)
+	return cat, nil
+}
+
+// * Borra los pendientes sin confirmar pasado ttl, también los de antes de un reinicio
+// * (cuyos tokens ya no existen)
+func (v *OwnedCatVerifier) ExpirePending(ctx context.Context) error {
+	now := time.Now()
+	v.mutex.Lock()
+	for token, entry := range v.pending {
+		if now.After(entry.expiresAt) {
+			delete(v.pending, token)
+		}
+	}
+	v.mutex.Unlock()
+
+	for _, tenant := range v.tenants.All() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if discarded := tenant.Cats.DiscardPendingCats(now.Add(-v.ttl)); discarded > 0 {
+			log.Printf("🧹 %d gatos sin confirmar borrados (%s)", discarded, tenant.ID)
+		}
+	}
+	return nil
+}
+
+func (v *OwnedCatVerifier) send(ctx context.Context, to string, cat m.CatProfile, token string, expiresAt time.Time) error {
+	link := v.baseURL + "/api/me/cats/verify?token=" + url.QueryEscape(token)
+	var body bytes.Buffer
+	err := v.template.Execute(&body, verifyEmail{
+		Name:      cat.Name,
+		Breed:     cat.Breed,
+		URL:       link,
+		ExpiresAt: expiresAt.Format("02/01/2006 15:04"),
+	})
+	if err != nil {
+		return err
+	}
+	// * Sin SMTP (desarrollo) el enlace solo se puede sacar del log
+	if _, ok := v.mailer.(LogMailer); ok {
+		log.Printf("📧 [sin SMTP] Confirmación del gato %d: %s", cat.ID, link)
+	}
+	return v.mailer.Send(ctx, Email{
+		To:      to,
+		Subject: fmt.Sprintf("Confirma a %s en Meownder", cat.Name),
+		HTML:    body.String(),
+	})
+}
+
+// * Quita un pendiente recién creado (el correo no salió)
+func (s *CatService) DiscardPendingCat(id int) {
+	s.discardPending(func(cat m.CatProfile) bool { return cat.ID == id })
+}
+
+// * Pendientes registrados antes de cutoff; devuelve cuántos se borraron
+func (s *CatService) DiscardPendingCats(cutoff time.Time) int {
+	return s.discardPending(func(cat m.CatProfile) bool { return cat.UpdatedAt.Before(cutoff) })
+}
+
+func (s *CatService) discardPending(match func(cat m.CatProfile) bool) int {
+	discarded := 0
+	s.rebuildProfiles(func(profiles []m.CatProfile) ([]m.CatProfile, error) {
+		kept := profiles[:0]
+		for _, cat := range profiles {
+			if cat.Status == m.StatusPending && match(cat) {
+				discarded++
+				continue
+			}
+			kept = append(kept, cat)
+		}
+		if discarded == 0 {
+			return nil, errNothingPending
+		}
+		return kept, nil
+	})
+	if discarded > 0 {
+		s.notifyReload()
+	}
+	return discarded
+}
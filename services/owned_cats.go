@@ -0,0 +1,61 @@
+package services
+
+import (
+	"log"
+	"time"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+)
+
+// * Los gatos de usuarios comparten catálogo con los del refugio: mismo mazo, mismos ids.
+// * Un gato que parece uno ya cargado devuelve *DuplicateError, salvo con force. status es
+// * active, o pending si el dueño tiene que confirmar (ver OwnedCatVerifier)
+func (s *CatService) AddOwnedCat(ownerID string, req m.OwnedCatRequest, force bool, status string) (*m.CatProfile, error) {
+	now := time.Now()
+	cat := m.CatProfile{
+		Name:        req.Name,
+		Age:         req.Age,
+		Breed:       req.Breed,
+		Personality: req.Personality,
+		Hobbies:     append([]string(nil), req.Hobbies...),
+		Bio:         req.Bio,
+		Img:         s.generateCatURL().URL,
+		Status:      status,
+		OwnerID:     ownerID,
+		UpdatedAt:   now,
+		CreatedAt:   &now,
+	}
+
+	err := s.rebuildProfiles(func(profiles []m.CatProfile) ([]m.CatProfile, error) {
+		if duplicate, ok := newDuplicateIndex(profiles).find(cat); ok && !force {
+			return nil, &DuplicateError{Duplicate: duplicate}
+		}
+		nextID := 1
+		for _, existing := range profiles {
+			nextID = max(nextID, existing.ID+1)
+		}
+		cat.ID = nextID
+		return append(profiles, cat), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("🐾 Gato %d (%s) registrado por %s", cat.ID, cat.Name, ownerID)
+	s.notifyReload()
+	result := cat.Clone()
+	return &result, nil
+}
+
+// * Incluye pausados y borradores del dueño; nunca los borrados
+func (s *CatService) OwnedCats(ownerID string) []m.CatProfile {
+	snap := s.snapshot()
+
+	owned := make([]m.CatProfile, 0)
+	for _, cat := range snap.profiles {
+		if cat.OwnerID == ownerID && cat.DeletedAt == nil {
+			owned = append(owned, cat.Clone())
+		}
+	}
+	return owned
+}
@@ -0,0 +1,104 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"log"
+	"sort"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+)
+
+const (
+	paletteColors = 5
+	// * Se muestrea una grilla de a lo sumo paletteSamples x paletteSamples píxeles
+	paletteSamples = 64
+	// * Decodificar es caro: pocos trabajadores y una cola corta
+	paletteWorkers   = 2
+	paletteQueueSize = 32
+)
+
+// * Nunca bloquea la descarga: con la cola llena la imagen se queda sin paleta hasta que
+// * se vuelva a descargar; mientras tanto el perfil sale sin "palette"
+func (p *ImageProxy) queuePalette(image *m.ImageData) {
+	if _, ok := p.meta.Palette(image.SourceURL); ok {
+		return
+	}
+	select {
+	case p.paletteQueue <- image:
+	default:
+		log.Printf("⚠️ Cola de paletas llena, se omite %s", image.SourceURL)
+	}
+}
+
+func (p *ImageProxy) paletteWorker() {
+	for image := range p.paletteQueue {
+		p.computePalette(image)
+	}
+}
+
+// * Decodifica la imagen entera, así que corre en segundo plano en los trabajadores
+func (p *ImageProxy) computePalette(image *m.ImageData) {
+	if _, ok := p.meta.Palette(image.SourceURL); ok {
+		return
+	}
+	palette, err := ExtractPalette(image.Data, paletteColors)
+	if err != nil {
+		log.Printf("⚠️ Sin paleta para %s: %v", image.SourceURL, err)
+		return
+	}
+	p.meta.RecordPalette(image.SourceURL, palette)
+}
+
+// * Colores dominantes como "#rrggbb", del más al menos frecuente. Cuantiza a 4 bits
+// * por canal y devuelve el promedio real de cada grupo, no el centro del cubo
+func ExtractPalette(data []byte, size int) ([]string, error) {
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	bounds := img.Bounds()
+	step := max(1, max(bounds.Dx(), bounds.Dy())/paletteSamples)
+
+	type bucket struct {
+		r, g, b, n int
+	}
+	buckets := make(map[uint16]*bucket)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y += step {
+		for x := bounds.Min.X; x < bounds.Max.X; x += step {
+			r, g, b, a := img.At(x, y).RGBA()
+			// * Los píxeles casi transparentes no aportan color visible
+			if a < 0x8000 {
+				continue
+			}
+			r, g, b = r>>8, g>>8, b>>8
+			key := uint16(r>>4)<<8 | uint16(g>>4)<<4 | uint16(b>>4)
+			bk := buckets[key]
+			if bk == nil {
+				bk = &bucket{}
+				buckets[key] = bk
+			}
+			bk.r += int(r)
+			bk.g += int(g)
+			bk.b += int(b)
+			bk.n++
+		}
+	}
+	if len(buckets) == 0 {
+		return nil, fmt.Errorf("la imagen no tiene píxeles visibles")
+	}
+
+	ranked := make([]*bucket, 0, len(buckets))
+	for _, bk := range buckets {
+		ranked = append(ranked, bk)
+	}
+	sort.Slice(ranked, func(i, j int) bool { return ranked[i].n > ranked[j].n })
+
+	palette := make([]string, 0, size)
+	for _, bk := range ranked[:min(size, len(ranked))] {
+		palette = append(palette, fmt.Sprintf("#%02x%02x%02x", bk.r/bk.n, bk.g/bk.n, bk.b/bk.n))
+	}
+	return palette, nil
+}
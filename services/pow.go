@@ -0,0 +1,211 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log"
+	"math/bits"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+)
+
+const (
+	PowHeader       = "X-Proof-Of-Work"
+	ClientSigHeader = "X-Client-Signature"
+	clientSigSkew   = 5 * time.Minute
+	// * Los usados vencidos se barren cada usedPruneInterval, no en cada verificación
+	usedPruneInterval = 30 * time.Second
+	// ! Con el mapa lleno se rechaza: olvidar uno vigente permitiría repetirlo
+	maxUsedEntries = 100_000
+)
+
+var (
+	ErrPowMissing = errors.New("falta la prueba de trabajo: pide un desafío en /api/challenge")
+	ErrPowInvalid = errors.New("prueba de trabajo inválida")
+	ErrPowExpired = errors.New("el desafío expiró: pide uno nuevo")
+	ErrPowReused  = errors.New("el desafío ya se usó: pide uno nuevo")
+	ErrPowBusy    = errors.New("demasiadas verificaciones en curso: reintenta en un momento")
+)
+
+// * Desafíos sin estado firmados con HMAC ("vence.aleatorio.firma"): el servidor solo
+// * recuerda los ya resueltos hasta que vencen, para que cada uno sirva una sola vez.
+// * Los clientes propios (la app) pueden saltarse el desafío firmando la petición
+type ProofOfWork struct {
+	secret       []byte
+	clientSecret []byte
+	difficulty   int
+	ttl          time.Duration
+
+	used      map[string]time.Time
+	nextPrune time.Time
+	mutex     sync.Mutex
+}
+
+// * difficulty 0 apaga la verificación. Sin secret se genera uno al arrancar; sin
+// * clientSecret no se aceptan firmas de cliente
+func NewProofOfWork(secret, clientSecret string, difficulty int, ttl time.Duration) *ProofOfWork {
+	key := []byte(secret)
+	if secret == "" && difficulty > 0 {
+		key = make([]byte, 32)
+		rand.Read(key)
+		// ! Con varias réplicas cada una firmaría con su clave y rechazaría los desafíos ajenos
+		log.Printf("⚠️ ABUSE_POW_SECRET vacío: se usa una clave aleatoria (no sirve con varias réplicas)")
+	}
+	return &ProofOfWork{
+		secret:       key,
+		clientSecret: []byte(clientSecret),
+		difficulty:   difficulty,
+		ttl:          ttl,
+		used:         make(map[string]time.Time),
+	}
+}
+
+func (p *ProofOfWork) Enabled() bool {
+	return p.difficulty > 0
+}
+
+func (p *ProofOfWork) Challenge() m.PowChallenge {
+	expires := time.Now().Add(p.ttl).Truncate(time.Second)
+	nonce := make([]byte, 12)
+	rand.Read(nonce)
+
+	payload := strconv.FormatInt(expires.Unix(), 10) + "." + hex.EncodeToString(nonce)
+	return m.PowChallenge{
+		Challenge:  payload + "." + p.sign(payload),
+		Difficulty: p.difficulty,
+		Algorithm:  "sha256",
+		Header:     PowHeader,
+		ExpiresAt:  expires,
+	}
+}
+
+// * Una firma de cliente válida basta; si no, hace falta la prueba de trabajo. uri es la
+// * ruta con su query
+func (p *ProofOfWork) VerifyRequest(method, uri, proof, signature string, body []byte) error {
+	if !p.Enabled() {
+		return nil
+	}
+	if signature != "" && p.verifyClient(method, uri, signature, body, time.Now()) {
+		return nil
+	}
+	return p.Verify(proof)
+}
+
+// * signature = "unix.nonce.firma", firma = hex(hmac_sha256(clientSecret,
+// * "unix nonce MÉTODO /ruta?query hex(sha256(cuerpo))")). Cada nonce sirve una vez: se
+// * recuerda hasta que su marca sale de la ventana de clientSigSkew
+func (p *ProofOfWork) verifyClient(method, uri, signature string, body []byte, now time.Time) bool {
+	if len(p.clientSecret) == 0 {
+		return false
+	}
+	parts := strings.Split(signature, ".")
+	if len(parts) != 3 {
+		return false
+	}
+	stamp, nonce, sum := parts[0], parts[1], parts[2]
+	if len(nonce) < 16 || len(nonce) > 64 {
+		return false
+	}
+	unix, err := strconv.ParseInt(stamp, 10, 64)
+	if err != nil {
+		return false
+	}
+	signedAt := time.Unix(unix, 0)
+	if skew := now.Sub(signedAt); skew > clientSigSkew || skew < -clientSigSkew {
+		return false
+	}
+
+	bodyHash := sha256.Sum256(body)
+	mac := hmac.New(sha256.New, p.clientSecret)
+	mac.Write([]byte(stamp + " " + nonce + " " + method + " " + uri + " " + hex.EncodeToString(bodyHash[:])))
+	if !hmac.Equal([]byte(sum), []byte(hex.EncodeToString(mac.Sum(nil)))) {
+		return false
+	}
+	return p.markUsed("firma:"+nonce, signedAt.Add(clientSigSkew), now) == nil
+}
+
+// * proof = "desafío:nonce"
+func (p *ProofOfWork) Verify(proof string) error {
+	if proof == "" {
+		return ErrPowMissing
+	}
+
+	challenge, nonce, ok := strings.Cut(proof, ":")
+	if !ok || nonce == "" || len(nonce) > 64 {
+		return ErrPowInvalid
+	}
+	parts := strings.Split(challenge, ".")
+	if len(parts) != 3 {
+		return ErrPowInvalid
+	}
+	payload := parts[0] + "." + parts[1]
+	if !hmac.Equal([]byte(parts[2]), []byte(p.sign(payload))) {
+		return ErrPowInvalid
+	}
+	unix, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return ErrPowInvalid
+	}
+	expires := time.Unix(unix, 0)
+	now := time.Now()
+	if now.After(expires) {
+		return ErrPowExpired
+	}
+
+	sum := sha256.Sum256([]byte(challenge + ":" + nonce))
+	if leadingZeroBits(sum[:]) < p.difficulty {
+		return ErrPowInvalid
+	}
+
+	return p.markUsed(challenge, expires, now)
+}
+
+// * Registra key hasta until: ErrPowReused si ya estaba vigente (desafío resuelto o firma
+// * repetida) y ErrPowBusy si no entra otra hasta el próximo barrido
+func (p *ProofOfWork) markUsed(key string, until, now time.Time) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if !now.Before(p.nextPrune) {
+		for used, expires := range p.used {
+			if now.After(expires) {
+				delete(p.used, used)
+			}
+		}
+		p.nextPrune = now.Add(usedPruneInterval)
+	}
+	// * Uno vencido que el barrido todavía no alcanzó cuenta como libre
+	expires, ok := p.used[key]
+	if ok && !now.After(expires) {
+		return ErrPowReused
+	}
+	if !ok && len(p.used) >= maxUsedEntries {
+		return ErrPowBusy
+	}
+	p.used[key] = until
+	return nil
+}
+
+func (p *ProofOfWork) sign(payload string) string {
+	mac := hmac.New(sha256.New, p.secret)
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))[:32]
+}
+
+func leadingZeroBits(sum []byte) int {
+	zeros := 0
+	for _, b := range sum {
+		if b != 0 {
+			return zeros + bits.LeadingZeros8(b)
+		}
+		zeros += 8
+	}
+	return zeros
+}
@@ -0,0 +1,98 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func clientSignature(secret string, at time.Time, nonce, method, uri string, body []byte) string {
+	stamp := strconv.FormatInt(at.Unix(), 10)
+	bodyHash := sha256.Sum256(body)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(stamp + " " + nonce + " " + method + " " + uri + " " + hex.EncodeToString(bodyHash[:])))
+	return stamp + "." + nonce + "." + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestClientSignature(t *testing.T) {
+	const secret = "secreto-de-la-app"
+	pow := NewProofOfWork("clave-pow", secret, 20, time.Minute)
+	now := time.Now()
+	body := []byte(`{"cat_id":7,"direction":"like"}`)
+
+	signature := clientSignature(secret, now, "0123456789abcdef", "POST", "/api/swipes", body)
+	if err := pow.VerifyRequest("POST", "/api/swipes", "", signature, body); err != nil {
+		t.Fatalf("firma válida rechazada: %v", err)
+	}
+	if err := pow.VerifyRequest("POST", "/api/swipes", "", signature, body); err == nil {
+		t.Fatal("la misma firma se aceptó dos veces")
+	}
+
+	cases := []struct {
+		name      string
+		signature string
+		uri       string
+		body      []byte
+	}{
+		{"cuerpo cambiado", clientSignature(secret, now, "fedcba9876543210", "POST", "/api/swipes", body), "/api/swipes", []byte(`{"cat_id":8,"direction":"like"}`)},
+		{"otra ruta", clientSignature(secret, now, "fedcba9876543211", "POST", "/api/swipes", body), "/api/messages", body},
+		{"otra query", clientSignature(secret, now, "fedcba9876543212", "POST", "/api/swipes", body), "/api/swipes?force=1", body},
+		{"otro secreto", clientSignature("otro", now, "fedcba9876543213", "POST", "/api/swipes", body), "/api/swipes", body},
+		{"vencida", clientSignature(secret, now.Add(-2*clientSigSkew), "fedcba9876543214", "POST", "/api/swipes", body), "/api/swipes", body},
+		{"nonce corto", clientSignature(secret, now, "abc", "POST", "/api/swipes", body), "/api/swipes", body},
+		{"formato viejo", strconv.FormatInt(now.Unix(), 10) + ".deadbeef", "/api/swipes", body},
+	}
+	for _, tc := range cases {
+		if err := pow.VerifyRequest("POST", tc.uri, "", tc.signature, tc.body); err != ErrPowMissing {
+			t.Errorf("%s: se esperaba caer en la prueba de trabajo, fue %v", tc.name, err)
+		}
+	}
+}
+
+func TestMarkUsedPrunesInBatches(t *testing.T) {
+	pow := NewProofOfWork("clave-pow", "", 20, time.Minute)
+	now := time.Now()
+	if err := pow.markUsed("viejo", now.Add(time.Second), now); err != nil {
+		t.Fatal(err)
+	}
+	if err := pow.markUsed("viejo", now.Add(time.Second), now); err != ErrPowReused {
+		t.Fatalf("repetido: %v", err)
+	}
+
+	// * Vencido pero antes del barrido: sigue en el mapa y aun así se puede volver a usar
+	later := now.Add(2 * time.Second)
+	if err := pow.markUsed("otro", later.Add(time.Minute), later); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := pow.used["viejo"]; !ok {
+		t.Fatal("se barrió antes del intervalo")
+	}
+	if err := pow.markUsed("viejo", later.Add(time.Minute), later); err != nil {
+		t.Fatalf("vencido reutilizado: %v", err)
+	}
+
+	swept := later.Add(usedPruneInterval + 2*time.Minute)
+	if err := pow.markUsed("nuevo", swept.Add(time.Minute), swept); err != nil {
+		t.Fatal(err)
+	}
+	if len(pow.used) != 1 {
+		t.Fatalf("tras el barrido quedan %d", len(pow.used))
+	}
+}
+
+func TestMarkUsedRejectsWhenFull(t *testing.T) {
+	pow := NewProofOfWork("clave-pow", "", 20, time.Minute)
+	now := time.Now()
+	for i := range maxUsedEntries {
+		pow.used[strconv.Itoa(i)] = now.Add(time.Minute)
+	}
+	if err := pow.markUsed("uno-más", now.Add(time.Minute), now); err != ErrPowBusy {
+		t.Fatalf("con el mapa lleno: %v", err)
+	}
+	if err := pow.markUsed("0", now.Add(time.Minute), now); err != ErrPowReused {
+		t.Fatalf("vigente con el mapa lleno: %v", err)
+	}
+}
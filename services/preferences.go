@@ -0,0 +1,73 @@
+package services
+
+import (
+	"strings"
+	"time"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+)
+
+func (s *SwipeService) Preferences(userID string) m.Preferences {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return clonePreferences(s.preferences[userID])
+}
+
+func (s *SwipeService) SetPreferences(userID string, prefs m.Preferences) m.Preferences {
+	now := time.Now()
+	prefs = clonePreferences(prefs)
+	prefs.UpdatedAt = &now
+
+	s.mutex.Lock()
+	s.preferences[userID] = prefs
+	s.mutex.Unlock()
+
+	return clonePreferences(prefs)
+}
+
+func clonePreferences(prefs m.Preferences) m.Preferences {
+	prefs.Breeds = append([]string{}, prefs.Breeds...)
+	prefs.Personalities = append([]string{}, prefs.Personalities...)
+	return prefs
+}
+
+// * Raza exacta (sin mayúsculas) y personalidad por palabra clave: "Juguetón y
+// * cariñoso" coincide con "juguetón". La distancia y el tipo de medio todavía no
+// * filtran nada porque los perfiles no tienen ubicación ni formato de imagen
+func matchesPreferences(cat m.CatProfile, prefs m.Preferences) bool {
+	if prefs.MinAge > 0 && cat.Age < prefs.MinAge {
+		return false
+	}
+	if prefs.MaxAge > 0 && cat.Age > prefs.MaxAge {
+		return false
+	}
+
+	if len(prefs.Breeds) > 0 {
+		found := false
+		for _, breed := range prefs.Breeds {
+			if normalizeKey(breed) == normalizeKey(cat.Breed) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if len(prefs.Personalities) > 0 {
+		personality := strings.ToLower(cat.Personality)
+		found := false
+		for _, keyword := range prefs.Personalities {
+			if keyword = normalizeKey(keyword); keyword != "" && strings.Contains(personality, keyword) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
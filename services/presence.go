@@ -0,0 +1,80 @@
+package services
+
+import (
+	"time"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+)
+
+// * Se reenvía solo a los otros participantes; nunca se guarda
+func (s *ChatService) Typing(userID, matchID string, typing bool) error {
+	info, err := s.swipes.Conversation(userID, matchID)
+	if err != nil {
+		return err
+	}
+	if s.swipes.Preferences(userID).HideTyping {
+		return nil
+	}
+
+	s.publish(others(info.Participants, userID), m.ChatEvent{
+		Type:           m.ChatEventTyping,
+		ConversationID: info.ID,
+		UserID:         userID,
+		Typing:         &typing,
+		At:             time.Now(),
+	})
+	return nil
+}
+
+// * Presencia del otro dueño del match, respetando su privacidad
+func (s *ChatService) Presence(userID, matchID string) (m.Presence, error) {
+	info, err := s.swipes.Conversation(userID, matchID)
+	if err != nil {
+		return m.Presence{}, err
+	}
+	counterpart := others(info.Participants, userID)
+	if len(counterpart) == 0 {
+		return m.Presence{}, ErrChatUnavailable
+	}
+	return s.presence(counterpart[0]), nil
+}
+
+func (s *ChatService) presence(userID string) m.Presence {
+	if s.swipes.Preferences(userID).HidePresence {
+		return m.Presence{UserID: userID, Hidden: true}
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	presence := m.Presence{UserID: userID, Online: len(s.subscribers[userID]) > 0}
+	if lastSeen, ok := s.lastSeen[userID]; ok && !presence.Online {
+		presence.LastSeen = &lastSeen
+	}
+	return presence
+}
+
+// * Avisa a todos sus matches mutuos; con la presencia oculta no se avisa nada
+func (s *ChatService) announcePresence(userID string) {
+	presence := s.presence(userID)
+	if presence.Hidden {
+		return
+	}
+
+	s.publish(s.swipes.MutualContacts(userID), m.ChatEvent{
+		Type:     m.ChatEventPresence,
+		UserID:   userID,
+		Presence: &presence,
+		At:       time.Now(),
+	})
+}
+
+func others(participants []string, userID string) []string {
+	result := make([]string, 0, len(participants))
+	for _, participant := range participants {
+		if participant != userID {
+			result = append(result, participant)
+		}
+	}
+	return result
+}
@@ -0,0 +1,115 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+)
+
+var (
+	ErrProfileNotFound       = errors.New("perfil no encontrado")
+	ErrProfileAlreadyDeleted = errors.New("el perfil ya está borrado")
+	ErrProfileNotDeleted     = errors.New("el perfil no está borrado")
+)
+
+func profileNotFound(id int) error {
+	return fmt.Errorf("%w: gato con ID %d", ErrProfileNotFound, id)
+}
+
+// * Incluye perfiles borrados; solo para el panel de admin
+func (s *CatService) AdminGetCatProfile(id int) (*m.CatProfile, error) {
+	snap := s.snapshot()
+
+	i, ok := snap.index.byID[id]
+	if !ok {
+		return nil, profileNotFound(id)
+	}
+
+	cat := snap.profiles[i].Clone()
+	return &cat, nil
+}
+
+func (s *CatService) SoftDeleteCatProfile(id int) (*m.CatProfile, error) {
+	now := time.Now()
+	return s.mutateProfile(id, func(cat *m.CatProfile) error {
+		if cat.DeletedAt != nil {
+			return ErrProfileAlreadyDeleted
+		}
+		cat.DeletedAt = &now
+		cat.UpdatedAt = now
+		return nil
+	})
+}
+
+func (s *CatService) RestoreCatProfile(id int) (*m.CatProfile, error) {
+	return s.mutateProfile(id, func(cat *m.CatProfile) error {
+		if cat.DeletedAt == nil {
+			return ErrProfileNotDeleted
+		}
+		cat.DeletedAt = nil
+		cat.UpdatedAt = time.Now()
+		return nil
+	})
+}
+
+// ! Irreversible: solo se permite sobre perfiles ya borrados lógicamente
+func (s *CatService) PurgeCatProfile(id int) error {
+	err := s.rebuildProfiles(func(profiles []m.CatProfile) ([]m.CatProfile, error) {
+		for i := range profiles {
+			if profiles[i].ID != id {
+				continue
+			}
+			if profiles[i].DeletedAt == nil {
+				return nil, ErrProfileNotDeleted
+			}
+			return append(profiles[:i], profiles[i+1:]...), nil
+		}
+		return nil, profileNotFound(id)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.notifyReload()
+	return nil
+}
+
+// * Los perfiles restaurados quedan con UpdatedAt de ahora: con su marca del respaldo
+// * quedarían detrás de cursores ya entregados y el feed de cambios nunca los mostraría
+func (s *CatService) replaceProfiles(profiles []m.CatProfile) {
+	now := time.Now()
+	for i := range profiles {
+		profiles[i].UpdatedAt = now
+	}
+	s.rebuildProfiles(func([]m.CatProfile) ([]m.CatProfile, error) {
+		return profiles, nil
+	})
+	s.notifyReload()
+}
+
+func (s *CatService) mutateProfile(id int, mutate func(cat *m.CatProfile) error) (*m.CatProfile, error) {
+	var result m.CatProfile
+	err := s.rebuildProfiles(func(profiles []m.CatProfile) ([]m.CatProfile, error) {
+		for i := range profiles {
+			if profiles[i].ID != id {
+				continue
+			}
+			if err := mutate(&profiles[i]); err != nil {
+				return nil, err
+			}
+			result = profiles[i].Clone()
+			return profiles, nil
+		}
+		return nil, profileNotFound(id)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("🗂️ Perfil %d actualizado por admin", id)
+	s.notifyReload()
+	return &result, nil
+}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"sort"
+	"time"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+)
+
+// * Lo más nuevo que se entrega: un cambio con marca anterior que todavía se esté
+// * publicando no puede quedar detrás de un cursor ya entregado
+const profileChangesSettle = time.Second
+
+// * Feed de cambios para integradores: cada perfil modificado después de since, del más
+// * viejo al más nuevo por (UpdatedAt, ID). No es un historial sino el estado actual: un
+// * perfil que cambió tres veces sale una sola vez, en su posición más nueva. Los que
+// * salieron del catálogo público (borrados, borradores, pendientes) salen como deleted,
+// * solo con el id, y también los que ya no existen (purgados o fuera de un respaldo
+// * restaurado), en la posición de su lápida. Como el cursor es la marca del perfil,
+// * sobrevive reinicios
+func (s *CatService) ProfileChanges(since *Cursor, limit int) m.ProfileChangesResponse {
+	snap := s.snapshot()
+	horizon := time.Now().Add(-profileChangesSettle)
+	pending := func(position Cursor) bool {
+		return !position.Time.After(horizon) && (since == nil || position.after(*since))
+	}
+
+	// * cat es nil para las lápidas
+	type entry struct {
+		position Cursor
+		cat      *m.CatProfile
+	}
+	var changed []entry
+	for i, cat := range snap.profiles {
+		position := Cursor{Time: cat.UpdatedAt, ID: cat.ID}
+		if !pending(position) {
+			continue
+		}
+		// * En la primera sincronización no hay nada que borrar del otro lado
+		if since == nil && !publicProfile(cat) {
+			continue
+		}
+		changed = append(changed, entry{position: position, cat: &snap.profiles[i]})
+	}
+	if since != nil {
+		for id, at := range snap.tombstones {
+			if position := (Cursor{Time: at, ID: id}); pending(position) {
+				changed = append(changed, entry{position: position})
+			}
+		}
+	}
+	sort.Slice(changed, func(i, j int) bool {
+		return changed[j].position.after(changed[i].position)
+	})
+
+	response := m.ProfileChangesResponse{Changes: []m.ProfileChange{}}
+	if since != nil {
+		response.Cursor = since.Encode()
+	}
+	if len(changed) > limit {
+		changed = changed[:limit]
+		response.HasMore = true
+	}
+
+	for _, e := range changed {
+		position := e.position.Encode()
+		change := m.ProfileChange{ID: e.position.ID, Cursor: position, At: e.position.Time}
+		switch cat := e.cat; {
+		case cat == nil || !publicProfile(*cat):
+			change.Type = m.ProfileChangeDeleted
+		case since == nil || (cat.CreatedAt != nil && cat.CreatedAt.After(since.Time)):
+			change.Type = m.ProfileChangeCreated
+		default:
+			change.Type = m.ProfileChangeUpdated
+		}
+		if change.Type != m.ProfileChangeDeleted {
+			profile := s.withImageMeta([]m.CatProfile{e.cat.Clone()})[0]
+			change.Profile = &profile
+		}
+		response.Changes = append(response.Changes, change)
+		response.Cursor = position
+	}
+	return response
+}
+
+// * Lo mismo que deja ver GetCatProfileByID
+func publicProfile(cat m.CatProfile) bool {
+	return cat.DeletedAt == nil && cat.Status != m.StatusDraft && cat.Status != m.StatusPending
+}
@@ -0,0 +1,58 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+)
+
+// * Misma lectura que loadCatProfiles pero sin tocar la red ni publicar nada: devuelve
+// * cuántos perfiles hay y los problemas de cada uno (para `meownder check`)
+func ValidateProfilesFile(path string) (int, []string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, nil, fmt.Errorf("error leyendo %s: %w", path, err)
+	}
+
+	var catsData struct {
+		Cats []m.CatProfile `json:"cats"`
+	}
+	if err := json.Unmarshal(data, &catsData); err != nil {
+		return 0, nil, fmt.Errorf("error parseando JSON: %w", err)
+	}
+
+	var problems []string
+	if len(catsData.Cats) == 0 {
+		problems = append(problems, "no hay perfiles")
+	}
+
+	seen := make(map[int]bool, len(catsData.Cats))
+	for i, cat := range catsData.Cats {
+		where := fmt.Sprintf("perfil #%d (id %d)", i+1, cat.ID)
+		switch {
+		case cat.ID <= 0:
+			problems = append(problems, where+": id inválido")
+		case seen[cat.ID]:
+			problems = append(problems, where+": id repetido")
+		}
+		seen[cat.ID] = true
+
+		if cat.Name == "" {
+			problems = append(problems, where+": falta name")
+		}
+		if cat.Breed == "" {
+			problems = append(problems, where+": falta breed")
+		}
+		if cat.Age < 0 || cat.Age > 30 {
+			problems = append(problems, fmt.Sprintf("%s: edad fuera de rango (%d)", where, cat.Age))
+		}
+		switch cat.Status {
+		case "", m.StatusDraft, m.StatusActive, m.StatusPaused, m.StatusAdopted, m.StatusPending:
+		default:
+			problems = append(problems, fmt.Sprintf("%s: estado desconocido %q", where, cat.Status))
+		}
+	}
+	return len(catsData.Cats), problems, nil
+}
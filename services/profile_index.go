@@ -0,0 +1,203 @@
+package services
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+)
+
+// * Vista inmutable de los perfiles: nunca se modifica después de publicarse
+type profileSnapshot struct {
+	profiles []m.CatProfile
+	// * Posiciones de los perfiles publicados: activos y no borrados
+	active []int
+	index  profileIndex
+	// * Cuándo se publicó este snapshot (carga, refresco de imágenes o edición)
+	publishedAt time.Time
+	// * Ids que salieron del catálogo (purga, restauración, recarga) y cuándo; el feed de
+	// * cambios los entrega como deleted. Se pasan de snapshot en snapshot y se pierden
+	// * al reiniciar, igual que cualquier edición del catálogo
+	tombstones map[int]time.Time
+
+	// * Se calcula la primera vez que se pide; un snapshot nuevo empieza sin resumen
+	summaryOnce sync.Once
+	summary     m.DatasetSummary
+}
+
+func newProfileSnapshot(profiles []m.CatProfile) *profileSnapshot {
+	active := make([]int, 0, len(profiles))
+	for i, cat := range profiles {
+		if cat.DeletedAt == nil && cat.Status == m.StatusActive {
+			active = append(active, i)
+		}
+	}
+	return &profileSnapshot{
+		profiles:    profiles,
+		active:      active,
+		index:       buildProfileIndex(profiles),
+		publishedAt: time.Now(),
+	}
+}
+
+func (snap *profileSnapshot) clone() []m.CatProfile {
+	profiles := make([]m.CatProfile, len(snap.profiles))
+	for i, cat := range snap.profiles {
+		profiles[i] = cat.Clone()
+	}
+	return profiles
+}
+
+func (snap *profileSnapshot) cloneActive() []m.CatProfile {
+	profiles := make([]m.CatProfile, len(snap.active))
+	for i, pos := range snap.active {
+		profiles[i] = snap.profiles[pos].Clone()
+	}
+	return profiles
+}
+
+func (s *CatService) snapshot() *profileSnapshot {
+	if snap := s.profiles.Load(); snap != nil {
+		return snap
+	}
+	return &profileSnapshot{}
+}
+
+// * Los escritores se serializan entre sí; los lectores siguen viendo el snapshot anterior
+func (s *CatService) updateProfiles(mutate func(profiles []m.CatProfile)) {
+	s.rebuildProfiles(func(profiles []m.CatProfile) ([]m.CatProfile, error) {
+		mutate(profiles)
+		return profiles, nil
+	})
+}
+
+// * Como updateProfiles pero puede quitar perfiles o abortar sin publicar nada
+func (s *CatService) rebuildProfiles(build func(profiles []m.CatProfile) ([]m.CatProfile, error)) error {
+	s.writeMutex.Lock()
+	defer s.writeMutex.Unlock()
+
+	profiles, err := build(s.snapshot().clone())
+	if err != nil {
+		return err
+	}
+	s.storeProfiles(profiles)
+	return nil
+}
+
+// * Publica profiles como el snapshot actual y deja lápida a los ids que desaparecieron.
+// ! Llamar con writeMutex tomado
+func (s *CatService) storeProfiles(profiles []m.CatProfile) {
+	previous := s.snapshot()
+	next := newProfileSnapshot(profiles)
+
+	now := time.Now()
+	next.tombstones = make(map[int]time.Time, len(previous.tombstones))
+	for id, at := range previous.tombstones {
+		if _, back := next.index.byID[id]; !back {
+			next.tombstones[id] = at
+		}
+	}
+	for id := range previous.index.byID {
+		if _, kept := next.index.byID[id]; !kept {
+			next.tombstones[id] = now
+		}
+	}
+
+	s.profiles.Store(next)
+	s.stableImages.Sync(s.stableTenant, profiles)
+}
+
+// * Índices por posición en el slice de perfiles; se reconstruyen en cada carga
+type profileIndex struct {
+	byID    map[int]int
+	byBreed map[string][]int
+	byHobby map[string][]int
+	byTag   map[string][]int
+}
+
+func buildProfileIndex(profiles []m.CatProfile) profileIndex {
+	index := profileIndex{
+		byID:    make(map[int]int, len(profiles)),
+		byBreed: make(map[string][]int),
+		byHobby: make(map[string][]int),
+		byTag:   make(map[string][]int),
+	}
+
+	for i, cat := range profiles {
+		index.byID[cat.ID] = i
+
+		breed := normalizeKey(cat.Breed)
+		index.byBreed[breed] = append(index.byBreed[breed], i)
+
+		for _, hobby := range cat.Hobbies {
+			key := normalizeKey(hobby)
+			if positions := index.byHobby[key]; len(positions) == 0 || positions[len(positions)-1] != i {
+				index.byHobby[key] = append(positions, i)
+			}
+		}
+		for _, tag := range cat.Tags {
+			index.byTag[tag] = append(index.byTag[tag], i)
+		}
+	}
+
+	return index
+}
+
+// * Posiciones candidatas para un filtro; nil significa "recorrer todo"
+func (idx profileIndex) candidates(filter m.ProfileFilter) []int {
+	var result []int
+	if filter.Breed != "" {
+		result = idx.byBreed[normalizeKey(filter.Breed)]
+		if result == nil {
+			return []int{}
+		}
+	}
+
+	if filter.Hobby != "" {
+		byHobby := idx.byHobby[normalizeKey(filter.Hobby)]
+		if byHobby == nil {
+			return []int{}
+		}
+		if result == nil {
+			result = byHobby
+		} else {
+			result = intersectSorted(result, byHobby)
+		}
+	}
+
+	for _, tag := range filter.Tags {
+		byTag := idx.byTag[tag]
+		if byTag == nil {
+			return []int{}
+		}
+		if result == nil {
+			result = byTag
+		} else {
+			result = intersectSorted(result, byTag)
+		}
+	}
+
+	return result
+}
+
+func intersectSorted(a, b []int) []int {
+	result := make([]int, 0, min(len(a), len(b)))
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] == b[j]:
+			result = append(result, a[i])
+			i++
+			j++
+		case a[i] < b[j]:
+			i++
+		default:
+			j++
+		}
+	}
+	return result
+}
+
+func normalizeKey(value string) string {
+	return strings.ToLower(strings.TrimSpace(value))
+}
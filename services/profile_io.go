@@ -0,0 +1,290 @@
+package services
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+)
+
+const (
+	FormatCSV   = "csv"
+	FormatJSONL = "jsonl"
+)
+
+// * Columnas del CSV; los hobbies y las etiquetas van separados por "|"
+var profileCSVColumns = []string{"id", "name", "age", "breed", "personality", "hobbies", "bio", "status", "img", "tags"}
+
+var errImportRejected = errors.New("importación rechazada")
+
+// * Lee filas de CSV (con cabecera) o JSON Lines; los errores de formato se reportan por
+// * línea y no cortan la lectura del resto del archivo
+func ParseProfileImport(format string, r io.Reader) ([]m.ProfileImportRow, []m.ImportRowError, error) {
+	switch format {
+	case FormatCSV:
+		return parseProfileCSV(r)
+	case FormatJSONL:
+		return parseProfileJSONL(r)
+	default:
+		return nil, nil, fmt.Errorf("formato no soportado: %s", format)
+	}
+}
+
+func parseProfileCSV(r io.Reader) ([]m.ProfileImportRow, []m.ImportRowError, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, nil, fmt.Errorf("CSV sin cabecera: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	var rows []m.ProfileImportRow
+	var rowErrors []m.ImportRowError
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		line, _ := reader.FieldPos(0)
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				rowErrors = append(rowErrors, m.ImportRowError{Line: parseErr.Line, Message: parseErr.Err.Error()})
+				continue
+			}
+			return nil, nil, err
+		}
+
+		get := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		row := m.ProfileImportRow{
+			Name:        get("name"),
+			Breed:       get("breed"),
+			Personality: get("personality"),
+			Bio:         get("bio"),
+			Status:      get("status"),
+			Img:         get("img"),
+			Line:        line,
+		}
+		for _, hobby := range strings.Split(get("hobbies"), "|") {
+			if hobby = strings.TrimSpace(hobby); hobby != "" {
+				row.Hobbies = append(row.Hobbies, hobby)
+			}
+		}
+		for _, tag := range strings.Split(get("tags"), "|") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				row.Tags = append(row.Tags, tag)
+			}
+		}
+
+		valid := true
+		for _, field := range []struct {
+			name string
+			dst  *int
+		}{{"id", &row.ID}, {"age", &row.Age}} {
+			raw := get(field.name)
+			if raw == "" {
+				continue
+			}
+			n, err := strconv.Atoi(raw)
+			if err != nil {
+				rowErrors = append(rowErrors, m.ImportRowError{Line: line, Field: field.name, Message: fmt.Sprintf("%s debe ser un número", field.name)})
+				valid = false
+				continue
+			}
+			*field.dst = n
+		}
+		if valid {
+			rows = append(rows, row)
+		}
+	}
+	return rows, rowErrors, nil
+}
+
+func parseProfileJSONL(r io.Reader) ([]m.ProfileImportRow, []m.ImportRowError, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var rows []m.ProfileImportRow
+	var rowErrors []m.ImportRowError
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		var row m.ProfileImportRow
+		if err := json.Unmarshal([]byte(text), &row); err != nil {
+			rowErrors = append(rowErrors, m.ImportRowError{Line: line, Message: "JSON inválido: " + err.Error()})
+			continue
+		}
+		row.Line = line
+		rows = append(rows, row)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+	return rows, rowErrors, nil
+}
+
+// * Todo o nada: con cualquier error, o en dry-run, no se publica ningún cambio. Las altas
+// * que parecen un perfil ya cargado también lo frenan, salvo con force
+func (s *CatService) ImportCatProfiles(rows []m.ProfileImportRow, dryRun, force bool) m.ImportReport {
+	report := m.ImportReport{DryRun: dryRun, Rows: len(rows)}
+	var transitions []m.ProfileTransition
+
+	err := s.rebuildProfiles(func(profiles []m.CatProfile) ([]m.CatProfile, error) {
+		byID := make(map[int]int, len(profiles))
+		nextID := 1
+		for i, cat := range profiles {
+			byID[cat.ID] = i
+			nextID = max(nextID, cat.ID+1)
+		}
+
+		now := time.Now()
+		duplicates := newDuplicateIndex(profiles)
+		seen := make(map[int]int)
+		for _, row := range rows {
+			if row.ID != 0 {
+				if first, dup := seen[row.ID]; dup {
+					report.Errors = append(report.Errors, m.ImportRowError{
+						Line: row.Line, Field: "id",
+						Message: fmt.Sprintf("id %d repetido (ya usado en la línea %d)", row.ID, first),
+					})
+					continue
+				}
+				seen[row.ID] = row.Line
+			}
+
+			if i, ok := byID[row.ID]; ok && row.ID != 0 {
+				cat := &profiles[i]
+				if row.Status != "" && row.Status != cat.Status {
+					if !canTransition(cat.Status, row.Status) {
+						report.Errors = append(report.Errors, m.ImportRowError{
+							Line: row.Line, Field: "status",
+							Message: fmt.Sprintf("%s: %s → %s", ErrInvalidTransition, cat.Status, row.Status),
+						})
+						continue
+					}
+					transitions = append(transitions, m.ProfileTransition{
+						CatID: cat.ID, CatName: row.Name, From: cat.Status, To: row.Status, At: now,
+					})
+					cat.Status = row.Status
+				}
+				applyImportRow(cat, row, now)
+				report.Updated++
+				continue
+			}
+
+			cat := m.CatProfile{ID: row.ID, Status: row.Status}
+			if cat.ID == 0 {
+				cat.ID = nextID
+			}
+			nextID = max(nextID, cat.ID+1)
+			if cat.Status == "" {
+				cat.Status = m.StatusActive
+			}
+			applyImportRow(&cat, row, now)
+			if duplicate, ok := duplicates.find(cat); ok {
+				duplicate.Line = row.Line
+				report.Duplicates = append(report.Duplicates, duplicate)
+			}
+			duplicates.add(cat)
+			if cat.Img == "" {
+				cat.Img = s.generateCatURL().URL
+			}
+			cat.CreatedAt = &now
+			profiles = append(profiles, cat)
+			byID[cat.ID] = len(profiles) - 1
+			report.Created++
+		}
+
+		if len(report.Errors) > 0 || dryRun || (len(report.Duplicates) > 0 && !force) {
+			return nil, errImportRejected
+		}
+		return profiles, nil
+	})
+
+	if err == nil {
+		report.Applied = true
+		s.notifyReload()
+		for _, transition := range transitions {
+			s.notifyTransition(transition)
+		}
+	}
+	return report
+}
+
+func applyImportRow(cat *m.CatProfile, row m.ProfileImportRow, now time.Time) {
+	cat.Name = row.Name
+	cat.Age = row.Age
+	cat.Breed = row.Breed
+	cat.Personality = row.Personality
+	cat.Hobbies = append([]string(nil), row.Hobbies...)
+	cat.Bio = row.Bio
+	cat.Tags = NormalizeTags(row.Tags)
+	if row.Img != "" {
+		cat.Img = row.Img
+	}
+	cat.UpdatedAt = now
+}
+
+// * Si w sabe hacer flush (la respuesta HTTP) cada perfil sale apenas se escribe
+func ExportCatProfiles(w io.Writer, format string, profiles []m.CatProfile) error {
+	flush := func() {}
+	if flusher, ok := w.(http.Flusher); ok {
+		flush = flusher.Flush
+	}
+
+	switch format {
+	case FormatCSV:
+		writer := csv.NewWriter(w)
+		if err := writer.Write(profileCSVColumns); err != nil {
+			return err
+		}
+		for _, cat := range profiles {
+			record := []string{
+				strconv.Itoa(cat.ID), cat.Name, strconv.Itoa(cat.Age), cat.Breed, cat.Personality,
+				strings.Join(cat.Hobbies, "|"), cat.Bio, cat.Status, cat.Img, strings.Join(cat.Tags, "|"),
+			}
+			if err := writer.Write(record); err != nil {
+				return err
+			}
+			writer.Flush()
+			if err := writer.Error(); err != nil {
+				return err
+			}
+			flush()
+		}
+		return nil
+	case FormatJSONL:
+		encoder := json.NewEncoder(w)
+		for _, cat := range profiles {
+			if err := encoder.Encode(cat); err != nil {
+				return err
+			}
+			flush()
+		}
+		return nil
+	default:
+		return fmt.Errorf("formato no soportado: %s", format)
+	}
+}
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+)
+
+const (
+	// * Una bio más corta no alcanza para presentar al gato
+	minBioRunes    = 40
+	minGoodHobbies = 3
+)
+
+// * Peso de cada parte del perfil; suman 100
+const (
+	qualityImageWeight       = 25
+	qualityBioWeight         = 25
+	qualityHobbiesWeight     = 20
+	qualityBreedWeight       = 15
+	qualityPersonalityWeight = 15
+)
+
+func ProfileQualityOf(cat m.CatProfile) m.ProfileQuality {
+	quality := m.ProfileQuality{Score: 100}
+	lose := func(points int, missing string) {
+		quality.Score -= points
+		quality.Missing = append(quality.Missing, missing)
+	}
+
+	if strings.TrimSpace(cat.Img) == "" {
+		lose(qualityImageWeight, m.QualityMissingImage)
+	}
+	switch bio := strings.TrimSpace(cat.Bio); {
+	case bio == "":
+		lose(qualityBioWeight, m.QualityMissingBio)
+	case utf8.RuneCountInString(bio) < minBioRunes:
+		lose(qualityBioWeight/2, m.QualityShortBio)
+	}
+	switch {
+	case len(cat.Hobbies) == 0:
+		lose(qualityHobbiesWeight, m.QualityNoHobbies)
+	case len(cat.Hobbies) < minGoodHobbies:
+		lose(qualityHobbiesWeight/2, m.QualityFewHobbies)
+	}
+	if strings.TrimSpace(cat.Breed) == "" {
+		lose(qualityBreedWeight, m.QualityMissingBreed)
+	}
+	if strings.TrimSpace(cat.Personality) == "" {
+		lose(qualityPersonalityWeight, m.QualityMissingPersonality)
+	}
+	return quality
+}
+
+// * Perfiles no borrados ordenados del menos al más completo, para saber qué mejorar primero
+func (s *CatService) QualityReport(query m.ProfileQualityQuery) m.QualityReport {
+	snap := s.snapshot()
+	report := m.QualityReport{GeneratedAt: snap.publishedAt}
+
+	missing := newValueCounter()
+	entries := []m.QualityReportEntry{}
+	scoreSum := 0
+	for _, cat := range snap.profiles {
+		if cat.DeletedAt != nil || (query.Status != "" && cat.Status != query.Status) {
+			continue
+		}
+		quality := ProfileQualityOf(cat)
+		report.Total++
+		scoreSum += quality.Score
+		if len(quality.Missing) == 0 {
+			report.Complete++
+		}
+		for _, item := range quality.Missing {
+			missing.add(item)
+		}
+		if query.MaxScore > 0 && quality.Score > query.MaxScore {
+			continue
+		}
+		entries = append(entries, m.QualityReportEntry{
+			ID:             cat.ID,
+			Name:           cat.Name,
+			Status:         cat.Status,
+			ProfileQuality: quality,
+		})
+	}
+
+	slices.SortFunc(entries, func(a, b m.QualityReportEntry) int {
+		return cmp.Or(cmp.Compare(a.Score, b.Score), cmp.Compare(a.ID, b.ID))
+	})
+	limit := query.Limit
+	if limit == 0 {
+		limit = 50
+	}
+	if len(entries) > limit {
+		entries = entries[:limit]
+	}
+
+	report.Profiles = entries
+	report.Count = len(entries)
+	report.Missing = missing.sorted()
+	if report.Total > 0 {
+		report.AverageScore = math.Round(float64(scoreSum)/float64(report.Total)*10) / 10
+	}
+	return report
+}
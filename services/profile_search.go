@@ -0,0 +1,202 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+	"github.com/ChrisTheAbysswalker/meownder-backend/search"
+)
+
+var ErrSearchUnavailable = errors.New("la búsqueda de texto no está disponible")
+
+// * Cambios que se piden al feed por vuelta de sincronización
+const searchSyncBatch = 500
+
+// * Búsqueda de texto sobre los perfiles públicos. El índice se mantiene con el mismo feed
+// * de cambios que usan los integradores (GET /changes): cada vuelta pide lo posterior al
+// * último cursor aplicado, así que da igual que el índice sea el embebido o uno compartido
+// * en Elasticsearch. Al arrancar el cursor vuelve a cero y se reindexa todo
+type ProfileSearch struct {
+	cats    *CatService
+	index   search.Index
+	maxHits int
+	timeout time.Duration
+
+	// * Una sincronización a la vez
+	syncMutex sync.Mutex
+	cursor    *Cursor
+	// * Pedido de sincronizar ya (sin esperar al ticker) tras un cambio en el catálogo
+	kick chan struct{}
+
+	indexedListeners []func()
+	listenersMutex   sync.Mutex
+}
+
+func NewProfileSearch(cats *CatService, index search.Index, maxHits int, timeout time.Duration) *ProfileSearch {
+	return &ProfileSearch{
+		cats:    cats,
+		index:   index,
+		maxHits: maxHits,
+		timeout: timeout,
+		kick:    make(chan struct{}, 1),
+	}
+}
+
+// * Se llama después de cada vuelta que cambió el índice (p. ej. para purgar cachés de
+// * respuestas con búsquedas viejas)
+func (p *ProfileSearch) OnIndexed(listener func()) {
+	p.listenersMutex.Lock()
+	defer p.listenersMutex.Unlock()
+	p.indexedListeners = append(p.indexedListeners, listener)
+}
+
+// * Sincroniza cada interval y, además, poco después de cada recarga del catálogo. Al
+// * arrancar también: los perfiles recién cargados recién entran al feed cuando se asientan
+func (p *ProfileSearch) Start(ctx context.Context, interval time.Duration) {
+	kick := func() {
+		select {
+		case p.kick <- struct{}{}:
+		default:
+		}
+	}
+	p.cats.OnReload(kick)
+	kick()
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			applied, err := p.Sync(ctx)
+			if err != nil && ctx.Err() == nil {
+				log.Printf("⚠️  No se pudo sincronizar el índice de búsqueda (%s): %v", p.index.Backend(), err)
+			}
+			if applied > 0 {
+				p.notifyIndexed()
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			case <-p.kick:
+				// * El feed retiene los cambios más nuevos hasta que se asientan
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(profileChangesSettle + 100*time.Millisecond):
+				}
+			}
+		}
+	}()
+}
+
+// * Aplica al índice todo lo que cambió desde la última vuelta y devuelve cuántos cambios
+// * fueron. Si falla, el cursor queda donde estaba y la próxima vuelta reintenta
+func (p *ProfileSearch) Sync(ctx context.Context) (int, error) {
+	p.syncMutex.Lock()
+	defer p.syncMutex.Unlock()
+
+	applied := 0
+	for {
+		page := p.cats.ProfileChanges(p.cursor, searchSyncBatch)
+		var docs []search.Document
+		var deleted []int
+		for _, change := range page.Changes {
+			if change.Type == m.ProfileChangeDeleted {
+				deleted = append(deleted, change.ID)
+				continue
+			}
+			docs = append(docs, searchDocument(*change.Profile))
+		}
+
+		writeCtx, cancel := context.WithTimeout(ctx, p.timeout)
+		err := p.index.Upsert(writeCtx, docs)
+		if err == nil {
+			err = p.index.Delete(writeCtx, deleted)
+		}
+		cancel()
+		if err != nil {
+			return applied, err
+		}
+
+		applied += len(page.Changes)
+		if page.Cursor != "" {
+			cursor, err := DecodeCursor(page.Cursor)
+			if err != nil {
+				return applied, err
+			}
+			p.cursor = cursor
+		}
+		if !page.HasMore {
+			return applied, nil
+		}
+	}
+}
+
+func (p *ProfileSearch) notifyIndexed() {
+	p.listenersMutex.Lock()
+	listeners := append([]func(){}, p.indexedListeners...)
+	p.listenersMutex.Unlock()
+
+	for _, listener := range listeners {
+		listener()
+	}
+}
+
+func searchDocument(cat m.CatProfile) search.Document {
+	return search.Document{
+		ID:          cat.ID,
+		Name:        cat.Name,
+		Breed:       cat.Breed,
+		Personality: cat.Personality,
+		Hobbies:     cat.Hobbies,
+		Tags:        cat.Tags,
+		Bio:         cat.Bio,
+	}
+}
+
+// * search puede ser nil: entonces ?q= responde 503
+func (s *CatService) SetSearch(search *ProfileSearch) {
+	s.search = search
+}
+
+// * Como FilterCatProfiles pero solo con los perfiles que coinciden con text, del más al
+// * menos relevante. El índice solo da ids: los perfiles salen del snapshot actual y pasan
+// * por los mismos filtros, así que un perfil borrado que el índice todavía no soltó no
+// * aparece
+func (s *CatService) SearchCatProfiles(ctx context.Context, text string, filter m.ProfileFilter) ([]m.CatProfile, int, error) {
+	p := s.search
+	if p == nil {
+		return nil, 0, ErrSearchUnavailable
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+	hits, err := p.index.Search(ctx, text, p.maxHits)
+	if err != nil {
+		log.Printf("⚠️  Búsqueda fallida en %s: %v", p.index.Backend(), err)
+		return nil, 0, ErrSearchUnavailable
+	}
+
+	snap := s.snapshot()
+	matched := make([]m.CatProfile, 0, len(hits))
+	for _, hit := range hits {
+		i, ok := snap.index.byID[hit.ID]
+		if ok && matchesFilter(snap.profiles[i], filter) {
+			matched = append(matched, snap.profiles[i].Clone())
+		}
+	}
+
+	total := len(matched)
+	if filter.Offset >= total {
+		return []m.CatProfile{}, total, nil
+	}
+	matched = matched[filter.Offset:]
+	if filter.Limit > 0 && filter.Limit < len(matched) {
+		matched = matched[:filter.Limit]
+	}
+	return s.withImageMeta(matched), total, nil
+}
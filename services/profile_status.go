@@ -0,0 +1,73 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+)
+
+var ErrInvalidTransition = errors.New("transición de estado no permitida")
+
+// * draft → active ⇄ paused → adopted; adoptado es terminal. pending (gato de usuario sin
+// * confirmar) pasa a active, o a draft si hay revisión (ver OwnedCatVerifier)
+var statusTransitions = map[string][]string{
+	m.StatusPending: {m.StatusActive, m.StatusDraft},
+	m.StatusDraft:   {m.StatusActive},
+	m.StatusActive:  {m.StatusPaused, m.StatusAdopted},
+	m.StatusPaused:  {m.StatusActive, m.StatusAdopted},
+	m.StatusAdopted: {},
+}
+
+func canTransition(from, to string) bool {
+	for _, allowed := range statusTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *CatService) OnTransition(listener func(m.ProfileTransition)) {
+	s.listenersMutex.Lock()
+	defer s.listenersMutex.Unlock()
+	s.transitionListeners = append(s.transitionListeners, listener)
+}
+
+func (s *CatService) TransitionCatProfile(id int, status string) (*m.CatProfile, error) {
+	var transition m.ProfileTransition
+	profile, err := s.mutateProfile(id, func(cat *m.CatProfile) error {
+		if !canTransition(cat.Status, status) {
+			return fmt.Errorf("%w: %s → %s", ErrInvalidTransition, cat.Status, status)
+		}
+		transition = m.ProfileTransition{
+			CatID:   cat.ID,
+			CatName: cat.Name,
+			From:    cat.Status,
+			To:      status,
+			At:      time.Now(),
+		}
+		cat.Status = status
+		cat.UpdatedAt = transition.At
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("🔀 %s (%d): %s → %s", transition.CatName, transition.CatID, transition.From, transition.To)
+	s.notifyTransition(transition)
+	return profile, nil
+}
+
+func (s *CatService) notifyTransition(transition m.ProfileTransition) {
+	s.listenersMutex.Lock()
+	listeners := append([]func(m.ProfileTransition){}, s.transitionListeners...)
+	s.listenersMutex.Unlock()
+
+	for _, listener := range listeners {
+		listener(transition)
+	}
+}
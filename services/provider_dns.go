@@ -0,0 +1,395 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+)
+
+const (
+	IPFamilyAny        = "any"
+	IPFamilyPreferIPv4 = "prefer-ipv4"
+	IPFamilyIPv4       = "ipv4"
+	IPFamilyIPv6       = "ipv6"
+)
+
+type dnsEntry struct {
+	ips     []net.IP
+	expires time.Time
+}
+
+type dnsCall struct {
+	done chan struct{}
+	ips  []net.IP
+	err  error
+}
+
+// * Cache de resoluciones para el cliente de proveedores: el validador hace un HEAD por
+// * URL y sin esto cada uno resolvía cataas.com. Consulta directo a los nameservers de
+// * resolv.conf para conocer el TTL real; si no puede, usa el resolver del sistema y
+// * guarda con el TTL mínimo
+type DNSCache struct {
+	minTTL   time.Duration
+	maxTTL   time.Duration
+	servers  []string
+	system   *net.Resolver
+	entries  map[string]dnsEntry
+	inflight map[string]*dnsCall
+	hits     int64
+	misses   int64
+	errors   int64
+	mutex    sync.Mutex
+}
+
+func NewDNSCache(minTTL, maxTTL time.Duration) *DNSCache {
+	return &DNSCache{
+		minTTL:   minTTL,
+		maxTTL:   maxTTL,
+		servers:  readNameservers("/etc/resolv.conf"),
+		system:   net.DefaultResolver,
+		entries:  make(map[string]dnsEntry),
+		inflight: make(map[string]*dnsCall),
+	}
+}
+
+func readNameservers(path string) []string {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	var servers []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && fields[0] == "nameserver" {
+			servers = append(servers, net.JoinHostPort(fields[1], "53"))
+		}
+	}
+	return servers
+}
+
+// * Las búsquedas simultáneas del mismo host comparten una sola consulta
+func (c *DNSCache) Lookup(ctx context.Context, host string) ([]net.IP, error) {
+	c.mutex.Lock()
+	if entry, ok := c.entries[host]; ok && time.Now().Before(entry.expires) {
+		c.hits++
+		c.mutex.Unlock()
+		return entry.ips, nil
+	}
+	if call, ok := c.inflight[host]; ok {
+		c.mutex.Unlock()
+		select {
+		case <-call.done:
+			return call.ips, call.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	call := &dnsCall{done: make(chan struct{})}
+	c.inflight[host] = call
+	c.misses++
+	c.mutex.Unlock()
+
+	// * Sin el ctx de la petición: la respuesta sirve también a las que esperan
+	lookupCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ips, ttl, err := c.resolve(lookupCtx, host)
+	cancel()
+
+	c.mutex.Lock()
+	delete(c.inflight, host)
+	if err != nil {
+		c.errors++
+	} else {
+		c.entries[host] = dnsEntry{ips: ips, expires: time.Now().Add(min(max(ttl, c.minTTL), c.maxTTL))}
+	}
+	c.mutex.Unlock()
+
+	call.ips, call.err = ips, err
+	close(call.done)
+	return ips, err
+}
+
+func (c *DNSCache) Size() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return len(c.entries)
+}
+
+// * Tras no poder conectar a ninguna IP se descarta la entrada y se vuelve a resolver
+func (c *DNSCache) Forget(host string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	delete(c.entries, host)
+}
+
+func (c *DNSCache) Stats(family string) m.DNSCacheStats {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return m.DNSCacheStats{
+		Entries:  len(c.entries),
+		Hits:     c.hits,
+		Misses:   c.misses,
+		Errors:   c.errors,
+		IPFamily: family,
+	}
+}
+
+func (c *DNSCache) resolve(ctx context.Context, host string) ([]net.IP, time.Duration, error) {
+	for _, server := range c.servers {
+		ips, ttl, err := queryNameserver(ctx, server, host)
+		if err == nil && len(ips) > 0 {
+			return ips, ttl, nil
+		}
+	}
+
+	addrs, err := c.system.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, 0, err
+	}
+	ips := make([]net.IP, len(addrs))
+	for i, addr := range addrs {
+		ips[i] = addr.IP
+	}
+	return ips, c.minTTL, nil
+}
+
+// * Una consulta A y otra AAAA por UDP; el TTL es el menor de toda la cadena (CNAMEs
+// * incluidos). Una respuesta truncada se descarta y decide el resolver del sistema
+func queryNameserver(ctx context.Context, server, host string) ([]net.IP, time.Duration, error) {
+	name, err := dnsmessage.NewName(strings.TrimSuffix(host, ".") + ".")
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "udp", server)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	var ips []net.IP
+	ttl := time.Duration(-1)
+	for _, qtype := range []dnsmessage.Type{dnsmessage.TypeA, dnsmessage.TypeAAAA} {
+		id := uint16(rand.IntN(1 << 16))
+		query := dnsmessage.Message{
+			Header:    dnsmessage.Header{ID: id, RecursionDesired: true},
+			Questions: []dnsmessage.Question{{Name: name, Type: qtype, Class: dnsmessage.ClassINET}},
+		}
+		packet, err := query.Pack()
+		if err != nil {
+			return nil, 0, err
+		}
+		if _, err := conn.Write(packet); err != nil {
+			return nil, 0, err
+		}
+
+		buf := make([]byte, 1232)
+		n, err := conn.Read(buf)
+		if err != nil {
+			return nil, 0, err
+		}
+		var reply dnsmessage.Message
+		if err := reply.Unpack(buf[:n]); err != nil {
+			return nil, 0, err
+		}
+		if reply.Header.ID != id || reply.Header.Truncated {
+			return nil, 0, errors.New("respuesta DNS inválida o truncada")
+		}
+		if reply.Header.RCode != dnsmessage.RCodeSuccess {
+			return nil, 0, fmt.Errorf("DNS respondió %s", reply.Header.RCode)
+		}
+
+		for _, answer := range reply.Answers {
+			answerTTL := time.Duration(answer.Header.TTL) * time.Second
+			if ttl < 0 || answerTTL < ttl {
+				ttl = answerTTL
+			}
+			switch body := answer.Body.(type) {
+			case *dnsmessage.AResource:
+				ips = append(ips, net.IP(body.A[:]))
+			case *dnsmessage.AAAAResource:
+				ips = append(ips, net.IP(body.AAAA[:]))
+			}
+		}
+	}
+	return ips, max(ttl, 0), nil
+}
+
+// * Dialer con la cache y happy eyeballs propio: se prueban primero las IPs de la
+// * familia preferida y, si no conectan en fallbackDelay, se corre en paralelo la otra
+type providerDialer struct {
+	cache         *DNSCache
+	family        string
+	fallbackDelay time.Duration
+	dialer        net.Dialer
+}
+
+func (d *providerDialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil || net.ParseIP(host) != nil {
+		return d.dialer.DialContext(ctx, network, addr)
+	}
+
+	ips, err := d.cache.Lookup(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	primaries, fallbacks := splitByFamily(ips, d.family)
+	if len(primaries) == 0 {
+		primaries, fallbacks = fallbacks, nil
+	}
+	if len(primaries) == 0 {
+		return nil, fmt.Errorf("%s no tiene direcciones %s", host, d.family)
+	}
+
+	conn, err := d.race(ctx, network, port, primaries, fallbacks)
+	if err != nil {
+		d.cache.Forget(host)
+	}
+	return conn, err
+}
+
+// * ipv4/ipv6 descartan la otra familia; any respeta el orden del DNS (la primera
+// * dirección define la familia principal, como hace Go)
+func splitByFamily(ips []net.IP, family string) (primaries, fallbacks []net.IP) {
+	if len(ips) == 0 {
+		return nil, nil
+	}
+	isV4 := func(ip net.IP) bool { return ip.To4() != nil }
+	preferV4 := isV4(ips[0])
+	switch family {
+	case IPFamilyIPv4, IPFamilyPreferIPv4:
+		preferV4 = true
+	case IPFamilyIPv6:
+		preferV4 = false
+	}
+	for _, ip := range ips {
+		if isV4(ip) == preferV4 {
+			primaries = append(primaries, ip)
+		} else if family != IPFamilyIPv4 && family != IPFamilyIPv6 {
+			fallbacks = append(fallbacks, ip)
+		}
+	}
+	return primaries, fallbacks
+}
+
+type dialResult struct {
+	conn net.Conn
+	err  error
+}
+
+func (d *providerDialer) race(ctx context.Context, network, port string, primaries, fallbacks []net.IP) (net.Conn, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make(chan dialResult, 2)
+	start := func(ips []net.IP) {
+		go func() {
+			conn, err := d.dialSerial(ctx, network, port, ips)
+			results <- dialResult{conn, err}
+		}()
+	}
+
+	start(primaries)
+	pending := 1
+	var fallbackTimer <-chan time.Time
+	if len(fallbacks) > 0 {
+		timer := time.NewTimer(d.fallbackDelay)
+		defer timer.Stop()
+		fallbackTimer = timer.C
+	}
+
+	var firstErr error
+	for pending > 0 || fallbackTimer != nil {
+		select {
+		case <-fallbackTimer:
+			fallbackTimer = nil
+			start(fallbacks)
+			pending++
+		case result := <-results:
+			pending--
+			if result.err == nil {
+				// * La otra carrera puede conectar igual: esa conexión se cierra
+				if pending > 0 {
+					go func() {
+						if late := <-results; late.conn != nil {
+							late.conn.Close()
+						}
+					}()
+				}
+				return result.conn, nil
+			}
+			if firstErr == nil {
+				firstErr = result.err
+			}
+			// * Falló la principal antes de tiempo: no tiene sentido esperar al timer
+			if fallbackTimer != nil {
+				fallbackTimer = nil
+				start(fallbacks)
+				pending++
+			}
+		}
+	}
+	return nil, firstErr
+}
+
+func (d *providerDialer) dialSerial(ctx context.Context, network, port string, ips []net.IP) (net.Conn, error) {
+	var firstErr error
+	for _, ip := range ips {
+		conn, err := d.dialer.DialContext(ctx, network, net.JoinHostPort(ip.String(), port))
+		if err == nil {
+			return conn, nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return nil, firstErr
+}
+
+// * Reemplaza la resolución y el dial del transporte de proveedores
+func (t *ProviderTransport) UseDNSCache(cache *DNSCache, family string, fallbackDelay time.Duration) error {
+	switch family {
+	case IPFamilyAny, IPFamilyPreferIPv4, IPFamilyIPv4, IPFamilyIPv6:
+	default:
+		return fmt.Errorf("familia de IP inválida: %q (any, prefer-ipv4, ipv4 o ipv6)", family)
+	}
+	dialer := &providerDialer{
+		cache:         cache,
+		family:        family,
+		fallbackDelay: fallbackDelay,
+		dialer:        net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second},
+	}
+	t.base.DialContext = dialer.DialContext
+	t.dns = cache
+	t.ipFamily = family
+	return nil
+}
+
+func (t *ProviderTransport) DNSStats() *m.DNSCacheStats {
+	if t == nil || t.dns == nil {
+		return nil
+	}
+	stats := t.dns.Stats(t.ipFamily)
+	return &stats
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// * Hosts a los que habla cada proveedor: la API y el CDN de las imágenes
+var providerHosts = map[string][]string{
+	"cataas":    {"cataas.com"},
+	"thecatapi": {"api.thecatapi.com", "cdn2.thecatapi.com"},
+}
+
+// * Transporte de todo lo que sale hacia los proveedores (URLs, HEAD del validador y
+// * descargas del proxy): proxy corporativo, User-Agent con contacto, headers propios y
+// * presupuesto de peticiones por proveedor
+type ProviderTransport struct {
+	base       *http.Transport
+	userAgent  string
+	headers    map[string]http.Header // por host
+	budgets    map[string]*outboundBudget
+	budgetList []*outboundBudget
+	dns        *DNSCache
+	ipFamily   string
+	chaos      *Chaos
+	// * Lo registra NewProviderMix si el mix incluye el proveedor offline
+	offline *offlineProvider
+}
+
+// * proxyURL vacío respeta HTTPS_PROXY/HTTP_PROXY/NO_PROXY del entorno
+func NewProviderTransport(proxyURL, userAgent string, headers map[string]http.Header) (*ProviderTransport, error) {
+	base := http.DefaultTransport.(*http.Transport).Clone()
+	if proxyURL != "" {
+		parsed, err := url.Parse(proxyURL)
+		if err != nil || parsed.Host == "" {
+			return nil, fmt.Errorf("proxy inválido: %q", proxyURL)
+		}
+		base.Proxy = http.ProxyURL(parsed)
+	}
+
+	byHost := make(map[string]http.Header)
+	for provider, header := range headers {
+		hosts, ok := providerHosts[provider]
+		if !ok {
+			return nil, fmt.Errorf("proveedor desconocido en headers: %s", provider)
+		}
+		for _, host := range hosts {
+			byHost[host] = header
+		}
+	}
+	return &ProviderTransport{
+		base:      base,
+		userAgent: userAgent,
+		headers:   byHost,
+		budgets:   make(map[string]*outboundBudget),
+	}, nil
+}
+
+// * Formato "cataas:X-Contact=ops@ejemplo.com;thecatapi:X-Team=gatos"
+func ParseProviderHeaders(spec string) (map[string]http.Header, error) {
+	headers := make(map[string]http.Header)
+	for _, part := range strings.Split(spec, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		provider, pair, ok := strings.Cut(part, ":")
+		name, value, hasValue := strings.Cut(pair, "=")
+		if !ok || !hasValue || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("header inválido: %q (se espera proveedor:Nombre=valor)", part)
+		}
+		provider = strings.ToLower(strings.TrimSpace(provider))
+		if headers[provider] == nil {
+			headers[provider] = make(http.Header)
+		}
+		headers[provider].Add(strings.TrimSpace(name), strings.TrimSpace(value))
+	}
+	return headers, nil
+}
+
+// * RoundTrip no debe modificar la petición original: se clona antes de tocar headers
+func (t *ProviderTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.chaos == nil {
+		return t.roundTrip(req)
+	}
+
+	malformed, err := t.chaos.upstream(req.Context(), providerForHost(req.URL.Hostname()))
+	if err != nil {
+		return nil, err
+	}
+	resp, err := t.roundTrip(req)
+	if err != nil || !malformed {
+		return resp, err
+	}
+	return malformResponse(resp), nil
+}
+
+func (t *ProviderTransport) roundTrip(req *http.Request) (*http.Response, error) {
+	if t.offline != nil && t.offline.owns(req.URL) {
+		return t.offline.respond(req), nil
+	}
+	if budget, ok := t.budgets[req.URL.Hostname()]; ok {
+		if err := budget.wait(req.Context()); err != nil {
+			return nil, err
+		}
+	}
+
+	extra := t.headers[req.URL.Hostname()]
+	if t.userAgent == "" && len(extra) == 0 {
+		return t.base.RoundTrip(req)
+	}
+
+	req = req.Clone(req.Context())
+	if t.userAgent != "" && req.Header.Get("User-Agent") == "" {
+		req.Header.Set("User-Agent", t.userAgent)
+	}
+	for name, values := range extra {
+		req.Header[name] = append([]string(nil), values...)
+	}
+	return t.base.RoundTrip(req)
+}
+
+// * nil-safe: sin transporte configurado queda el de Go por defecto
+func (t *ProviderTransport) Client(timeout time.Duration) *http.Client {
+	client := &http.Client{Timeout: timeout}
+	if t != nil {
+		client.Transport = t
+	}
+	return client
+}
@@ -0,0 +1,478 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+	"github.com/ChrisTheAbysswalker/meownder-backend/retry"
+)
+
+// * Fuente de imágenes de gatos; cada proveedor sabe armar (o pedir) una URL nueva
+type ImageProvider interface {
+	Name() string
+	Host() string
+	// * true si NewURL consulta la red: un éxito ya indica que el proveedor está sano
+	Remote() bool
+	Supports(opts m.ImageOptions) bool
+	NewURL(ctx context.Context, opts m.ImageOptions) (m.CatURL, error)
+}
+
+type ProviderWeight struct {
+	Name   string
+	Weight int
+}
+
+// * Formato: "cataas=70,thecatapi=30"
+func ParseProviderWeights(spec string) ([]ProviderWeight, error) {
+	var weights []ProviderWeight
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, rawWeight, found := strings.Cut(part, "=")
+		weight := 1
+		if found {
+			w, err := strconv.Atoi(strings.TrimSpace(rawWeight))
+			if err != nil || w < 0 {
+				return nil, fmt.Errorf("peso inválido para %q: %s", name, rawWeight)
+			}
+			weight = w
+		}
+		weights = append(weights, ProviderWeight{Name: strings.ToLower(strings.TrimSpace(name)), Weight: weight})
+	}
+	if len(weights) == 0 {
+		return nil, fmt.Errorf("no hay proveedores configurados")
+	}
+	return weights, nil
+}
+
+type weightedProvider struct {
+	ImageProvider
+	// * Atómico: IMAGE_PROVIDERS se puede recargar en caliente
+	weight   atomic.Int32
+	failures atomic.Int32
+	recent   map[string]bool
+	mutex    sync.Mutex
+}
+
+// * Pesos efectivos: cada fallo seguido reduce a la mitad el peso del proveedor (hasta 1/16)
+// * sin sacarlo del todo, así puede recuperarse cuando vuelve a responder
+func (p *weightedProvider) effectiveWeight() int {
+	failures := min(int(p.failures.Load()), 4)
+	weight := int(p.weight.Load())
+	if weight == 0 {
+		return 0
+	}
+	return max(weight>>failures, 1)
+}
+
+// * Dedup por proveedor: dos proveedores nunca comparten URLs
+func (p *weightedProvider) markSeen(catURL string) bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.recent[catURL] {
+		return false
+	}
+	p.recent[catURL] = true
+	return true
+}
+
+func (p *weightedProvider) cleanRecent() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if len(p.recent) > 50 {
+		p.recent = make(map[string]bool)
+		log.Printf("🧹 Cache de %s limpiado", p.Name())
+	}
+}
+
+func (p *weightedProvider) reportCall(err error) {
+	if errors.Is(err, ErrOutboundBudget) {
+		return
+	}
+	if err != nil {
+		p.report(false)
+	} else if p.Remote() {
+		p.report(true)
+	}
+}
+
+func (p *weightedProvider) report(ok bool) {
+	if ok {
+		p.failures.Store(0)
+		return
+	}
+	p.failures.Add(1)
+}
+
+type ProviderMix struct {
+	providers []*weightedProvider
+	transport *ProviderTransport
+	// * nil salvo con el proveedor offline en IMAGE_PROVIDERS
+	offline *offlineProvider
+}
+
+// * transport puede ser nil: entonces se usa el cliente HTTP por defecto de Go. baseURL
+// * solo lo usa el proveedor offline, que sirve las imágenes desde este mismo servidor
+func NewProviderMix(weights []ProviderWeight, theCatAPIKey, baseURL string, transport *ProviderTransport) (*ProviderMix, error) {
+	mix := &ProviderMix{transport: transport}
+	for _, w := range weights {
+		var provider ImageProvider
+		switch w.Name {
+		case "cataas":
+			provider = cataasProvider{}
+		case "thecatapi":
+			provider = newTheCatAPIProvider(theCatAPIKey, transport)
+		case "offline":
+			offline, err := newOfflineProvider(baseURL)
+			if err != nil {
+				return nil, err
+			}
+			if transport != nil {
+				transport.offline = offline
+			}
+			mix.offline = offline
+			provider = offline
+		default:
+			return nil, fmt.Errorf("proveedor desconocido: %s", w.Name)
+		}
+		weighted := &weightedProvider{
+			ImageProvider: provider,
+			recent:        make(map[string]bool),
+		}
+		weighted.weight.Store(int32(w.Weight))
+		mix.providers = append(mix.providers, weighted)
+	}
+	return mix, nil
+}
+
+// * Cambia los pesos sin reiniciar. Solo entre los proveedores que ya están en el mix
+// * (agregar uno requiere reiniciar); los que no aparecen quedan con peso 0
+func (mix *ProviderMix) SetWeights(weights []ProviderWeight) error {
+	next := make(map[string]int, len(weights))
+	total := 0
+	for _, w := range weights {
+		if mix.provider(w.Name) == nil {
+			return fmt.Errorf("el proveedor %s no está en el mix; agregarlo requiere reiniciar", w.Name)
+		}
+		next[w.Name] = w.Weight
+		total += w.Weight
+	}
+	if total == 0 {
+		return fmt.Errorf("todos los proveedores quedarían con peso 0")
+	}
+
+	for _, p := range mix.providers {
+		p.weight.Store(int32(next[p.Name()]))
+	}
+	return nil
+}
+
+func (mix *ProviderMix) Has(name string) bool {
+	return mix.provider(name) != nil
+}
+
+func (mix *ProviderMix) provider(name string) *weightedProvider {
+	for _, p := range mix.providers {
+		if p.Name() == name {
+			return p
+		}
+	}
+	return nil
+}
+
+// * Para los clientes que hablan con los proveedores fuera del mix (validador, proxy)
+func (mix *ProviderMix) Transport() *ProviderTransport {
+	if mix == nil {
+		return nil
+	}
+	return mix.transport
+}
+
+// * Reparte count entre los proveedores que soportan las opciones, proporcional al
+// * peso efectivo (método del resto mayor para que la suma dé exacta)
+func (mix *ProviderMix) compose(count int, opts m.ImageOptions) []*weightedProvider {
+	var eligible []*weightedProvider
+	total := 0
+	for _, p := range mix.providers {
+		if w := p.effectiveWeight(); w > 0 && p.Supports(opts) {
+			eligible = append(eligible, p)
+			total += w
+		}
+	}
+	if total == 0 {
+		return nil
+	}
+
+	type share struct {
+		provider  *weightedProvider
+		count     int
+		remainder int
+	}
+	shares := make([]share, len(eligible))
+	assigned := 0
+	for i, p := range eligible {
+		exact := count * p.effectiveWeight()
+		shares[i] = share{provider: p, count: exact / total, remainder: exact % total}
+		assigned += shares[i].count
+	}
+	sort.SliceStable(shares, func(i, j int) bool { return shares[i].remainder > shares[j].remainder })
+	for i := 0; assigned < count; i = (i + 1) % len(shares) {
+		shares[i].count++
+		assigned++
+	}
+
+	slots := make([]*weightedProvider, 0, count)
+	for _, sh := range shares {
+		for i := 0; i < sh.count; i++ {
+			slots = append(slots, sh.provider)
+		}
+	}
+	return slots
+}
+
+func (mix *ProviderMix) fallback(failed *weightedProvider, opts m.ImageOptions) *weightedProvider {
+	best := failed
+	bestWeight := 0
+	for _, p := range mix.providers {
+		if p == failed || !p.Supports(opts) {
+			continue
+		}
+		if w := p.effectiveWeight(); w > bestWeight {
+			best, bestWeight = p, w
+		}
+	}
+	return best
+}
+
+// * Devuelve el nombre del proveedor dueño de la URL ("" si no es de ninguno)
+func (mix *ProviderMix) report(catURL string, ok bool) string {
+	parsed, err := url.Parse(catURL)
+	if err != nil {
+		return ""
+	}
+	for _, p := range mix.providers {
+		if p.Host() == parsed.Host {
+			p.report(ok)
+			return p.Name()
+		}
+	}
+	return ""
+}
+
+func (mix *ProviderMix) cleanRecent() {
+	for _, p := range mix.providers {
+		p.cleanRecent()
+	}
+}
+
+// * Proveedores con tantos fallos seguidos que el mix ya los da por caídos
+func (mix *ProviderMix) OpenCircuits() []string {
+	var open []string
+	for _, p := range mix.providers {
+		if p.failures.Load() >= providerDownThreshold {
+			open = append(open, p.Name())
+		}
+	}
+	return open
+}
+
+func (mix *ProviderMix) Stats() []m.ProviderStats {
+	stats := make([]m.ProviderStats, 0, len(mix.providers))
+	for _, p := range mix.providers {
+		stats = append(stats, m.ProviderStats{
+			Name:            p.Name(),
+			Weight:          int(p.weight.Load()),
+			EffectiveWeight: p.effectiveWeight(),
+			Failures:        int(p.failures.Load()),
+		})
+	}
+	return stats
+}
+
+type cataasProvider struct{}
+
+func (cataasProvider) Name() string { return "cataas" }
+
+func (cataasProvider) Host() string { return "cataas.com" }
+
+func (cataasProvider) Remote() bool { return false }
+
+func (cataasProvider) Supports(opts m.ImageOptions) bool { return true }
+
+func (cataasProvider) NewURL(ctx context.Context, opts m.ImageOptions) (m.CatURL, error) {
+	return newCataasURL(opts), nil
+}
+
+func newCataasURL(opts m.ImageOptions) m.CatURL {
+	timestamp := time.Now().UnixNano()
+	baseURL := "https://cataas.com/cat"
+	if opts.Tag != "" {
+		baseURL = fmt.Sprintf("%s/%s", baseURL, opts.Tag)
+	}
+	if opts.Says != "" {
+		baseURL = fmt.Sprintf("%s/says/%s", baseURL, url.PathEscape(opts.Says))
+	}
+
+	randNum, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		log.Printf("Error generando número aleatorio: %v", err)
+		randNum = big.NewInt(0)
+	}
+
+	catURL := fmt.Sprintf("%s?timestamp=%d&rand=%s", baseURL, timestamp, randNum.String())
+	if opts.Size != "" {
+		catURL = fmt.Sprintf("%s&type=%s", catURL, opts.Size)
+	}
+	if opts.Filter != "" {
+		catURL = fmt.Sprintf("%s&filter=%s", catURL, opts.Filter)
+	}
+	id := fmt.Sprintf("cat-%d-%s", timestamp, randNum.String())
+
+	return m.CatURL{
+		URL:       catURL,
+		ID:        id,
+		Timestamp: timestamp,
+	}
+}
+
+// * TheCatAPI no expone una URL aleatoria directa: hay que pedir una imagen a la API
+type theCatAPIProvider struct {
+	client *http.Client
+	apiKey string
+}
+
+// * Equivalencias de los tamaños de cataas con los de TheCatAPI
+var theCatAPISizes = map[string]string{
+	"xsmall": "thumb",
+	"small":  "small",
+	"medium": "med",
+}
+
+func newTheCatAPIProvider(apiKey string, transport *ProviderTransport) *theCatAPIProvider {
+	return &theCatAPIProvider{
+		client: transport.Client(3 * time.Second),
+		apiKey: apiKey,
+	}
+}
+
+func (p *theCatAPIProvider) Name() string { return "thecatapi" }
+
+func (p *theCatAPIProvider) Host() string { return "cdn2.thecatapi.com" }
+
+func (p *theCatAPIProvider) Remote() bool { return true }
+
+// ! No soporta los tags de cataas ni el recorte cuadrado
+func (p *theCatAPIProvider) Supports(opts m.ImageOptions) bool {
+	if opts.Tag != "" {
+		return false
+	}
+	_, ok := theCatAPISizes[opts.Size]
+	return opts.Size == "" || ok
+}
+
+func (p *theCatAPIProvider) NewURL(ctx context.Context, opts m.ImageOptions) (m.CatURL, error) {
+	endpoint := "https://api.thecatapi.com/v1/images/search?limit=1"
+	if size, ok := theCatAPISizes[opts.Size]; ok {
+		endpoint += "&size=" + size
+	}
+	if opts.StaticOnly {
+		endpoint += "&mime_types=jpg,png"
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return m.CatURL{}, retry.Permanent(err)
+	}
+	if p.apiKey != "" {
+		req.Header.Set("x-api-key", p.apiKey)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return m.CatURL{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return m.CatURL{}, fmt.Errorf("thecatapi respondió %d", resp.StatusCode)
+	}
+
+	var images []struct {
+		ID  string `json:"id"`
+		URL string `json:"url"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&images); err != nil {
+		return m.CatURL{}, fmt.Errorf("respuesta inválida de thecatapi: %w", err)
+	}
+	if len(images) == 0 {
+		return m.CatURL{}, fmt.Errorf("thecatapi no devolvió imágenes")
+	}
+
+	return m.CatURL{
+		URL:       images[0].URL,
+		ID:        "thecatapi-" + images[0].ID,
+		Timestamp: time.Now().UnixNano(),
+	}, nil
+}
+
+type ProviderProbe struct {
+	Name    string
+	Latency time.Duration
+	Err     error
+}
+
+// * Pide una URL a cada proveedor y le hace HEAD, sin tocar pesos ni contadores de fallos
+func (mix *ProviderMix) Probe(ctx context.Context, timeout time.Duration) []ProviderProbe {
+	client := mix.transport.Client(timeout)
+	probes := make([]ProviderProbe, len(mix.providers))
+
+	var wg sync.WaitGroup
+	for i, p := range mix.providers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			start := time.Now()
+			probes[i] = ProviderProbe{Name: p.Name(), Err: probeProvider(ctx, client, p)}
+			probes[i].Latency = time.Since(start)
+		}()
+	}
+	wg.Wait()
+	return probes
+}
+
+func probeProvider(ctx context.Context, client *http.Client, provider ImageProvider) error {
+	catURL, err := provider.NewURL(ctx, m.ImageOptions{})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, catURL.URL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("HEAD %s respondió %d", catURL.URL, resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,202 @@
+package services
+
+import (
+	"fmt"
+	"hash/fnv"
+	"log"
+	"math/rand/v2"
+	"sort"
+	"sync"
+	"time"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+)
+
+const (
+	questsPerDay     = 3
+	questReward      = 1
+	seniorCatAge     = 10
+	questBreedTarget = 3
+)
+
+// * Misiones diarias deterministas por usuario y día (UTC): el progreso se recalcula desde el
+// * historial y solo se guarda qué recompensas ya se entregaron
+type QuestService struct {
+	swipes   *SwipeService
+	cats     *CatService
+	chat     *ChatService
+	rewarded map[string]bool
+	mutex    sync.Mutex
+}
+
+func NewQuestService(swipes *SwipeService, cats *CatService, chat *ChatService) *QuestService {
+	service := &QuestService{
+		swipes:   swipes,
+		cats:     cats,
+		chat:     chat,
+		rewarded: make(map[string]bool),
+	}
+	swipes.OnRecord(func(swipe m.Swipe, matches []m.Match) {
+		service.sync(swipe.UserID, swipe.CreatedAt)
+		for _, match := range matches {
+			if match.UserID != swipe.UserID {
+				service.sync(match.UserID, swipe.CreatedAt)
+			}
+		}
+	})
+	chat.OnMessage(func(message m.Message) {
+		service.sync(message.SenderID, message.CreatedAt)
+	})
+	return service
+}
+
+func (s *QuestService) Quests(userID string) m.QuestsResponse {
+	now := time.Now()
+	day := utcDay(now)
+	return m.QuestsResponse{
+		Day:        day.Format(streakDayLayout),
+		Quests:     s.sync(userID, now),
+		SuperLikes: s.swipes.SuperLikes(userID),
+		ResetsAt:   day.Add(24 * time.Hour),
+	}
+}
+
+// * Calcula el progreso y acredita las misiones recién completadas (una sola vez)
+func (s *QuestService) sync(userID string, now time.Time) []m.Quest {
+	day := utcDay(now)
+	quests := s.daily(userID, day)
+	s.measure(userID, day, quests)
+
+	for i := range quests {
+		quest := &quests[i]
+		key := userID + "|" + quest.ID
+
+		s.mutex.Lock()
+		already := s.rewarded[key]
+		grant := quest.Done && !already
+		if grant {
+			s.rewarded[key] = true
+		}
+		s.mutex.Unlock()
+
+		quest.Rewarded = already || grant
+		if !grant {
+			continue
+		}
+		balance := s.swipes.GrantSuperLikes(userID, quest.Reward)
+		log.Printf("🎯 %s completó \"%s\" (+%d super-like, saldo %d)", userID, quest.Title, quest.Reward, balance)
+		completed := *quest
+		s.chat.Notify(userID, m.ChatEvent{
+			Type:  m.ChatEventQuest,
+			Quest: &completed,
+			Text:  fmt.Sprintf("¡Misión completada! +%d super-like", quest.Reward),
+			At:    now,
+		})
+	}
+	return quests
+}
+
+func (s *QuestService) daily(userID string, day time.Time) []m.Quest {
+	hash := fnv.New64a()
+	hash.Write([]byte(userID + "|" + day.Format(streakDayLayout)))
+	seed := hash.Sum64()
+	rng := rand.New(rand.NewPCG(seed, seed))
+
+	catalog := s.cats.GetCatProfiles()
+	breedCount := make(map[string]int)
+	breedName := make(map[string]string)
+	seniors := 0
+	for _, cat := range catalog {
+		if cat.OwnerID == userID {
+			continue
+		}
+		key := normalizeKey(cat.Breed)
+		breedCount[key]++
+		breedName[key] = cat.Breed
+		if cat.Age >= seniorCatAge {
+			seniors++
+		}
+	}
+
+	candidates := []m.Quest{
+		{Kind: m.QuestSwipes, Title: "Desliza 10 perfiles", Target: 10},
+		{Kind: m.QuestMatch, Title: "Consigue un match", Target: 1},
+	}
+	if len(breedCount) > 0 {
+		breeds := make([]string, 0, len(breedCount))
+		for key := range breedCount {
+			breeds = append(breeds, key)
+		}
+		sort.Strings(breeds)
+		key := breeds[rng.IntN(len(breeds))]
+		target := min(questBreedTarget, breedCount[key])
+		title := fmt.Sprintf("Dale like a %d gatos %s", target, breedName[key])
+		if target == 1 {
+			title = fmt.Sprintf("Dale like a un gato %s", breedName[key])
+		}
+		candidates = append(candidates, m.Quest{Kind: m.QuestLikeBreed, Title: title, Target: target, Param: breedName[key]})
+	}
+	if seniors >= 2 {
+		candidates = append(candidates, m.Quest{Kind: m.QuestLikeSenior, Title: fmt.Sprintf("Dale like a 2 gatos de %d años o más", seniorCatAge), Target: 2})
+	}
+	// * Sin un match mutuo no hay con quién chatear
+	if len(s.swipes.MutualContacts(userID)) > 0 {
+		candidates = append(candidates, m.Quest{Kind: m.QuestSendMessage, Title: "Envía un mensaje en un chat", Target: 1})
+	}
+
+	rng.Shuffle(len(candidates), func(i, j int) {
+		candidates[i], candidates[j] = candidates[j], candidates[i]
+	})
+	quests := candidates[:min(questsPerDay, len(candidates))]
+	for i := range quests {
+		quests[i].ID = day.Format(streakDayLayout) + "-" + quests[i].Kind
+		quests[i].Reward = questReward
+	}
+	return quests
+}
+
+func (s *QuestService) measure(userID string, day time.Time, quests []m.Quest) {
+	var swipes, seniorLikes, matches int
+	breedLikes := make(map[string]int)
+
+	for _, swipe := range s.swipes.History(userID) {
+		if swipe.CreatedAt.Before(day) {
+			continue
+		}
+		swipes++
+		if !m.IsLike(swipe.Direction) {
+			continue
+		}
+		cat, err := s.cats.AdminGetCatProfile(swipe.CatID)
+		if err != nil {
+			continue
+		}
+		breedLikes[normalizeKey(cat.Breed)]++
+		if cat.Age >= seniorCatAge {
+			seniorLikes++
+		}
+	}
+	for _, match := range s.swipes.allMatches(userID) {
+		if !match.CreatedAt.Before(day) {
+			matches++
+		}
+	}
+
+	for i := range quests {
+		quest := &quests[i]
+		switch quest.Kind {
+		case m.QuestSwipes:
+			quest.Progress = swipes
+		case m.QuestMatch:
+			quest.Progress = matches
+		case m.QuestLikeBreed:
+			quest.Progress = breedLikes[normalizeKey(quest.Param)]
+		case m.QuestLikeSenior:
+			quest.Progress = seniorLikes
+		case m.QuestSendMessage:
+			quest.Progress = s.chat.SentSince(userID, day)
+		}
+		quest.Progress = min(quest.Progress, quest.Target)
+		quest.Done = quest.Progress >= quest.Target
+	}
+}
@@ -0,0 +1,180 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ChrisTheAbysswalker/meownder-backend/content"
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+)
+
+const quizSection = "quiz"
+
+var ErrInvalidQuizAnswers = errors.New("respuestas del quiz inválidas")
+
+type quizArchetype struct {
+	ID string
+	// * Raíces de palabras de la personalidad (sin género): "cariños" cubre cariñoso y cariñosa
+	Keywords []string
+}
+
+// ! El orden desempata: ante igualdad de puntos gana el primero
+var quizArchetypes = []quizArchetype{
+	{ID: "adventurer", Keywords: []string{"aventurer", "valiente", "atlétic", "competitiv", "energétic", "hiperactiv", "cazador", "feroz", "protector"}},
+	{ID: "homebody", Keywords: []string{"relajad", "tranquil", "dulce", "cariños", "maternal", "paciente", "glotón", "leal"}},
+	{ID: "thinker", Keywords: []string{"intelectual", "misterios", "independiente", "elegante", "digno", "formal", "educad", "majestuos", "sofisticad"}},
+	{ID: "clown", Keywords: []string{"payaso", "juguet", "travies", "adorable", "fotogénic", "vocal", "curios", "mimad"}},
+}
+
+type quizQuestion struct {
+	ID string
+	// * opción -> arquetipo al que suma un punto
+	Options []quizOption
+}
+
+type quizOption struct {
+	ID        string
+	Archetype string
+}
+
+// * La estructura vive aquí y los textos en content/quiz: todos los idiomas puntúan igual
+var quizQuestions = []quizQuestion{
+	{ID: "weekend", Options: []quizOption{{"hike", "adventurer"}, {"sofa", "homebody"}, {"museum", "thinker"}, {"party", "clown"}}},
+	{ID: "glass", Options: []quizOption{{"chase", "adventurer"}, {"hug", "homebody"}, {"analyze", "thinker"}, {"record", "clown"}}},
+	{ID: "date", Options: []quizOption{{"camping", "adventurer"}, {"cooking", "homebody"}, {"bookshop", "thinker"}, {"karaoke", "clown"}}},
+	{ID: "morning", Options: []quizOption{{"run", "adventurer"}, {"breakfast", "homebody"}, {"news", "thinker"}, {"dance", "clown"}}},
+	{ID: "superpower", Options: []quizOption{{"land", "adventurer"}, {"naps", "homebody"}, {"mind", "thinker"}, {"laugh", "clown"}}},
+}
+
+type quizContent struct {
+	Questions map[string]struct {
+		Text    string            `json:"text"`
+		Options map[string]string `json:"options"`
+	} `json:"questions"`
+	Archetypes map[string]struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+	} `json:"archetypes"`
+}
+
+func QuizLocale(requested, acceptLanguage string) string {
+	if requested != "" {
+		return requested
+	}
+	return content.Negotiate(quizSection, acceptLanguage)
+}
+
+func Quiz(locale string) (*m.QuizResponse, error) {
+	text, err := content.Load[quizContent](quizSection, locale)
+	if err != nil {
+		return nil, err
+	}
+
+	questions := make([]m.QuizQuestion, 0, len(quizQuestions))
+	for _, question := range quizQuestions {
+		localized := text.Questions[question.ID]
+		options := make([]m.QuizOption, 0, len(question.Options))
+		for _, option := range question.Options {
+			options = append(options, m.QuizOption{ID: option.ID, Text: localized.Options[option.ID]})
+		}
+		questions = append(questions, m.QuizQuestion{ID: question.ID, Text: localized.Text, Options: options})
+	}
+	return &m.QuizResponse{Locale: locale, Questions: questions}, nil
+}
+
+// * Exige una respuesta por pregunta; el arquetipo ganador se guarda en las preferencias
+// * sin tocar el resto de campos
+func (s *SwipeService) ScoreQuiz(userID string, answers []m.QuizAnswer, locale string) (*m.QuizResult, error) {
+	chosen := make(map[string]string, len(answers))
+	for _, answer := range answers {
+		if _, dup := chosen[answer.QuestionID]; dup {
+			return nil, fmt.Errorf("%w: pregunta %q repetida", ErrInvalidQuizAnswers, answer.QuestionID)
+		}
+		chosen[answer.QuestionID] = answer.OptionID
+	}
+
+	scores := make(map[string]int, len(quizArchetypes))
+	for _, archetype := range quizArchetypes {
+		scores[archetype.ID] = 0
+	}
+	for _, question := range quizQuestions {
+		optionID, ok := chosen[question.ID]
+		if !ok {
+			return nil, fmt.Errorf("%w: falta responder %q", ErrInvalidQuizAnswers, question.ID)
+		}
+		archetype := ""
+		for _, option := range question.Options {
+			if option.ID == optionID {
+				archetype = option.Archetype
+				break
+			}
+		}
+		if archetype == "" {
+			return nil, fmt.Errorf("%w: opción %q desconocida en %q", ErrInvalidQuizAnswers, optionID, question.ID)
+		}
+		scores[archetype]++
+		delete(chosen, question.ID)
+	}
+	for questionID := range chosen {
+		return nil, fmt.Errorf("%w: pregunta %q desconocida", ErrInvalidQuizAnswers, questionID)
+	}
+
+	winner := quizArchetypes[0].ID
+	for _, archetype := range quizArchetypes {
+		if scores[archetype.ID] > scores[winner] {
+			winner = archetype.ID
+		}
+	}
+
+	text, err := content.Load[quizContent](quizSection, locale)
+	if err != nil {
+		return nil, err
+	}
+
+	prefs := s.Preferences(userID)
+	prefs.Archetype = winner
+	prefs = s.SetPreferences(userID, prefs)
+
+	return &m.QuizResult{
+		Archetype: m.Archetype{
+			ID:          winner,
+			Name:        text.Archetypes[winner].Name,
+			Description: text.Archetypes[winner].Description,
+		},
+		Scores:      scores,
+		Preferences: prefs,
+	}, nil
+}
+
+// * Cuántas palabras clave del arquetipo aparecen en la personalidad del gato
+func archetypeAffinity(cat m.CatProfile, archetypeID string) int {
+	if archetypeID == "" {
+		return 0
+	}
+	personality := strings.ToLower(cat.Personality)
+	for _, archetype := range quizArchetypes {
+		if archetype.ID != archetypeID {
+			continue
+		}
+		affinity := 0
+		for _, keyword := range archetype.Keywords {
+			if strings.Contains(personality, keyword) {
+				affinity++
+			}
+		}
+		return affinity
+	}
+	return 0
+}
+
+// * Orden estable: dentro del mismo nivel de afinidad se respeta el barajado de la semilla
+func rankByArchetype(cats []m.CatProfile, archetypeID string) {
+	if archetypeID == "" {
+		return
+	}
+	sort.SliceStable(cats, func(i, j int) bool {
+		return archetypeAffinity(cats[i], archetypeID) > archetypeAffinity(cats[j], archetypeID)
+	})
+}
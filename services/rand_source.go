@@ -0,0 +1,48 @@
+package services
+
+import (
+	"math/rand/v2"
+	"sync"
+)
+
+// * Toda la aleatoriedad de los servicios pasa por aquí para poder fijar la semilla
+type RandSource interface {
+	IntN(n int) int
+	Int64N(n int64) int64
+	Float64() float64
+	Shuffle(n int, swap func(i, j int))
+}
+
+// * *rand.Rand no es seguro entre goroutines, así que se serializa con un mutex
+type lockedRand struct {
+	rng   *rand.Rand
+	mutex sync.Mutex
+}
+
+func NewRandSource(seed uint64) RandSource {
+	return &lockedRand{rng: rand.New(rand.NewPCG(seed, seed))}
+}
+
+func (r *lockedRand) IntN(n int) int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.rng.IntN(n)
+}
+
+func (r *lockedRand) Int64N(n int64) int64 {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.rng.Int64N(n)
+}
+
+func (r *lockedRand) Float64() float64 {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.rng.Float64()
+}
+
+func (r *lockedRand) Shuffle(n int, swap func(i, j int)) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.rng.Shuffle(n, swap)
+}
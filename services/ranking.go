@@ -0,0 +1,207 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+)
+
+// * Reordena en el lugar un mazo ya barajado y filtrado por preferencias
+type DeckRanker interface {
+	Name() string
+	Rank(cats []m.CatProfile, prefs m.Preferences)
+}
+
+var deckRankers = map[string]DeckRanker{
+	"archetype": archetypeRanker{},
+	"diverse":   diverseRanker{},
+}
+
+func ParseDeckRanker(name string) (DeckRanker, error) {
+	ranker, ok := deckRankers[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return nil, fmt.Errorf("ranking desconocido: %q", name)
+	}
+	return ranker, nil
+}
+
+// * El ranking de siempre: compatibles con el arquetipo del quiz primero
+type archetypeRanker struct{}
+
+func (archetypeRanker) Name() string { return "archetype" }
+
+func (archetypeRanker) Rank(cats []m.CatProfile, prefs m.Preferences) {
+	rankByArchetype(cats, prefs.Archetype)
+}
+
+// * Como archetype, pero evita dos gatos seguidos de la misma raza mientras haya otra
+// * opción entre los que quedan del mismo nivel de afinidad o menor
+type diverseRanker struct{}
+
+func (diverseRanker) Name() string { return "diverse" }
+
+func (diverseRanker) Rank(cats []m.CatProfile, prefs m.Preferences) {
+	rankByArchetype(cats, prefs.Archetype)
+	for i := 1; i < len(cats); i++ {
+		if normalizeKey(cats[i].Breed) != normalizeKey(cats[i-1].Breed) {
+			continue
+		}
+		for j := i + 1; j < len(cats); j++ {
+			if normalizeKey(cats[j].Breed) != normalizeKey(cats[i-1].Breed) {
+				// * Se desplaza el bloque para no alterar el orden relativo del resto
+				next := cats[j]
+				copy(cats[i+1:j+1], cats[i:j])
+				cats[i] = next
+				break
+			}
+		}
+	}
+}
+
+// * Corre un ranking candidato junto al vivo sin afectar lo que se sirve: se calcula
+// * fuera de la petición, sobre una muestra de mazos, y solo se guardan métricas
+type ShadowRanking struct {
+	candidate  DeckRanker
+	sampleRate float64
+	topK       int
+	// * Cupos para no acumular goroutines si el candidato es lento
+	slots chan struct{}
+
+	stats m.ShadowRankingStats
+	// * Sumas para los promedios de stats
+	overlapSum, displacementSum, candidateMsSum float64
+	firstMatches                                int
+	mutex                                       sync.Mutex
+}
+
+func NewShadowRanking(live, candidate DeckRanker, sampleRate float64, topK int) *ShadowRanking {
+	return &ShadowRanking{
+		candidate:  candidate,
+		sampleRate: sampleRate,
+		topK:       topK,
+		slots:      make(chan struct{}, 4),
+		stats: m.ShadowRankingStats{
+			Enabled:    true,
+			Live:       live.Name(),
+			Candidate:  candidate.Name(),
+			SampleRate: sampleRate,
+			TopK:       topK,
+		},
+	}
+}
+
+func (sr *ShadowRanking) sampled(rng RandSource) bool {
+	return sr != nil && rng.Float64() < sr.sampleRate
+}
+
+// * input es el mazo antes del ranking en vivo; liveIDs, el orden que se sirvió.
+// * Sin cupo libre la muestra se descarta: la sombra nunca debe frenar al mazo real
+func (sr *ShadowRanking) observe(input []m.CatProfile, prefs m.Preferences, liveIDs []int) {
+	select {
+	case sr.slots <- struct{}{}:
+	default:
+		return
+	}
+	go func() {
+		defer func() { <-sr.slots }()
+		sr.compare(input, prefs, liveIDs)
+	}()
+}
+
+func (sr *ShadowRanking) compare(input []m.CatProfile, prefs m.Preferences, liveIDs []int) {
+	start := time.Now()
+	failed := func() (failed bool) {
+		defer func() {
+			if r := recover(); r != nil {
+				log.Printf("⚠️ Ranking sombra %s falló: %v", sr.candidate.Name(), r)
+				failed = true
+			}
+		}()
+		sr.candidate.Rank(input, prefs)
+		return false
+	}()
+	elapsed := time.Since(start)
+
+	sr.mutex.Lock()
+	defer sr.mutex.Unlock()
+	if failed || len(input) != len(liveIDs) {
+		sr.stats.Failures++
+		return
+	}
+
+	candidateIDs := make([]int, len(input))
+	for i, cat := range input {
+		candidateIDs[i] = cat.ID
+	}
+	overlap := topOverlap(liveIDs, candidateIDs, sr.topK)
+	displacement := meanDisplacement(liveIDs, candidateIDs)
+
+	sr.stats.Samples++
+	sr.overlapSum += overlap
+	sr.displacementSum += displacement
+	sr.candidateMsSum += float64(elapsed.Microseconds()) / 1000
+	if len(liveIDs) > 0 && liveIDs[0] == candidateIDs[0] {
+		sr.firstMatches++
+	}
+	if overlap < 0.5 {
+		log.Printf("🌓 Ranking sombra %s diverge: top-%d compartido %.0f%%, desplazamiento medio %.1f", sr.candidate.Name(), sr.topK, overlap*100, displacement)
+	}
+}
+
+func (sr *ShadowRanking) Stats() m.ShadowRankingStats {
+	if sr == nil {
+		return m.ShadowRankingStats{}
+	}
+	sr.mutex.Lock()
+	defer sr.mutex.Unlock()
+
+	stats := sr.stats
+	if n := float64(stats.Samples); n > 0 {
+		stats.AvgTopOverlap = sr.overlapSum / n
+		stats.AvgDisplacement = sr.displacementSum / n
+		stats.AvgCandidateMs = sr.candidateMsSum / n
+		stats.FirstMatchRate = float64(sr.firstMatches) / n
+	}
+	return stats
+}
+
+func topOverlap(a, b []int, k int) float64 {
+	k = min(k, len(a), len(b))
+	if k == 0 {
+		return 1
+	}
+	top := make(map[int]bool, k)
+	for _, id := range a[:k] {
+		top[id] = true
+	}
+	shared := 0
+	for _, id := range b[:k] {
+		if top[id] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(k)
+}
+
+func meanDisplacement(a, b []int) float64 {
+	if len(a) == 0 {
+		return 0
+	}
+	position := make(map[int]int, len(a))
+	for i, id := range a {
+		position[id] = i
+	}
+	total := 0
+	for i, id := range b {
+		delta := position[id] - i
+		if delta < 0 {
+			delta = -delta
+		}
+		total += delta
+	}
+	return float64(total) / float64(len(a))
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"context"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type Readiness struct {
+	ready atomic.Bool
+}
+
+func NewReadiness() *Readiness {
+	return &Readiness{}
+}
+
+func (r *Readiness) MarkReady() {
+	r.ready.Store(true)
+}
+
+func (r *Readiness) IsReady() bool {
+	return r.ready.Load()
+}
+
+type WarmUpOptions struct {
+	URLs     int
+	Prefetch bool
+	Timeout  time.Duration
+}
+
+// * Calienta caches antes de declarar la instancia lista. Nunca bloquea la readiness
+// * más allá del timeout: si cataas falla arrancamos igual, solo que en frío
+func WarmUp(ctx context.Context, cats *CatService, proxy *ImageProxy, opts WarmUpOptions, readiness *Readiness) {
+	defer readiness.MarkReady()
+
+	ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	var wg sync.WaitGroup
+
+	if opts.URLs > 0 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cats.PrevalidateURLs(ctx, opts.URLs, 5*time.Second)
+		}()
+	}
+
+	if opts.Prefetch {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			profiles := cats.GetCatProfiles()
+			urls := make([]string, 0, len(profiles))
+			for _, cat := range profiles {
+				urls = append(urls, cat.Img)
+			}
+			proxy.Prefetch(ctx, urls)
+		}()
+	}
+
+	wg.Wait()
+	log.Printf("🔥 Warm-up completado en %s", time.Since(start).Round(time.Millisecond))
+}
@@ -0,0 +1,105 @@
+package services
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+)
+
+const (
+	requestSlot  = 10 * time.Second
+	requestSlots = 60
+	// * Cotas de latencia crecientes un 19% (2^¼) desde 0.5ms hasta ~32s: un p99 sale con
+	// * ese margen de error sin guardar cada muestra
+	latencyBuckets = 65
+	latencyBase    = 0.5
+)
+
+// * Ventana deslizante de peticiones HTTP en ranuras de 10s; alcanza para 10 minutos
+type requestWindow struct {
+	slots [requestSlots]requestSlotStats
+	mutex sync.Mutex
+}
+
+type requestSlotStats struct {
+	// * Número de ranura (unix / 10s); si no coincide con la actual, la ranura es vieja
+	index   int64
+	count   int64
+	errors  int64
+	latency [latencyBuckets + 1]int64
+}
+
+func (w *requestWindow) record(status int, latency time.Duration, now time.Time) {
+	index := now.UnixNano() / int64(requestSlot)
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	slot := &w.slots[index%requestSlots]
+	if slot.index != index {
+		*slot = requestSlotStats{index: index}
+	}
+	slot.count++
+	if status >= 500 {
+		slot.errors++
+	}
+	slot.latency[latencyBucket(latency)]++
+}
+
+func (w *requestWindow) summary(window time.Duration, now time.Time) m.RequestStats {
+	window = min(max(window, requestSlot), requestSlot*requestSlots)
+	current := now.UnixNano() / int64(requestSlot)
+	oldest := current - int64(window/requestSlot) + 1
+
+	var count, errors int64
+	var latency [latencyBuckets + 1]int64
+
+	w.mutex.Lock()
+	for i := range w.slots {
+		slot := &w.slots[i]
+		if slot.index < oldest || slot.index > current {
+			continue
+		}
+		count += slot.count
+		errors += slot.errors
+		for b, n := range slot.latency {
+			latency[b] += n
+		}
+	}
+	w.mutex.Unlock()
+
+	stats := m.RequestStats{
+		WindowSeconds: int(window.Seconds()),
+		Requests:      count,
+		Errors:        errors,
+	}
+	if count > 0 {
+		stats.ErrorRate = float64(errors) / float64(count)
+		stats.P50Ms = latencyQuantile(latency[:], count, 0.50)
+		stats.P99Ms = latencyQuantile(latency[:], count, 0.99)
+	}
+	return stats
+}
+
+func latencyBucket(latency time.Duration) int {
+	ms := float64(latency) / float64(time.Millisecond)
+	if ms <= latencyBase {
+		return 0
+	}
+	return min(int(math.Ceil(4*math.Log2(ms/latencyBase))), latencyBuckets)
+}
+
+// * Cota superior del bucket donde cae el cuantil
+func latencyQuantile(buckets []int64, count int64, q float64) float64 {
+	target := int64(math.Ceil(q * float64(count)))
+	var seen int64
+	for b, n := range buckets {
+		seen += n
+		if seen >= target {
+			return math.Round(latencyBase*math.Pow(2, float64(b)/4)*10) / 10
+		}
+	}
+	return math.Round(latencyBase*math.Pow(2, latencyBuckets/4.0)*10) / 10
+}
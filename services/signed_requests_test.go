@@ -0,0 +1,111 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// * Vector calculado aparte (hmac y hashlib de Python) con el formato del README
+const (
+	vectorSecret    = "secreto-del-norte"
+	vectorTimestamp = "1767225600"
+	vectorTarget    = "/api/admin/cats/7/status?notify=false"
+	vectorBody      = `{"status":"adopted"}`
+	vectorHash      = "fab0af96a62e88209d03212c76cd553d892b61c062db5d1a6ce14b691a061ab4"
+	vectorSignature = "585f31421c762529764e2d4a15d0a1a1f7bec2a058dec11452a7cb6c4c7c13e0"
+)
+
+const statusRoute = "/api/admin/cats/:id/status"
+
+func sign(secret, timestamp, method, target string, body []byte) (contentHash, signature string) {
+	sum := sha256.Sum256(body)
+	contentHash = hex.EncodeToString(sum[:])
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "\n" + method + "\n" + target + "\n" + contentHash))
+	return contentHash, hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerifySignatureKnownVector(t *testing.T) {
+	// * La marca del vector es fija: la ventana tiene que alcanzarla
+	signatures := NewRequestSignatures(100 * 365 * 24 * time.Hour)
+
+	status, err := signatures.VerifySignature(vectorSecret, "norte", "POST", statusRoute, vectorTarget, vectorTimestamp, vectorHash, vectorSignature, []byte(vectorBody))
+	if err != nil {
+		t.Fatalf("vector rechazado: %d %v", status, err)
+	}
+
+	// * La misma petición otra vez es una repetición, en mayúsculas también
+	_, err = signatures.VerifySignature(vectorSecret, "norte", "POST", statusRoute, vectorTarget, vectorTimestamp, vectorHash, vectorSignature, []byte(vectorBody))
+	if !errors.Is(err, ErrSignatureReplay) {
+		t.Fatalf("repetición: err = %v", err)
+	}
+	upper := "585F31421C762529764E2D4A15D0A1A1F7BEC2A058DEC11452A7CB6C4C7C13E0"
+	_, err = signatures.VerifySignature(vectorSecret, "norte", "POST", statusRoute, vectorTarget, vectorTimestamp, vectorHash, upper, []byte(vectorBody))
+	if !errors.Is(err, ErrSignatureReplay) {
+		t.Fatalf("repetición en mayúsculas: err = %v", err)
+	}
+}
+
+func TestVerifySignatureRejects(t *testing.T) {
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	old := strconv.FormatInt(time.Now().Add(-10*time.Minute).Unix(), 10)
+	body := []byte(vectorBody)
+	hash, signature := sign(vectorSecret, now, "POST", vectorTarget, body)
+	_, oldSignature := sign(vectorSecret, old, "POST", vectorTarget, body)
+	_, foreign := sign("otro-secreto", now, "POST", vectorTarget, body)
+	_, otherTarget := sign(vectorSecret, now, "POST", "/api/admin/cats/8/status", body)
+
+	cases := []struct {
+		name                       string
+		secret, method, route      string
+		timestamp, hash, signature string
+		body                       []byte
+		status                     int
+		err                        error
+	}{
+		{"ruta sin firma", vectorSecret, "GET", "/api/admin/backup", now, hash, signature, body, http.StatusForbidden, ErrSignatureScope},
+		{"patrocinio", vectorSecret, "PUT", "/api/admin/cats/:id/sponsor", now, hash, signature, body, http.StatusForbidden, ErrSignatureScope},
+		{"refugio sin secreto", "", "POST", statusRoute, now, hash, signature, body, http.StatusForbidden, ErrSigningDisabled},
+		{"marca vieja", vectorSecret, "POST", statusRoute, old, hash, oldSignature, body, http.StatusUnauthorized, ErrSignatureExpired},
+		{"marca ilegible", vectorSecret, "POST", statusRoute, "ayer", hash, signature, body, http.StatusUnauthorized, ErrSignatureTimestamp},
+		{"cuerpo cambiado", vectorSecret, "POST", statusRoute, now, hash, signature, []byte(`{"status":"paused"}`), http.StatusUnauthorized, ErrContentHash},
+		{"otro secreto", vectorSecret, "POST", statusRoute, now, hash, foreign, body, http.StatusUnauthorized, ErrSignatureInvalid},
+		{"otro perfil", vectorSecret, "POST", statusRoute, now, hash, otherTarget, body, http.StatusUnauthorized, ErrSignatureInvalid},
+	}
+	signatures := NewRequestSignatures(5 * time.Minute)
+	for _, tc := range cases {
+		status, err := signatures.VerifySignature(tc.secret, "norte", tc.method, tc.route, vectorTarget, tc.timestamp, tc.hash, tc.signature, tc.body)
+		if status != tc.status || !errors.Is(err, tc.err) {
+			t.Errorf("%s: %d %v, se esperaba %d %v", tc.name, status, err, tc.status, tc.err)
+		}
+	}
+
+	// * Ninguno de los rechazos gastó la firma buena
+	if _, err := signatures.VerifySignature(vectorSecret, "norte", "POST", statusRoute, vectorTarget, now, hash, signature, body); err != nil {
+		t.Fatalf("firma válida rechazada: %v", err)
+	}
+}
+
+func TestVerifySignatureForgetsExpired(t *testing.T) {
+	signatures := NewRequestSignatures(time.Minute)
+	signatures.seen["norte old"] = time.Now().Add(-time.Second)
+	signatures.seen["norte fresh"] = time.Now().Add(time.Minute)
+
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	hash, signature := sign(vectorSecret, now, "POST", vectorTarget, nil)
+	if _, err := signatures.VerifySignature(vectorSecret, "norte", "POST", statusRoute, vectorTarget, now, hash, signature, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := signatures.seen["norte old"]; ok {
+		t.Error("una firma vencida sigue en memoria")
+	}
+	if _, ok := signatures.seen["norte fresh"]; !ok {
+		t.Error("se olvidó una firma todavía vigente")
+	}
+}
@@ -0,0 +1,171 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+)
+
+// * Configuración a probar sobre el mismo flujo de eventos
+type SimulationConfig struct {
+	ProfilesPath     string
+	MatchProbability float64
+	Ranking          DeckRanker
+	Seed             uint64
+}
+
+// * Acepta un arreglo JSON (swipes.json de un respaldo) o un evento por línea
+func ReadSwipeEvents(r io.Reader) ([]m.Swipe, error) {
+	reader := bufio.NewReader(r)
+	first, err := peekNonSpace(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []m.Swipe
+	if first == '[' {
+		if err := json.NewDecoder(reader).Decode(&events); err != nil {
+			return nil, fmt.Errorf("eventos inválidos: %w", err)
+		}
+	} else {
+		decoder := json.NewDecoder(reader)
+		for line := 1; ; line++ {
+			var event m.Swipe
+			if err := decoder.Decode(&event); err == io.EOF {
+				break
+			} else if err != nil {
+				return nil, fmt.Errorf("evento %d inválido: %w", line, err)
+			}
+			events = append(events, event)
+		}
+	}
+
+	// * Se reproducen en el orden en que ocurrieron, no en el del archivo
+	sort.SliceStable(events, func(i, j int) bool { return events[i].CreatedAt.Before(events[j].CreatedAt) })
+	return events, nil
+}
+
+func peekNonSpace(reader *bufio.Reader) (byte, error) {
+	for {
+		b, err := reader.Peek(1)
+		if err == io.EOF {
+			return 0, fmt.Errorf("no hay eventos")
+		}
+		if err != nil {
+			return 0, err
+		}
+		if !bytes.ContainsAny(b, " \t\r\n") {
+			return b[0], nil
+		}
+		reader.Discard(1)
+	}
+}
+
+// * Reproduce los eventos contra servicios nuevos en memoria: nada se persiste ni se
+// * notifica. Los super-likes se regalan al vuelo porque el saldo original no viaja
+// * en el flujo
+func Simulate(ctx context.Context, events []m.Swipe, cfg SimulationConfig) (m.SimulationResult, error) {
+	cats := NewCatService(cfg.ProfilesPath, nil, nil, nil, nil, nil, nil)
+	if len(cats.snapshot().profiles) == 0 {
+		return m.SimulationResult{}, fmt.Errorf("no se pudieron cargar perfiles de %s", cfg.ProfilesPath)
+	}
+	swipes := NewSwipeService(cats, cfg.MatchProbability, NewRandSource(cfg.Seed), nil, NewIcebreakerService(nil, 0))
+	if cfg.Ranking != nil {
+		swipes.SetRanking(cfg.Ranking, nil)
+	}
+
+	result := m.SimulationResult{
+		MatchProbability: cfg.MatchProbability,
+		Ranking:          swipes.ranker.Name(),
+		Events:           len(events),
+		Rejected:         make(map[string]int),
+	}
+	var rankSum, ranked int
+	for _, event := range events {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		isLike := m.IsLike(event.Direction)
+		if isLike {
+			// * Semilla fija por usuario: el mismo mazo que vería en cada momento
+			seed := int64(cfg.Seed % uint64(m.MaxDeckSeed))
+			deck, _ := swipes.Deck(event.UserID, &seed, 0, nil)
+			for i, cat := range deck {
+				if cat.ID == event.CatID {
+					rankSum += i + 1
+					ranked++
+					break
+				}
+			}
+		}
+		if event.Direction == m.SwipeSuperLike && swipes.SuperLikes(event.UserID) == 0 {
+			swipes.GrantSuperLikes(event.UserID, 1)
+		}
+
+		swipeResult, err := swipes.Record(ctx, event.UserID, event.CatID, event.FromCatID, event.Direction)
+		if err != nil {
+			result.Rejected[simulationErrorCode(err)]++
+			continue
+		}
+		result.Applied++
+		switch event.Direction {
+		case m.SwipeLike:
+			result.Likes++
+		case m.SwipeSuperLike:
+			result.SuperLikes++
+		default:
+			result.Passes++
+		}
+		if match := swipeResult.Match; match != nil {
+			if match.Mutual {
+				result.MutualMatches++
+			} else {
+				result.ShelterMatches++
+			}
+		}
+	}
+
+	for _, state := range swipes.mutual.pairs {
+		if state.Status == m.PairPending {
+			result.PendingPairs++
+		}
+	}
+	if likes := result.Likes + result.SuperLikes; likes > 0 {
+		result.MatchRate = float64(result.ShelterMatches+result.MutualMatches) / float64(likes)
+	}
+	if ranked > 0 {
+		result.AvgLikeRank = float64(rankSum) / float64(ranked)
+	}
+	if len(result.Rejected) == 0 {
+		result.Rejected = nil
+	}
+	return result, nil
+}
+
+func simulationErrorCode(err error) string {
+	switch {
+	case errors.Is(err, ErrAlreadySwiped):
+		return "already_swiped"
+	case errors.Is(err, ErrProfileUnavailable):
+		return "profile_unavailable"
+	case errors.Is(err, ErrOwnCat):
+		return "own_cat"
+	case errors.Is(err, ErrNoOwnCat):
+		return "no_own_cat"
+	case errors.Is(err, ErrFromCatRequired):
+		return "from_cat_required"
+	case errors.Is(err, ErrNotYourCat):
+		return "not_your_cat"
+	case errors.Is(err, ErrNoSuperLikes):
+		return "no_super_likes"
+	}
+	return "profile_not_found"
+}
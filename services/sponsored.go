@@ -0,0 +1,193 @@
+package services
+
+import (
+	"errors"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+)
+
+var (
+	ErrNotSponsored     = errors.New("el perfil no está patrocinado")
+	ErrSponsorOwnedCat  = errors.New("solo se patrocinan gatos del refugio")
+	ErrSponsorUntilPast = errors.New("until debe ser una fecha futura")
+)
+
+// * Lugares asegurados para perfiles patrocinados: uno cada every tarjetas (desde la
+// * primera), como mucho maxPerDeck por mazo. La rotación es justa: va primero el que hace
+// * más tiempo que no sale y, a igualdad, el de menos impresiones; así el mismo gato no
+// * encabeza todos los mazos. Los patrocinados que no entran van al final del mazo.
+// * Las impresiones viven en memoria. Un *SponsorRotation nil deja el mazo tal cual
+type SponsorRotation struct {
+	every       int
+	maxPerDeck  int
+	impressions map[int]*sponsorImpressions
+	mutex       sync.Mutex
+}
+
+type sponsorImpressions struct {
+	count     int
+	lastShown time.Time
+}
+
+// * every <= 0 o maxPerDeck <= 0 apagan los lugares asegurados (las impresiones se siguen contando)
+func NewSponsorRotation(every, maxPerDeck int) *SponsorRotation {
+	return &SponsorRotation{
+		every:       every,
+		maxPerDeck:  maxPerDeck,
+		impressions: make(map[int]*sponsorImpressions),
+	}
+}
+
+// * Reordena un mazo ya rankeado
+func (r *SponsorRotation) Place(deck []m.CatProfile, now time.Time) []m.CatProfile {
+	if r == nil || r.every <= 0 || r.maxPerDeck <= 0 {
+		return deck
+	}
+
+	var sponsored, organic []m.CatProfile
+	for _, cat := range deck {
+		if sponsoredAt(cat, now) {
+			sponsored = append(sponsored, cat)
+		} else {
+			organic = append(organic, cat)
+		}
+	}
+	if len(sponsored) == 0 {
+		return deck
+	}
+
+	r.mutex.Lock()
+	sort.SliceStable(sponsored, func(i, j int) bool {
+		a, b := r.stats(sponsored[i].ID), r.stats(sponsored[j].ID)
+		if !a.lastShown.Equal(b.lastShown) {
+			return a.lastShown.Before(b.lastShown)
+		}
+		if a.count != b.count {
+			return a.count < b.count
+		}
+		return sponsored[i].ID < sponsored[j].ID
+	})
+	r.mutex.Unlock()
+
+	slots := min(len(sponsored), r.maxPerDeck)
+	placed := make([]m.CatProfile, 0, len(deck))
+	next := 0
+	for len(organic) > 0 || next < slots {
+		if next < slots && (len(placed)%r.every == 0 || len(organic) == 0) {
+			placed = append(placed, sponsored[next])
+			next++
+			continue
+		}
+		placed = append(placed, organic[0])
+		organic = organic[1:]
+	}
+	return append(placed, sponsored[slots:]...)
+}
+
+// * Cuenta una impresión por cada patrocinado del mazo que efectivamente se sirvió
+func (r *SponsorRotation) Record(deck []m.CatProfile, now time.Time) {
+	if r == nil {
+		return
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for _, cat := range deck {
+		if !sponsoredAt(cat, now) {
+			continue
+		}
+		stats := r.impressions[cat.ID]
+		if stats == nil {
+			stats = &sponsorImpressions{}
+			r.impressions[cat.ID] = stats
+		}
+		stats.count++
+		stats.lastShown = now
+	}
+}
+
+// * Perfiles con patrocinio (vencido o no) y sus impresiones
+func (r *SponsorRotation) Report(profiles []m.CatProfile, now time.Time) m.SponsoredReport {
+	report := m.SponsoredReport{Profiles: make([]m.SponsoredStat, 0)}
+	if r == nil {
+		return report
+	}
+	report.Every = r.every
+	report.MaxPerDeck = r.maxPerDeck
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for _, cat := range profiles {
+		if cat.Sponsored == nil {
+			continue
+		}
+		stat := m.SponsoredStat{
+			ID:     cat.ID,
+			Name:   cat.Name,
+			Since:  cat.Sponsored.Since,
+			Until:  cat.Sponsored.Until,
+			Active: cat.Status == m.StatusActive && cat.Sponsored.ActiveAt(now),
+		}
+		stats := r.stats(cat.ID)
+		stat.Impressions = stats.count
+		if !stats.lastShown.IsZero() {
+			lastShown := stats.lastShown
+			stat.LastShownAt = &lastShown
+		}
+		report.Profiles = append(report.Profiles, stat)
+	}
+	sort.Slice(report.Profiles, func(i, j int) bool { return report.Profiles[i].ID < report.Profiles[j].ID })
+	report.Count = len(report.Profiles)
+	return report
+}
+
+// ! Llamar con mutex tomado
+func (r *SponsorRotation) stats(id int) sponsorImpressions {
+	if stats := r.impressions[id]; stats != nil {
+		return *stats
+	}
+	return sponsorImpressions{}
+}
+
+func sponsoredAt(cat m.CatProfile, now time.Time) bool {
+	return cat.Sponsored != nil && cat.Sponsored.ActiveAt(now)
+}
+
+// * Marca el perfil como patrocinado; si ya lo estaba solo cambia el vencimiento
+func (s *CatService) Sponsor(id int, until *time.Time) (*m.CatProfile, error) {
+	now := time.Now()
+	if until != nil && !until.After(now) {
+		return nil, ErrSponsorUntilPast
+	}
+
+	profile, err := s.mutateProfile(id, func(cat *m.CatProfile) error {
+		if cat.OwnerID != "" {
+			return ErrSponsorOwnedCat
+		}
+		since := now
+		if cat.Sponsored != nil && cat.Sponsored.ActiveAt(now) {
+			since = cat.Sponsored.Since
+		}
+		cat.Sponsored = &m.Sponsorship{Since: since, Until: until}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("📣 Perfil %d patrocinado", id)
+	return profile, nil
+}
+
+func (s *CatService) Unsponsor(id int) (*m.CatProfile, error) {
+	return s.mutateProfile(id, func(cat *m.CatProfile) error {
+		if cat.Sponsored == nil {
+			return ErrNotSponsored
+		}
+		cat.Sponsored = nil
+		return nil
+	})
+}
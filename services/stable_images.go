@@ -0,0 +1,176 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+)
+
+const stableImagesFile = "images.json"
+
+// * Imagen fija por perfil: la URL asignada se guarda en disco junto con los bytes que
+// * devolvió la primera vez (cataas manda un gato distinto en cada petición a la misma URL).
+// * Así un perfil se ve igual entre reinicios y solo cambia con un refresco explícito.
+// * Un *StableImages nil no hace nada (modo aleatorio de siempre)
+type StableImages struct {
+	dir string
+	// * tenant -> ID de perfil -> URL
+	urls   map[string]map[string]string
+	pinned map[string]int
+	mutex  sync.RWMutex
+}
+
+func NewStableImages(dir string) (*StableImages, error) {
+	st := &StableImages{
+		dir:    dir,
+		urls:   make(map[string]map[string]string),
+		pinned: make(map[string]int),
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, stableImagesFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return st, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &st.urls); err != nil {
+		return nil, fmt.Errorf("error parseando %s: %w", stableImagesFile, err)
+	}
+	for _, profiles := range st.urls {
+		for _, url := range profiles {
+			st.pinned[url]++
+		}
+	}
+	log.Printf("📌 Imágenes fijas cargadas: %d", len(st.pinned))
+	return st, nil
+}
+
+func (st *StableImages) URL(tenant string, id int) (string, bool) {
+	if st == nil {
+		return "", false
+	}
+	st.mutex.RLock()
+	defer st.mutex.RUnlock()
+
+	url, ok := st.urls[tenant][strconv.Itoa(id)]
+	return url, ok
+}
+
+// * Fija la imagen actual de cada perfil; los que ya no están (purgados) se sueltan
+// * junto con sus bytes. Solo escribe si algo cambió
+func (st *StableImages) Sync(tenant string, profiles []m.CatProfile) {
+	if st == nil {
+		return
+	}
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+
+	current := st.urls[tenant]
+	next := make(map[string]string, len(profiles))
+	changed := len(current) != len(profiles)
+	for _, cat := range profiles {
+		key := strconv.Itoa(cat.ID)
+		next[key] = cat.Img
+		if current[key] != cat.Img {
+			changed = true
+		}
+	}
+	if !changed {
+		return
+	}
+
+	for _, url := range next {
+		st.pinned[url]++
+	}
+	for _, url := range current {
+		if st.pinned[url]--; st.pinned[url] <= 0 {
+			delete(st.pinned, url)
+			os.Remove(st.bytesPath(url))
+		}
+	}
+	st.urls[tenant] = next
+
+	if err := st.save(); err != nil {
+		log.Printf("⚠️ Error guardando imágenes fijas: %v", err)
+	}
+}
+
+// * Bytes guardados de una URL fijada (ver ImageProxy)
+func (st *StableImages) Load(url string) (*m.ImageData, bool) {
+	if st == nil || !st.isPinned(url) {
+		return nil, false
+	}
+	data, err := os.ReadFile(st.bytesPath(url))
+	if err != nil {
+		return nil, false
+	}
+	contentType := http.DetectContentType(data)
+	return &m.ImageData{
+		SourceURL:   url,
+		ContentType: contentType,
+		Data:        data,
+		Meta:        ExtractImageMeta(contentType, data),
+	}, true
+}
+
+// * Solo guarda imágenes de perfiles: las del pool y los lotes siguen siendo efímeras
+func (st *StableImages) Store(image *m.ImageData) {
+	if st == nil || !st.isPinned(image.SourceURL) {
+		return
+	}
+	path := st.bytesPath(image.SourceURL)
+	if _, err := os.Stat(path); err == nil {
+		return
+	}
+	if err := os.MkdirAll(st.dir, 0o755); err != nil {
+		log.Printf("⚠️ Error creando directorio de imágenes fijas: %v", err)
+		return
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, image.Data, 0o644); err != nil {
+		log.Printf("⚠️ Error guardando imagen fija: %v", err)
+		return
+	}
+	os.Rename(tmp, path)
+}
+
+func (st *StableImages) isPinned(url string) bool {
+	st.mutex.RLock()
+	defer st.mutex.RUnlock()
+	return st.pinned[url] > 0
+}
+
+func (st *StableImages) bytesPath(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return filepath.Join(st.dir, hex.EncodeToString(sum[:16])+".img")
+}
+
+// ! Llamar con mutex tomado
+func (st *StableImages) save() error {
+	data, err := json.Marshal(st.urls)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(st.dir, 0o755); err != nil {
+		return fmt.Errorf("error creando directorio de imágenes fijas: %w", err)
+	}
+
+	// ! Escribir a un temporal y renombrar para no dejar el archivo a medias
+	path := filepath.Join(st.dir, stableImagesFile)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
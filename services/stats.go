@@ -0,0 +1,120 @@
+package services
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+)
+
+// * Punto único de contadores del servicio: los servicios avisan y GET /stats lee.
+// * Los tamaños de cache no se cuentan, se consultan al armar la foto (ver Gauge).
+// * Todos los métodos aceptan un colector nil (simulaciones, herramientas)
+type StatsCollector struct {
+	startedAt     time.Time
+	batches       atomic.Int64
+	urlsGenerated atomic.Int64
+	dedupHits     atomic.Int64
+	requests      requestWindow
+
+	providerErrors map[string]int64
+	gauges         map[string]func() int
+	mutex          sync.Mutex
+}
+
+const statsRequestWindow = 5 * time.Minute
+
+func NewStatsCollector() *StatsCollector {
+	return &StatsCollector{
+		startedAt:      time.Now(),
+		providerErrors: make(map[string]int64),
+		gauges:         make(map[string]func() int),
+	}
+}
+
+func (c *StatsCollector) BatchServed(urls int) {
+	if c == nil {
+		return
+	}
+	c.batches.Add(1)
+	c.urlsGenerated.Add(int64(urls))
+}
+
+func (c *StatsCollector) DedupHit() {
+	if c == nil {
+		return
+	}
+	c.dedupHits.Add(1)
+}
+
+func (c *StatsCollector) ProviderError(provider string) {
+	if c == nil || provider == "" {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.providerErrors[provider]++
+}
+
+// * Lo llama el middleware de métricas al terminar cada petición
+func (c *StatsCollector) RequestServed(status int, latency time.Duration) {
+	if c == nil {
+		return
+	}
+	c.requests.record(status, latency, time.Now())
+}
+
+// * Tasa de errores y latencias de los últimos window (entre 10s y 10m)
+func (c *StatsCollector) Requests(window time.Duration) m.RequestStats {
+	if c == nil {
+		return m.RequestStats{}
+	}
+	return c.requests.summary(window, time.Now())
+}
+
+// * size se llama en cada GET /stats: tiene que ser barato y seguro entre goroutines
+func (c *StatsCollector) Gauge(name string, size func() int) {
+	if c == nil {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.gauges[name] = size
+}
+
+func (c *StatsCollector) Snapshot() m.ServiceStats {
+	c.mutex.Lock()
+	providerErrors := make(map[string]int64, len(c.providerErrors))
+	for name, count := range c.providerErrors {
+		providerErrors[name] = count
+	}
+	gauges := make(map[string]func() int, len(c.gauges))
+	for name, size := range c.gauges {
+		gauges[name] = size
+	}
+	c.mutex.Unlock()
+
+	// * Fuera del lock: cada gauge toma el de su propio servicio
+	caches := make(map[string]int, len(gauges))
+	for name, size := range gauges {
+		caches[name] = size()
+	}
+
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	return m.ServiceStats{
+		StartedAt:      c.startedAt,
+		UptimeSeconds:  int64(time.Since(c.startedAt).Seconds()),
+		Batches:        c.batches.Load(),
+		URLsGenerated:  c.urlsGenerated.Load(),
+		DedupHits:      c.dedupHits.Load(),
+		ProviderErrors: providerErrors,
+		Caches:         caches,
+		Requests:       c.requests.summary(statsRequestWindow, time.Now()),
+		Goroutines:     runtime.NumGoroutine(),
+		HeapBytes:      mem.HeapAlloc,
+	}
+}
@@ -0,0 +1,47 @@
+package services
+
+import (
+	"slices"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+)
+
+const (
+	// * Se sortea entre los primeros del mazo ya rankeado: sorpresa, pero no al azar puro
+	surprisePool = 10
+	// * Sorpresas recientes que no se repiten aunque el usuario no las haya deslizado
+	surpriseMemory = 5
+)
+
+// * Un gato para el botón "sorpréndeme": sale de un mazo nuevo (mismas exclusiones,
+// * preferencias y ranking que Deck) y evita repetir las últimas sorpresas mientras haya otros
+func (s *SwipeService) Surprise(userID string) *m.CatProfile {
+	// * Sin lugares patrocinados: la sorpresa no es publicidad y no cuenta impresiones
+	deck, _ := s.rankedDeck(userID, nil, nil)
+	if len(deck) == 0 {
+		return nil
+	}
+	deck = deck[:min(len(deck), surprisePool)]
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	recent := s.surprises[userID]
+	fresh := make([]m.CatProfile, 0, len(deck))
+	for _, cat := range deck {
+		if !slices.Contains(recent, cat.ID) {
+			fresh = append(fresh, cat)
+		}
+	}
+	if len(fresh) == 0 {
+		fresh = deck
+	}
+
+	cat := fresh[s.rand.IntN(len(fresh))]
+	recent = append(recent, cat.ID)
+	if len(recent) > surpriseMemory {
+		recent = recent[len(recent)-surpriseMemory:]
+	}
+	s.surprises[userID] = recent
+	return &cat
+}
@@ -0,0 +1,220 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+)
+
+var (
+	ErrSwipeThrottled = errors.New("demasiados swipes seguidos, espera un momento")
+	ErrFlagNotFound   = errors.New("el usuario no está marcado")
+)
+
+const (
+	swipeFraudRateWindow = time.Minute
+	// * Cada cuántas observaciones se olvidan los usuarios inactivos
+	swipeFraudSweepEvery = 1000
+)
+
+// * Error con el tiempo de espera para Retry-After
+type ThrottledError struct {
+	RetryAfter time.Duration
+}
+
+func (e *ThrottledError) Error() string { return ErrSwipeThrottled.Error() }
+
+func (e *ThrottledError) Unwrap() error { return ErrSwipeThrottled }
+
+type SwipeFraudPolicy struct {
+	// * Likes en un minuto desde los que la sesión se marca; 0 = sin límite
+	MaxLikesPerMinute int
+	// * Swipes seguidos que se miran para la regularidad; 0 = sin chequeo
+	UniformWindow int
+	// * Coeficiente de variación de los intervalos por debajo del cual se marca
+	MaxUniformity float64
+	// * Cuánto se rechazan los swipes de una sesión recién marcada
+	Cooldown time.Duration
+}
+
+// * Detecta patrones de swipe no humanos por usuario: ráfagas de likes o intervalos de
+// * reloj. La sesión queda marcada (hasta que el admin la libere) y frenada durante
+// * Cooldown. Complementa a AbuseGuard, que mira IPs y no usuarios.
+// * Un *SwipeFraudDetector nil no detecta nada
+type SwipeFraudDetector struct {
+	policy       SwipeFraudPolicy
+	sessions     map[string]*swipeSession
+	flags        map[string]*m.SwipeFlag
+	observations int
+	mutex        sync.Mutex
+}
+
+type swipeSession struct {
+	likes  []time.Time
+	swipes []time.Time
+}
+
+func NewSwipeFraudDetector(policy SwipeFraudPolicy) *SwipeFraudDetector {
+	return &SwipeFraudDetector{
+		policy:   policy,
+		sessions: make(map[string]*swipeSession),
+		flags:    make(map[string]*m.SwipeFlag),
+	}
+}
+
+// * Antes de registrar el swipe: *ThrottledError mientras dure el castigo
+func (d *SwipeFraudDetector) Allow(userID string, now time.Time) error {
+	if d == nil {
+		return nil
+	}
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if flag := d.flags[userID]; flag != nil && now.Before(flag.ThrottledUntil) {
+		return &ThrottledError{RetryAfter: flag.ThrottledUntil.Sub(now)}
+	}
+	return nil
+}
+
+// * Después de registrar el swipe; devuelve si la sesión está marcada
+func (d *SwipeFraudDetector) Observe(swipe m.Swipe) bool {
+	if d == nil {
+		return false
+	}
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	now := swipe.CreatedAt
+	d.observations++
+	if d.observations%swipeFraudSweepEvery == 0 {
+		d.sweep(now)
+	}
+
+	session := d.sessions[swipe.UserID]
+	if session == nil {
+		session = &swipeSession{}
+		d.sessions[swipe.UserID] = session
+	}
+	session.swipes = append(session.swipes, now)
+	if len(session.swipes) > d.policy.UniformWindow+1 {
+		session.swipes = session.swipes[len(session.swipes)-d.policy.UniformWindow-1:]
+	}
+	if m.IsLike(swipe.Direction) {
+		session.likes = append(session.likes, now)
+	}
+	cutoff := now.Add(-swipeFraudRateWindow)
+	for len(session.likes) > 0 && !session.likes[0].After(cutoff) {
+		session.likes = session.likes[1:]
+	}
+
+	if reason, detail, ok := d.suspicious(session); ok {
+		d.flag(swipe.UserID, reason, detail, now)
+		// * Se empieza de cero: al volver del castigo no se marca por los mismos swipes
+		delete(d.sessions, swipe.UserID)
+	}
+	return d.flags[swipe.UserID] != nil
+}
+
+func (d *SwipeFraudDetector) Flagged(userID string) bool {
+	if d == nil {
+		return false
+	}
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.flags[userID] != nil
+}
+
+// * Más recientes primero
+func (d *SwipeFraudDetector) Flags() []m.SwipeFlag {
+	flags := make([]m.SwipeFlag, 0)
+	if d == nil {
+		return flags
+	}
+	d.mutex.Lock()
+	for _, flag := range d.flags {
+		flags = append(flags, *flag)
+	}
+	d.mutex.Unlock()
+
+	sort.Slice(flags, func(i, j int) bool { return flags[i].FlaggedAt.After(flags[j].FlaggedAt) })
+	return flags
+}
+
+// * El admin revisó la sesión y es humana: vuelve a contar en las métricas
+func (d *SwipeFraudDetector) Clear(userID string) error {
+	if d == nil {
+		return ErrFlagNotFound
+	}
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if d.flags[userID] == nil {
+		return ErrFlagNotFound
+	}
+	delete(d.flags, userID)
+	delete(d.sessions, userID)
+	return nil
+}
+
+// ! Llamar con mutex tomado
+func (d *SwipeFraudDetector) suspicious(session *swipeSession) (reason, detail string, ok bool) {
+	if limit := d.policy.MaxLikesPerMinute; limit > 0 && len(session.likes) > limit {
+		return m.FraudReasonRate, fmt.Sprintf("%d likes en un minuto", len(session.likes)), true
+	}
+
+	window := d.policy.UniformWindow
+	if window < 2 || len(session.swipes) <= window {
+		return "", "", false
+	}
+	intervals := make([]float64, window)
+	var mean float64
+	for i := range intervals {
+		intervals[i] = session.swipes[i+1].Sub(session.swipes[i]).Seconds()
+		mean += intervals[i]
+	}
+	mean /= float64(window)
+
+	var variance float64
+	for _, interval := range intervals {
+		variance += (interval - mean) * (interval - mean)
+	}
+	// * Coeficiente de variación: una persona nunca desliza con la regularidad de un reloj
+	variation := 0.0
+	if mean > 0 {
+		variation = math.Sqrt(variance/float64(window)) / mean
+	}
+	if variation < d.policy.MaxUniformity {
+		return m.FraudReasonUniform, fmt.Sprintf("%d swipes cada %.2fs (variación %.3f)", window, mean, variation), true
+	}
+	return "", "", false
+}
+
+// ! Llamar con mutex tomado
+func (d *SwipeFraudDetector) flag(userID, reason, detail string, now time.Time) {
+	flag := d.flags[userID]
+	if flag == nil {
+		flag = &m.SwipeFlag{UserID: userID, FlaggedAt: now}
+		d.flags[userID] = flag
+	}
+	flag.Reason = reason
+	flag.Detail = detail
+	flag.ThrottledUntil = now.Add(d.policy.Cooldown)
+	flag.Hits++
+	log.Printf("🤖 Swipes sospechosos de %s (%s): %s", userID, reason, detail)
+}
+
+// ! Llamar con mutex tomado
+func (d *SwipeFraudDetector) sweep(now time.Time) {
+	cutoff := now.Add(-swipeFraudRateWindow)
+	for userID, session := range d.sessions {
+		if last := session.swipes[len(session.swipes)-1]; last.Before(cutoff) {
+			delete(d.sessions, userID)
+		}
+	}
+}
@@ -0,0 +1,533 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+)
+
+var (
+	ErrAlreadySwiped      = errors.New("ya deslizaste este perfil")
+	ErrProfileUnavailable = errors.New("este gato ya no está disponible")
+	ErrOwnCat             = errors.New("no puedes deslizar a tu propio gato")
+	ErrNoOwnCat           = errors.New("registra un gato antes de deslizar gatos de otros usuarios")
+	ErrFromCatRequired    = errors.New("tienes varios gatos: indica from_cat_id")
+	ErrNotYourCat         = errors.New("from_cat_id no es uno de tus gatos")
+	ErrNoSuperLikes       = errors.New("no te quedan super-likes")
+)
+
+// * Persistencia opcional de swipes; la implementación SQL escribe también el outbox
+type SwipeStore interface {
+	LoadSwipes(ctx context.Context) ([]m.Swipe, []m.Match, error)
+	SaveSwipe(ctx context.Context, swipe m.Swipe, matches []m.Match) error
+	UpdateMatch(ctx context.Context, match m.Match) error
+}
+
+type SwipeService struct {
+	catService       *CatService
+	matchProbability float64
+	rand             RandSource
+	store            SwipeStore
+	swipes           map[string][]m.Swipe
+	seen             map[string]map[int]bool
+	matches          map[string][]m.Match
+	preferences      map[string]m.Preferences
+	superLikes       map[string]int
+	surprises        map[string][]int
+	mutual           *MatchService
+	icebreakers      *IcebreakerService
+	ranker           DeckRanker
+	shadow           *ShadowRanking
+	sponsors         *SponsorRotation
+	fraud            *SwipeFraudDetector
+	prefetch         *DeckPrefetch
+	changes          *DeckChanges
+	recordListeners  []func(m.Swipe, []m.Match)
+	listenersMutex   sync.Mutex
+	matchCount       int
+	mutex            sync.RWMutex
+}
+
+// * store puede ser nil: entonces todo vive solo en memoria
+func NewSwipeService(catService *CatService, matchProbability float64, rng RandSource, store SwipeStore, icebreakers *IcebreakerService) *SwipeService {
+	return &SwipeService{
+		catService:       catService,
+		matchProbability: matchProbability,
+		rand:             rng,
+		store:            store,
+		swipes:           make(map[string][]m.Swipe),
+		seen:             make(map[string]map[int]bool),
+		matches:          make(map[string][]m.Match),
+		preferences:      make(map[string]m.Preferences),
+		superLikes:       make(map[string]int),
+		surprises:        make(map[string][]int),
+		mutual:           NewMatchService(),
+		icebreakers:      icebreakers,
+		ranker:           archetypeRanker{},
+	}
+}
+
+// * shadow puede ser nil; el ranking candidato nunca cambia lo que se sirve
+func (s *SwipeService) SetRanking(live DeckRanker, shadow *ShadowRanking) {
+	s.ranker = live
+	s.shadow = shadow
+}
+
+// * sponsors puede ser nil: entonces los patrocinados no tienen lugar asegurado
+func (s *SwipeService) SetSponsors(sponsors *SponsorRotation) {
+	s.sponsors = sponsors
+}
+
+// * fraud puede ser nil: entonces no se frena ni se marca a nadie
+func (s *SwipeService) SetFraudDetector(fraud *SwipeFraudDetector) {
+	s.fraud = fraud
+}
+
+// * prefetch puede ser nil: entonces POST /deck/prefetch no está disponible
+func (s *SwipeService) SetDeckPrefetch(prefetch *DeckPrefetch) {
+	s.prefetch = prefetch
+}
+
+// * Sin historial GET /deck/changes nunca tiene cambios que entregar
+func (s *SwipeService) EnableDeckChanges(retain int) {
+	s.changes = NewDeckChanges(s, retain)
+}
+
+func (s *SwipeService) SwipeFlags() []m.SwipeFlag {
+	return s.fraud.Flags()
+}
+
+func (s *SwipeService) ClearSwipeFlag(userID string) error {
+	return s.fraud.Clear(userID)
+}
+
+func (s *SwipeService) SponsoredReport() m.SponsoredReport {
+	profiles := make([]m.CatProfile, 0)
+	for _, cat := range s.catService.snapshot().profiles {
+		if cat.DeletedAt == nil {
+			profiles = append(profiles, cat)
+		}
+	}
+	return s.sponsors.Report(profiles, time.Now())
+}
+
+func (s *SwipeService) ShadowRankingStats() m.ShadowRankingStats {
+	if s.shadow == nil {
+		return m.ShadowRankingStats{Live: s.ranker.Name()}
+	}
+	return s.shadow.Stats()
+}
+
+// * Gatos del refugio: un like se convierte en match si el gato "corresponde" (probabilidad
+// * configurable). Gatos de otros usuarios: se desliza con un gato propio y el match solo
+// * existe cuando ambos dueños se dieron like (ver MatchService)
+func (s *SwipeService) Record(ctx context.Context, userID string, catID, fromCatID int, direction string) (*m.SwipeResult, error) {
+	if err := s.fraud.Allow(userID, time.Now()); err != nil {
+		return nil, err
+	}
+
+	cat, err := s.catService.GetCatProfileByID(catID)
+	if err != nil {
+		return nil, err
+	}
+	if cat.Status != m.StatusActive {
+		return nil, ErrProfileUnavailable
+	}
+
+	var fromCat *m.CatProfile
+	if cat.OwnerID != "" {
+		if cat.OwnerID == userID {
+			return nil, ErrOwnCat
+		}
+		fromCat, err = s.resolveFromCat(userID, fromCatID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result, matches, err := s.record(ctx, userID, cat, fromCat, direction)
+	if err != nil {
+		return nil, err
+	}
+
+	// * Fuera del lock: el generador puede ser un LLM remoto
+	if len(matches) > 0 {
+		s.attachIcebreakers(ctx, matches, *cat, fromCat)
+		result.Match = &matches[0]
+	}
+	// * Los swipes de una sesión marcada no suman insignias ni misiones
+	if !s.fraud.Observe(result.Swipe) {
+		s.notifyRecord(result.Swipe, matches)
+	}
+	return result, nil
+}
+
+// * Para módulos que reaccionan a cada swipe (insignias, etc.); se llama fuera del lock
+func (s *SwipeService) OnRecord(listener func(swipe m.Swipe, matches []m.Match)) {
+	s.listenersMutex.Lock()
+	defer s.listenersMutex.Unlock()
+	s.recordListeners = append(s.recordListeners, listener)
+}
+
+func (s *SwipeService) notifyRecord(swipe m.Swipe, matches []m.Match) {
+	s.listenersMutex.Lock()
+	listeners := append([]func(m.Swipe, []m.Match){}, s.recordListeners...)
+	s.listenersMutex.Unlock()
+
+	for _, listener := range listeners {
+		listener(swipe, matches)
+	}
+}
+
+func (s *SwipeService) record(ctx context.Context, userID string, cat, fromCat *m.CatProfile, direction string) (*m.SwipeResult, []m.Match, error) {
+	catID := cat.ID
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.seen[userID][catID] {
+		return nil, nil, ErrAlreadySwiped
+	}
+	if direction == m.SwipeSuperLike && s.superLikes[userID] <= 0 {
+		return nil, nil, ErrNoSuperLikes
+	}
+
+	swipe := m.Swipe{
+		UserID:    userID,
+		CatID:     catID,
+		Direction: direction,
+		CreatedAt: time.Now(),
+	}
+	result := &m.SwipeResult{Swipe: swipe}
+	var matches []m.Match
+	var pair m.PairState
+
+	if fromCat != nil {
+		swipe.FromCatID = fromCat.ID
+		result.Swipe = swipe
+		pair = s.mutual.next(fromCat.ID, cat.ID, m.IsLike(direction))
+		result.Pair = &pair
+		if pair.Status == m.PairMatched {
+			// * Un match por cada dueño, cada uno visto desde su lado
+			matches = []m.Match{
+				{
+					ID: fmt.Sprintf("m-%d", s.matchCount+1), UserID: userID,
+					CatID: cat.ID, CatName: cat.Name, WithCatID: fromCat.ID, Mutual: true,
+					CreatedAt: swipe.CreatedAt,
+				},
+				{
+					ID: fmt.Sprintf("m-%d", s.matchCount+2), UserID: cat.OwnerID,
+					CatID: fromCat.ID, CatName: fromCat.Name, WithCatID: cat.ID, Mutual: true,
+					CreatedAt: swipe.CreatedAt,
+				},
+			}
+		}
+	} else if direction == m.SwipeSuperLike || (direction == m.SwipeLike && s.rand.Float64() < s.matchProbability) {
+		matches = []m.Match{{
+			ID:        fmt.Sprintf("m-%d", s.matchCount+1),
+			UserID:    userID,
+			CatID:     catID,
+			CatName:   cat.Name,
+			CreatedAt: swipe.CreatedAt,
+		}}
+	}
+	// * Primero la base de datos: si falla, la memoria no queda adelantada
+	if s.store != nil {
+		if err := s.store.SaveSwipe(ctx, swipe, matches); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	s.swipes[userID] = append(s.swipes[userID], swipe)
+	if s.seen[userID] == nil {
+		s.seen[userID] = make(map[int]bool)
+	}
+	s.seen[userID][catID] = true
+	if direction == m.SwipeSuperLike {
+		s.superLikes[userID]--
+	}
+	if fromCat != nil {
+		s.mutual.set(fromCat.ID, cat.ID, pair)
+	}
+
+	for _, match := range matches {
+		s.matchCount++
+		s.matches[match.UserID] = append(s.matches[match.UserID], match)
+	}
+	if len(matches) == 2 {
+		log.Printf("💞 Match mutuo %s/%s: %s (%s) y %s (%s)", matches[0].ID, matches[1].ID, fromCat.Name, userID, cat.Name, cat.OwnerID)
+	} else if len(matches) == 1 {
+		log.Printf("💘 Match %s: %s con %s", matches[0].ID, userID, cat.Name)
+	}
+
+	return result, matches, nil
+}
+
+// * Sin fromCatID vale el único gato del usuario; con varios hay que elegir
+func (s *SwipeService) resolveFromCat(userID string, fromCatID int) (*m.CatProfile, error) {
+	owned := s.catService.OwnedCats(userID)
+	if len(owned) == 0 {
+		return nil, ErrNoOwnCat
+	}
+	if fromCatID == 0 {
+		if len(owned) > 1 {
+			return nil, ErrFromCatRequired
+		}
+		fromCatID = owned[0].ID
+	}
+	for _, cat := range owned {
+		if cat.ID != fromCatID {
+			continue
+		}
+		if cat.Status != m.StatusActive {
+			return nil, ErrProfileUnavailable
+		}
+		return &cat, nil
+	}
+	return nil, ErrNotYourCat
+}
+
+// * Rehidrata la memoria desde el store al arrancar
+func (s *SwipeService) Restore(ctx context.Context) error {
+	if s.store == nil {
+		return nil
+	}
+	swipes, matches, err := s.store.LoadSwipes(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	s.loadState(swipes, matches)
+	s.mutex.Unlock()
+
+	log.Printf("💾 Swipes restaurados: %d swipes, %d matches", len(swipes), len(matches))
+	return nil
+}
+
+func (s *SwipeService) replaceState(swipes []m.Swipe, matches []m.Match) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.swipes = make(map[string][]m.Swipe)
+	s.seen = make(map[string]map[int]bool)
+	s.matches = make(map[string][]m.Match)
+	s.mutual = NewMatchService()
+	s.matchCount = 0
+	s.loadState(swipes, matches)
+}
+
+// ! Requiere s.mutex tomado
+func (s *SwipeService) loadState(swipes []m.Swipe, matches []m.Match) {
+	for _, swipe := range swipes {
+		s.swipes[swipe.UserID] = append(s.swipes[swipe.UserID], swipe)
+		if s.seen[swipe.UserID] == nil {
+			s.seen[swipe.UserID] = make(map[int]bool)
+		}
+		s.seen[swipe.UserID][swipe.CatID] = true
+		if swipe.FromCatID != 0 {
+			s.mutual.set(swipe.FromCatID, swipe.CatID, s.mutual.next(swipe.FromCatID, swipe.CatID, m.IsLike(swipe.Direction)))
+		}
+	}
+	for _, match := range matches {
+		s.matches[match.UserID] = append(s.matches[match.UserID], match)
+		s.matchCount = max(s.matchCount, matchCursor(match).ID)
+	}
+}
+
+// * Todos los swipes y matches en orden de creación
+func (s *SwipeService) export() ([]m.Swipe, []m.Match) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	swipes := make([]m.Swipe, 0)
+	for _, userSwipes := range s.swipes {
+		swipes = append(swipes, userSwipes...)
+	}
+	matches := make([]m.Match, 0)
+	for _, userMatches := range s.matches {
+		matches = append(matches, userMatches...)
+	}
+	sort.SliceStable(swipes, func(i, j int) bool { return swipes[i].CreatedAt.Before(swipes[j].CreatedAt) })
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].CreatedAt.Before(matches[j].CreatedAt) })
+	return swipes, matches
+}
+
+// * Sin los archivados; esos salen en ArchivedMatches
+func (s *SwipeService) Matches(userID string) []m.Match {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return activeMatches(s.matches[userID])
+}
+
+// * Incluye archivados
+func (s *SwipeService) allMatches(userID string) []m.Match {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return append([]m.Match{}, s.matches[userID]...)
+}
+
+func activeMatches(matches []m.Match) []m.Match {
+	active := make([]m.Match, 0, len(matches))
+	for _, match := range matches {
+		if match.ArchivedAt == nil {
+			active = append(active, match)
+		}
+	}
+	return active
+}
+
+func (s *SwipeService) History(userID string) []m.Swipe {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return append([]m.Swipe{}, s.swipes[userID]...)
+}
+
+func (s *SwipeService) HistoryPage(userID string, cursor *Cursor, limit int) ([]m.Swipe, *Cursor) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return paginateDesc(s.swipes[userID], swipeCursor, cursor, limit)
+}
+
+func (s *SwipeService) MatchesPage(userID string, cursor *Cursor, limit int) ([]m.Match, *Cursor) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return paginateDesc(activeMatches(s.matches[userID]), matchCursor, cursor, limit)
+}
+
+// * Un usuario solo puede deslizar cada gato una vez, así que el ID del gato desempata
+func swipeCursor(swipe m.Swipe) Cursor {
+	return Cursor{Time: swipe.CreatedAt, ID: swipe.CatID}
+}
+
+func matchCursor(match m.Match) Cursor {
+	var n int
+	fmt.Sscanf(match.ID, "m-%d", &n)
+	return Cursor{Time: match.CreatedAt, ID: n}
+}
+
+// * Siguiente perfil que el usuario aún no ha visto; nil cuando ya vio todos
+func (s *SwipeService) NextCandidate(userID string) *m.CatProfile {
+	unseen, prefs := s.candidates(userID, s.catService.GetCatProfiles())
+	if len(unseen) == 0 {
+		return nil
+	}
+	cat := unseen[s.pickWeighted(unseen, prefs.Archetype)]
+	return &cat
+}
+
+// * Perfiles que el usuario aún puede deslizar según sus preferencias, en el orden recibido
+func (s *SwipeService) candidates(userID string, profiles []m.CatProfile) ([]m.CatProfile, m.Preferences) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	seen := s.seen[userID]
+	prefs := s.preferences[userID]
+	unseen := make([]m.CatProfile, 0, len(profiles))
+	for _, cat := range profiles {
+		if !seen[cat.ID] && cat.OwnerID != userID && matchesPreferences(cat, prefs) {
+			unseen = append(unseen, cat)
+		}
+	}
+	return unseen, prefs
+}
+
+// * Sorteo ponderado por afinidad con el arquetipo del quiz: los compatibles salen más
+// * seguido pero el resto nunca desaparece. Sin arquetipo es uniforme
+func (s *SwipeService) pickWeighted(cats []m.CatProfile, archetypeID string) int {
+	weights := make([]int, len(cats))
+	total := 0
+	for i, cat := range cats {
+		weights[i] = 1 + 2*archetypeAffinity(cat, archetypeID)
+		total += weights[i]
+	}
+	n := s.rand.IntN(total)
+	for i, weight := range weights {
+		if n < weight {
+			return i
+		}
+		n -= weight
+	}
+	return len(cats) - 1
+}
+
+// * Mazo barajado con una semilla compartible: se baraja el catálogo completo y luego se
+// * quitan los ya vistos, así dos usuarios con la misma semilla ven el mismo orden relativo.
+// * Con arquetipo del quiz los gatos compatibles suben al principio (ver DeckRanker)
+// * filter (opcional) deja solo los perfiles que cumple: etiquetas, colecciones.
+// * Los patrocinados ocupan sus lugares asegurados y cuentan impresión (ver SponsorRotation)
+func (s *SwipeService) Deck(userID string, seed *int64, limit int, filter func(m.CatProfile) bool) ([]m.CatProfile, int64) {
+	now := time.Now()
+	deck, usedSeed := s.placedDeck(userID, seed, limit, filter, now)
+	s.sponsors.Record(deck, now)
+	return deck, usedSeed
+}
+
+// * Deck sin contar impresiones: el mazo prefetcheado las cuenta recién al servirse
+func (s *SwipeService) placedDeck(userID string, seed *int64, limit int, filter func(m.CatProfile) bool, now time.Time) ([]m.CatProfile, int64) {
+	deck, usedSeed := s.rankedDeck(userID, seed, filter)
+	deck = s.sponsors.Place(deck, now)
+	if limit > 0 && limit < len(deck) {
+		deck = deck[:limit]
+	}
+	return deck, usedSeed
+}
+
+// * Ver CatService.ValidateProfileImages
+func (s *SwipeService) ValidateDeck(ctx context.Context, cats []m.CatProfile) ([]m.CatProfile, bool) {
+	return s.catService.ValidateProfileImages(ctx, cats)
+}
+
+// * Mazo barajado, filtrado y rankeado, sin lugares patrocinados
+func (s *SwipeService) rankedDeck(userID string, seed *int64, filter func(m.CatProfile) bool) ([]m.CatProfile, int64) {
+	if seed == nil {
+		generated := s.rand.Int64N(m.MaxDeckSeed)
+		seed = &generated
+	}
+
+	profiles := s.catService.GetCatProfiles()
+	if filter != nil {
+		profiles = slices.DeleteFunc(profiles, func(cat m.CatProfile) bool { return !filter(cat) })
+	}
+	deckRand := rand.New(rand.NewPCG(uint64(*seed), uint64(*seed)))
+	deckRand.Shuffle(len(profiles), func(i, j int) {
+		profiles[i], profiles[j] = profiles[j], profiles[i]
+	})
+
+	deck, prefs := s.candidates(userID, profiles)
+	var shadowInput []m.CatProfile
+	if s.shadow.sampled(s.rand) {
+		shadowInput = append([]m.CatProfile(nil), deck...)
+	}
+	s.ranker.Rank(deck, prefs)
+	if shadowInput != nil {
+		liveIDs := make([]int, len(deck))
+		for i, cat := range deck {
+			liveIDs[i] = cat.ID
+		}
+		s.shadow.observe(shadowInput, prefs, liveIDs)
+	}
+	return deck, *seed
+}
+
+func (s *SwipeService) SuperLikes(userID string) int {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.superLikes[userID]
+}
+
+// * Recompensas (misiones, promociones); devuelve el saldo nuevo
+func (s *SwipeService) GrantSuperLikes(userID string, amount int) int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.superLikes[userID] += amount
+	return s.superLikes[userID]
+}
@@ -0,0 +1,104 @@
+package services
+
+import (
+	"errors"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+)
+
+var ErrTagNotFound = errors.New("ningún perfil tiene esa etiqueta")
+
+// * Minúsculas, espacios internos como "-" y sin repetidas ("Gato de Regazo" -> "gato-de-regazo").
+// * Acepta listas separadas por coma, como llegan en ?tags=
+func NormalizeTags(tags []string) []string {
+	var normalized []string
+	for _, raw := range tags {
+		for _, tag := range strings.Split(raw, ",") {
+			tag = strings.Join(strings.Fields(strings.ToLower(tag)), "-")
+			if tag != "" && !slices.Contains(normalized, tag) {
+				normalized = append(normalized, tag)
+			}
+		}
+	}
+	return normalized
+}
+
+// * tags ya normalizadas
+func hasTags(cat m.CatProfile, tags []string) bool {
+	for _, tag := range tags {
+		if !slices.Contains(cat.Tags, tag) {
+			return false
+		}
+	}
+	return true
+}
+
+// * Filtro para el mazo; nil sin etiquetas
+func TagMatcher(tags []string) func(m.CatProfile) bool {
+	if len(tags) == 0 {
+		return nil
+	}
+	return func(cat m.CatProfile) bool { return hasTags(cat, tags) }
+}
+
+// * Etiquetas de los perfiles publicados, de la más usada a la menos
+func (s *CatService) Tags(limit int) []m.SummaryCount {
+	snap := s.snapshot()
+	tags := newValueCounter()
+	for _, i := range snap.active {
+		for _, tag := range snap.profiles[i].Tags {
+			tags.add(tag)
+		}
+	}
+
+	sorted := tags.sorted()
+	if limit > 0 && len(sorted) > limit {
+		sorted = sorted[:limit]
+	}
+	return sorted
+}
+
+// * Reemplaza las etiquetas del perfil
+func (s *CatService) SetTags(id int, tags []string) (*m.CatProfile, error) {
+	return s.mutateProfile(id, func(cat *m.CatProfile) error {
+		cat.Tags = NormalizeTags(tags)
+		// * Las etiquetas se indexan para la búsqueda de texto: el feed de cambios tiene que verlas
+		cat.UpdatedAt = time.Now()
+		return nil
+	})
+}
+
+// * Quita la etiqueta de todos los perfiles (colección retirada); devuelve cuántos la tenían
+func (s *CatService) RemoveTag(tag string) (int, error) {
+	tags := NormalizeTags([]string{tag})
+	if len(tags) == 0 {
+		return 0, ErrTagNotFound
+	}
+
+	removed := 0
+	now := time.Now()
+	err := s.rebuildProfiles(func(profiles []m.CatProfile) ([]m.CatProfile, error) {
+		for i := range profiles {
+			if j := slices.Index(profiles[i].Tags, tags[0]); j >= 0 {
+				profiles[i].Tags = slices.Delete(profiles[i].Tags, j, j+1)
+				profiles[i].UpdatedAt = now
+				removed++
+			}
+		}
+		if removed == 0 {
+			return nil, ErrTagNotFound
+		}
+		return profiles, nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	log.Printf("🏷️ Etiqueta %q quitada de %d perfiles", tags[0], removed)
+	s.notifyReload()
+	return removed, nil
+}
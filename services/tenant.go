@@ -0,0 +1,93 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+)
+
+const DefaultTenantID = "default"
+
+// * Parámetro con el que un enlace elige refugio (ver TenantURL)
+const TenantQueryParam = "tenant"
+
+var tenantIDPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,31}$`)
+
+// * Cada refugio tiene su propio catálogo, contadores y clave de admin
+type Tenant struct {
+	ID            string
+	Name          string
+	AdminKey      string
+	SigningSecret string
+	Cats          *CatService
+	Swipes        *SwipeService
+	Chat          *ChatService
+	Badges        *BadgeService
+	Quests        *QuestService
+}
+
+type TenantRegistry struct {
+	tenants map[string]*Tenant
+	order   []string
+}
+
+func NewTenantRegistry() *TenantRegistry {
+	return &TenantRegistry{tenants: make(map[string]*Tenant)}
+}
+
+// ! Solo durante el arranque: el registro no se modifica mientras sirve peticiones
+func (r *TenantRegistry) Add(tenant *Tenant) {
+	if _, ok := r.tenants[tenant.ID]; !ok {
+		r.order = append(r.order, tenant.ID)
+	}
+	r.tenants[tenant.ID] = tenant
+}
+
+func (r *TenantRegistry) Get(id string) (*Tenant, bool) {
+	tenant, ok := r.tenants[id]
+	return tenant, ok
+}
+
+func (r *TenantRegistry) Default() *Tenant {
+	return r.tenants[DefaultTenantID]
+}
+
+func (r *TenantRegistry) All() []*Tenant {
+	tenants := make([]*Tenant, 0, len(r.order))
+	for _, id := range r.order {
+		tenants = append(tenants, r.tenants[id])
+	}
+	return tenants
+}
+
+// * Sin archivo solo existe el tenant por defecto
+func LoadTenantSpecs(path string) ([]m.TenantSpec, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var specs []m.TenantSpec
+	if err := json.Unmarshal(data, &specs); err != nil {
+		return nil, fmt.Errorf("%s inválido: %w", path, err)
+	}
+	for _, spec := range specs {
+		if !tenantIDPattern.MatchString(spec.ID) || spec.ID == DefaultTenantID {
+			return nil, fmt.Errorf("id de tenant inválido: %q", spec.ID)
+		}
+		if spec.ProfilesFile == "" {
+			return nil, fmt.Errorf("el tenant %s no tiene profiles_file", spec.ID)
+		}
+	}
+	return specs, nil
+}
@@ -0,0 +1,232 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+)
+
+// * Escribe bios y personalidades para perfiles; field es m.TextFieldBio o m.TextFieldPersonality
+type TextGenProvider interface {
+	Name() string
+	Generate(ctx context.Context, field string, cat m.CatProfile) (string, error)
+}
+
+// * Determinista: el mismo gato produce siempre el mismo texto
+type TemplateTextGen struct{}
+
+func (TemplateTextGen) Name() string { return "template" }
+
+var (
+	personalityTemplates = []string{
+		"%s y curioso",
+		"Tranquilo, pero %s",
+		"%s con momentos de drama",
+		"Independiente y %s",
+	}
+	personalityTraits = []string{"juguetón", "dormilón", "cariñoso", "explorador", "elegante", "travieso"}
+	bioTemplates      = []string{
+		"Soy %s, %s de %d años. Mi especialidad: %s. Busco a alguien que respete mis siestas.",
+		"Me llamo %s. Soy %s, tengo %d años y lo mío es %s. ¿Me das un premio?",
+		"%s aquí: %s de %d años, experto en %s. Acepto mimos en horario de oficina.",
+	}
+)
+
+func (TemplateTextGen) Generate(ctx context.Context, field string, cat m.CatProfile) (string, error) {
+	seed := textSeed(cat)
+	switch field {
+	case m.TextFieldPersonality:
+		trait := personalityTraits[seed%len(personalityTraits)]
+		text := fmt.Sprintf(personalityTemplates[seed%len(personalityTemplates)], trait)
+		return strings.ToUpper(text[:1]) + text[1:], nil
+	case m.TextFieldBio:
+		hobby := "dormir al sol"
+		if len(cat.Hobbies) > 0 {
+			hobby = strings.ToLower(strings.TrimSpace(cat.Hobbies[seed%len(cat.Hobbies)]))
+		}
+		breed := "un gato"
+		if cat.Breed != "" {
+			breed = "un " + cat.Breed
+		}
+		return fmt.Sprintf(bioTemplates[seed%len(bioTemplates)], cat.Name, breed, cat.Age, hobby), nil
+	}
+	return "", fmt.Errorf("campo desconocido: %s", field)
+}
+
+func textSeed(cat m.CatProfile) int {
+	hash := fnv.New32a()
+	hash.Write([]byte(cat.Name + "|" + cat.Breed + "|" + strings.Join(cat.Hobbies, ",")))
+	return int(hash.Sum32() & 0x7fffffff)
+}
+
+type LLMTextGen struct {
+	client *OpenAIClient
+}
+
+func NewLLMTextGen(client *OpenAIClient) *LLMTextGen {
+	return &LLMTextGen{client: client}
+}
+
+func (LLMTextGen) Name() string { return "llm" }
+
+func (l *LLMTextGen) Generate(ctx context.Context, field string, cat m.CatProfile) (string, error) {
+	about := fmt.Sprintf("%s, %s de %d años. Hobbies: %s.", cat.Name, cat.Breed, cat.Age, strings.Join(cat.Hobbies, ", "))
+	var prompt string
+	switch field {
+	case m.TextFieldBio:
+		if cat.Personality != "" {
+			about += " Personalidad: " + cat.Personality + "."
+		}
+		prompt = "Escribe en español, en primera persona y en menos de 300 caracteres, la bio de app de citas de este gato de refugio: " + about
+	case m.TextFieldPersonality:
+		prompt = "Describe en español y en menos de 8 palabras la personalidad de este gato de refugio: " + about
+	default:
+		return "", fmt.Errorf("campo desconocido: %s", field)
+	}
+	prompt += " Responde solo con el texto."
+
+	text, err := l.client.Complete(ctx, prompt)
+	if err != nil {
+		return "", err
+	}
+	text = strings.Trim(strings.TrimSpace(text), "\"«»")
+	if text == "" {
+		return "", fmt.Errorf("el LLM devolvió un texto vacío")
+	}
+	return text, nil
+}
+
+const maxTextCacheEntries = 1000
+
+// * Envuelve al proveedor con cache, límite de llamadas por minuto y plantillas de respaldo:
+// * un import grande nunca queda esperando al LLM
+type TextWriter struct {
+	provider TextGenProvider
+	fallback TemplateTextGen
+	timeout  time.Duration
+	limit    int
+	window   time.Time
+	calls    int
+	cache    map[string]string
+	mutex    sync.Mutex
+}
+
+// * provider puede ser nil (solo plantillas); perMinute <= 0 quita el límite
+func NewTextWriter(provider TextGenProvider, perMinute int, timeout time.Duration) *TextWriter {
+	return &TextWriter{
+		provider: provider,
+		timeout:  timeout,
+		limit:    perMinute,
+		cache:    make(map[string]string),
+	}
+}
+
+func (w *TextWriter) Write(ctx context.Context, field string, cat m.CatProfile) (m.GeneratedText, error) {
+	key := textCacheKey(field, cat)
+
+	w.mutex.Lock()
+	if text, ok := w.cache[key]; ok {
+		w.mutex.Unlock()
+		return m.GeneratedText{Field: field, Text: text, Provider: "cache", Cached: true}, nil
+	}
+	allowed := w.provider != nil && w.allow()
+	w.mutex.Unlock()
+
+	if allowed {
+		providerCtx, cancel := context.WithTimeout(ctx, w.timeout)
+		text, err := w.provider.Generate(providerCtx, field, cat)
+		cancel()
+		if err == nil {
+			w.store(key, text)
+			return m.GeneratedText{Field: field, Text: text, Provider: w.provider.Name()}, nil
+		}
+		log.Printf("⚠️ Texto %s de %s con %s falló, usando plantilla: %v", field, cat.Name, w.provider.Name(), err)
+	}
+
+	// * Las plantillas no se cachean: así el LLM puede reemplazarlas cuando haya cupo
+	text, err := w.fallback.Generate(ctx, field, cat)
+	if err != nil {
+		return m.GeneratedText{}, err
+	}
+	return m.GeneratedText{Field: field, Text: text, Provider: w.fallback.Name()}, nil
+}
+
+// ! Requiere w.mutex tomado
+func (w *TextWriter) allow() bool {
+	if w.limit <= 0 {
+		return true
+	}
+	now := time.Now()
+	if now.Sub(w.window) >= time.Minute {
+		w.window = now
+		w.calls = 0
+	}
+	if w.calls >= w.limit {
+		return false
+	}
+	w.calls++
+	return true
+}
+
+func (w *TextWriter) store(key, text string) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if len(w.cache) >= maxTextCacheEntries {
+		w.cache = make(map[string]string)
+	}
+	w.cache[key] = text
+}
+
+func textCacheKey(field string, cat m.CatProfile) string {
+	return strings.Join([]string{field, cat.Name, cat.Breed, fmt.Sprint(cat.Age), strings.Join(cat.Hobbies, ","), cat.Personality}, "|")
+}
+
+// * Completa los campos pedidos; sin overwrite respeta los que ya tienen texto
+func (w *TextWriter) Fill(ctx context.Context, cat *m.CatProfile, fields []string, overwrite bool) ([]m.GeneratedText, error) {
+	generated := make([]m.GeneratedText, 0, len(fields))
+	// * La personalidad primero: la bio la usa como contexto
+	for _, field := range []string{m.TextFieldPersonality, m.TextFieldBio} {
+		if !containsField(fields, field) {
+			continue
+		}
+		target := &cat.Bio
+		if field == m.TextFieldPersonality {
+			target = &cat.Personality
+		}
+		if *target != "" && !overwrite {
+			continue
+		}
+		text, err := w.Write(ctx, field, *cat)
+		if err != nil {
+			return nil, err
+		}
+		*target = text.Text
+		generated = append(generated, text)
+	}
+	return generated, nil
+}
+
+func containsField(fields []string, field string) bool {
+	for _, f := range fields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// * Aplica bio y personalidad generadas sin tocar el resto del perfil
+func (s *CatService) SetProfileText(id int, bio, personality string) (*m.CatProfile, error) {
+	return s.mutateProfile(id, func(cat *m.CatProfile) error {
+		cat.Bio = bio
+		cat.Personality = personality
+		cat.UpdatedAt = time.Now()
+		return nil
+	})
+}
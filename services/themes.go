@@ -0,0 +1,185 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+)
+
+var ErrThemeNotFound = errors.New("tema no encontrado")
+
+// * Override que apaga los temas aunque el calendario diga otra cosa
+const ThemeOverrideNone = "none"
+
+const themeDayLayout = "01-02"
+
+var themeFilters = map[string]bool{"": true, "mono": true, "negate": true, "blur": true, "sepia": true}
+
+type ThemeService struct {
+	themes   []m.Theme
+	override string
+	mutex    sync.RWMutex
+}
+
+// * Sin archivo no hay temas; un archivo inválido sí es un error de arranque
+func LoadThemes(path string) (*ThemeService, error) {
+	service := &ThemeService{}
+	if path == "" {
+		return service, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return service, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var file struct {
+		Themes []m.Theme `json:"themes"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("%s inválido: %w", path, err)
+	}
+
+	seen := make(map[string]bool)
+	for _, theme := range file.Themes {
+		if theme.ID == "" || theme.ID == ThemeOverrideNone || seen[theme.ID] {
+			return nil, fmt.Errorf("id de tema inválido o repetido: %q", theme.ID)
+		}
+		seen[theme.ID] = true
+		if err := validateTheme(theme); err != nil {
+			return nil, fmt.Errorf("tema %s: %w", theme.ID, err)
+		}
+	}
+	service.themes = file.Themes
+	log.Printf("🎃 Temas de temporada cargados: %d", len(service.themes))
+	return service, nil
+}
+
+func validateTheme(theme m.Theme) error {
+	if theme.Weekday != "" {
+		if _, ok := ParseWeekday(theme.Weekday); !ok {
+			return fmt.Errorf("día de la semana inválido: %q", theme.Weekday)
+		}
+	} else {
+		if _, err := time.Parse(themeDayLayout, theme.From); err != nil {
+			return fmt.Errorf("from inválido (MM-DD): %q", theme.From)
+		}
+		if _, err := time.Parse(themeDayLayout, theme.To); err != nil {
+			return fmt.Errorf("to inválido (MM-DD): %q", theme.To)
+		}
+	}
+	if !themeFilters[theme.Filter] {
+		return fmt.Errorf("filtro de cataas desconocido: %q", theme.Filter)
+	}
+	return nil
+}
+
+func ParseWeekday(name string) (time.Weekday, bool) {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.EqualFold(day.String(), name) {
+			return day, true
+		}
+	}
+	return 0, false
+}
+
+// * Gana el override; si no, el primer tema del archivo cuyo calendario coincide
+func (s *ThemeService) Active(now time.Time) *m.Theme {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	if s.override == ThemeOverrideNone {
+		return nil
+	}
+	for _, theme := range s.themes {
+		if s.override != "" && theme.ID == s.override {
+			return &theme
+		}
+	}
+	if s.override != "" {
+		return nil
+	}
+	for _, theme := range s.themes {
+		if themeMatches(theme, now) {
+			return &theme
+		}
+	}
+	return nil
+}
+
+// * Los rangos comparan "MM-DD" como texto; si from > to el rango cruza el fin de año
+func themeMatches(theme m.Theme, now time.Time) bool {
+	if theme.Weekday != "" {
+		day, _ := ParseWeekday(theme.Weekday)
+		return now.Weekday() == day
+	}
+	today := now.Format(themeDayLayout)
+	if theme.From <= theme.To {
+		return today >= theme.From && today <= theme.To
+	}
+	return today >= theme.From || today <= theme.To
+}
+
+// * Aplica el tema activo a las opciones; no pisa lo que el cliente pidió explícitamente
+func (s *ThemeService) Decorate(opts m.ImageOptions, now time.Time) (m.ImageOptions, *m.ActiveTheme) {
+	theme := s.Active(now)
+	if theme == nil {
+		return opts, nil
+	}
+	if opts.Says == "" {
+		opts.Says = theme.Says
+	}
+	if opts.Filter == "" {
+		opts.Filter = theme.Filter
+	}
+	return opts, &m.ActiveTheme{ID: theme.ID, Name: theme.Name, Color: theme.Color}
+}
+
+func (s *ThemeService) Themes() m.ThemesResponse {
+	active := s.Active(time.Now())
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return m.ThemesResponse{
+		Themes:   append([]m.Theme{}, s.themes...),
+		Active:   active,
+		Override: s.override,
+	}
+}
+
+func (s *ThemeService) SetOverride(themeID string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if themeID != ThemeOverrideNone {
+		found := false
+		for _, theme := range s.themes {
+			if theme.ID == themeID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return ErrThemeNotFound
+		}
+	}
+	s.override = themeID
+	log.Printf("🎨 Override de tema: %s", themeID)
+	return nil
+}
+
+func (s *ThemeService) ClearOverride() {
+	s.mutex.Lock()
+	s.override = ""
+	s.mutex.Unlock()
+	log.Println("🎨 Override de tema eliminado, vuelve el calendario")
+}
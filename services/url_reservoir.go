@@ -0,0 +1,121 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// * Reserva persistente de URLs que pasaron validación; es el último recurso
+// * cuando cataas no responde
+type URLReservoir struct {
+	path  string
+	max   int
+	urls  []string
+	index map[string]bool
+	mutex sync.RWMutex
+}
+
+func NewURLReservoir(path string, max int) *URLReservoir {
+	r := &URLReservoir{
+		path:  path,
+		max:   max,
+		index: make(map[string]bool),
+	}
+
+	if err := r.load(); err != nil {
+		log.Printf("⚠️ Error cargando reserva de URLs: %v", err)
+	} else if len(r.urls) > 0 {
+		log.Printf("💾 Reserva de URLs cargada: %d", len(r.urls))
+	}
+
+	return r
+}
+
+func (r *URLReservoir) Add(url string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.index[url] {
+		return
+	}
+	if len(r.urls) >= r.max {
+		delete(r.index, r.urls[0])
+		r.urls = r.urls[1:]
+	}
+	r.urls = append(r.urls, url)
+	r.index[url] = true
+}
+
+func (r *URLReservoir) Sample(count int) []string {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	if count > len(r.urls) {
+		count = len(r.urls)
+	}
+	result := make([]string, 0, count)
+	for _, i := range rand.Perm(len(r.urls))[:count] {
+		result = append(result, r.urls[i])
+	}
+	return result
+}
+
+func (r *URLReservoir) Size() int {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return len(r.urls)
+}
+
+func (r *URLReservoir) Save() error {
+	if r.path == "" {
+		return nil
+	}
+
+	r.mutex.RLock()
+	data, err := json.Marshal(r.urls)
+	r.mutex.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return fmt.Errorf("error creando directorio de la reserva: %w", err)
+	}
+
+	// ! Escribir a un temporal y renombrar para no dejar el archivo a medias
+	tmp := r.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, r.path)
+}
+
+func (r *URLReservoir) load() error {
+	if r.path == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(r.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var urls []string
+	if err := json.Unmarshal(data, &urls); err != nil {
+		return fmt.Errorf("reserva corrupta: %w", err)
+	}
+
+	for _, url := range urls {
+		r.Add(url)
+	}
+	return nil
+}
@@ -0,0 +1,187 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+)
+
+const (
+	validationWorkers = 5
+	maxValidatedURLs  = 100
+)
+
+var errInvalidURL = errors.New("la URL no pasó la validación")
+
+// * Llena el pool de URLs ya validadas (HEAD 200) para servirlas sin latencia en frío
+func (s *CatService) PrevalidateURLs(ctx context.Context, count int, timeout time.Duration) int {
+	jobs := make(chan struct{})
+	var wg sync.WaitGroup
+	var added int
+	var addedMutex sync.Mutex
+
+	for w := 0; w < validationWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range jobs {
+				err := s.retry.Do(ctx, func(attempt int) error {
+					catURL := s.generateCatURL()
+					if !s.validateCatURL(catURL, timeout) {
+						return errInvalidURL
+					}
+					s.addValidatedURL(catURL)
+					return nil
+				})
+				if err == nil {
+					addedMutex.Lock()
+					added++
+					addedMutex.Unlock()
+				}
+			}
+		}()
+	}
+
+	for i := 0; i < count; i++ {
+		select {
+		case jobs <- struct{}{}:
+		case <-ctx.Done():
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	log.Printf("✅ Pool de URLs validadas: %d nuevas (%d en total)", added, s.ValidatedPoolSize())
+
+	if s.reservoir != nil && added > 0 {
+		if err := s.reservoir.Save(); err != nil {
+			log.Printf("⚠️ Error guardando reserva de URLs: %v", err)
+		}
+	}
+	return added
+}
+
+func (s *CatService) TakeValidatedURL() (m.CatURL, bool) {
+	s.poolMutex.Lock()
+	defer s.poolMutex.Unlock()
+
+	if len(s.validatedURLs) == 0 {
+		return m.CatURL{}, false
+	}
+	catURL := s.validatedURLs[0]
+	s.validatedURLs = s.validatedURLs[1:]
+	return catURL, true
+}
+
+func (s *CatService) ValidatedPoolSize() int {
+	s.poolMutex.Lock()
+	defer s.poolMutex.Unlock()
+	return len(s.validatedURLs)
+}
+
+func (s *CatService) addValidatedURL(catURL m.CatURL) {
+	s.poolMutex.Lock()
+	defer s.poolMutex.Unlock()
+
+	if len(s.validatedURLs) >= maxValidatedURLs {
+		s.validatedURLs = s.validatedURLs[1:]
+	}
+	s.validatedURLs = append(s.validatedURLs, catURL)
+}
+
+// * Variante estricta de GenerateCatURLs: primero el pool pre-validado y luego HEAD en
+// * vivo hasta completar el lote o agotar el deadline (lo que llegue primero)
+func (s *CatService) GenerateValidatedCatURLs(ctx context.Context, count int, opts m.ImageOptions) (*m.CatBatch, error) {
+	start := time.Now()
+	deadline, _ := ctx.Deadline()
+
+	s.countMutex.Lock()
+	s.batchCount++
+	currentBatch := s.batchCount
+	s.countMutex.Unlock()
+
+	urls := make([]string, 0, count)
+
+	// ! El pool solo tiene URLs sin tag, tamaño ni decoración de tema
+	if opts == (m.ImageOptions{}) {
+		for len(urls) < count {
+			catURL, ok := s.TakeValidatedURL()
+			if !ok {
+				break
+			}
+			urls = append(urls, catURL.URL)
+		}
+	}
+	fromPool := len(urls)
+
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	filled := func() bool {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return len(urls) >= count
+	}
+	missing := count - len(urls)
+	for w := 0; w < min(missing, validationWorkers); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// * Con el backoff de la política entre fallos: si el proveedor falla al instante
+			// * (p. ej. sin cupo de salida) los trabajadores esperan en vez de girar en vacío
+			for ctx.Err() == nil && !filled() {
+				_ = s.retry.Do(ctx, func(attempt int) error {
+					if filled() {
+						return nil
+					}
+					timeout := 2 * time.Second
+					if !deadline.IsZero() {
+						timeout = min(timeout, time.Until(deadline))
+					}
+					catURL := s.generateCatURLWith(opts)
+					if !s.validateCatURL(catURL, timeout) {
+						return errInvalidURL
+					}
+
+					mutex.Lock()
+					if len(urls) < count {
+						urls = append(urls, catURL.URL)
+					}
+					mutex.Unlock()
+					return nil
+				})
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(urls) == 0 {
+		s.reporter.CaptureError(errProvidersExhausted, map[string]string{"path": "validated"})
+		return nil, fmt.Errorf("ninguna imagen pasó la validación a tiempo")
+	}
+
+	meta := &m.BatchMeta{
+		Validated:     true,
+		FromPool:      fromPool,
+		ValidatedLive: len(urls) - fromPool,
+		ElapsedMs:     time.Since(start).Milliseconds(),
+	}
+	if !deadline.IsZero() {
+		meta.DeadlineMs = deadline.Sub(start).Milliseconds()
+	}
+
+	s.stats.BatchServed(len(urls))
+	log.Printf("✅ Lote validado %d: %d imágenes (%d del pool) en %dms", currentBatch, len(urls), fromPool, meta.ElapsedMs)
+	return &m.CatBatch{
+		URLs:      urls,
+		Batch:     currentBatch,
+		Meta:      meta,
+		Requested: count,
+		Errors:    missingErrors(len(urls), count, "no pasó la validación antes del deadline"),
+		Truncated: len(urls) < count && ctx.Err() != nil,
+	}, nil
+}
@@ -0,0 +1,384 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+)
+
+var ErrChannelNotFound = errors.New("canal no encontrado")
+
+type WebhookService struct {
+	catService    *CatService
+	links         *LinkService
+	baseURL       string
+	client        *http.Client
+	secretGrace   time.Duration
+	path          string
+	channels      map[string]m.WebhookChannel
+	channelsMutex sync.RWMutex
+}
+
+// * secretGrace es cuánto sigue firmando el secreto anterior tras una rotación. Con path
+// * los canales y sus secretos sobreviven reinicios; un canal guardado pisa al inicial con
+// * el mismo id. Los iniciales sin secreto reciben uno al azar: el admin lo conoce rotándolo
+func NewWebhookService(catService *CatService, links *LinkService, baseURL, path string, initial []m.WebhookChannel, secretGrace time.Duration) *WebhookService {
+	service := &WebhookService{
+		catService: catService,
+		links:      links,
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		secretGrace: secretGrace,
+		path:        path,
+		channels:    make(map[string]m.WebhookChannel),
+	}
+
+	for _, channel := range initial {
+		if len(channel.Secrets) == 0 {
+			channel.Secrets = []m.WebhookSecret{newWebhookSecret(1)}
+		}
+		channel.SecretVersion = channel.Secrets[0].Version
+		service.channels[channel.ID] = channel
+	}
+
+	if err := service.load(); err != nil {
+		log.Printf("⚠️ Error cargando canales de webhooks: %v", err)
+	}
+
+	return service
+}
+
+func (s *WebhookService) ListChannels() []m.WebhookChannel {
+	s.channelsMutex.RLock()
+	defer s.channelsMutex.RUnlock()
+
+	now := time.Now()
+	channels := make([]m.WebhookChannel, 0, len(s.channels))
+	for _, channel := range s.channels {
+		channels = append(channels, channelView(channel, now))
+	}
+	sort.Slice(channels, func(i, j int) bool { return channels[i].ID < channels[j].ID })
+
+	return channels
+}
+
+// * La respuesta trae el secreto de firma; no se vuelve a mostrar
+func (s *WebhookService) CreateChannel(req m.WebhookChannelRequest) m.WebhookChannelSecret {
+	secret := newWebhookSecret(1)
+	channel := m.WebhookChannel{
+		ID:            newChannelID(),
+		Name:          req.Name,
+		Kind:          req.Kind,
+		URL:           req.URL,
+		Enabled:       req.Enabled == nil || *req.Enabled,
+		SecretVersion: secret.Version,
+		Secrets:       []m.WebhookSecret{secret},
+	}
+
+	s.channelsMutex.Lock()
+	s.channels[channel.ID] = channel
+	s.persist()
+	s.channelsMutex.Unlock()
+
+	log.Printf("🔗 Canal %s (%s) registrado", channel.Name, channel.Kind)
+	return m.WebhookChannelSecret{WebhookChannel: channelView(channel, time.Now()), Secret: secret.Secret}
+}
+
+func (s *WebhookService) UpdateChannel(id string, req m.WebhookChannelRequest) (m.WebhookChannel, error) {
+	s.channelsMutex.Lock()
+	defer s.channelsMutex.Unlock()
+
+	channel, ok := s.channels[id]
+	if !ok {
+		return m.WebhookChannel{}, ErrChannelNotFound
+	}
+
+	channel.Name = req.Name
+	channel.Kind = req.Kind
+	channel.URL = req.URL
+	if req.Enabled != nil {
+		channel.Enabled = *req.Enabled
+	}
+	s.channels[id] = channel
+	s.persist()
+
+	return channelView(channel, time.Now()), nil
+}
+
+func (s *WebhookService) DeleteChannel(id string) error {
+	s.channelsMutex.Lock()
+	defer s.channelsMutex.Unlock()
+
+	if _, ok := s.channels[id]; !ok {
+		return ErrChannelNotFound
+	}
+	delete(s.channels, id)
+	s.persist()
+	return nil
+}
+
+func (s *WebhookService) PostCatOfTheDay(ctx context.Context) error {
+	cat, err := s.catService.CatOfTheDay(time.Now())
+	if err != nil {
+		return err
+	}
+
+	if err := s.broadcast(ctx, cat, catOfTheDayTitle(cat)); err != nil {
+		return err
+	}
+
+	log.Printf("📣 Gato del día publicado: %s", cat.Name)
+	return nil
+}
+
+// * Listener de transiciones: anuncia las adopciones en los canales activos
+func (s *WebhookService) AnnounceAdoption(transition m.ProfileTransition) {
+	if transition.To != m.StatusAdopted {
+		return
+	}
+
+	cat, err := s.catService.GetCatProfileByID(transition.CatID)
+	if err != nil {
+		log.Printf("⚠️ No se pudo anunciar la adopción de %s: %v", transition.CatName, err)
+		return
+	}
+
+	// ! Se llama desde la petición de admin; el envío no debe bloquearla
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if err := s.broadcast(ctx, cat, fmt.Sprintf("🏡 ¡%s encontró hogar!", cat.Name)); err != nil {
+			log.Printf("⚠️ Error anunciando adopción: %v", err)
+		}
+	}()
+}
+
+// * Publisher del outbox: los matches se anuncian en los canales; el resto de
+// * eventos no tiene destino todavía y se da por publicado
+func (s *WebhookService) Publish(ctx context.Context, event m.OutboxEvent) error {
+	// ! Los canales de webhook son del tenant por defecto
+	if event.Topic != m.TopicMatchCreated || event.TenantID != DefaultTenantID {
+		return nil
+	}
+
+	var match m.Match
+	if err := json.Unmarshal(event.Payload, &match); err != nil {
+		return fmt.Errorf("payload inválido: %w", err)
+	}
+	cat, err := s.catService.GetCatProfileByID(match.CatID)
+	if err != nil {
+		// ! El perfil ya no es público; no tiene sentido reintentar
+		log.Printf("⚠️ Match %s sin perfil publicable: %v", match.ID, err)
+		return nil
+	}
+	return s.broadcast(ctx, cat, fmt.Sprintf("💘 ¡%s tiene un nuevo match!", cat.Name))
+}
+
+func (s *WebhookService) broadcast(ctx context.Context, cat *m.CatProfile, title string) error {
+	var failed []string
+	for _, channel := range s.ListChannels() {
+		if !channel.Enabled {
+			continue
+		}
+		if err := s.post(ctx, channel, cat, title); err != nil {
+			log.Printf("⚠️ Error enviando a %s: %v", channel.Name, err)
+			failed = append(failed, channel.Name)
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("fallaron %d canales: %s", len(failed), strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+func catOfTheDayTitle(cat *m.CatProfile) string {
+	return fmt.Sprintf("🐱 Gato del día: %s", cat.Name)
+}
+
+func (s *WebhookService) SendTest(ctx context.Context, id string) error {
+	s.channelsMutex.RLock()
+	channel, ok := s.channels[id]
+	s.channelsMutex.RUnlock()
+	if !ok {
+		return ErrChannelNotFound
+	}
+
+	cat, err := s.catService.CatOfTheDay(time.Now())
+	if err != nil {
+		return err
+	}
+	return s.post(ctx, channel, cat, catOfTheDayTitle(cat))
+}
+
+func (s *WebhookService) post(ctx context.Context, channel m.WebhookChannel, cat *m.CatProfile, title string) error {
+	// * Enlace corto para contar los clics que llegan desde Discord/Slack
+	shareURL := fmt.Sprintf("%s/share/%d", s.baseURL, cat.ID)
+	if link, err := s.links.ProfileLink(ctx, DefaultTenantID, cat.ID); err == nil {
+		shareURL = link.ShortURL
+	}
+	imageURL := fmt.Sprintf("%s/api/profiles/%d/image", s.baseURL, cat.ID)
+	snippet := BioSnippet(cat.Bio, 180)
+
+	var payload any
+	switch channel.Kind {
+	case "discord":
+		payload = map[string]any{
+			"embeds": []map[string]any{{
+				"title":       title,
+				"description": snippet,
+				"url":         shareURL,
+				"image":       map[string]string{"url": imageURL},
+			}},
+		}
+	case "slack":
+		payload = map[string]any{
+			"text": title,
+			"blocks": []map[string]any{
+				{
+					"type": "section",
+					"text": map[string]string{
+						"type": "mrkdwn",
+						"text": fmt.Sprintf("*<%s|%s>*\n%s", shareURL, title, snippet),
+					},
+				},
+				{
+					"type":      "image",
+					"image_url": imageURL,
+					"alt_text":  cat.Name,
+				},
+			},
+		}
+	default:
+		return fmt.Errorf("tipo de canal desconocido: %s", channel.Kind)
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, channel.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	signWebhook(req.Header, activeSecrets(channel.Secrets, time.Now()), body, time.Now())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("el webhook respondió %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// * Recorta a limit runas (sin contar el "…" que se agrega)
+func BioSnippet(bio string, limit int) string {
+	runes := []rune(bio)
+	if len(runes) <= limit {
+		return bio
+	}
+	return strings.TrimSpace(string(runes[:limit])) + "…"
+}
+
+// * En disco los secretos sí se guardan (hacen falta para firmar); en las respuestas no viajan
+type storedChannel struct {
+	m.WebhookChannel
+	Secrets []m.WebhookSecret `json:"secrets"`
+}
+
+// * Se llama con channelsMutex tomado
+func (s *WebhookService) persist() {
+	if err := s.save(); err != nil {
+		log.Printf("⚠️ Error guardando canales de webhooks: %v", err)
+	}
+}
+
+func (s *WebhookService) save() error {
+	if s.path == "" {
+		return nil
+	}
+
+	now := time.Now()
+	stored := make([]storedChannel, 0, len(s.channels))
+	for _, channel := range s.channels {
+		stored = append(stored, storedChannel{WebhookChannel: channel, Secrets: activeSecrets(channel.Secrets, now)})
+	}
+	sort.Slice(stored, func(i, j int) bool { return stored[i].ID < stored[j].ID })
+
+	data, err := json.MarshalIndent(stored, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("error creando directorio de webhooks: %w", err)
+	}
+
+	// ! Escribir a un temporal y renombrar para no dejar el archivo a medias
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
+
+func (s *WebhookService) load() error {
+	if s.path == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var stored []storedChannel
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return fmt.Errorf("lista de canales corrupta: %w", err)
+	}
+
+	now := time.Now()
+	for _, entry := range stored {
+		channel := entry.WebhookChannel
+		channel.Secrets = activeSecrets(entry.Secrets, now)
+		if len(channel.Secrets) == 0 {
+			log.Printf("⚠️ Canal %s guardado sin secretos vigentes, se omite", channel.ID)
+			continue
+		}
+		s.channels[channel.ID] = channel
+	}
+	log.Printf("🔗 Canales de webhooks cargados: %d", len(stored))
+	return nil
+}
+
+func newChannelID() string {
+	buf := make([]byte, 6)
+	if _, err := rand.Read(buf); err != nil {
+		return fmt.Sprintf("ch-%d", time.Now().UnixNano())
+	}
+	return "ch-" + hex.EncodeToString(buf)
+}
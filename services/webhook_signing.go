@@ -0,0 +1,100 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+)
+
+const (
+	webhookSignatureHeader = "X-Meownder-Signature"
+	webhookTimestampHeader = "X-Meownder-Timestamp"
+	webhookSecretPrefix    = "whsec_"
+)
+
+// * Rota el secreto del canal: el nuevo firma desde ya y el anterior sigue firmando durante
+// * la gracia, así el receptor puede cambiar de clave sin perder envíos. Con gracia 0 (clave
+// * filtrada) el anterior deja de valer al instante
+func (s *WebhookService) RotateSecret(id string, req m.RotateSecretRequest) (m.WebhookChannelSecret, error) {
+	grace := s.secretGrace
+	if req.GraceSeconds != nil {
+		grace = time.Duration(*req.GraceSeconds) * time.Second
+	}
+
+	s.channelsMutex.Lock()
+	defer s.channelsMutex.Unlock()
+
+	channel, ok := s.channels[id]
+	if !ok {
+		return m.WebhookChannelSecret{}, ErrChannelNotFound
+	}
+
+	now := time.Now()
+	current := newWebhookSecret(channel.SecretVersion + 1)
+	secrets := []m.WebhookSecret{current}
+	// * Solo el vigente pasa a anterior; los que ya eran anteriores dejan de firmar
+	if grace > 0 {
+		for _, secret := range channel.Secrets {
+			if secret.Until == nil {
+				until := now.Add(grace)
+				secret.Until = &until
+				secrets = append(secrets, secret)
+			}
+		}
+	}
+	channel.Secrets = secrets
+	channel.SecretVersion = current.Version
+	s.channels[id] = channel
+	s.persist()
+
+	return m.WebhookChannelSecret{WebhookChannel: channelView(channel, now), Secret: current.Secret}, nil
+}
+
+// * Secretos que todavía firman, el vigente primero
+func activeSecrets(secrets []m.WebhookSecret, now time.Time) []m.WebhookSecret {
+	return slices.DeleteFunc(slices.Clone(secrets), func(secret m.WebhookSecret) bool {
+		return secret.Until != nil && now.After(*secret.Until)
+	})
+}
+
+// * El canal como lo ve el admin: sin secretos y con el vencimiento del anterior si sigue vigente
+func channelView(channel m.WebhookChannel, now time.Time) m.WebhookChannel {
+	channel.Secrets = activeSecrets(channel.Secrets, now)
+	channel.PreviousSecretUntil = nil
+	for _, secret := range channel.Secrets {
+		if secret.Until != nil && (channel.PreviousSecretUntil == nil || secret.Until.After(*channel.PreviousSecretUntil)) {
+			channel.PreviousSecretUntil = secret.Until
+		}
+	}
+	return channel
+}
+
+func newWebhookSecret(version int) m.WebhookSecret {
+	return m.WebhookSecret{Version: version, Secret: webhookSecretPrefix + randomHex(24)}
+}
+
+// * X-Meownder-Signature = "v2=firma,v1=firma", una por secreto activo, con
+// * firma = hex(hmac_sha256(secreto, "timestamp.cuerpo"))
+func signWebhook(header http.Header, secrets []m.WebhookSecret, body []byte, now time.Time) {
+	if len(secrets) == 0 {
+		return
+	}
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	signatures := make([]string, 0, len(secrets))
+	for _, secret := range secrets {
+		mac := hmac.New(sha256.New, []byte(secret.Secret))
+		mac.Write([]byte(timestamp + "."))
+		mac.Write(body)
+		signatures = append(signatures, fmt.Sprintf("v%d=%s", secret.Version, hex.EncodeToString(mac.Sum(nil))))
+	}
+	header.Set(webhookTimestampHeader, timestamp)
+	header.Set(webhookSignatureHeader, strings.Join(signatures, ","))
+}
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+)
+
+func TestSignWebhookKnownVector(t *testing.T) {
+	at := time.Unix(1767225600, 0)
+	until := at.Add(time.Hour)
+	secrets := []m.WebhookSecret{
+		{Version: 4, Secret: "whsec_actual"},
+		{Version: 3, Secret: "whsec_anterior", Until: &until},
+	}
+
+	header := http.Header{}
+	signWebhook(header, secrets, []byte(`{"content":"Nuevo match con Luna"}`), at)
+
+	// * Calculadas aparte con hmac y hashlib de Python
+	want := "v4=1b420c75750cc9ec3c25f090b58829f76d828e450b2f17856b753d8eeac8383a," +
+		"v3=863a563a68401231fa3e94c33d68a0dda4ac2326f945b8a4ba7194b8874a094d"
+	if got := header.Get(webhookSignatureHeader); got != want {
+		t.Errorf("firma = %s\nse esperaba %s", got, want)
+	}
+	if got := header.Get(webhookTimestampHeader); got != "1767225600" {
+		t.Errorf("timestamp = %s", got)
+	}
+
+	empty := http.Header{}
+	signWebhook(empty, nil, []byte("{}"), at)
+	if len(empty) != 0 {
+		t.Errorf("sin secretos no se firma: %v", empty)
+	}
+}
+
+func TestRotateSecret(t *testing.T) {
+	service := NewWebhookService(nil, nil, "https://meownder.app", "", []m.WebhookChannel{
+		{ID: "general", Name: "General", Kind: "discord", URL: "https://discord.test/hook", Enabled: true},
+	}, time.Hour)
+	original := service.channels["general"].Secrets[0]
+
+	rotated, err := service.RotateSecret("general", m.RotateSecretRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rotated.SecretVersion != original.Version+1 || !strings.HasPrefix(rotated.Secret, webhookSecretPrefix) || rotated.Secret == original.Secret {
+		t.Fatalf("rotación = %+v", rotated)
+	}
+	if rotated.PreviousSecretUntil == nil {
+		t.Fatal("falta previous_secret_until durante la gracia")
+	}
+
+	now := time.Now()
+	active := activeSecrets(service.channels["general"].Secrets, now)
+	if len(active) != 2 || active[0].Secret != rotated.Secret || active[1].Secret != original.Secret {
+		t.Fatalf("secretos activos = %+v", active)
+	}
+	if expired := activeSecrets(service.channels["general"].Secrets, now.Add(2*time.Hour)); len(expired) != 1 {
+		t.Fatalf("tras la gracia quedan %d secretos", len(expired))
+	}
+
+	// * Otra rotación descarta el que estaba en gracia; con gracia 0 no queda anterior
+	second, _ := service.RotateSecret("general", m.RotateSecretRequest{})
+	if active := activeSecrets(service.channels["general"].Secrets, now); len(active) != 2 || active[1].Secret != rotated.Secret {
+		t.Fatalf("tras la segunda rotación = %+v", active)
+	}
+	zero := 0
+	leaked, _ := service.RotateSecret("general", m.RotateSecretRequest{GraceSeconds: &zero})
+	if active := activeSecrets(service.channels["general"].Secrets, now); len(active) != 1 || active[0].Secret != leaked.Secret {
+		t.Fatalf("con gracia 0 = %+v", active)
+	}
+	if leaked.PreviousSecretUntil != nil || leaked.SecretVersion != second.SecretVersion+1 {
+		t.Fatalf("rotación sin gracia = %+v", leaked)
+	}
+
+	if _, err := service.RotateSecret("nadie", m.RotateSecretRequest{}); err != ErrChannelNotFound {
+		t.Fatalf("canal inexistente: %v", err)
+	}
+}
+
+func TestWebhookChannelsPersist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "webhooks.json")
+	seeded := []m.WebhookChannel{{ID: "discord-default", Name: "Discord", Kind: "discord", URL: "https://discord.test/hook", Enabled: true}}
+	service := NewWebhookService(nil, nil, "https://meownder.app", path, seeded, time.Hour)
+
+	enabled := false
+	created := service.CreateChannel(m.WebhookChannelRequest{Name: "Slack refugio", Kind: "slack", URL: "https://slack.test/a"})
+	if _, err := service.UpdateChannel(created.ID, m.WebhookChannelRequest{Name: "Slack norte", Kind: "slack", URL: "https://slack.test/b", Enabled: &enabled}); err != nil {
+		t.Fatal(err)
+	}
+	rotated, err := service.RotateSecret("discord-default", m.RotateSecretRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// * Tras un reinicio siguen el canal editado y el secreto rotado (con el anterior en gracia)
+	reloaded := NewWebhookService(nil, nil, "https://meownder.app", path, seeded, time.Hour)
+	channel, ok := reloaded.channels[created.ID]
+	if !ok || channel.Name != "Slack norte" || channel.URL != "https://slack.test/b" || channel.Enabled {
+		t.Fatalf("canal creado tras recargar = %+v", channel)
+	}
+	if channel.Secrets[0].Secret != created.Secret {
+		t.Fatal("el canal creado perdió su secreto")
+	}
+	discord := reloaded.channels["discord-default"]
+	if discord.SecretVersion != rotated.SecretVersion || len(discord.Secrets) != 2 || discord.Secrets[0].Secret != rotated.Secret {
+		t.Fatalf("canal inicial tras recargar = %+v", discord)
+	}
+
+	if err := reloaded.DeleteChannel(created.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := NewWebhookService(nil, nil, "https://meownder.app", path, seeded, time.Hour).channels[created.ID]; ok {
+		t.Fatal("el canal borrado volvió tras recargar")
+	}
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/url"
+	"strings"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+)
+
+var (
+	ErrInvalidWidgetKey = errors.New("clave de widget inválida")
+	ErrWidgetOrigin     = errors.New("este origen no puede usar la clave de widget")
+)
+
+// * Claves públicas del widget embebible, cada una restringida a los orígenes que la
+// * pueden incrustar. "*" permite cualquier origen
+type WidgetKeys struct {
+	origins map[string][]string
+}
+
+// * Formato por entrada: "clave=https://blog.com https://otro.com"
+func ParseWidgetKeys(specs []string) (*WidgetKeys, error) {
+	keys := &WidgetKeys{origins: make(map[string][]string)}
+	for _, spec := range specs {
+		key, rawOrigins, found := strings.Cut(spec, "=")
+		key = strings.TrimSpace(key)
+		if !found || key == "" {
+			return nil, fmt.Errorf("clave de widget sin orígenes: %q", spec)
+		}
+		var origins []string
+		for _, origin := range strings.Fields(rawOrigins) {
+			if origin != "*" {
+				parsed, err := url.Parse(origin)
+				if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" || parsed.Path != "" {
+					return nil, fmt.Errorf("origen inválido para la clave %s: %q", key, origin)
+				}
+			}
+			origins = append(origins, origin)
+		}
+		if len(origins) == 0 {
+			return nil, fmt.Errorf("la clave de widget %s no tiene orígenes", key)
+		}
+		keys.origins[key] = origins
+	}
+	return keys, nil
+}
+
+func (k *WidgetKeys) Origins(key string) ([]string, error) {
+	origins, ok := k.origins[key]
+	if !ok {
+		return nil, ErrInvalidWidgetKey
+	}
+	return origins, nil
+}
+
+// * Sin Origin (mismo origen, el iframe del propio widget) siempre se permite
+func (k *WidgetKeys) Check(key, origin string) error {
+	origins, err := k.Origins(key)
+	if err != nil {
+		return err
+	}
+	if origin == "" {
+		return nil
+	}
+	for _, allowed := range origins {
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			return nil
+		}
+	}
+	return ErrWidgetOrigin
+}
+
+// * Gato al azar para el widget; exceptuar evita repetir el que ya se ve
+func (s *CatService) WidgetCat(except int, baseURL, tenantID string) (*m.WidgetCat, error) {
+	profiles := s.GetCatProfiles()
+	if len(profiles) > 1 {
+		for i, cat := range profiles {
+			if cat.ID == except {
+				profiles = append(profiles[:i], profiles[i+1:]...)
+				break
+			}
+		}
+	}
+	if len(profiles) == 0 {
+		return nil, fmt.Errorf("no hay perfiles cargados")
+	}
+
+	cat := profiles[rand.IntN(len(profiles))]
+	return &m.WidgetCat{
+		ID:    cat.ID,
+		Name:  cat.Name,
+		Age:   cat.Age,
+		Breed: cat.Breed,
+		Img:   TenantURL(baseURL, fmt.Sprintf("/api/profiles/%d/image", cat.ID), tenantID),
+		URL:   TenantURL(baseURL, fmt.Sprintf("/share/%d", cat.ID), tenantID),
+	}, nil
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+	s "github.com/ChrisTheAbysswalker/meownder-backend/services"
+)
+
+// * meownder simulate -events swipes.json [-profiles cats.json] [-probability 0.3,0.5]
+// * [-ranking archetype,diverse] [-seed 1] [-json]: una corrida por combinación
+func runSimulateCommand(defaultProbability float64, args []string) error {
+	flags := flag.NewFlagSet("simulate", flag.ContinueOnError)
+	eventsPath := flags.String("events", "", "swipes a reproducir: JSON (swipes.json de un respaldo) o JSONL")
+	profilesPath := flags.String("profiles", "cats.json", "perfiles contra los que se reproduce")
+	probabilities := flags.String("probability", strconv.FormatFloat(defaultProbability, 'f', -1, 64), "probabilidades de match del refugio, separadas por coma")
+	rankings := flags.String("ranking", "archetype", "rankings del mazo, separados por coma")
+	seed := flags.Uint64("seed", 1, "semilla de la aleatoriedad (misma semilla, mismo resultado)")
+	asJSON := flags.Bool("json", false, "salida en JSON")
+	verbose := flags.Bool("v", false, "mostrar los logs de los servicios")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *eventsPath == "" {
+		return fmt.Errorf("falta -events")
+	}
+
+	var probabilityValues []float64
+	for _, raw := range strings.Split(*probabilities, ",") {
+		value, err := strconv.ParseFloat(strings.TrimSpace(raw), 64)
+		if err != nil || value < 0 || value > 1 {
+			return fmt.Errorf("probabilidad inválida: %q", raw)
+		}
+		probabilityValues = append(probabilityValues, value)
+	}
+	var rankers []s.DeckRanker
+	for _, name := range strings.Split(*rankings, ",") {
+		ranker, err := s.ParseDeckRanker(name)
+		if err != nil {
+			return err
+		}
+		rankers = append(rankers, ranker)
+	}
+
+	file, err := os.Open(*eventsPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	events, err := s.ReadSwipeEvents(file)
+	if err != nil {
+		return err
+	}
+
+	// * Cada corrida recarga los perfiles y eso loguea una línea por gato
+	if !*verbose {
+		log.SetOutput(io.Discard)
+		defer log.SetOutput(os.Stderr)
+	}
+
+	results := make([]m.SimulationResult, 0, len(probabilityValues)*len(rankers))
+	for _, ranker := range rankers {
+		for _, probability := range probabilityValues {
+			result, err := s.Simulate(context.Background(), events, s.SimulationConfig{
+				ProfilesPath:     *profilesPath,
+				MatchProbability: probability,
+				Ranking:          ranker,
+				Seed:             *seed,
+			})
+			if err != nil {
+				return err
+			}
+			results = append(results, result)
+		}
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(results)
+	}
+	printSimulationResults(len(events), results)
+	return nil
+}
+
+func printSimulationResults(events int, results []m.SimulationResult) {
+	fmt.Printf("🧪 %d eventos reproducidos\n", events)
+	fmt.Printf("   %-10s %5s %9s %7s %7s %7s %7s %9s %10s\n", "ranking", "prob", "aplicados", "likes", "matches", "mutuos", "tasa", "rank like", "rechazados")
+	for _, result := range results {
+		rejected := 0
+		for _, count := range result.Rejected {
+			rejected += count
+		}
+		fmt.Printf("   %-10s %5.2f %9d %7d %7d %7d %6.1f%% %9.1f %10d\n",
+			result.Ranking, result.MatchProbability, result.Applied, result.Likes+result.SuperLikes,
+			result.ShelterMatches, result.MutualMatches, result.MatchRate*100, result.AvgLikeRank, rejected)
+	}
+
+	// * El detalle de rechazos es igual en todas las corridas salvo super-likes: basta el primero
+	if len(results) > 0 && len(results[0].Rejected) > 0 {
+		codes := make([]string, 0, len(results[0].Rejected))
+		for code := range results[0].Rejected {
+			codes = append(codes, code)
+		}
+		sort.Strings(codes)
+		for _, code := range codes {
+			fmt.Printf("   ⚠️ %s: %d\n", code, results[0].Rejected[code])
+		}
+	}
+}
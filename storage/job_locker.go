@@ -0,0 +1,54 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// * Reclamos de ejecuciones del scheduler en la tabla scheduler_runs: la clave primaria
+// * (job, slot) garantiza que una sola réplica gane aunque inserten a la vez
+type SQLJobLocker struct {
+	db        *sql.DB
+	driver    string
+	owner     string
+	retention time.Duration
+}
+
+func NewSQLJobLocker(db *sql.DB, driver, owner string, retention time.Duration) *SQLJobLocker {
+	return &SQLJobLocker{db: db, driver: driver, owner: owner, retention: retention}
+}
+
+func (l *SQLJobLocker) Claim(ctx context.Context, job string, slot time.Time) (bool, error) {
+	now := time.Now().UTC()
+	slot = slot.UTC().Truncate(time.Second)
+
+	_, insertErr := l.db.ExecContext(ctx,
+		fmt.Sprintf("INSERT INTO scheduler_runs (job, slot, owner, claimed_at) VALUES (%s, %s, %s, %s)", placeholders(l.driver, 4)...),
+		job, slot, l.owner, now)
+	if insertErr == nil {
+		l.prune(ctx, now)
+		return true, nil
+	}
+
+	// * Cada driver reporta distinto la clave duplicada: si la fila existe, otra réplica ganó
+	var owner string
+	err := l.db.QueryRowContext(ctx,
+		fmt.Sprintf("SELECT owner FROM scheduler_runs WHERE job = %s AND slot = %s", placeholders(l.driver, 2)...),
+		job, slot).Scan(&owner)
+	if err == sql.ErrNoRows {
+		return false, fmt.Errorf("error reclamando %s: %w", job, insertErr)
+	}
+	if err != nil {
+		return false, err
+	}
+	return owner == l.owner, nil
+}
+
+// * Las filas viejas ya no protegen nada; se borran de paso
+func (l *SQLJobLocker) prune(ctx context.Context, now time.Time) {
+	l.db.ExecContext(ctx,
+		fmt.Sprintf("DELETE FROM scheduler_runs WHERE claimed_at < %s", Placeholder(l.driver, 1)),
+		now.Add(-l.retention))
+}
@@ -0,0 +1,56 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+)
+
+type SQLLinkStore struct {
+	db     *sql.DB
+	driver string
+}
+
+func NewSQLLinkStore(db *sql.DB, driver string) *SQLLinkStore {
+	return &SQLLinkStore{db: db, driver: driver}
+}
+
+func (s *SQLLinkStore) LoadLinks(ctx context.Context) ([]m.ShortLink, error) {
+	rows, err := s.db.QueryContext(ctx,
+		"SELECT code, tenant_id, cat_id, target_url, clicks, created_at, expires_at, last_click_at FROM short_links ORDER BY created_at")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var links []m.ShortLink
+	for rows.Next() {
+		var link m.ShortLink
+		if err := rows.Scan(&link.Code, &link.TenantID, &link.CatID, &link.TargetURL, &link.Clicks, &link.CreatedAt, &link.ExpiresAt, &link.LastClickAt); err != nil {
+			return nil, err
+		}
+		links = append(links, link)
+	}
+	return links, rows.Err()
+}
+
+func (s *SQLLinkStore) SaveLink(ctx context.Context, link m.ShortLink) error {
+	_, err := s.db.ExecContext(ctx,
+		fmt.Sprintf("INSERT INTO short_links (code, tenant_id, cat_id, target_url, clicks, created_at, expires_at) VALUES (%s, %s, %s, %s, %s, %s, %s)", placeholders(s.driver, 7)...),
+		link.Code, link.TenantID, link.CatID, link.TargetURL, link.Clicks, link.CreatedAt, link.ExpiresAt)
+	if err != nil {
+		return fmt.Errorf("error guardando enlace: %w", err)
+	}
+	return nil
+}
+
+// * Incremento en la base: varias instancias pueden contar clics a la vez
+func (s *SQLLinkStore) RecordClick(ctx context.Context, code string, at time.Time) error {
+	_, err := s.db.ExecContext(ctx,
+		fmt.Sprintf("UPDATE short_links SET clicks = clicks + 1, last_click_at = %s WHERE code = %s", placeholders(s.driver, 2)...),
+		at, code)
+	return err
+}
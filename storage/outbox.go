@@ -0,0 +1,130 @@
+package storage
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+	"github.com/ChrisTheAbysswalker/meownder-backend/schemas"
+)
+
+const (
+	relayBatchSize = 50
+	// * Después de estos intentos el evento queda apartado para revisión manual
+	relayMaxAttempts = 10
+)
+
+type Publisher interface {
+	Publish(ctx context.Context, event m.OutboxEvent) error
+}
+
+// ! El payload se valida contra la última versión del esquema del tema: uno que no cumple
+// ! hace fallar la transacción entera en vez de llegar distinto a los integradores
+func insertOutbox(ctx context.Context, tx *sql.Tx, driver, tenantID, topic string, payload any, at time.Time) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	version, ok := schemas.Latest(topic)
+	if !ok {
+		return fmt.Errorf("evento %s: %w", topic, schemas.ErrUnknownTopic)
+	}
+	if err := schemas.Validate(topic, version, data); err != nil {
+		return fmt.Errorf("evento %s v%d: %w", topic, version, err)
+	}
+	_, err = tx.ExecContext(ctx,
+		fmt.Sprintf("INSERT INTO outbox (id, tenant_id, topic, schema_version, payload, created_at) VALUES (%s, %s, %s, %s, %s, %s)", placeholders(driver, 6)...),
+		newEventID(at), tenantID, topic, version, string(data), at)
+	if err != nil {
+		return fmt.Errorf("error guardando evento %s: %w", topic, err)
+	}
+	return nil
+}
+
+// * Último prefijo entregado: dos eventos con el mismo instante (el swipe y su match) no
+// * pueden quedar ordenados por la parte aleatoria
+var (
+	lastEventNanos int64
+	eventIDMutex   sync.Mutex
+)
+
+// * Prefijo de tiempo para que el orden por id coincida con el de creación
+func newEventID(at time.Time) string {
+	eventIDMutex.Lock()
+	nanos := max(at.UnixNano(), lastEventNanos+1)
+	lastEventNanos = nanos
+	eventIDMutex.Unlock()
+
+	buf := make([]byte, 6)
+	_, _ = rand.Read(buf)
+	return fmt.Sprintf("%019d-%s", nanos, hex.EncodeToString(buf))
+}
+
+// * Publica los eventos pendientes del outbox; entrega al menos una vez, así que los
+// * publishers tienen que tolerar duplicados
+type Relay struct {
+	db        *sql.DB
+	driver    string
+	publisher Publisher
+}
+
+func NewRelay(db *sql.DB, driver string, publisher Publisher) *Relay {
+	return &Relay{db: db, driver: driver, publisher: publisher}
+}
+
+// ! Pensado para una sola instancia de relay; con varias habría que repartir con
+// ! SELECT ... FOR UPDATE SKIP LOCKED (solo Postgres)
+func (r *Relay) RelayOnce(ctx context.Context) error {
+	rows, err := r.db.QueryContext(ctx,
+		fmt.Sprintf("SELECT id, tenant_id, topic, schema_version, payload, created_at, attempts FROM outbox WHERE published_at IS NULL AND attempts < %d ORDER BY id LIMIT %d", relayMaxAttempts, relayBatchSize))
+	if err != nil {
+		return err
+	}
+
+	var events []m.OutboxEvent
+	for rows.Next() {
+		var event m.OutboxEvent
+		var payload string
+		if err := rows.Scan(&event.ID, &event.TenantID, &event.Topic, &event.SchemaVersion, &payload, &event.CreatedAt, &event.Attempts); err != nil {
+			rows.Close()
+			return err
+		}
+		event.Payload = []byte(payload)
+		events = append(events, event)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	published := 0
+	for _, event := range events {
+		if err := r.publisher.Publish(ctx, event); err != nil {
+			log.Printf("⚠️ Evento %s (%s) no publicado: %v", event.ID, event.Topic, err)
+			_, _ = r.db.ExecContext(ctx,
+				fmt.Sprintf("UPDATE outbox SET attempts = attempts + 1, last_error = %s WHERE id = %s", placeholders(r.driver, 2)...),
+				err.Error(), event.ID)
+			// * Se corta para respetar el orden: el resto espera al siguiente ciclo
+			break
+		}
+		_, err := r.db.ExecContext(ctx,
+			fmt.Sprintf("UPDATE outbox SET published_at = %s WHERE id = %s", placeholders(r.driver, 2)...),
+			time.Now().UTC(), event.ID)
+		if err != nil {
+			return err
+		}
+		published++
+	}
+
+	if published > 0 {
+		log.Printf("📤 Outbox: %d eventos publicados", published)
+	}
+	return nil
+}
@@ -0,0 +1,5 @@
+package storage
+
+// * Driver de Postgres (pgx, Go puro). Se registra como "pgx"; Open traduce "postgres",
+// * el valor por defecto de DATABASE_DRIVER
+import _ "github.com/jackc/pgx/v5/stdlib"
@@ -0,0 +1,36 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// * Open solo valida el driver; la conexión real se abre con la primera consulta
+func Open(driver, url string) (*sql.DB, error) {
+	if driver == "postgres" {
+		driver = "pgx"
+	}
+	db, err := sql.Open(driver, url)
+	if err != nil {
+		return nil, fmt.Errorf("error abriendo la base de datos: %w", err)
+	}
+	return db, nil
+}
+
+// ! Postgres usa $1, $2...; el resto de drivers comunes usa ?
+func Placeholder(driver string, n int) string {
+	switch driver {
+	case "postgres", "pgx":
+		return fmt.Sprintf("$%d", n)
+	default:
+		return "?"
+	}
+}
+
+func placeholders(driver string, count int) []any {
+	result := make([]any, count)
+	for i := range result {
+		result[i] = Placeholder(driver, i+1)
+	}
+	return result
+}
@@ -0,0 +1,246 @@
+package storage_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	_ "modernc.org/sqlite"
+
+	"github.com/ChrisTheAbysswalker/meownder-backend/migrate"
+	"github.com/ChrisTheAbysswalker/meownder-backend/migrations"
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+	"github.com/ChrisTheAbysswalker/meownder-backend/storage"
+)
+
+// * Cada prueba corre contra SQLite (siempre) y contra Postgres si TEST_DATABASE_URL apunta
+// * a una base descartable: las migraciones se aplican al empezar y se revierten al terminar
+type testDB struct {
+	name   string
+	driver string
+	db     *sql.DB
+}
+
+func databases(t *testing.T) []testDB {
+	t.Helper()
+	var dbs []testDB
+
+	db, err := storage.Open("sqlite", filepath.Join(t.TempDir(), "meownder.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dbs = append(dbs, testDB{name: "sqlite", driver: "sqlite", db: db})
+
+	if url := os.Getenv("TEST_DATABASE_URL"); url != "" {
+		db, err := storage.Open("postgres", url)
+		if err != nil {
+			t.Fatal(err)
+		}
+		dbs = append(dbs, testDB{name: "postgres", driver: "postgres", db: db})
+	}
+
+	for _, tdb := range dbs {
+		migrator, err := migrate.New(tdb.db, tdb.driver, migrations.FS)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := migrator.Up(context.Background()); err != nil {
+			t.Fatalf("%s: %v", tdb.name, err)
+		}
+		t.Cleanup(func() {
+			if _, err := migrator.Down(context.Background(), 1000); err != nil {
+				t.Errorf("%s: revirtiendo migraciones: %v", tdb.name, err)
+			}
+			tdb.db.Close()
+		})
+	}
+	return dbs
+}
+
+func TestMigrationsRoundTrip(t *testing.T) {
+	for _, tdb := range databases(t) {
+		t.Run(tdb.name, func(t *testing.T) {
+			ctx := context.Background()
+			migrator, err := migrate.New(tdb.db, tdb.driver, migrations.FS)
+			if err != nil {
+				t.Fatal(err)
+			}
+			statuses, err := migrator.Status(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, status := range statuses {
+				if status.AppliedAt == nil {
+					t.Errorf("migración %d (%s) sin aplicar", status.Version, status.Name)
+				}
+			}
+
+			reverted, err := migrator.Down(ctx, len(statuses))
+			if err != nil || reverted != len(statuses) {
+				t.Fatalf("Down: %d de %d, %v", reverted, len(statuses), err)
+			}
+			applied, err := migrator.Up(ctx)
+			if err != nil || applied != len(statuses) {
+				t.Fatalf("Up: %d de %d, %v", applied, len(statuses), err)
+			}
+		})
+	}
+}
+
+type recordingPublisher struct {
+	events []m.OutboxEvent
+	fail   error
+}
+
+func (p *recordingPublisher) Publish(_ context.Context, event m.OutboxEvent) error {
+	if p.fail != nil {
+		return p.fail
+	}
+	p.events = append(p.events, event)
+	return nil
+}
+
+func TestSwipeStoreAndOutboxRelay(t *testing.T) {
+	for _, tdb := range databases(t) {
+		t.Run(tdb.name, func(t *testing.T) {
+			ctx := context.Background()
+			at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+			store := storage.NewSQLSwipeStore(tdb.db, tdb.driver, "norte")
+			swipe := m.Swipe{UserID: "ana", CatID: 7, Direction: "like", CreatedAt: at}
+			match := m.Match{ID: "m1", UserID: "ana", CatID: 7, CatName: "Luna", CreatedAt: at}
+			if err := store.SaveSwipe(ctx, swipe, []m.Match{match}); err != nil {
+				t.Fatal(err)
+			}
+
+			swipes, matches, err := store.LoadSwipes(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(swipes) != 1 || swipes[0].CatID != 7 || !swipes[0].CreatedAt.Equal(at) {
+				t.Fatalf("swipes = %+v", swipes)
+			}
+			if len(matches) != 1 || matches[0].CatName != "Luna" {
+				t.Fatalf("matches = %+v", matches)
+			}
+			// * Otro tenant no ve nada
+			if other, _, _ := storage.NewSQLSwipeStore(tdb.db, tdb.driver, "sur").LoadSwipes(ctx); len(other) != 0 {
+				t.Fatalf("swipes de otro tenant: %+v", other)
+			}
+
+			archived := at.Add(time.Hour)
+			match.ArchivedAt = &archived
+			if err := store.UpdateMatch(ctx, match); err != nil {
+				t.Fatal(err)
+			}
+			if _, matches, _ = store.LoadSwipes(ctx); matches[0].ArchivedAt == nil || !matches[0].ArchivedAt.Equal(archived) {
+				t.Fatalf("archived_at = %v", matches[0].ArchivedAt)
+			}
+
+			// * Un fallo suma un intento y no publica nada
+			failing := &recordingPublisher{fail: errors.New("broker caído")}
+			if err := storage.NewRelay(tdb.db, tdb.driver, failing).RelayOnce(ctx); err != nil {
+				t.Fatal(err)
+			}
+			var attempts int
+			if err := tdb.db.QueryRow("SELECT MAX(attempts) FROM outbox").Scan(&attempts); err != nil || attempts != 1 {
+				t.Fatalf("attempts = %d, %v", attempts, err)
+			}
+
+			publisher := &recordingPublisher{}
+			relay := storage.NewRelay(tdb.db, tdb.driver, publisher)
+			if err := relay.RelayOnce(ctx); err != nil {
+				t.Fatal(err)
+			}
+			if len(publisher.events) != 2 {
+				t.Fatalf("publicados %d eventos, se esperaban 2", len(publisher.events))
+			}
+			if publisher.events[0].Topic != m.TopicSwipeRecorded || publisher.events[1].Topic != m.TopicMatchCreated {
+				t.Errorf("orden = %s, %s", publisher.events[0].Topic, publisher.events[1].Topic)
+			}
+			for _, event := range publisher.events {
+				if event.TenantID != "norte" || event.SchemaVersion != 1 {
+					t.Errorf("evento %+v", event)
+				}
+			}
+
+			// * Ya publicados: la siguiente vuelta no repite
+			if err := relay.RelayOnce(ctx); err != nil || len(publisher.events) != 2 {
+				t.Fatalf("segunda vuelta: %d eventos, %v", len(publisher.events), err)
+			}
+		})
+	}
+}
+
+func TestSwipeStoreRollsBackWithOutbox(t *testing.T) {
+	for _, tdb := range databases(t) {
+		t.Run(tdb.name, func(t *testing.T) {
+			ctx := context.Background()
+			store := storage.NewSQLSwipeStore(tdb.db, tdb.driver, "default")
+			swipe := m.Swipe{UserID: "ana", CatID: 3, Direction: "like", CreatedAt: time.Now().UTC()}
+			if err := store.SaveSwipe(ctx, swipe, nil); err != nil {
+				t.Fatal(err)
+			}
+			// * El mismo swipe choca con la clave primaria: no puede quedar su evento suelto
+			if err := store.SaveSwipe(ctx, swipe, nil); err == nil {
+				t.Fatal("se esperaba error por swipe repetido")
+			}
+			var events int
+			if err := tdb.db.QueryRow("SELECT COUNT(*) FROM outbox").Scan(&events); err != nil || events != 1 {
+				t.Fatalf("eventos = %d, %v", events, err)
+			}
+		})
+	}
+}
+
+func TestJobLockerSingleWinner(t *testing.T) {
+	for _, tdb := range databases(t) {
+		t.Run(tdb.name, func(t *testing.T) {
+			ctx := context.Background()
+			slot := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+			first := storage.NewSQLJobLocker(tdb.db, tdb.driver, "replica-a", time.Hour)
+			second := storage.NewSQLJobLocker(tdb.db, tdb.driver, "replica-b", time.Hour)
+
+			if won, err := first.Claim(ctx, "backup", slot); err != nil || !won {
+				t.Fatalf("primera réplica: %v, %v", won, err)
+			}
+			if won, err := second.Claim(ctx, "backup", slot); err != nil || won {
+				t.Fatalf("segunda réplica: %v, %v", won, err)
+			}
+			if won, err := first.Claim(ctx, "backup", slot); err != nil || !won {
+				t.Fatalf("reintento de la dueña: %v, %v", won, err)
+			}
+		})
+	}
+}
+
+func TestLinkStoreCountsClicks(t *testing.T) {
+	for _, tdb := range databases(t) {
+		t.Run(tdb.name, func(t *testing.T) {
+			ctx := context.Background()
+			store := storage.NewSQLLinkStore(tdb.db, tdb.driver)
+			created := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+			link := m.ShortLink{Code: "abc123", TenantID: "norte", CatID: 7, TargetURL: "https://meownder.app/share/7", CreatedAt: created}
+			if err := store.SaveLink(ctx, link); err != nil {
+				t.Fatal(err)
+			}
+			clicked := created.Add(time.Minute)
+			for range 2 {
+				if err := store.RecordClick(ctx, "abc123", clicked); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			links, err := store.LoadLinks(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(links) != 1 || links[0].Clicks != 2 || links[0].LastClickAt == nil || !links[0].LastClickAt.Equal(clicked) {
+				t.Fatalf("links = %+v", links)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+)
+
+// * Guarda swipes y matches junto con sus eventos en la misma transacción: si el proceso
+// * muere a mitad, o queda todo (y el relay lo publica) o no queda nada
+type SQLSwipeStore struct {
+	db       *sql.DB
+	driver   string
+	tenantID string
+}
+
+func NewSQLSwipeStore(db *sql.DB, driver, tenantID string) *SQLSwipeStore {
+	return &SQLSwipeStore{db: db, driver: driver, tenantID: tenantID}
+}
+
+// * En orden de creación, igual que los guarda SwipeService en memoria
+func (s *SQLSwipeStore) LoadSwipes(ctx context.Context) ([]m.Swipe, []m.Match, error) {
+	rows, err := s.db.QueryContext(ctx,
+		fmt.Sprintf("SELECT user_id, cat_id, from_cat_id, direction, created_at FROM swipes WHERE tenant_id = %s ORDER BY created_at", Placeholder(s.driver, 1)),
+		s.tenantID)
+	if err != nil {
+		return nil, nil, err
+	}
+	var swipes []m.Swipe
+	for rows.Next() {
+		var swipe m.Swipe
+		if err := rows.Scan(&swipe.UserID, &swipe.CatID, &swipe.FromCatID, &swipe.Direction, &swipe.CreatedAt); err != nil {
+			rows.Close()
+			return nil, nil, err
+		}
+		swipes = append(swipes, swipe)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	rows, err = s.db.QueryContext(ctx,
+		fmt.Sprintf("SELECT id, user_id, cat_id, cat_name, with_cat_id, mutual, nudged_at, archived_at, revived_at, created_at FROM matches WHERE tenant_id = %s ORDER BY created_at", Placeholder(s.driver, 1)),
+		s.tenantID)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+	var matches []m.Match
+	for rows.Next() {
+		var match m.Match
+		if err := rows.Scan(&match.ID, &match.UserID, &match.CatID, &match.CatName, &match.WithCatID, &match.Mutual, &match.NudgedAt, &match.ArchivedAt, &match.RevivedAt, &match.CreatedAt); err != nil {
+			return nil, nil, err
+		}
+		matches = append(matches, match)
+	}
+	return swipes, matches, rows.Err()
+}
+
+func (s *SQLSwipeStore) SaveSwipe(ctx context.Context, swipe m.Swipe, matches []m.Match) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx,
+		fmt.Sprintf("INSERT INTO swipes (tenant_id, user_id, cat_id, from_cat_id, direction, created_at) VALUES (%s, %s, %s, %s, %s, %s)", placeholders(s.driver, 6)...),
+		s.tenantID, swipe.UserID, swipe.CatID, swipe.FromCatID, swipe.Direction, swipe.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("error guardando swipe: %w", err)
+	}
+	if err := insertOutbox(ctx, tx, s.driver, s.tenantID, m.TopicSwipeRecorded, swipe, swipe.CreatedAt); err != nil {
+		return err
+	}
+
+	// * Un match mutuo son dos filas (una por dueño) y dos eventos
+	for _, match := range matches {
+		_, err = tx.ExecContext(ctx,
+			fmt.Sprintf("INSERT INTO matches (tenant_id, id, user_id, cat_id, cat_name, with_cat_id, mutual, created_at) VALUES (%s, %s, %s, %s, %s, %s, %s, %s)", placeholders(s.driver, 8)...),
+			s.tenantID, match.ID, match.UserID, match.CatID, match.CatName, match.WithCatID, match.Mutual, match.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("error guardando match: %w", err)
+		}
+		if err := insertOutbox(ctx, tx, s.driver, s.tenantID, m.TopicMatchCreated, match, match.CreatedAt); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// * Solo cambia el ciclo de vida del match; no genera evento en el outbox
+func (s *SQLSwipeStore) UpdateMatch(ctx context.Context, match m.Match) error {
+	_, err := s.db.ExecContext(ctx,
+		fmt.Sprintf("UPDATE matches SET nudged_at = %s, archived_at = %s, revived_at = %s WHERE tenant_id = %s AND id = %s", placeholders(s.driver, 5)...),
+		match.NudgedAt, match.ArchivedAt, match.RevivedAt, s.tenantID, match.ID)
+	if err != nil {
+		return fmt.Errorf("error actualizando match: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const apiBase = "https://api.telegram.org/bot"
+
+type update struct {
+	UpdateID      int            `json:"update_id"`
+	Message       *message       `json:"message"`
+	CallbackQuery *callbackQuery `json:"callback_query"`
+}
+
+type message struct {
+	MessageID int    `json:"message_id"`
+	Chat      chat   `json:"chat"`
+	Text      string `json:"text"`
+}
+
+type chat struct {
+	ID int64 `json:"id"`
+}
+
+type callbackQuery struct {
+	ID      string   `json:"id"`
+	Message *message `json:"message"`
+	Data    string   `json:"data"`
+}
+
+type inlineKeyboard struct {
+	InlineKeyboard [][]inlineButton `json:"inline_keyboard"`
+}
+
+type inlineButton struct {
+	Text         string `json:"text"`
+	CallbackData string `json:"callback_data"`
+}
+
+type apiResponse struct {
+	OK          bool            `json:"ok"`
+	Description string          `json:"description"`
+	Result      json.RawMessage `json:"result"`
+}
+
+type client struct {
+	token string
+	http  *http.Client
+}
+
+func newClient(token string, pollTimeout time.Duration) *client {
+	return &client{
+		token: token,
+		// * El timeout HTTP debe superar el long polling de getUpdates
+		http: &http.Client{Timeout: pollTimeout + 10*time.Second},
+	}
+}
+
+func (c *client) call(ctx context.Context, method string, params any, result any) error {
+	body, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiBase+c.token+"/"+method, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var apiResp apiResponse
+	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
+		return fmt.Errorf("respuesta inválida de Telegram: %w", err)
+	}
+	if !apiResp.OK {
+		return fmt.Errorf("telegram %s: %s", method, apiResp.Description)
+	}
+
+	if result != nil {
+		return json.Unmarshal(apiResp.Result, result)
+	}
+	return nil
+}
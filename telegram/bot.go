@@ -0,0 +1,189 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+	s "github.com/ChrisTheAbysswalker/meownder-backend/services"
+)
+
+const (
+	pollTimeout = 30 * time.Second
+	// * Telegram rechaza sendPhoto con un caption de más de 1024 caracteres
+	captionLimit = 1024
+)
+
+// * Reservados de MarkdownV2: cualquier texto del perfil va escapado para no romper el formato
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "_", `\_`, "*", `\*`, "[", `\[`, "]", `\]`, "(", `\(`, ")", `\)`, "~", `\~`,
+	"`", "\\`", ">", `\>`, "#", `\#`, "+", `\+`, "-", `\-`, "=", `\=`, "|", `\|`,
+	"{", `\{`, "}", `\}`, ".", `\.`, "!", `\!`,
+)
+
+// * Segunda interfaz de cliente: mismo SwipeService que la API REST
+type Bot struct {
+	api    *client
+	swipes *s.SwipeService
+}
+
+func NewBot(token string, swipes *s.SwipeService) *Bot {
+	return &Bot{
+		api:    newClient(token, pollTimeout),
+		swipes: swipes,
+	}
+}
+
+func (b *Bot) Run(ctx context.Context) {
+	log.Println("🤖 Bot de Telegram iniciado (long polling)")
+
+	offset := 0
+	for {
+		if ctx.Err() != nil {
+			return
+		}
+
+		var updates []update
+		err := b.api.call(ctx, "getUpdates", map[string]any{
+			"offset":          offset,
+			"timeout":         int(pollTimeout.Seconds()),
+			"allowed_updates": []string{"message", "callback_query"},
+		}, &updates)
+		if err != nil {
+			log.Printf("⚠️ Error consultando Telegram: %v", err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(5 * time.Second):
+			}
+			continue
+		}
+
+		for _, u := range updates {
+			offset = u.UpdateID + 1
+			b.handle(ctx, u)
+		}
+	}
+}
+
+func (b *Bot) handle(ctx context.Context, u update) {
+	switch {
+	case u.CallbackQuery != nil:
+		b.handleCallback(ctx, u.CallbackQuery)
+	case u.Message != nil:
+		b.handleMessage(ctx, u.Message)
+	}
+}
+
+func (b *Bot) handleMessage(ctx context.Context, msg *message) {
+	switch strings.Fields(msg.Text + " ")[0] {
+	case "/start":
+		b.sendText(ctx, msg.Chat.ID, "¡Bienvenido a Meownder! 🐾 Desliza con 👍 o 👎.")
+		b.sendNextCat(ctx, msg.Chat.ID)
+	case "/next":
+		b.sendNextCat(ctx, msg.Chat.ID)
+	case "/matches":
+		b.sendMatches(ctx, msg.Chat.ID)
+	default:
+		b.sendText(ctx, msg.Chat.ID, "Comandos: /next, /matches")
+	}
+}
+
+// * callback_data tiene la forma "like:<id>" o "pass:<id>"
+func (b *Bot) handleCallback(ctx context.Context, cb *callbackQuery) {
+	if cb.Message == nil {
+		return
+	}
+	chatID := cb.Message.Chat.ID
+
+	direction, idStr, found := strings.Cut(cb.Data, ":")
+	catID, err := strconv.Atoi(idStr)
+	if !found || err != nil || (direction != m.SwipeLike && direction != m.SwipePass) {
+		b.answerCallback(ctx, cb.ID, "Acción inválida")
+		return
+	}
+
+	result, err := b.swipes.Record(ctx, userID(chatID), catID, 0, direction)
+	if err != nil {
+		b.answerCallback(ctx, cb.ID, err.Error())
+		return
+	}
+	b.answerCallback(ctx, cb.ID, "")
+
+	// * Quitar los botones para que no se pueda votar dos veces el mismo mensaje
+	_ = b.api.call(ctx, "editMessageReplyMarkup", map[string]any{
+		"chat_id":    chatID,
+		"message_id": cb.Message.MessageID,
+	}, nil)
+
+	if result.Match != nil {
+		b.sendText(ctx, chatID, fmt.Sprintf("💘 ¡Es un match con %s!", result.Match.CatName))
+	}
+	b.sendNextCat(ctx, chatID)
+}
+
+func (b *Bot) sendNextCat(ctx context.Context, chatID int64) {
+	cat := b.swipes.NextCandidate(userID(chatID))
+	if cat == nil {
+		b.sendText(ctx, chatID, "Ya viste todos los gatos 😿 Vuelve más tarde.")
+		return
+	}
+
+	caption := formatCaption(cat)
+	err := b.api.call(ctx, "sendPhoto", map[string]any{
+		"chat_id":    chatID,
+		"photo":      cat.Img,
+		"caption":    caption,
+		"parse_mode": "MarkdownV2",
+		"reply_markup": inlineKeyboard{InlineKeyboard: [][]inlineButton{{
+			{Text: "👎", CallbackData: fmt.Sprintf("%s:%d", m.SwipePass, cat.ID)},
+			{Text: "👍", CallbackData: fmt.Sprintf("%s:%d", m.SwipeLike, cat.ID)},
+		}}},
+	}, nil)
+	if err != nil {
+		log.Printf("⚠️ Error enviando gato a Telegram: %v", err)
+	}
+}
+
+// * El límite cuenta el texto visible, sin los escapes ni las marcas de formato; la bio
+// * se recorta para que entre con el resto
+func formatCaption(cat *m.CatProfile) string {
+	header := fmt.Sprintf("%s, %d años · %s\n%s\n\n", cat.Name, cat.Age, cat.Breed, cat.Personality)
+	bio := s.BioSnippet(cat.Bio, max(0, captionLimit-utf8.RuneCountInString(header)-1))
+	return fmt.Sprintf("*%s*, %d años · %s\n_%s_\n\n%s",
+		markdownEscaper.Replace(cat.Name), cat.Age, markdownEscaper.Replace(cat.Breed),
+		markdownEscaper.Replace(cat.Personality), markdownEscaper.Replace(bio))
+}
+
+func (b *Bot) sendMatches(ctx context.Context, chatID int64) {
+	matches := b.swipes.Matches(userID(chatID))
+	if len(matches) == 0 {
+		b.sendText(ctx, chatID, "Aún no tienes matches. ¡Sigue deslizando!")
+		return
+	}
+
+	names := make([]string, 0, len(matches))
+	for _, match := range matches {
+		names = append(names, "• "+match.CatName)
+	}
+	b.sendText(ctx, chatID, "Tus matches 💕\n"+strings.Join(names, "\n"))
+}
+
+func (b *Bot) sendText(ctx context.Context, chatID int64, text string) {
+	if err := b.api.call(ctx, "sendMessage", map[string]any{"chat_id": chatID, "text": text}, nil); err != nil {
+		log.Printf("⚠️ Error enviando mensaje a Telegram: %v", err)
+	}
+}
+
+func (b *Bot) answerCallback(ctx context.Context, id, text string) {
+	_ = b.api.call(ctx, "answerCallbackQuery", map[string]any{"callback_query_id": id, "text": text}, nil)
+}
+
+func userID(chatID int64) string {
+	return fmt.Sprintf("tg:%d", chatID)
+}
@@ -0,0 +1,30 @@
+package telegram
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	m "github.com/ChrisTheAbysswalker/meownder-backend/models"
+)
+
+func TestFormatCaptionEscapes(t *testing.T) {
+	cat := &m.CatProfile{Name: "Don_Gato*", Age: 3, Breed: "Siamés (mix)", Personality: "[curioso]", Bio: "Le gusta `dormir`. ¡Mucho!"}
+	want := "*Don\\_Gato\\**, 3 años · Siamés \\(mix\\)\n_\\[curioso\\]_\n\nLe gusta \\`dormir\\`\\. ¡Mucho\\!"
+	if got := formatCaption(cat); got != want {
+		t.Errorf("caption = %q\nse esperaba %q", got, want)
+	}
+}
+
+func TestFormatCaptionFitsLimit(t *testing.T) {
+	cat := &m.CatProfile{Name: "Luna", Age: 2, Breed: "Persa", Personality: "Tranquila", Bio: strings.Repeat("ronronea ", 300)}
+	caption := formatCaption(cat)
+	// * Lo que cuenta Telegram es el texto sin escapes ni marcas
+	visible := strings.NewReplacer(`\`, "", "*", "", "_", "").Replace(caption)
+	if n := utf8.RuneCountInString(visible); n > captionLimit {
+		t.Fatalf("caption de %d caracteres", n)
+	}
+	if !strings.HasSuffix(caption, "…") {
+		t.Errorf("la bio no se recortó: %q", caption[len(caption)-20:])
+	}
+}